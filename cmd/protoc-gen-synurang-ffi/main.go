@@ -26,6 +26,7 @@ var templates *template.Template
 type FileData struct {
 	Package       string
 	GoPackageName string
+	GoDescriptor  string // File_<path>_proto variable of the .pb.go file
 	Services      []ServiceData
 	HasStreaming  bool
 	DartPackage   string
@@ -45,9 +46,10 @@ type GoImport struct {
 }
 
 type ServiceData struct {
-	Name    string
-	GoName  string
-	Methods []MethodData
+	Name     string
+	FullName string // e.g., "pkg.MyService"
+	GoName   string
+	Methods  []MethodData
 }
 
 type MethodData struct {
@@ -227,6 +229,7 @@ func buildFileData(gen *protogen.Plugin, file *protogen.File, serviceList map[st
 	data := FileData{
 		Package:       string(file.Desc.Package()),
 		GoPackageName: string(file.GoPackageName),
+		GoDescriptor:  file.GoDescriptorIdent.GoName,
 	}
 
 	// Language-specific fields
@@ -267,8 +270,9 @@ func buildFileData(gen *protogen.Plugin, file *protogen.File, serviceList map[st
 		}
 
 		svcData := ServiceData{
-			Name:   string(service.Desc.Name()),
-			GoName: service.GoName,
+			Name:     string(service.Desc.Name()),
+			FullName: string(service.Desc.FullName()),
+			GoName:   service.GoName,
 		}
 
 		for _, method := range service.Methods {
@@ -39,28 +39,52 @@ static void invoke_stream_callback(StreamCallback cb, long long streamId, char m
         cb(streamId, msgType, data, len);
     }
 }
+
+// Completion callback signature (for InvokeBackendAsync)
+typedef void (*CallCompleteCallback)(long long callId, void* data, long long len);
+
+static void invoke_call_complete_callback(CallCompleteCallback cb, long long callId, void* data, long long len) {
+    if (cb) {
+        cb(callId, data, len);
+    }
+}
+
+// Serve error callback signature (serialized core.v1.ListenerStatus)
+typedef void (*ServeErrorCallback)(void* data, long long len);
+
+static void invoke_serve_error_callback(ServeErrorCallback cb, void* data, long long len) {
+    if (cb) {
+        cb(data, len);
+    }
+}
 */
 import "C"
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
-	"net"
+	"math"
 	"os"
 	"os/signal"
 	goruntime "runtime"
 	"runtime/debug"
+	"strconv"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 	"unsafe"
 
 	pb "github.com/ivere27/synurang/pkg/api"
+	"github.com/ivere27/synurang/pkg/plugin"
 	"github.com/ivere27/synurang/pkg/service"
+	"github.com/ivere27/synurang/pkg/synurang"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 )
@@ -71,19 +95,37 @@ var (
 	buildDate  string
 )
 
+var sigChan = make(chan os.Signal, 1)
+
+// ffiEngine is a service.Engine together with the C callbacks registered on
+// it and its pending Go -> Dart requests.
+type ffiEngine struct {
+	*service.Engine
+	requests *service.RequestHandler
+
+	callbackMu           sync.RWMutex // guards the fields below
+	dartCallback         C.InvokeDartCallback
+	streamCallback       C.StreamCallback
+	callCompleteCallback C.CallCompleteCallback
+	abiServices          []string // served through the Synurang ABI since start
+}
+
 var (
-	srv          *grpc.Server
-	impl         *service.CoreServiceServer
-	implMu       sync.RWMutex
-	listeners    []net.Listener
-	serverErrors chan error
-	sigChan      = make(chan os.Signal, 1)
-	dartCallback C.InvokeDartCallback
-
-	// Stream callback for server/bidi streaming
-	streamCallback C.StreamCallback
+	// defaultEngine is handle 0, the engine of the exports without a handle.
+	defaultEngine = &ffiEngine{Engine: service.DefaultEngine(), requests: service.DefaultRequestHandler}
+
+	engines      = map[int64]*ffiEngine{0: defaultEngine}
+	enginesMu    sync.RWMutex
+	nextEngineId int64
 )
 
+// lookupEngine returns the engine with the given handle, or nil.
+func lookupEngine(handle C.longlong) *ffiEngine {
+	enginesMu.RLock()
+	defer enginesMu.RUnlock()
+	return engines[int64(handle)]
+}
+
 func init() {
 	log.SetFlags(log.Ldate | log.Ltime)
 	log.Printf("Synurang - Commit: %s, Date: %s, Build: %s\n", commitHash, commitDate, buildDate)
@@ -93,6 +135,122 @@ func init() {
 	flag.String("flutter-port", "", "Flutter gRPC server TCP port (for bidirectional communication)")
 	flag.String("flutter-socket", "", "Flutter gRPC server UDS socket path (for bidirectional communication)")
 	flag.String("token", "jwttoken", "auth token")
+	flag.String("tls-cert", "", "TLS certificate file (PEM) for the TCP listener")
+	flag.String("tls-key", "", "TLS private key file (PEM) for the TCP listener")
+	flag.String("tls-client-ca", "", "CA file (PEM) of the client certificates required for mutual TLS")
+	flag.Bool("tls-auto", false, "serve TCP with a generated self-signed certificate if -tls-cert is not set")
+	flag.String("policy", "", "authorization policy file (JSON or YAML)")
+	flag.Bool("public-reflection", false, "serve server reflection without the token and policy checks")
+	flag.String("socket-mode", "", "permissions of the -golang-socket file in octal, e.g. 0600")
+	flag.String("socket-owner", "", "owner of the -golang-socket file: user, user:group or :group")
+	flag.String("socket-allow-uid", "", "comma-separated uids allowed to connect to -golang-socket (Linux)")
+
+	// Go hosts load the engine with synurang.LoadEngine, which starts and
+	// stops it through the Synurang ABI instead of StartGrpcServer.
+	plugin.OnInit(func(data []byte) error {
+		var ec synurang.EngineConfig
+		if len(data) > 0 {
+			if err := json.Unmarshal(data, &ec); err != nil {
+				return fmt.Errorf("invalid engine config: %w", err)
+			}
+		}
+		cfg, err := engineConfig(ec)
+		if err != nil {
+			return err
+		}
+		_, err = defaultEngine.start(cfg)
+		return err
+	})
+	plugin.OnShutdown(func(ctx context.Context) error {
+		StopGrpcServer()
+		return nil
+	})
+}
+
+// engineConfig converts the Synurang_Init configuration to a service.Config.
+func engineConfig(ec synurang.EngineConfig) (*service.Config, error) {
+	socketMode, err := parseSocketMode(ec.SocketMode)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &service.Config{
+		EngineSocketPath: ec.EngineSocketPath,
+		EngineTcpPort:    ec.EngineTcpPort,
+		ViewSocketPath:   ec.ViewSocketPath,
+		ViewTcpPort:      ec.ViewTcpPort,
+		Token:            ec.Token,
+		CachePath:        ec.CachePath,
+		EnableCache:      ec.CachePath != "" && ec.EnableCache,
+		StreamTimeout:    time.Duration(ec.StreamTimeoutMs) * time.Millisecond,
+
+		FailOnListenError: ec.FailOnListenError,
+		ShutdownTimeout:   time.Duration(ec.ShutdownTimeoutMs) * time.Millisecond,
+		HealthInterval:    time.Duration(ec.HealthIntervalMs) * time.Millisecond,
+
+		TLSCertFile:     ec.TLSCertFile,
+		TLSKeyFile:      ec.TLSKeyFile,
+		TLSClientCAFile: ec.TLSClientCAFile,
+		TLSAutoCert:     ec.TLSAutoCert,
+
+		ViewTLS:           ec.ViewTLS,
+		ViewTLSCAFile:     ec.ViewTLSCAFile,
+		ViewTLSCertFile:   ec.ViewTLSCertFile,
+		ViewTLSKeyFile:    ec.ViewTLSKeyFile,
+		ViewTLSServerName: ec.ViewTLSServerName,
+
+		JWTKeys:     jwtKeys(ec.JWTKeys),
+		JWTIssuer:   ec.JWTIssuer,
+		JWTAudience: ec.JWTAudience,
+		JWTLeeway:   time.Duration(ec.JWTLeewayMs) * time.Millisecond,
+
+		PolicyFile:       ec.PolicyFile,
+		PublicReflection: ec.PublicReflection,
+
+		SocketMode:  socketMode,
+		SocketOwner: ec.SocketOwner,
+	}
+	if len(ec.SocketPeerUIDs)+len(ec.SocketPeerGIDs)+len(ec.SocketPeerPIDs) > 0 {
+		cfg.SocketPeers = &service.PeerAllowlist{
+			UIDs: ec.SocketPeerUIDs,
+			GIDs: ec.SocketPeerGIDs,
+			PIDs: ec.SocketPeerPIDs,
+		}
+	}
+	return cfg, nil
+}
+
+// parseSocketMode parses an octal socket file mode; "" is 0, the default.
+func parseSocketMode(s string) (os.FileMode, error) {
+	if s == "" {
+		return 0, nil
+	}
+	mode, err := strconv.ParseUint(s, 8, 32)
+	if err != nil || mode > 0777 {
+		return 0, fmt.Errorf("invalid socket mode %q", s)
+	}
+	return os.FileMode(mode), nil
+}
+
+// parseUIDs parses a comma-separated list of uids.
+func parseUIDs(s string) ([]uint32, error) {
+	var uids []uint32
+	for _, f := range strings.Split(s, ",") {
+		uid, err := strconv.ParseUint(strings.TrimSpace(f), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid uid %q", f)
+		}
+		uids = append(uids, uint32(uid))
+	}
+	return uids, nil
+}
+
+// jwtKeys converts the JWT keys of an EngineConfig.
+func jwtKeys(keys []synurang.JWTKey) []service.JWTKey {
+	var out []service.JWTKey
+	for _, k := range keys {
+		out = append(out, service.JWTKey{ID: k.ID, Secret: k.Secret, PublicKey: k.PublicKey})
+	}
+	return out
 }
 
 // =============================================================================
@@ -102,11 +260,73 @@ func init() {
 //export StartGrpcServer
 func StartGrpcServer(cArg C.struct_CoreArgument) C.int {
 	log.Println("Synurang - StartGrpcServer called")
-	if srv != nil {
-		log.Println("Synurang - already started")
+	if _, err := defaultEngine.start(coreConfig(cArg)); err != nil {
 		return -1
 	}
+	return 0
+}
+
+// StartGrpcServerWithStatus is StartGrpcServer returning a serialized
+// core.v1.ServerStatus: every endpoint with its bound address or error, the
+// cache state, and why the server did not start. With failOnListenError != 0
+// a listener that cannot bind fails the start. The caller frees the result
+// with FreeFfiData.
+//
+//export StartGrpcServerWithStatus
+func StartGrpcServerWithStatus(cArg C.struct_CoreArgument, failOnListenError C.int) C.FfiData {
+	log.Println("Synurang - StartGrpcServerWithStatus called")
+	return StartEngineWithStatus(0, cArg, failOnListenError)
+}
+
+// GetServerStatus returns the serialized core.v1.ServerStatus of the server,
+// including the errors that ended a listener after it started. The caller
+// frees the result with FreeFfiData.
+//
+//export GetServerStatus
+func GetServerStatus() C.FfiData {
+	return EngineGetServerStatus(0)
+}
+
+// GetHealth returns the serialized core.v1.HealthReport of the server: the
+// grpc.health.v1 status of the engine and of each subsystem (cache, view
+// connection, ...), e.g. for a degraded-mode banner. The caller frees the
+// result with FreeFfiData.
+//
+//export GetHealth
+func GetHealth() C.FfiData {
+	return EngineGetHealth(0)
+}
+
+// ListServices returns the serialized core.v1.ServiceList of every service
+// of the server: those served over gRPC and those registered for FFI
+// dispatch or plugins, as server reflection describes them. The caller frees
+// the result with FreeFfiData.
+//
+//export ListServices
+func ListServices() C.FfiData {
+	return EngineListServices(0)
+}
+
+// DescribeMethod returns the serialized core.v1.MethodDescription of method
+// ("/package.Service/Method"), or empty data if no service has it. The
+// caller frees the result with FreeFfiData.
+//
+//export DescribeMethod
+func DescribeMethod(method *C.char) C.FfiData {
+	return EngineDescribeMethod(0, method)
+}
 
+// RegisterServeErrorCallback sets the callback receiving a serialized
+// core.v1.ListenerStatus when an error ends a listener after the server
+// started. The callee frees the data with FreeFfiData.
+//
+//export RegisterServeErrorCallback
+func RegisterServeErrorCallback(callback C.ServeErrorCallback) {
+	EngineRegisterServeErrorCallback(0, callback)
+}
+
+// coreConfig converts the StartGrpcServer arguments to a service.Config.
+func coreConfig(cArg C.struct_CoreArgument) *service.Config {
 	cfg := &service.Config{}
 
 	if unsafe.Pointer(cArg.storagePath) != nil {
@@ -141,280 +361,551 @@ func StartGrpcServer(cArg C.struct_CoreArgument) C.int {
 	if unsafe.Pointer(cArg.token) != nil {
 		cfg.Token = C.GoString(cArg.token)
 	}
+	return cfg
+}
 
-	// Set the global default stream timeout for FFI callbacks
-	service.SetDefaultStreamTimeout(cfg.StreamTimeout)
-
-	listeners = make([]net.Listener, 0)
-	var listenersMu sync.Mutex
-	var wg sync.WaitGroup
-
-	// UDS listener
-	if cfg.EngineSocketPath != "" {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			if _, err := os.Stat(cfg.EngineSocketPath); err == nil {
-				os.Remove(cfg.EngineSocketPath)
-			}
-			udsListen, err := net.Listen("unix", cfg.EngineSocketPath)
-			if err != nil {
-				log.Printf("Failed to listen on UDS: %v", err)
-			} else {
-				listenersMu.Lock()
-				listeners = append(listeners, udsListen)
-				listenersMu.Unlock()
-			}
-		}()
-	}
-
-	// TCP listener
-	if cfg.EngineTcpPort != "" {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			tcpListen, err := net.Listen("tcp", ":"+cfg.EngineTcpPort)
-			if err != nil {
-				log.Printf("Failed to listen on TCP: %v", err)
-			} else {
-				listenersMu.Lock()
-				listeners = append(listeners, tcpListen)
-				listenersMu.Unlock()
-			}
-		}()
-	}
-
-	wg.Wait()
-
-	// Initialize server
-	serviceImpl := service.NewCoreService(cfg)
-	s := service.NewGrpcServer(serviceImpl, cfg)
-
-	srv = s
-	implMu.Lock()
-	impl = serviceImpl
-	implMu.Unlock()
-
-	// Start serving
-	serverErrors = make(chan error, 1)
-	if len(listeners) > 0 {
-		go func() {
-			for _, listener := range listeners {
-				go func(l net.Listener) {
-					log.Printf("Synurang - Serving on %s", l.Addr().String())
-					if err := srv.Serve(l); err != nil {
-						log.Printf("Server error: %v", err)
-						serverErrors <- err
-					}
-				}(listener)
-			}
-		}()
-	} else {
-		log.Println("Synurang - Running in FFI-only mode")
+// start starts the engine with cfg and reports its status.
+func (e *ffiEngine) start(cfg *service.Config) (service.Status, error) {
+	st, err := e.Start(cfg)
+	if err != nil {
+		log.Printf("Synurang - %v", err)
+		return st, err
 	}
 
-	return 0
+	// Serve the same services through the Synurang ABI (Synurang_Invoke),
+	// which has a single registry per process. The interceptors authorize
+	// and track those calls as they do the other FFI calls.
+	if e == defaultEngine {
+		core := e.Core()
+		e.callbackMu.Lock()
+		defer e.callbackMu.Unlock()
+		for _, desc := range engineServices(core) {
+			plugin.RegisterGRPCServiceWithInterceptors(desc, core, e.abiUnaryInterceptor, e.abiStreamInterceptor)
+			e.abiServices = append(e.abiServices, desc.ServiceName)
+		}
+	}
+	return st, nil
 }
 
 //export StopGrpcServer
 func StopGrpcServer() C.int {
 	log.Printf("Synurang - StopGrpcServer called (goroutines: %d)", goruntime.NumGoroutine())
-	if srv != nil {
-		implMu.Lock()
-		if impl != nil {
-			impl.Close()
-			impl = nil
-		}
-		implMu.Unlock()
-
-		srv.Stop()
+	defaultEngine.stop(defaultEngine.ShutdownTimeout())
+	log.Printf("Synurang - StopGrpcServer finished (goroutines: %d)", goruntime.NumGoroutine())
+	return 0
+}
 
-		for _, listener := range listeners {
-			listener.Close()
-			if addr := listener.Addr(); addr.Network() == "unix" {
-				os.Remove(addr.String())
-			}
-		}
-		srv = nil
-		listeners = nil
+// StopGrpcServerWithTimeout stops the default engine gracefully: new calls
+// are rejected and those in progress may finish for timeoutMs milliseconds
+// (0 terminates them at once). Returns the serialized core.v1.ShutdownReport
+// of what had to be terminated.
+//
+//export StopGrpcServerWithTimeout
+func StopGrpcServerWithTimeout(timeoutMs C.longlong) C.FfiData {
+	report := defaultEngine.stop(time.Duration(timeoutMs) * time.Millisecond)
+	return protoData(report.Proto())
+}
 
+// stop stops the engine if it is running, letting calls in progress finish
+// for timeout.
+func (e *ffiEngine) stop(timeout time.Duration) service.ShutdownReport {
+	e.callbackMu.Lock()
+	for _, name := range e.abiServices {
+		plugin.UnregisterService(name)
+	}
+	e.abiServices = nil
+	e.callbackMu.Unlock()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	report, ok := e.Shutdown(ctx)
+	if ok {
+		// Unblock the Dart requests the shutdown gave up on
+		e.requests.CleanupPending()
 		goruntime.GC()
 		debug.FreeOSMemory()
 	}
-	log.Printf("Synurang - StopGrpcServer finished (goroutines: %d)", goruntime.NumGoroutine())
-	return 0
+	return report
+}
+
+// abiUnaryInterceptor is FfiUnaryInterceptor for calls through the Synurang
+// ABI, whose metadata and timeout come in their call header (see
+// plugin.CallHeader).
+func (e *ffiEngine) abiUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	call, err := service.ParseCallHeader(plugin.CallHeader(ctx))
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := call.ContextFrom(ctx)
+	defer cancel()
+	return e.FfiUnaryInterceptor(ctx, req, info, handler)
+}
+
+// abiStreamInterceptor is abiUnaryInterceptor for streaming calls.
+func (e *ffiEngine) abiStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	call, err := service.ParseCallHeader(plugin.CallHeader(ss.Context()))
+	if err != nil {
+		return err
+	}
+	ctx, cancel := call.ContextFrom(ss.Context())
+	defer cancel()
+	return e.FfiStreamInterceptor(srv, headerStream{ServerStream: ss, ctx: ctx}, info, handler)
+}
+
+// headerStream is a grpc.ServerStream with the context of its call header.
+type headerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s headerStream) Context() context.Context { return s.ctx }
+
+// engineServices lists the services the engine serves through the Synurang ABI.
+func engineServices(s *service.CoreServiceServer) []*grpc.ServiceDesc {
+	descs := []*grpc.ServiceDesc{&pb.HealthService_ServiceDesc}
+	if s.CacheServiceServer != nil {
+		descs = append(descs, &pb.CacheService_ServiceDesc)
+	}
+	return descs
 }
 
 // =============================================================================
-// FFI Exports - Backend Invocation (Dart -> Go)
+// FFI Exports - Engines
+// Each engine is isolated: its own server, listeners, cache, stream sessions
+// and callbacks. The exports without an engine handle act on handle 0.
 // =============================================================================
 
-//export InvokeBackend
-func InvokeBackend(method *C.char, data unsafe.Pointer, dataLen C.longlong) C.FfiData {
-	implMu.RLock()
-	localImpl := impl
-	implMu.RUnlock()
+// CreateEngine creates a stopped engine and returns its handle (> 0).
+//
+//export CreateEngine
+func CreateEngine() C.longlong {
+	e := &ffiEngine{Engine: service.NewEngine(), requests: service.NewRequestHandler(10 * time.Second)}
 
-	if localImpl == nil {
-		errStr := "Server implementation not initialized"
-		cErr := C.CBytes([]byte(errStr))
-		return C.FfiData{
-			data: cErr,
-			len:  C.longlong(-len(errStr)),
-		}
+	enginesMu.Lock()
+	nextEngineId++
+	handle := nextEngineId
+	engines[handle] = e
+	enginesMu.Unlock()
+
+	log.Printf("Synurang - Created engine %d", handle)
+	return C.longlong(handle)
+}
+
+// DestroyEngine stops the engine and releases its handle. Returns -1 for the
+// default engine (0) or an unknown handle.
+//
+//export DestroyEngine
+func DestroyEngine(engine C.longlong) C.int {
+	enginesMu.Lock()
+	e, ok := engines[int64(engine)]
+	if ok && e != defaultEngine {
+		delete(engines, int64(engine))
+	}
+	enginesMu.Unlock()
+	if !ok || e == defaultEngine {
+		return -1
 	}
 
-	goMethod := C.GoString(method)
-	goData := unsafe.Slice((*byte)(data), int(dataLen))
+	e.stop(e.ShutdownTimeout())
+	e.requests.CleanupPending()
+	log.Printf("Synurang - Destroyed engine %d", engine)
+	return 0
+}
 
-	// Zero-copy: InvokeFfi allocates C memory and serializes directly
-	cPtr, size, err := pb.InvokeFfi(localImpl, context.Background(), goMethod, goData)
+// StartEngine is StartGrpcServer for an engine. Returns -1 if the handle is
+// unknown or the engine is already running.
+//
+//export StartEngine
+func StartEngine(engine C.longlong, cArg C.struct_CoreArgument) C.int {
+	e := lookupEngine(engine)
+	if e == nil {
+		return -1
+	}
+	if _, err := e.start(coreConfig(cArg)); err != nil {
+		return -1
+	}
+	return 0
+}
 
-	if err != nil {
-		log.Printf("Invoke error: %v", err)
-		st, ok := status.FromError(err)
-		var pbErr *pb.Error
-		if ok {
-			for _, detail := range st.Details() {
-				if e, ok := detail.(*pb.Error); ok {
-					pbErr = e
-					break
-				}
-			}
-		}
-		if pbErr == nil {
-			pbErr = &pb.Error{
-				Message:  err.Error(),
-				GrpcCode: int32(st.Code()),
-			}
-		}
-		errBytes, _ := proto.Marshal(pbErr)
-		cErr := C.CBytes(errBytes)
-		return C.FfiData{
-			data: cErr,
-			len:  C.longlong(-len(errBytes)),
-		}
+// StartEngineWithStatus is StartGrpcServerWithStatus for an engine.
+//
+//export StartEngineWithStatus
+func StartEngineWithStatus(engine C.longlong, cArg C.struct_CoreArgument, failOnListenError C.int) C.FfiData {
+	e := lookupEngine(engine)
+	if e == nil {
+		return protoData(&pb.ServerStatus{Error: service.ErrorProto(errUnknownEngine(engine))})
 	}
+	cfg := coreConfig(cArg)
+	cfg.FailOnListenError = failOnListenError != 0
+	st, _ := e.start(cfg)
+	return protoData(st.Proto())
+}
 
-	return C.FfiData{
-		data: cPtr,
-		len:  C.longlong(size),
+// EngineGetServerStatus is GetServerStatus for an engine.
+//
+//export EngineGetServerStatus
+func EngineGetServerStatus(engine C.longlong) C.FfiData {
+	e := lookupEngine(engine)
+	if e == nil {
+		return protoData(&pb.ServerStatus{Error: service.ErrorProto(errUnknownEngine(engine))})
 	}
+	return protoData(e.Status().Proto())
 }
 
-// parseMetadata parses "key=value\n" metadata format and extracts timeout if present.
-// Returns metadata map and timeout in milliseconds (0 = no timeout).
-func parseMetadata(metaData []byte) (map[string]string, int64) {
-	if len(metaData) == 0 {
-		return nil, 0
+// EngineGetHealth is GetHealth for an engine.
+//
+//export EngineGetHealth
+func EngineGetHealth(engine C.longlong) C.FfiData {
+	e := lookupEngine(engine)
+	if e == nil {
+		return protoData(&pb.HealthReport{Status: "NOT_SERVING"})
 	}
-	meta := make(map[string]string)
-	var timeoutMs int64
-	for _, line := range splitLines(metaData) {
-		if len(line) == 0 {
-			continue
-		}
-		idx := indexOf(line, '=')
-		if idx > 0 {
-			key := string(line[:idx])
-			value := string(line[idx+1:])
-			if key == "__timeout_ms" {
-				fmt.Sscanf(value, "%d", &timeoutMs)
-			} else {
-				meta[key] = value
-			}
-		}
+	ctx, cancel := context.WithTimeout(context.Background(), service.DefaultHealthInterval)
+	defer cancel()
+	return protoData(e.Health(ctx).Proto())
+}
+
+// EngineListServices is ListServices for an engine.
+//
+//export EngineListServices
+func EngineListServices(engine C.longlong) C.FfiData {
+	e := lookupEngine(engine)
+	if e == nil {
+		return C.FfiData{data: nil, len: 0}
 	}
-	return meta, timeoutMs
+	return protoData(e.ListServices())
 }
 
-func splitLines(data []byte) [][]byte {
-	var lines [][]byte
-	start := 0
-	for i, b := range data {
-		if b == '\n' {
-			lines = append(lines, data[start:i])
-			start = i + 1
-		}
+// EngineDescribeMethod is DescribeMethod for an engine.
+//
+//export EngineDescribeMethod
+func EngineDescribeMethod(engine C.longlong, method *C.char) C.FfiData {
+	e := lookupEngine(engine)
+	if e == nil || method == nil {
+		return C.FfiData{data: nil, len: 0}
 	}
-	if start < len(data) {
-		lines = append(lines, data[start:])
+	desc, err := e.DescribeMethod(C.GoString(method))
+	if err != nil {
+		return C.FfiData{data: nil, len: 0}
 	}
-	return lines
+	return protoData(desc)
 }
 
-func indexOf(data []byte, ch byte) int {
-	for i, b := range data {
-		if b == ch {
-			return i
-		}
+// EngineRegisterServeErrorCallback is RegisterServeErrorCallback for an engine.
+//
+//export EngineRegisterServeErrorCallback
+func EngineRegisterServeErrorCallback(engine C.longlong, callback C.ServeErrorCallback) {
+	e := lookupEngine(engine)
+	if e == nil {
+		return
+	}
+	if callback == nil {
+		e.SetServeErrorCallback(nil)
+		return
 	}
-	return -1
+	e.SetServeErrorCallback(func(ls service.ListenerStatus) {
+		out := protoData(ls.Proto())
+		C.invoke_serve_error_callback(callback, out.data, out.len)
+	})
+}
+
+// StopEngine is StopGrpcServer for an engine; the handle stays valid.
+//
+//export StopEngine
+func StopEngine(engine C.longlong) C.int {
+	e := lookupEngine(engine)
+	if e == nil {
+		return -1
+	}
+	e.stop(e.ShutdownTimeout())
+	return 0
+}
+
+// StopEngineWithTimeout is StopGrpcServerWithTimeout for an engine.
+//
+//export StopEngineWithTimeout
+func StopEngineWithTimeout(engine C.longlong, timeoutMs C.longlong) C.FfiData {
+	e := lookupEngine(engine)
+	if e == nil {
+		return protoData(&pb.ShutdownReport{})
+	}
+	report := e.stop(time.Duration(timeoutMs) * time.Millisecond)
+	return protoData(report.Proto())
+}
+
+// =============================================================================
+// FFI Exports - Backend Invocation (Dart -> Go)
+// =============================================================================
+
+//export InvokeBackend
+func InvokeBackend(method *C.char, data unsafe.Pointer, dataLen C.longlong) C.FfiData {
+	return invokeBackend(method, data, dataLen, service.FfiCall{}, nil)
 }
 
+// InvokeBackendWithMeta is InvokeBackend with "key=value\n" request metadata.
+// Handlers see it as incoming gRPC metadata; the reserved __timeout_ms key
+// sets the deadline. When a token is configured, the metadata must carry
+// "authorization=Bearer <token>" as it would over gRPC.
+//
+// The text format cannot carry binary values; new callers should use
+// InvokeBackendWithHeader.
+//
 //export InvokeBackendWithMeta
 func InvokeBackendWithMeta(method *C.char, data unsafe.Pointer, dataLen C.longlong,
 	metaData unsafe.Pointer, metaLen C.longlong) C.FfiData {
+	return invokeBackend(method, data, dataLen, textCall(metaData, metaLen), nil)
+}
 
-	implMu.RLock()
-	localImpl := impl
-	implMu.RUnlock()
+// InvokeBackendWithHeader is InvokeBackend with a serialized core.v1.CallHeader
+// carrying metadata (including -bin keys), deadline, call ID and trace context.
+//
+//export InvokeBackendWithHeader
+func InvokeBackendWithHeader(method *C.char, data unsafe.Pointer, dataLen C.longlong,
+	header unsafe.Pointer, headerLen C.longlong) C.FfiData {
+	call, err := service.ParseCallHeader(cBytes(header, headerLen))
+	return invokeBackend(method, data, dataLen, call, err)
+}
+
+// InvokeBackendCall is InvokeBackendWithHeader returning a serialized
+// core.v1.CallResponse: the response or error together with the header and
+// trailer metadata the handler set with grpc.SetHeader and grpc.SetTrailer.
+// Its length is never negative; failures are reported in the envelope.
+//
+//export InvokeBackendCall
+func InvokeBackendCall(method *C.char, data unsafe.Pointer, dataLen C.longlong,
+	header unsafe.Pointer, headerLen C.longlong) C.FfiData {
+	return EngineInvokeBackendCall(0, method, data, dataLen, header, headerLen)
+}
 
+// EngineInvokeBackendCall is InvokeBackendCall on an engine.
+//
+//export EngineInvokeBackendCall
+func EngineInvokeBackendCall(engine C.longlong, method *C.char, data unsafe.Pointer, dataLen C.longlong,
+	header unsafe.Pointer, headerLen C.longlong) C.FfiData {
+	e := lookupEngine(engine)
+	if e == nil {
+		return protoData(service.NewCallResponse(nil, errUnknownEngine(engine), nil))
+	}
+	call, err := service.ParseCallHeader(cBytes(header, headerLen))
+	ctx, cancel := call.Context()
+	defer cancel()
+	return protoData(e.invokeBackendCall(ctx, C.GoString(method), cBytes(data, dataLen), err))
+}
+
+// InvokeBackendAsync starts InvokeBackendCall on its own goroutine and returns
+// immediately, so a slow handler holds no caller thread. The serialized
+// core.v1.CallResponse is delivered to the callback registered with
+// RegisterCallCompleteCallback; the callee owns the data and frees it with
+// FreeFfiData. callId is chosen by the caller and overrides the header's
+// call_id. Returns 0 if the call was accepted (the callback then runs exactly
+// once), 1 if no callback is registered or callId is already in flight.
+//
+//export InvokeBackendAsync
+func InvokeBackendAsync(callId C.longlong, method *C.char, data unsafe.Pointer, dataLen C.longlong,
+	header unsafe.Pointer, headerLen C.longlong) C.int {
+	return EngineInvokeBackendAsync(0, callId, method, data, dataLen, header, headerLen)
+}
+
+// EngineInvokeBackendAsync is InvokeBackendAsync on an engine; call IDs are
+// scoped to the engine. Also returns 1 if the handle is unknown.
+//
+//export EngineInvokeBackendAsync
+func EngineInvokeBackendAsync(engine C.longlong, callId C.longlong, method *C.char, data unsafe.Pointer, dataLen C.longlong,
+	header unsafe.Pointer, headerLen C.longlong) C.int {
+	e := lookupEngine(engine)
+	if e == nil {
+		return 1
+	}
+	e.callbackMu.RLock()
+	registered := e.callCompleteCallback != nil
+	e.callbackMu.RUnlock()
+	if !registered {
+		return 1
+	}
+
+	// The caller frees its buffers when we return
+	goData, err := goBytes(data, dataLen)
+	if err != nil {
+		log.Printf("InvokeBackendAsync rejected: %v", err)
+		return 1
+	}
+
+	id := int64(callId)
+	call, headerErr := service.ParseCallHeader(cBytes(header, headerLen))
+	call.CallID = uint64(id)
+	ctx, cancel := call.Context()
+	if !e.Calls.Add(id, cancel) {
+		cancel()
+		return 1
+	}
+
+	goMethod := C.GoString(method)
+	go func() {
+		defer e.Calls.Done(id)
+		out := protoData(e.invokeBackendCall(ctx, goMethod, goData, headerErr))
+
+		e.callbackMu.RLock()
+		cb := e.callCompleteCallback
+		e.callbackMu.RUnlock()
+		if cb == nil {
+			FreeFfiData(out.data)
+			return
+		}
+		C.invoke_call_complete_callback(cb, callId, out.data, out.len)
+	}()
+	return 0
+}
+
+// CancelBackendCall cancels the context of a call started with
+// InvokeBackendAsync. Its callback still runs, typically with a Canceled
+// error. Unknown or finished call IDs are ignored.
+//
+//export CancelBackendCall
+func CancelBackendCall(callId C.longlong) {
+	EngineCancelBackendCall(0, callId)
+}
+
+//export EngineCancelBackendCall
+func EngineCancelBackendCall(engine C.longlong, callId C.longlong) {
+	if e := lookupEngine(engine); e != nil {
+		e.Calls.Cancel(int64(callId))
+	}
+}
+
+// RegisterCallCompleteCallback sets the callback receiving the results of
+// InvokeBackendAsync.
+//
+//export RegisterCallCompleteCallback
+func RegisterCallCompleteCallback(callback C.CallCompleteCallback) {
+	EngineRegisterCallCompleteCallback(0, callback)
+}
+
+//export EngineRegisterCallCompleteCallback
+func EngineRegisterCallCompleteCallback(engine C.longlong, callback C.CallCompleteCallback) {
+	if e := lookupEngine(engine); e != nil {
+		e.callbackMu.Lock()
+		e.callCompleteCallback = callback
+		e.callbackMu.Unlock()
+	}
+}
+
+// errUnknownEngine is the error of a call on a handle CreateEngine never
+// returned or DestroyEngine released.
+func errUnknownEngine(engine C.longlong) error {
+	return status.Errorf(codes.NotFound, "engine %d not found", int64(engine))
+}
+
+// invokeBackend runs a unary call on the default engine; headerErr is a
+// failure to decode its header.
+func invokeBackend(method *C.char, data unsafe.Pointer, dataLen C.longlong, call service.FfiCall, headerErr error) C.FfiData {
+	goMethod := C.GoString(method)
+	ctx, cancel := call.Context()
+	defer cancel()
+	ctx, end, err := defaultEngine.BeginCall(ctx, goMethod)
+	if err != nil {
+		return ffiError(err)
+	}
+	defer end()
+
+	localImpl := defaultEngine.Core()
 	if localImpl == nil {
 		errStr := "Server implementation not initialized"
 		cErr := C.CBytes([]byte(errStr))
 		return C.FfiData{data: cErr, len: C.longlong(-len(errStr))}
 	}
+	if headerErr != nil {
+		return ffiError(headerErr)
+	}
 
-	goMethod := C.GoString(method)
 	goData := unsafe.Slice((*byte)(data), int(dataLen))
 
-	// Parse metadata and timeout
-	var goMeta []byte
-	if metaLen > 0 {
-		goMeta = unsafe.Slice((*byte)(metaData), int(metaLen))
-	}
-	metadata, timeoutMs := parseMetadata(goMeta)
+	// Handler-set metadata is dropped here; see InvokeBackendCall
+	ctx, _ = service.NewFfiTransportStream(ctx, goMethod)
 
-	// Create context with optional timeout
-	ctx := context.Background()
-	if timeoutMs > 0 {
-		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, time.Duration(timeoutMs)*time.Millisecond)
-		defer cancel()
+	ctx, err = localImpl.AuthorizeCall(ctx, goMethod)
+	if err != nil {
+		return ffiError(err)
 	}
 
-	// Metadata available in 'metadata' map for handlers that need it
-	_ = metadata
-
 	// Zero-copy: InvokeFfi allocates C memory and serializes directly
 	cPtr, size, err := pb.InvokeFfi(localImpl, ctx, goMethod, goData)
-
 	if err != nil {
-		st, ok := status.FromError(err)
-		var pbErr *pb.Error
-		if ok {
-			for _, detail := range st.Details() {
-				if e, ok := detail.(*pb.Error); ok {
-					pbErr = e
-					break
-				}
-			}
-		}
-		if pbErr == nil {
-			pbErr = &pb.Error{
-				Message:  err.Error(),
-				GrpcCode: int32(st.Code()),
-			}
-		}
-		errBytes, _ := proto.Marshal(pbErr)
-		cErr := C.CBytes(errBytes)
-		return C.FfiData{data: cErr, len: C.longlong(-len(errBytes))}
+		log.Printf("Invoke error: %v", err)
+		return ffiError(err)
 	}
 
 	return C.FfiData{data: cPtr, len: C.longlong(size)}
 }
 
+// invokeBackendCall runs a unary call for InvokeBackendCall and
+// InvokeBackendAsync; headerErr is a failure to decode its header.
+func (e *ffiEngine) invokeBackendCall(ctx context.Context, method string, data []byte, headerErr error) *pb.CallResponse {
+	ctx, end, err := e.BeginCall(ctx, method)
+	if err != nil {
+		return service.NewCallResponse(nil, err, nil)
+	}
+	defer end()
+
+	localImpl := e.Core()
+	if localImpl == nil {
+		return service.NewCallResponse(nil, status.Error(codes.Unavailable, "Server implementation not initialized"), nil)
+	}
+	if headerErr != nil {
+		return service.NewCallResponse(nil, headerErr, nil)
+	}
+
+	ctx, stream := service.NewFfiTransportStream(ctx, method)
+
+	ctx, err = localImpl.AuthorizeCall(ctx, method)
+	var resp []byte
+	if err == nil {
+		resp, err = pb.Invoke(localImpl, ctx, method, data)
+	}
+	if err != nil {
+		log.Printf("Invoke error: %v", err)
+	}
+	return service.NewCallResponse(resp, err, stream)
+}
+
+// protoData serializes m into C memory for the caller to free.
+func protoData(m proto.Message) C.FfiData {
+	out, _ := proto.Marshal(m)
+	if len(out) == 0 {
+		return C.FfiData{}
+	}
+	return C.FfiData{data: C.CBytes(out), len: C.longlong(len(out))}
+}
+
+// textCall decodes the legacy "key=value\n" metadata of the *WithMeta exports.
+func textCall(metaData unsafe.Pointer, metaLen C.longlong) service.FfiCall {
+	md, timeoutMs := service.ParseFfiMetadata(cBytes(metaData, metaLen))
+	return service.FfiCall{Metadata: md, TimeoutMs: timeoutMs}
+}
+
+// ffiError encodes err as the serialized core.v1.Error of a failed
+// InvokeBackend call (negative length).
+func ffiError(err error) C.FfiData {
+	errBytes, _ := proto.Marshal(service.ErrorProto(err))
+	cErr := C.CBytes(errBytes)
+	return C.FfiData{data: cErr, len: C.longlong(-len(errBytes))}
+}
+
+// cBytes views a caller-owned buffer for the duration of an FFI call.
+func cBytes(data unsafe.Pointer, n C.longlong) []byte {
+	if data == nil || n <= 0 {
+		return nil
+	}
+	return unsafe.Slice((*byte)(data), int(n))
+}
+
+// goBytes copies the n bytes at data into Go memory, failing rather than
+// truncating if n does not fit the C int of C.GoBytes.
+func goBytes(data unsafe.Pointer, n C.longlong) ([]byte, error) {
+	if n < 0 || n > math.MaxInt32 {
+		return nil, fmt.Errorf("data length %d out of range", int64(n))
+	}
+	return C.GoBytes(data, C.int(n)), nil
+}
+
 //export FreeFfiData
 func FreeFfiData(data unsafe.Pointer) {
 	if data != nil {
@@ -428,18 +919,36 @@ func FreeFfiData(data unsafe.Pointer) {
 
 //export RegisterDartCallback
 func RegisterDartCallback(callback C.InvokeDartCallback) {
-	log.Printf("RegisterDartCallback called")
+	EngineRegisterDartCallback(0, callback)
+}
+
+// EngineRegisterDartCallback is RegisterDartCallback on an engine. Its
+// requests are answered with EngineSendFfiResponse.
+//
+//export EngineRegisterDartCallback
+func EngineRegisterDartCallback(engine C.longlong, callback C.InvokeDartCallback) {
+	log.Printf("RegisterDartCallback called (engine %d)", engine)
+	e := lookupEngine(engine)
+	if e == nil {
+		return
+	}
 
 	// Cleanup pending requests from previous callback (hot-reload support)
-	service.DefaultRequestHandler.CleanupPending()
+	e.requests.CleanupPending()
+
+	e.callbackMu.Lock()
+	e.dartCallback = callback
+	e.callbackMu.Unlock()
 
-	dartCallback = callback
-	service.DartCallback = func(method string, data []byte) ([]byte, error) {
-		if dartCallback == nil {
+	e.SetDartCallback(func(method string, data []byte) ([]byte, error) {
+		e.callbackMu.RLock()
+		cb := e.dartCallback
+		e.callbackMu.RUnlock()
+		if cb == nil {
 			return nil, fmt.Errorf("dart callback is nil")
 		}
 
-		requestId, ch := service.DefaultRequestHandler.CreateRequest()
+		requestId, ch := e.requests.CreateRequest()
 
 		cMethod := C.CString(method)
 		defer C.free(unsafe.Pointer(cMethod))
@@ -447,16 +956,29 @@ func RegisterDartCallback(callback C.InvokeDartCallback) {
 		cData := C.CBytes(data)
 		defer C.free(cData)
 
-		C.invoke_dart_callback(dartCallback, C.longlong(requestId), cMethod, cData, C.longlong(len(data)))
+		C.invoke_dart_callback(cb, C.longlong(requestId), cMethod, cData, C.longlong(len(data)))
 
-		return service.DefaultRequestHandler.WaitForResponse(requestId, ch)
-	}
+		return e.requests.WaitForResponse(requestId, ch)
+	})
 }
 
 //export SendFfiResponse
 func SendFfiResponse(requestId C.longlong, data unsafe.Pointer, dataLen C.longlong) {
-	goData := C.GoBytes(data, C.int(dataLen))
-	service.DefaultRequestHandler.HandleResponse(int64(requestId), goData)
+	EngineSendFfiResponse(0, requestId, data, dataLen)
+}
+
+//export EngineSendFfiResponse
+func EngineSendFfiResponse(engine C.longlong, requestId C.longlong, data unsafe.Pointer, dataLen C.longlong) {
+	e := lookupEngine(engine)
+	if e == nil {
+		return
+	}
+	goData, err := goBytes(data, dataLen)
+	if err != nil {
+		e.requests.FailResponse(int64(requestId), err)
+		return
+	}
+	e.requests.HandleResponse(int64(requestId), goData)
 }
 
 // =============================================================================
@@ -465,12 +987,29 @@ func SendFfiResponse(requestId C.longlong, data unsafe.Pointer, dataLen C.longlo
 
 //export RegisterStreamCallback
 func RegisterStreamCallback(callback C.StreamCallback) {
-	log.Println("RegisterStreamCallback called")
-	streamCallback = callback
+	EngineRegisterStreamCallback(0, callback)
+}
+
+// EngineRegisterStreamCallback is RegisterStreamCallback on an engine. Stream
+// IDs are unique across engines, so engines may share one callback.
+//
+//export EngineRegisterStreamCallback
+func EngineRegisterStreamCallback(engine C.longlong, callback C.StreamCallback) {
+	log.Printf("RegisterStreamCallback called (engine %d)", engine)
+	e := lookupEngine(engine)
+	if e == nil {
+		return
+	}
+	e.callbackMu.Lock()
+	e.streamCallback = callback
+	e.callbackMu.Unlock()
 
 	// Register the regular stream callback (1 copy at FFI boundary)
-	service.SetStreamCallback(func(streamId int64, msgType byte, data []byte) {
-		if streamCallback == nil {
+	e.SetStreamCallback(func(streamId int64, msgType byte, data []byte) {
+		e.callbackMu.RLock()
+		cb := e.streamCallback
+		e.callbackMu.RUnlock()
+		if cb == nil {
 			return
 		}
 		var cData unsafe.Pointer
@@ -481,52 +1020,187 @@ func RegisterStreamCallback(callback C.StreamCallback) {
 			// Do NOT free cData here! Ownership is transferred to Dart.
 			// Dart must free it using calloc.free() after processing.
 		}
-		C.invoke_stream_callback(streamCallback, C.longlong(streamId), C.char(msgType), cData, cLen)
+		C.invoke_stream_callback(cb, C.longlong(streamId), C.char(msgType), cData, cLen)
 	})
 
 	// Register the zero-copy stream callback (receives C pointer directly)
-	service.SetStreamCallbackFfi(func(streamId int64, msgType byte, data unsafe.Pointer, len int64) {
-		if streamCallback == nil {
+	e.SetStreamCallbackFfi(func(streamId int64, msgType byte, data unsafe.Pointer, len int64) {
+		e.callbackMu.RLock()
+		cb := e.streamCallback
+		e.callbackMu.RUnlock()
+		if cb == nil {
 			return
 		}
 		// Data is already in C memory, pass directly - no copy!
-		C.invoke_stream_callback(streamCallback, C.longlong(streamId), C.char(msgType), data, C.longlong(len))
+		C.invoke_stream_callback(cb, C.longlong(streamId), C.char(msgType), data, C.longlong(len))
 	})
 }
 
 //export InvokeBackendServerStream
 func InvokeBackendServerStream(method *C.char, data unsafe.Pointer, dataLen C.longlong) C.longlong {
+	return defaultEngine.startServerStream(method, data, dataLen, service.FfiCall{}, nil)
+}
+
+//export InvokeBackendClientStream
+func InvokeBackendClientStream(method *C.char) C.longlong {
+	return defaultEngine.startClientStream(method, service.FfiCall{}, nil)
+}
+
+//export InvokeBackendBidiStream
+func InvokeBackendBidiStream(method *C.char) C.longlong {
+	return defaultEngine.startBidiStream(method, service.FfiCall{}, nil)
+}
+
+// The stream entry points take the same metadata as InvokeBackendWithMeta and
+// InvokeBackendWithHeader; handlers see it as StreamSession.Metadata. They
+// return -1 if the method is unknown, the header is malformed or the token
+// check fails.
+
+//export InvokeBackendServerStreamWithMeta
+func InvokeBackendServerStreamWithMeta(method *C.char, data unsafe.Pointer, dataLen C.longlong,
+	metaData unsafe.Pointer, metaLen C.longlong) C.longlong {
+	return defaultEngine.startServerStream(method, data, dataLen, textCall(metaData, metaLen), nil)
+}
+
+//export InvokeBackendClientStreamWithMeta
+func InvokeBackendClientStreamWithMeta(method *C.char, metaData unsafe.Pointer, metaLen C.longlong) C.longlong {
+	return defaultEngine.startClientStream(method, textCall(metaData, metaLen), nil)
+}
+
+//export InvokeBackendBidiStreamWithMeta
+func InvokeBackendBidiStreamWithMeta(method *C.char, metaData unsafe.Pointer, metaLen C.longlong) C.longlong {
+	return defaultEngine.startBidiStream(method, textCall(metaData, metaLen), nil)
+}
+
+//export InvokeBackendServerStreamWithHeader
+func InvokeBackendServerStreamWithHeader(method *C.char, data unsafe.Pointer, dataLen C.longlong,
+	header unsafe.Pointer, headerLen C.longlong) C.longlong {
+	return EngineInvokeBackendServerStream(0, method, data, dataLen, header, headerLen)
+}
+
+//export InvokeBackendClientStreamWithHeader
+func InvokeBackendClientStreamWithHeader(method *C.char, header unsafe.Pointer, headerLen C.longlong) C.longlong {
+	return EngineInvokeBackendClientStream(0, method, header, headerLen)
+}
+
+//export InvokeBackendBidiStreamWithHeader
+func InvokeBackendBidiStreamWithHeader(method *C.char, header unsafe.Pointer, headerLen C.longlong) C.longlong {
+	return EngineInvokeBackendBidiStream(0, method, header, headerLen)
+}
+
+// The Engine*Stream exports are the *WithHeader stream entry points on an
+// engine; they also return -1 if the handle is unknown.
+
+//export EngineInvokeBackendServerStream
+func EngineInvokeBackendServerStream(engine C.longlong, method *C.char, data unsafe.Pointer, dataLen C.longlong,
+	header unsafe.Pointer, headerLen C.longlong) C.longlong {
+	e := lookupEngine(engine)
+	if e == nil {
+		return -1
+	}
+	call, err := service.ParseCallHeader(cBytes(header, headerLen))
+	return e.startServerStream(method, data, dataLen, call, err)
+}
+
+//export EngineInvokeBackendClientStream
+func EngineInvokeBackendClientStream(engine C.longlong, method *C.char, header unsafe.Pointer, headerLen C.longlong) C.longlong {
+	e := lookupEngine(engine)
+	if e == nil {
+		return -1
+	}
+	call, err := service.ParseCallHeader(cBytes(header, headerLen))
+	return e.startClientStream(method, call, err)
+}
+
+//export EngineInvokeBackendBidiStream
+func EngineInvokeBackendBidiStream(engine C.longlong, method *C.char, header unsafe.Pointer, headerLen C.longlong) C.longlong {
+	e := lookupEngine(engine)
+	if e == nil {
+		return -1
+	}
+	call, err := service.ParseCallHeader(cBytes(header, headerLen))
+	return e.startBidiStream(method, call, err)
+}
+
+func (e *ffiEngine) startServerStream(method *C.char, data unsafe.Pointer, dataLen C.longlong, call service.FfiCall, headerErr error) C.longlong {
 	goMethod := C.GoString(method)
+	if !e.authorizeStream(goMethod, call, headerErr) {
+		return -1
+	}
 	var goData []byte
-	if dataLen > 0 {
-		goData = C.GoBytes(data, C.int(dataLen))
+	if dataLen != 0 {
+		var err error
+		if goData, err = goBytes(data, dataLen); err != nil {
+			log.Printf("Stream %s rejected: %v", goMethod, err)
+			return -1
+		}
 	}
 
-	return C.longlong(service.HandleServerStream(goMethod, goData))
+	return C.longlong(e.HandleServerStreamWithMeta(goMethod, goData, call.Metadata))
 }
 
-//export InvokeBackendClientStream
-func InvokeBackendClientStream(method *C.char) C.longlong {
+func (e *ffiEngine) startClientStream(method *C.char, call service.FfiCall, headerErr error) C.longlong {
 	goMethod := C.GoString(method)
-	return C.longlong(service.HandleClientStream(goMethod))
+	if !e.authorizeStream(goMethod, call, headerErr) {
+		return -1
+	}
+	return C.longlong(e.HandleClientStreamWithMeta(goMethod, call.Metadata))
 }
 
-//export InvokeBackendBidiStream
-func InvokeBackendBidiStream(method *C.char) C.longlong {
+func (e *ffiEngine) startBidiStream(method *C.char, call service.FfiCall, headerErr error) C.longlong {
 	goMethod := C.GoString(method)
-	return C.longlong(service.HandleBidiStream(goMethod))
+	if !e.authorizeStream(goMethod, call, headerErr) {
+		return -1
+	}
+	return C.longlong(e.HandleBidiStreamWithMeta(goMethod, call.Metadata))
+}
+
+// authorizeStream rejects a stream whose header failed to decode or whose
+// metadata fails the token check or the policy.
+func (e *ffiEngine) authorizeStream(method string, call service.FfiCall, headerErr error) bool {
+	if headerErr != nil {
+		log.Printf("Stream %s rejected: %v", method, headerErr)
+		return false
+	}
+
+	localImpl := e.Core()
+	if localImpl == nil {
+		return true // no server, so no token to check
+	}
+
+	ctx, cancel := service.FfiContext(call.Metadata, 0)
+	defer cancel()
+	if _, err := localImpl.AuthorizeCall(ctx, method); err != nil {
+		log.Printf("Stream %s rejected: %v", method, err)
+		return false
+	}
+	return true
 }
 
 //export SendStreamData
 func SendStreamData(streamId C.longlong, data unsafe.Pointer, dataLen C.longlong) C.int {
+	return EngineSendStreamData(0, streamId, data, dataLen)
+}
+
+//export EngineSendStreamData
+func EngineSendStreamData(engine C.longlong, streamId C.longlong, data unsafe.Pointer, dataLen C.longlong) C.int {
+	e := lookupEngine(engine)
+	if e == nil {
+		return -1
+	}
+
 	// OPTION 1: Safe (current) - copies data into Go heap
-	goData := C.GoBytes(data, C.int(dataLen))
+	goData, err := goBytes(data, dataLen)
+	if err != nil {
+		log.Printf("SendStreamData error: %v", err)
+		return -1
+	}
 
 	// OPTION 2: Zero-copy - use unsafe.Slice to view Dart's memory directly
 	// Note: Only safe if the data is not accessed after this function returns
 	// goData := unsafe.Slice((*byte)(data), int(dataLen))
 
-	err := service.SendToStream(int64(streamId), goData)
+	err = e.SendToStream(int64(streamId), goData)
 	if err != nil {
 		log.Printf("SendStreamData error: %v", err)
 		return -1
@@ -536,38 +1210,63 @@ func SendStreamData(streamId C.longlong, data unsafe.Pointer, dataLen C.longlong
 
 //export CloseStream
 func CloseStream(streamId C.longlong) {
-	service.CloseStreamSession(int64(streamId))
+	EngineCloseStream(0, streamId)
+}
+
+//export EngineCloseStream
+func EngineCloseStream(engine C.longlong, streamId C.longlong) {
+	if e := lookupEngine(engine); e != nil {
+		e.CloseStreamSession(int64(streamId))
+	}
 }
 
 //export CloseStreamInput
 func CloseStreamInput(streamId C.longlong) {
-	service.CloseStreamInput(int64(streamId))
+	EngineCloseStreamInput(0, streamId)
+}
+
+//export EngineCloseStreamInput
+func EngineCloseStreamInput(engine C.longlong, streamId C.longlong) {
+	if e := lookupEngine(engine); e != nil {
+		e.CloseStreamInput(int64(streamId))
+	}
 }
 
 //export StreamReady
 func StreamReady(streamId C.longlong) {
-	service.SignalStreamReady(int64(streamId))
+	EngineStreamReady(0, streamId)
+}
+
+//export EngineStreamReady
+func EngineStreamReady(engine C.longlong, streamId C.longlong) {
+	if e := lookupEngine(engine); e != nil {
+		e.SignalStreamReady(int64(streamId))
+	}
 }
 
 // =============================================================================
 // ZERO-COPY CACHE FFI FUNCTIONS
 // These functions bypass protobuf serialization for binary data.
+// The Engine* variants use the cache of an engine.
 // =============================================================================
 
 //export CacheGet
 func CacheGet(storeName *C.char, key *C.char) C.FfiData {
-	implMu.RLock()
-	currentImpl := impl
-	implMu.RUnlock()
+	return EngineCacheGet(0, storeName, key)
+}
 
-	if currentImpl == nil || currentImpl.CacheServiceServer == nil {
+//export EngineCacheGet
+func EngineCacheGet(engine C.longlong, storeName *C.char, key *C.char) C.FfiData {
+	currentImpl, ctx, end := engineCache(engine, "/core.v1.CacheService/Get")
+	if currentImpl == nil {
 		return C.FfiData{data: nil, len: 0}
 	}
+	defer end()
 
 	goStoreName := C.GoString(storeName)
 	goKey := C.GoString(key)
 
-	resp, err := currentImpl.CacheServiceServer.Get(context.Background(), &pb.GetCacheRequest{
+	resp, err := currentImpl.CacheServiceServer.Get(ctx, &pb.GetCacheRequest{
 		StoreName: goStoreName,
 		Key:       goKey,
 	})
@@ -585,13 +1284,16 @@ func CacheGet(storeName *C.char, key *C.char) C.FfiData {
 
 //export CachePut
 func CachePut(storeName *C.char, key *C.char, data unsafe.Pointer, dataLen C.longlong, ttlSeconds C.longlong) C.int {
-	implMu.RLock()
-	currentImpl := impl
-	implMu.RUnlock()
+	return EngineCachePut(0, storeName, key, data, dataLen, ttlSeconds)
+}
 
-	if currentImpl == nil || currentImpl.CacheServiceServer == nil {
+//export EngineCachePut
+func EngineCachePut(engine C.longlong, storeName *C.char, key *C.char, data unsafe.Pointer, dataLen C.longlong, ttlSeconds C.longlong) C.int {
+	currentImpl, ctx, end := engineCache(engine, "/core.v1.CacheService/Put")
+	if currentImpl == nil {
 		return -1
 	}
+	defer end()
 
 	goStoreName := C.GoString(storeName)
 	goKey := C.GoString(key)
@@ -600,7 +1302,7 @@ func CachePut(storeName *C.char, key *C.char, data unsafe.Pointer, dataLen C.lon
 	// the database write is complete, at which point we return and Dart frees the memory.
 	goData := unsafe.Slice((*byte)(data), int(dataLen))
 
-	_, err := currentImpl.CacheServiceServer.Put(context.Background(), &pb.PutCacheRequest{
+	_, err := currentImpl.CacheServiceServer.Put(ctx, &pb.PutCacheRequest{
 		StoreName:  goStoreName,
 		Key:        goKey,
 		Value:      goData,
@@ -616,18 +1318,21 @@ func CachePut(storeName *C.char, key *C.char, data unsafe.Pointer, dataLen C.lon
 
 //export CacheContains
 func CacheContains(storeName *C.char, key *C.char) C.int {
-	implMu.RLock()
-	currentImpl := impl
-	implMu.RUnlock()
+	return EngineCacheContains(0, storeName, key)
+}
 
-	if currentImpl == nil || currentImpl.CacheServiceServer == nil {
+//export EngineCacheContains
+func EngineCacheContains(engine C.longlong, storeName *C.char, key *C.char) C.int {
+	currentImpl, ctx, end := engineCache(engine, "/core.v1.CacheService/Contains")
+	if currentImpl == nil {
 		return -1
 	}
+	defer end()
 
 	goStoreName := C.GoString(storeName)
 	goKey := C.GoString(key)
 
-	resp, err := currentImpl.CacheServiceServer.Contains(context.Background(), &pb.GetCacheRequest{
+	resp, err := currentImpl.CacheServiceServer.Contains(ctx, &pb.GetCacheRequest{
 		StoreName: goStoreName,
 		Key:       goKey,
 	})
@@ -643,18 +1348,21 @@ func CacheContains(storeName *C.char, key *C.char) C.int {
 
 //export CacheDelete
 func CacheDelete(storeName *C.char, key *C.char) C.int {
-	implMu.RLock()
-	currentImpl := impl
-	implMu.RUnlock()
+	return EngineCacheDelete(0, storeName, key)
+}
 
-	if currentImpl == nil || currentImpl.CacheServiceServer == nil {
+//export EngineCacheDelete
+func EngineCacheDelete(engine C.longlong, storeName *C.char, key *C.char) C.int {
+	currentImpl, ctx, end := engineCache(engine, "/core.v1.CacheService/Delete")
+	if currentImpl == nil {
 		return -1
 	}
+	defer end()
 
 	goStoreName := C.GoString(storeName)
 	goKey := C.GoString(key)
 
-	_, err := currentImpl.CacheServiceServer.Delete(context.Background(), &pb.DeleteCacheRequest{
+	_, err := currentImpl.CacheServiceServer.Delete(ctx, &pb.DeleteCacheRequest{
 		StoreName: goStoreName,
 		Key:       goKey,
 	})
@@ -665,6 +1373,36 @@ func CacheDelete(storeName *C.char, key *C.char) C.int {
 	return 0
 }
 
+// engineCache returns the core services of a running engine with a cache
+// and the context of the cache call of method, or nil. The call is
+// authorized like any FFI call; it carries no metadata, so an engine that
+// requires a token rejects it. It is registered with the engine so a
+// graceful stop lets it finish; end must be called once it returns.
+func engineCache(engine C.longlong, method string) (*service.CoreServiceServer, context.Context, func()) {
+	e := lookupEngine(engine)
+	if e == nil {
+		return nil, nil, nil
+	}
+	ctx, cancel := service.FfiContext(nil, 0)
+	core, ctx, end, err := e.BeginFfiCall(ctx, method)
+	if err != nil {
+		cancel()
+		if status.Code(err) == codes.Unauthenticated {
+			log.Printf("Cache call %s rejected: %v", method, err)
+		}
+		return nil, nil, nil
+	}
+	if core.CacheServiceServer == nil {
+		end()
+		cancel()
+		return nil, nil, nil
+	}
+	return core, ctx, func() {
+		end()
+		cancel()
+	}
+}
+
 // =============================================================================
 // Standalone Main (for testing without FFI)
 // =============================================================================
@@ -692,10 +1430,32 @@ func main() {
 	cArg.viewSocketPath = C.CString(flutterSocket)
 	cArg.token = C.CString(token)
 
-	StartGrpcServer(*cArg)
+	cfg := coreConfig(*cArg)
+	cfg.TLSCertFile = flag.Lookup("tls-cert").Value.String()
+	cfg.TLSKeyFile = flag.Lookup("tls-key").Value.String()
+	cfg.TLSClientCAFile = flag.Lookup("tls-client-ca").Value.String()
+	cfg.TLSAutoCert = flag.Lookup("tls-auto").Value.String() == "true"
+	cfg.PolicyFile = flag.Lookup("policy").Value.String()
+	cfg.PublicReflection = flag.Lookup("public-reflection").Value.String() == "true"
+	var err error
+	if cfg.SocketMode, err = parseSocketMode(flag.Lookup("socket-mode").Value.String()); err != nil {
+		log.Fatal(err)
+	}
+	cfg.SocketOwner = flag.Lookup("socket-owner").Value.String()
+	if uids := flag.Lookup("socket-allow-uid").Value.String(); uids != "" {
+		allowed, err := parseUIDs(uids)
+		if err != nil {
+			log.Fatal(err)
+		}
+		cfg.SocketPeers = &service.PeerAllowlist{UIDs: allowed}
+	}
+	if _, err := defaultEngine.start(cfg); err != nil {
+		log.Printf("Failed to start: %v", err)
+		os.Exit(1)
+	}
 
 	select {
-	case err := <-serverErrors:
+	case err := <-defaultEngine.Errors():
 		log.Printf("Server error: %v", err)
 		os.Exit(1)
 	case sig := <-sigChan:
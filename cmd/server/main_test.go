@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unsafe"
+
+	pb "github.com/ivere27/synurang/pkg/api"
+	"github.com/ivere27/synurang/pkg/service"
+	"github.com/ivere27/synurang/pkg/synurang"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// testEngine is the handle of the engine startTestEngine registers.
+const testEngine = 1 << 40
+
+// startTestEngine starts an engine with cfg under handle testEngine.
+func startTestEngine(t *testing.T, cfg *service.Config) *ffiEngine {
+	t.Helper()
+	cfg.EngineSocketPath = filepath.Join(t.TempDir(), "engine.sock")
+	e := &ffiEngine{Engine: service.NewEngine(), requests: service.DefaultRequestHandler}
+	if _, err := e.Start(cfg); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	enginesMu.Lock()
+	engines[testEngine] = e
+	enginesMu.Unlock()
+	t.Cleanup(func() {
+		enginesMu.Lock()
+		delete(engines, testEngine)
+		enginesMu.Unlock()
+		e.Stop()
+	})
+	return e
+}
+
+func TestEngineCache_Policy(t *testing.T) {
+	startTestEngine(t, &service.Config{
+		CachePath:   t.TempDir(),
+		EnableCache: true,
+		Policy: &service.Policy{Rules: []service.PolicyRule{
+			{Methods: []string{"/core.v1.CacheService/Get"}, Deny: true},
+		}},
+	})
+
+	value := []byte("value")
+	if rc := EngineCachePut(testEngine, nil, nil, unsafe.Pointer(&value[0]), 5, 0); rc != 0 {
+		t.Fatalf("EngineCachePut = %d", rc)
+	}
+	if rc := EngineCacheContains(testEngine, nil, nil); rc != 1 {
+		t.Errorf("EngineCacheContains = %d, want 1", rc)
+	}
+	if got := EngineCacheGet(testEngine, nil, nil); got.data != nil || got.len != 0 {
+		t.Errorf("EngineCacheGet returned %d bytes despite a deny rule", got.len)
+	}
+}
+
+func TestEngineCache_Token(t *testing.T) {
+	startTestEngine(t, &service.Config{
+		CachePath:   t.TempDir(),
+		EnableCache: true,
+		Token:       "secret",
+	})
+	if rc := EngineCacheContains(testEngine, nil, nil); rc != -1 {
+		t.Errorf("EngineCacheContains without a token = %d, want -1", rc)
+	}
+}
+
+func TestEngineData_LengthOutOfRange(t *testing.T) {
+	e := startTestEngine(t, &service.Config{})
+	e.requests = service.NewRequestHandler(5 * time.Second)
+
+	requestId, ch := e.requests.CreateRequest()
+	if requestId != 1 {
+		t.Fatalf("requestId = %d, want 1", requestId)
+	}
+	EngineSendFfiResponse(testEngine, 1, nil, -1)
+	start := time.Now()
+	if _, err := e.requests.WaitForResponse(requestId, ch); err == nil || !strings.Contains(err.Error(), "out of range") {
+		t.Errorf("WaitForResponse = %v, want an out of range error", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("the request only failed after its timeout")
+	}
+
+	if rc := EngineSendStreamData(testEngine, 1, nil, 1<<31); rc != -1 {
+		t.Errorf("EngineSendStreamData = %d, want -1", rc)
+	}
+}
+
+func TestLoadEngine_Token(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the engine library")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not found")
+	}
+	lib := filepath.Join(t.TempDir(), "libsynurang.so")
+	build := exec.Command(goTool, "build", "-buildmode=c-shared", "-o", lib, ".")
+	if out, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("building the engine library: %v\n%s", err, out)
+	}
+
+	engine, err := synurang.LoadEngine(lib, synurang.EngineConfig{Token: "secret"})
+	if err != nil {
+		t.Fatalf("LoadEngine: %v", err)
+	}
+	defer engine.Close()
+	client := pb.NewHealthServiceClient(synurang.NewPluginClientConn(engine, ""))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := client.Ping(ctx, &emptypb.Empty{}); err == nil || !strings.Contains(err.Error(), "Unauthenticated") {
+		t.Errorf("Ping without a token = %v, want Unauthenticated", err)
+	}
+	for _, token := range []string{"wrong", "secret"} {
+		ctx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+		_, err := client.Ping(ctx, &emptypb.Empty{})
+		if ok := err == nil; ok != (token == "secret") {
+			t.Errorf("Ping with token %q = %v", token, err)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+// Command synurang-plugin-serve loads a plugin shared library and serves its
+// services over TCP and/or UDS with server reflection enabled, so the plugin
+// can be called with grpcurl or Postman without writing a host.
+//
+// Service definitions come from a FileDescriptorSet or from .proto files
+// (compiled with protoc, which must be on PATH).
+//
+// Usage:
+//
+//	synurang-plugin-serve -plugin ./plugin.so -descriptor_set api.pb -port 50051
+//	synurang-plugin-serve -plugin ./plugin.so -proto api/my.proto -I api -socket /tmp/plugin.sock
+//
+//	grpcurl -plaintext localhost:50051 list
+package main
+
+import (
+	"flag"
+	"log"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ivere27/synurang/internal/descset"
+	"github.com/ivere27/synurang/pkg/synurang"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionv1alpha "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+func main() {
+	pluginPath := flag.String("plugin", "", "path to the plugin shared library")
+	descriptorSets := flag.String("descriptor_set", "", "comma-separated FileDescriptorSet files (protoc --include_imports --descriptor_set_out)")
+	protoFiles := flag.String("proto", "", "comma-separated .proto files, compiled with protoc")
+	importPaths := flag.String("I", "", "comma-separated protoc import paths for -proto (default: each file's directory)")
+	services := flag.String("service", "", "comma-separated services to serve (full or short names); default: all services in the descriptors")
+	port := flag.String("port", "", "TCP port to listen on")
+	socket := flag.String("socket", "", "Unix domain socket path to listen on")
+	flag.Parse()
+
+	if *pluginPath == "" || (*descriptorSets == "" && *protoFiles == "") || (*port == "" && *socket == "") {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	descPaths := descset.SplitList(*descriptorSets)
+	if *protoFiles != "" {
+		compiled, err := descset.Compile(descset.SplitList(*protoFiles), descset.SplitList(*importPaths))
+		if err != nil {
+			log.Fatalf("Failed to compile protos: %v", err)
+		}
+		defer os.Remove(compiled)
+		descPaths = append(descPaths, compiled)
+	}
+
+	files, err := descset.Load(descPaths)
+	if err != nil {
+		log.Fatalf("Failed to load descriptors: %v", err)
+	}
+	selected, err := descset.Services(files, descset.SplitList(*services))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(selected) == 0 {
+		log.Fatal("No services to serve")
+	}
+
+	plugin, err := synurang.LoadPlugin(*pluginPath, synurang.WithLogger(slog.Default()))
+	if err != nil {
+		log.Fatalf("Failed to load plugin: %v", err)
+	}
+	defer plugin.Close()
+
+	s := grpc.NewServer()
+	synurang.RegisterPluginServices(s, plugin, selected...)
+
+	// Reflection resolves the plugin's types from the supplied descriptors and
+	// everything else (the reflection service itself) from the global registry.
+	refl := reflection.ServerOptions{
+		Services:           s,
+		DescriptorResolver: resolver{files},
+	}
+	reflectionv1.RegisterServerReflectionServer(s, reflection.NewServerV1(refl))
+	reflectionv1alpha.RegisterServerReflectionServer(s, reflection.NewServer(refl))
+
+	var listeners []net.Listener
+	if *port != "" {
+		lis, err := net.Listen("tcp", ":"+*port)
+		if err != nil {
+			log.Fatalf("Failed to listen on TCP port %s: %v", *port, err)
+		}
+		listeners = append(listeners, lis)
+	}
+	if *socket != "" {
+		os.Remove(*socket)
+		lis, err := net.Listen("unix", *socket)
+		if err != nil {
+			log.Fatalf("Failed to listen on UDS %s: %v", *socket, err)
+		}
+		defer os.Remove(*socket)
+		listeners = append(listeners, lis)
+	}
+
+	for _, sd := range selected {
+		log.Printf("Serving %s", sd.FullName())
+	}
+	for _, lis := range listeners {
+		log.Printf("Listening on %s %s", lis.Addr().Network(), lis.Addr())
+		go func(lis net.Listener) {
+			if err := s.Serve(lis); err != nil {
+				log.Printf("Serve error on %s: %v", lis.Addr(), err)
+			}
+		}(lis)
+	}
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+	<-sig
+	log.Println("Shutting down...")
+	s.GracefulStop()
+}
+
+// resolver looks descriptors up in the loaded files, then in the global registry.
+type resolver struct {
+	files *protoregistry.Files
+}
+
+func (r resolver) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	if fd, err := r.files.FindFileByPath(path); err == nil {
+		return fd, nil
+	}
+	return protoregistry.GlobalFiles.FindFileByPath(path)
+}
+
+func (r resolver) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	if d, err := r.files.FindDescriptorByName(name); err == nil {
+		return d, nil
+	}
+	return protoregistry.GlobalFiles.FindDescriptorByName(name)
+}
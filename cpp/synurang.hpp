@@ -92,6 +92,23 @@ extern "C" {
 // Server lifecycle
 int StartGrpcServer(synurang::CoreArgument cArg);
 int StopGrpcServer();
+// Stops gracefully, letting calls finish for timeoutMs; returns a serialized
+// core.v1.ShutdownReport of what was terminated. Free with FreeFfiData
+synurang::FfiData StopGrpcServerWithTimeout(long long timeoutMs);
+// Returns a serialized core.v1.ServerStatus (bound endpoints, cache state,
+// start error); free with FreeFfiData
+synurang::FfiData StartGrpcServerWithStatus(synurang::CoreArgument cArg, int failOnListenError);
+synurang::FfiData GetServerStatus();
+// Return a serialized core.v1.ServiceList of every service, and the
+// core.v1.MethodDescription of one method (empty if unknown); free with
+// FreeFfiData
+synurang::FfiData ListServices();
+synurang::FfiData DescribeMethod(char* method);
+// Returns a serialized core.v1.HealthReport; free with FreeFfiData
+synurang::FfiData GetHealth();
+// Receives a serialized core.v1.ListenerStatus when a listener stops serving
+typedef void (*ServeErrorCallback)(void* data, long long len);
+void RegisterServeErrorCallback(ServeErrorCallback callback);
 
 // Unary invocation (Dart -> C++)
 // Zero-copy request: data points to Dart's memory, read-only.
@@ -99,6 +116,20 @@ int StopGrpcServer();
 synurang::FfiData InvokeBackend(char* method, void* data, long long len);
 synurang::FfiData InvokeBackendWithMeta(char* method, void* data, long long len, 
                                         void* meta, long long metaLen);
+// header is a serialized core.v1.CallHeader
+synurang::FfiData InvokeBackendWithHeader(char* method, void* data, long long len,
+                                          void* header, long long headerLen);
+// Like InvokeBackendWithHeader, but returns a serialized core.v1.CallResponse
+synurang::FfiData InvokeBackendCall(char* method, void* data, long long len,
+                                    void* header, long long headerLen);
+
+// Asynchronous unary invocation: the CallResponse is delivered to the
+// completion callback, which frees it with FreeFfiData
+typedef void (*CallCompleteCallback)(long long callId, void* data, long long len);
+void RegisterCallCompleteCallback(CallCompleteCallback callback);
+int InvokeBackendAsync(long long callId, char* method, void* data, long long len,
+                       void* header, long long headerLen);
+void CancelBackendCall(long long callId);
 
 // Memory management
 void FreeFfiData(void* data);
@@ -115,6 +146,14 @@ void RegisterStreamCallback(StreamCallback callback);
 long long InvokeBackendServerStream(char* method, void* data, long long len);
 long long InvokeBackendClientStream(char* method);
 long long InvokeBackendBidiStream(char* method);
+long long InvokeBackendServerStreamWithMeta(char* method, void* data, long long len,
+                                            void* meta, long long metaLen);
+long long InvokeBackendClientStreamWithMeta(char* method, void* meta, long long metaLen);
+long long InvokeBackendBidiStreamWithMeta(char* method, void* meta, long long metaLen);
+long long InvokeBackendServerStreamWithHeader(char* method, void* data, long long len,
+                                              void* header, long long headerLen);
+long long InvokeBackendClientStreamWithHeader(char* method, void* header, long long headerLen);
+long long InvokeBackendBidiStreamWithHeader(char* method, void* header, long long headerLen);
 int SendStreamData(long long streamId, void* data, long long len);
 void CloseStream(long long streamId);
 void CloseStreamInput(long long streamId);
@@ -39,6 +39,24 @@ static void invoke_stream_callback(StreamCallback cb, long long streamId, char m
         cb(streamId, msgType, data, len);
     }
 }
+
+// Completion callback signature (for InvokeBackendAsync)
+typedef void (*CallCompleteCallback)(long long callId, void* data, long long len);
+
+static void invoke_call_complete_callback(CallCompleteCallback cb, long long callId, void* data, long long len) {
+    if (cb) {
+        cb(callId, data, len);
+    }
+}
+
+// Serve error callback signature (serialized core.v1.ListenerStatus)
+typedef void (*ServeErrorCallback)(void* data, long long len);
+
+static void invoke_serve_error_callback(ServeErrorCallback cb, void* data, long long len) {
+    if (cb) {
+        cb(data, len);
+    }
+}
 */
 import "C"
 
@@ -47,7 +65,6 @@ import (
 	"flag"
 	"fmt"
 	"log"
-	"net"
 	"os"
 	"os/signal"
 	goruntime "runtime"
@@ -66,6 +83,8 @@ import (
 	example_pb "github.com/ivere27/synurang/example/pkg/api"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 )
@@ -77,18 +96,20 @@ var (
 )
 
 var (
-	srv          *grpc.Server
-	coreImpl     *service.CoreServiceServer
+	// The example serves a single engine, the default one
+	engine       = service.DefaultEngine()
 	greeterImpl  *example_service.GreeterServiceServer
 	implMu       sync.RWMutex
-	listeners    []net.Listener
-	serverErrors chan error
 	sigChan      = make(chan os.Signal, 1)
 	dartCallback C.InvokeDartCallback
 	quitChan     chan struct{}
 
 	// Stream callback for server/bidi streaming
 	streamCallback C.StreamCallback
+
+	// Completion callback and in-flight calls of InvokeBackendAsync
+	callCompleteCallback C.CallCompleteCallback
+	callbackMu           sync.RWMutex
 )
 
 func init() {
@@ -109,12 +130,84 @@ func init() {
 //export StartGrpcServer
 func StartGrpcServer(cArg C.struct_CoreArgument) C.int {
 	log.Println("SynuraExample - StartGrpcServer called")
-	if srv != nil {
-		log.Println("SynuraExample - already started")
+	if _, err := startServer(cArg, false); err != nil {
 		return -1
 	}
+	return 0
+}
+
+// StartGrpcServerWithStatus is StartGrpcServer returning a serialized
+// core.v1.ServerStatus. The caller frees the result with FreeFfiData.
+//
+//export StartGrpcServerWithStatus
+func StartGrpcServerWithStatus(cArg C.struct_CoreArgument, failOnListenError C.int) C.FfiData {
+	log.Println("SynuraExample - StartGrpcServerWithStatus called")
+	st, _ := startServer(cArg, failOnListenError != 0)
+	return protoData(st.Proto())
+}
+
+// GetServerStatus returns the serialized core.v1.ServerStatus of the server.
+// The caller frees the result with FreeFfiData.
+//
+//export GetServerStatus
+func GetServerStatus() C.FfiData {
+	return protoData(engine.Status().Proto())
+}
+
+// GetHealth returns the serialized core.v1.HealthReport of the server. The
+// caller frees the result with FreeFfiData.
+//
+//export GetHealth
+func GetHealth() C.FfiData {
+	ctx, cancel := context.WithTimeout(context.Background(), service.DefaultHealthInterval)
+	defer cancel()
+	return protoData(engine.Health(ctx).Proto())
+}
+
+// ListServices returns the serialized core.v1.ServiceList of every service
+// of the server, including those only reachable over FFI. The caller frees
+// the result with FreeFfiData.
+//
+//export ListServices
+func ListServices() C.FfiData {
+	return protoData(engine.ListServices())
+}
+
+// DescribeMethod returns the serialized core.v1.MethodDescription of method,
+// or empty data if no service has it. The caller frees the result with
+// FreeFfiData.
+//
+//export DescribeMethod
+func DescribeMethod(method *C.char) C.FfiData {
+	if method == nil {
+		return C.FfiData{data: nil, len: 0}
+	}
+	desc, err := engine.DescribeMethod(C.GoString(method))
+	if err != nil {
+		return C.FfiData{data: nil, len: 0}
+	}
+	return protoData(desc)
+}
+
+// RegisterServeErrorCallback sets the callback receiving a serialized
+// core.v1.ListenerStatus when an error ends a listener after the server
+// started. The callee frees the data with FreeFfiData.
+//
+//export RegisterServeErrorCallback
+func RegisterServeErrorCallback(callback C.ServeErrorCallback) {
+	if callback == nil {
+		engine.SetServeErrorCallback(nil)
+		return
+	}
+	engine.SetServeErrorCallback(func(ls service.ListenerStatus) {
+		out := protoData(ls.Proto())
+		C.invoke_serve_error_callback(callback, out.data, out.len)
+	})
+}
 
-	cfg := &service.Config{}
+// startServer starts the engine with the StartGrpcServer arguments.
+func startServer(cArg C.struct_CoreArgument, failOnListenError bool) (service.Status, error) {
+	cfg := &service.Config{FailOnListenError: failOnListenError}
 
 	if unsafe.Pointer(cArg.storagePath) != nil {
 		cfg.EngineSocketPath = C.GoString(cArg.storagePath)
@@ -149,62 +242,18 @@ func StartGrpcServer(cArg C.struct_CoreArgument) C.int {
 		cfg.Token = C.GoString(cArg.token)
 	}
 
-	// Set the global default stream timeout for FFI callbacks
-	service.SetDefaultStreamTimeout(cfg.StreamTimeout)
-
-	listeners = make([]net.Listener, 0)
-	var listenersMu sync.Mutex
-	var wg sync.WaitGroup
-
-	// UDS listener
-	if cfg.EngineSocketPath != "" {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			if _, err := os.Stat(cfg.EngineSocketPath); err == nil {
-				os.Remove(cfg.EngineSocketPath)
-			}
-			udsListen, err := net.Listen("unix", cfg.EngineSocketPath)
-			if err != nil {
-				log.Printf("Failed to listen on UDS: %v", err)
-			} else {
-				listenersMu.Lock()
-				listeners = append(listeners, udsListen)
-				listenersMu.Unlock()
-			}
-		}()
-	}
-
-	// TCP listener
-	if cfg.EngineTcpPort != "" {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			tcpListen, err := net.Listen("tcp", ":"+cfg.EngineTcpPort)
-			if err != nil {
-				log.Printf("Failed to listen on TCP: %v", err)
-			} else {
-				listenersMu.Lock()
-				listeners = append(listeners, tcpListen)
-				listenersMu.Unlock()
-			}
-		}()
-	}
-
-	wg.Wait()
-
-	// Initialize server
-	serviceImpl := service.NewCoreService(cfg)
-	exampleImpl := example_service.NewGreeterServiceServer(serviceImpl)
-
 	// Pass a registrar to register Greeter
-	s := service.NewGrpcServer(serviceImpl, cfg, func(gs *grpc.Server, core *service.CoreServiceServer) {
+	var exampleImpl *example_service.GreeterServiceServer
+	st, err := engine.Start(cfg, func(gs *grpc.Server, core *service.CoreServiceServer) {
+		exampleImpl = example_service.NewGreeterServiceServer(core)
 		example_pb.RegisterGoGreeterServiceServer(gs, exampleImpl)
 	})
+	if err != nil {
+		log.Printf("SynuraExample - %v", err)
+		return st, err
+	}
 
-	srv = s
 	implMu.Lock()
-	coreImpl = serviceImpl
 	greeterImpl = exampleImpl
 	implMu.Unlock()
 
@@ -223,61 +272,48 @@ func StartGrpcServer(cArg C.struct_CoreArgument) C.int {
 		}
 	}()
 
-	// Start serving
-	serverErrors = make(chan error, 1)
-	if len(listeners) > 0 {
-		go func() {
-			for _, listener := range listeners {
-				go func(l net.Listener) {
-					log.Printf("SynuraExample - Serving on %s", l.Addr().String())
-					if err := srv.Serve(l); err != nil {
-						log.Printf("Server error: %v", err)
-						serverErrors <- err
-					}
-				}(listener)
-			}
-		}()
-	} else {
-		log.Println("SynuraExample - Running in FFI-only mode")
-	}
-
-	return 0
+	return st, nil
 }
 
 //export StopGrpcServer
 func StopGrpcServer() C.int {
 	log.Printf("SynuraExample - StopGrpcServer called (goroutines: %d)", goruntime.NumGoroutine())
-	if srv != nil {
-		// Stop debug tracker
-		if quitChan != nil {
-			close(quitChan)
-			quitChan = nil
-		}
+	stopServer(engine.ShutdownTimeout())
+	log.Printf("SynuraExample - StopGrpcServer finished (goroutines: %d)", goruntime.NumGoroutine())
+	return 0
+}
 
-		implMu.Lock()
-		if coreImpl != nil {
-			coreImpl.Close()
-			coreImpl = nil
-		}
-		greeterImpl = nil
-		implMu.Unlock()
+//export StopGrpcServerWithTimeout
+func StopGrpcServerWithTimeout(timeoutMs C.longlong) C.FfiData {
+	report := stopServer(time.Duration(timeoutMs) * time.Millisecond)
+	return protoData(report.Proto())
+}
 
-		srv.Stop()
+// stopServer stops the engine gracefully, letting calls in progress finish
+// for timeout.
+func stopServer(timeout time.Duration) service.ShutdownReport {
+	if engine.Server() == nil {
+		return service.ShutdownReport{}
+	}
+	// Stop debug tracker
+	if quitChan != nil {
+		close(quitChan)
+		quitChan = nil
+	}
 
-		for _, listener := range listeners {
-			listener.Close()
-			if addr := listener.Addr(); addr.Network() == "unix" {
-				os.Remove(addr.String())
-			}
-		}
-		srv = nil
-		listeners = nil
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	report, _ := engine.Shutdown(ctx)
+	service.DefaultRequestHandler.CleanupPending()
 
-		goruntime.GC()
-		debug.FreeOSMemory()
-	}
-	log.Printf("SynuraExample - StopGrpcServer finished (goroutines: %d)", goruntime.NumGoroutine())
-	return 0
+	// Cleared after the drain, which the calls in progress still need it for
+	implMu.Lock()
+	greeterImpl = nil
+	implMu.Unlock()
+
+	goruntime.GC()
+	debug.FreeOSMemory()
+	return report
 }
 
 // =============================================================================
@@ -286,8 +322,111 @@ func StopGrpcServer() C.int {
 
 //export InvokeBackend
 func InvokeBackend(method *C.char, data unsafe.Pointer, dataLen C.longlong) C.FfiData {
+	return invokeBackend(method, data, dataLen, service.FfiCall{}, nil)
+}
+
+// InvokeBackendWithMeta is InvokeBackend with legacy "key=value\n" metadata.
+//
+//export InvokeBackendWithMeta
+func InvokeBackendWithMeta(method *C.char, data unsafe.Pointer, dataLen C.longlong,
+	metaData unsafe.Pointer, metaLen C.longlong) C.FfiData {
+	return invokeBackend(method, data, dataLen, textCall(metaData, metaLen), nil)
+}
+
+// InvokeBackendWithHeader is InvokeBackend with a serialized core.v1.CallHeader.
+// Handlers see its metadata as incoming gRPC metadata. The token is checked
+// here as the gRPC interceptors would.
+//
+//export InvokeBackendWithHeader
+func InvokeBackendWithHeader(method *C.char, data unsafe.Pointer, dataLen C.longlong,
+	header unsafe.Pointer, headerLen C.longlong) C.FfiData {
+	call, err := service.ParseCallHeader(cBytes(header, headerLen))
+	return invokeBackend(method, data, dataLen, call, err)
+}
+
+// InvokeBackendCall is InvokeBackendWithHeader returning a serialized
+// core.v1.CallResponse, which also carries the header and trailer metadata
+// the handler set.
+//
+//export InvokeBackendCall
+func InvokeBackendCall(method *C.char, data unsafe.Pointer, dataLen C.longlong,
+	header unsafe.Pointer, headerLen C.longlong) C.FfiData {
+	call, err := service.ParseCallHeader(cBytes(header, headerLen))
+	ctx, cancel := call.Context()
+	defer cancel()
+	return protoData(invokeBackendCall(ctx, C.GoString(method), cBytes(data, dataLen), err))
+}
+
+// InvokeBackendAsync runs InvokeBackendCall on a goroutine and delivers the
+// result to the callback registered with RegisterCallCompleteCallback, which
+// frees it with FreeFfiData. Returns 0 if accepted, 1 if no callback is
+// registered or callId is already in flight.
+//
+//export InvokeBackendAsync
+func InvokeBackendAsync(callId C.longlong, method *C.char, data unsafe.Pointer, dataLen C.longlong,
+	header unsafe.Pointer, headerLen C.longlong) C.int {
+	callbackMu.RLock()
+	registered := callCompleteCallback != nil
+	callbackMu.RUnlock()
+	if !registered {
+		return 1
+	}
+
+	id := int64(callId)
+	call, headerErr := service.ParseCallHeader(cBytes(header, headerLen))
+	call.CallID = uint64(id)
+	ctx, cancel := call.Context()
+	if !engine.Calls.Add(id, cancel) {
+		cancel()
+		return 1
+	}
+
+	goMethod := C.GoString(method)
+	goData := C.GoBytes(data, C.int(dataLen))
+	go func() {
+		defer engine.Calls.Done(id)
+		out := protoData(invokeBackendCall(ctx, goMethod, goData, headerErr))
+
+		callbackMu.RLock()
+		cb := callCompleteCallback
+		callbackMu.RUnlock()
+		if cb == nil {
+			FreeFfiData(out.data)
+			return
+		}
+		C.invoke_call_complete_callback(cb, callId, out.data, out.len)
+	}()
+	return 0
+}
+
+// CancelBackendCall cancels the context of a call started with
+// InvokeBackendAsync; its callback still runs.
+//
+//export CancelBackendCall
+func CancelBackendCall(callId C.longlong) {
+	engine.Calls.Cancel(int64(callId))
+}
+
+//export RegisterCallCompleteCallback
+func RegisterCallCompleteCallback(callback C.CallCompleteCallback) {
+	callbackMu.Lock()
+	callCompleteCallback = callback
+	callbackMu.Unlock()
+}
+
+func invokeBackend(method *C.char, data unsafe.Pointer, dataLen C.longlong, call service.FfiCall, headerErr error) C.FfiData {
+	goMethod := C.GoString(method)
+	ctx, cancel := call.Context()
+	defer cancel()
+	ctx, end, err := engine.BeginCall(ctx, goMethod)
+	if err != nil {
+		errBytes, _ := proto.Marshal(service.ErrorProto(err))
+		return C.FfiData{data: C.CBytes(errBytes), len: C.longlong(-len(errBytes))}
+	}
+	defer end()
+
+	localCore := engine.Core()
 	implMu.RLock()
-	localCore := coreImpl
 	localGreeter := greeterImpl
 	implMu.RUnlock()
 
@@ -300,22 +439,31 @@ func InvokeBackend(method *C.char, data unsafe.Pointer, dataLen C.longlong) C.Ff
 		}
 	}
 
-	goMethod := C.GoString(method)
 	goData := unsafe.Slice((*byte)(data), int(dataLen))
 
+	// Lets handlers call grpc.SetHeader/SetTrailer; see InvokeBackendCall
+	ctx, _ = service.NewFfiTransportStream(ctx, goMethod)
+
+	// Reject the call as the gRPC auth interceptor would
+	err = headerErr
+	if err == nil {
+		ctx, err = localCore.AuthorizeCall(ctx, goMethod)
+	}
+
 	// ==========================================================================
 	// OPTION 1: Simple with Invoke (easy, works with TCP/UDS too)
 	// - Returns []byte, then copies to C memory
 	// - Same code works for FFI, TCP, and UDS modes
 	// ==========================================================================
 	var resp []byte
-	var err error
 
 	// Route to the correct dispatcher (each .proto has its own Invoke)
-	if strings.HasPrefix(goMethod, "/example.v1.") {
-		resp, err = example_pb.Invoke(localGreeter, context.Background(), goMethod, goData)
-	} else {
-		resp, err = pb.Invoke(localCore, context.Background(), goMethod, goData)
+	if err == nil {
+		if strings.HasPrefix(goMethod, "/example.v1.") {
+			resp, err = example_pb.Invoke(localGreeter, ctx, goMethod, goData)
+		} else {
+			resp, err = pb.Invoke(localCore, ctx, goMethod, goData)
+		}
 	}
 
 	// ==========================================================================
@@ -324,11 +472,12 @@ func InvokeBackend(method *C.char, data unsafe.Pointer, dataLen C.longlong) C.Ff
 	// ==========================================================================
 	// var cPtr unsafe.Pointer
 	// var size int64
-	// var err error
-	// if strings.HasPrefix(goMethod, "/example.v1.") {
-	// 	cPtr, size, err = example_pb.InvokeFfi(localGreeter, context.Background(), goMethod, goData)
-	// } else {
-	// 	cPtr, size, err = pb.InvokeFfi(localCore, context.Background(), goMethod, goData)
+	// if err == nil {
+	// 	if strings.HasPrefix(goMethod, "/example.v1.") {
+	// 		cPtr, size, err = example_pb.InvokeFfi(localGreeter, ctx, goMethod, goData)
+	// 	} else {
+	// 		cPtr, size, err = pb.InvokeFfi(localCore, ctx, goMethod, goData)
+	// 	}
 	// }
 	// if err == nil {
 	// 	return C.FfiData{data: cPtr, len: C.longlong(size)}
@@ -366,6 +515,67 @@ func InvokeBackend(method *C.char, data unsafe.Pointer, dataLen C.longlong) C.Ff
 	}
 }
 
+// invokeBackendCall runs a unary call for InvokeBackendCall and
+// InvokeBackendAsync.
+func invokeBackendCall(ctx context.Context, method string, data []byte, headerErr error) *pb.CallResponse {
+	ctx, end, err := engine.BeginCall(ctx, method)
+	if err != nil {
+		return service.NewCallResponse(nil, err, nil)
+	}
+	defer end()
+
+	localCore := engine.Core()
+	implMu.RLock()
+	localGreeter := greeterImpl
+	implMu.RUnlock()
+
+	if localCore == nil {
+		return service.NewCallResponse(nil, status.Error(codes.Unavailable, "Server implementation not initialized"), nil)
+	}
+	if headerErr != nil {
+		return service.NewCallResponse(nil, headerErr, nil)
+	}
+
+	ctx, stream := service.NewFfiTransportStream(ctx, method)
+
+	var resp []byte
+	ctx, err = localCore.AuthorizeCall(ctx, method)
+	if err == nil {
+		if strings.HasPrefix(method, "/example.v1.") {
+			resp, err = example_pb.Invoke(localGreeter, ctx, method, data)
+		} else {
+			resp, err = pb.Invoke(localCore, ctx, method, data)
+		}
+	}
+	if err != nil {
+		log.Printf("Invoke error: %v", err)
+	}
+	return service.NewCallResponse(resp, err, stream)
+}
+
+// protoData serializes m into C memory for the caller to free.
+func protoData(m proto.Message) C.FfiData {
+	out, _ := proto.Marshal(m)
+	if len(out) == 0 {
+		return C.FfiData{}
+	}
+	return C.FfiData{data: C.CBytes(out), len: C.longlong(len(out))}
+}
+
+// textCall decodes the legacy "key=value\n" metadata of the *WithMeta exports.
+func textCall(metaData unsafe.Pointer, metaLen C.longlong) service.FfiCall {
+	md, timeoutMs := service.ParseFfiMetadata(cBytes(metaData, metaLen))
+	return service.FfiCall{Metadata: md, TimeoutMs: timeoutMs}
+}
+
+// cBytes views a caller-owned buffer for the duration of an FFI call.
+func cBytes(data unsafe.Pointer, n C.longlong) []byte {
+	if data == nil || n <= 0 {
+		return nil
+	}
+	return unsafe.Slice((*byte)(data), int(n))
+}
+
 //export FreeFfiData
 func FreeFfiData(data unsafe.Pointer) {
 	if data != nil {
@@ -447,28 +657,47 @@ func RegisterStreamCallback(callback C.StreamCallback) {
 
 //export InvokeBackendServerStream
 func InvokeBackendServerStream(method *C.char, data unsafe.Pointer, dataLen C.longlong) C.longlong {
+	return startServerStream(method, data, dataLen, service.FfiCall{}, nil)
+}
+
+//export InvokeBackendServerStreamWithMeta
+func InvokeBackendServerStreamWithMeta(method *C.char, data unsafe.Pointer, dataLen C.longlong,
+	metaData unsafe.Pointer, metaLen C.longlong) C.longlong {
+	return startServerStream(method, data, dataLen, textCall(metaData, metaLen), nil)
+}
+
+//export InvokeBackendServerStreamWithHeader
+func InvokeBackendServerStreamWithHeader(method *C.char, data unsafe.Pointer, dataLen C.longlong,
+	header unsafe.Pointer, headerLen C.longlong) C.longlong {
+	call, err := service.ParseCallHeader(cBytes(header, headerLen))
+	return startServerStream(method, data, dataLen, call, err)
+}
+
+func startServerStream(method *C.char, data unsafe.Pointer, dataLen C.longlong, call service.FfiCall, headerErr error) C.longlong {
 	goMethod := C.GoString(method)
 	goData := C.GoBytes(data, C.int(dataLen))
 
-	implMu.RLock()
-	localCore := coreImpl
-	implMu.RUnlock()
+	localCore := engine.Core()
 
 	if localCore == nil {
 		log.Println("Error: Server not initialized for streaming")
 		return -1
 	}
+	md, ok := authorizeStream(localCore, goMethod, call, headerErr)
+	if !ok {
+		return -1
+	}
 
 	var streamId int64 = -1
 
 	// Dispatch
 	switch goMethod {
 	case "/example.v1.GoGreeterService/BarServerStream":
-		streamId = service.StartServerStream(goMethod, func(session *service.StreamSession) {
+		streamId = service.StartServerStreamWithMeta(goMethod, md, func(session *service.StreamSession) {
 			example_service.HandleBarServerStream(session, goData)
 		})
 	case "/example.v1.GoGreeterService/DownloadFile":
-		streamId = service.StartServerStream(goMethod, func(session *service.StreamSession) {
+		streamId = service.StartServerStreamWithMeta(goMethod, md, func(session *service.StreamSession) {
 			example_service.HandleDownloadFile(session, goData)
 		})
 	default:
@@ -481,26 +710,43 @@ func InvokeBackendServerStream(method *C.char, data unsafe.Pointer, dataLen C.lo
 
 //export InvokeBackendClientStream
 func InvokeBackendClientStream(method *C.char) C.longlong {
+	return startClientStream(method, service.FfiCall{}, nil)
+}
+
+//export InvokeBackendClientStreamWithMeta
+func InvokeBackendClientStreamWithMeta(method *C.char, metaData unsafe.Pointer, metaLen C.longlong) C.longlong {
+	return startClientStream(method, textCall(metaData, metaLen), nil)
+}
+
+//export InvokeBackendClientStreamWithHeader
+func InvokeBackendClientStreamWithHeader(method *C.char, header unsafe.Pointer, headerLen C.longlong) C.longlong {
+	call, err := service.ParseCallHeader(cBytes(header, headerLen))
+	return startClientStream(method, call, err)
+}
+
+func startClientStream(method *C.char, call service.FfiCall, headerErr error) C.longlong {
 	goMethod := C.GoString(method)
 
-	implMu.RLock()
-	localCore := coreImpl
-	implMu.RUnlock()
+	localCore := engine.Core()
 
 	if localCore == nil {
 		log.Println("Error: Server not initialized for streaming")
 		return -1
 	}
+	md, ok := authorizeStream(localCore, goMethod, call, headerErr)
+	if !ok {
+		return -1
+	}
 
 	var streamId int64 = -1
 
 	switch goMethod {
 	case "/example.v1.GoGreeterService/BarClientStream":
-		streamId = service.StartClientStream(goMethod, func(session *service.StreamSession) {
+		streamId = service.StartClientStreamWithMeta(goMethod, md, func(session *service.StreamSession) {
 			example_service.HandleBarClientStream(session)
 		})
 	case "/example.v1.GoGreeterService/UploadFile":
-		streamId = service.StartClientStream(goMethod, func(session *service.StreamSession) {
+		streamId = service.StartClientStreamWithMeta(goMethod, md, func(session *service.StreamSession) {
 			example_service.HandleUploadFile(session)
 		})
 	default:
@@ -513,26 +759,43 @@ func InvokeBackendClientStream(method *C.char) C.longlong {
 
 //export InvokeBackendBidiStream
 func InvokeBackendBidiStream(method *C.char) C.longlong {
+	return startBidiStream(method, service.FfiCall{}, nil)
+}
+
+//export InvokeBackendBidiStreamWithMeta
+func InvokeBackendBidiStreamWithMeta(method *C.char, metaData unsafe.Pointer, metaLen C.longlong) C.longlong {
+	return startBidiStream(method, textCall(metaData, metaLen), nil)
+}
+
+//export InvokeBackendBidiStreamWithHeader
+func InvokeBackendBidiStreamWithHeader(method *C.char, header unsafe.Pointer, headerLen C.longlong) C.longlong {
+	call, err := service.ParseCallHeader(cBytes(header, headerLen))
+	return startBidiStream(method, call, err)
+}
+
+func startBidiStream(method *C.char, call service.FfiCall, headerErr error) C.longlong {
 	goMethod := C.GoString(method)
 
-	implMu.RLock()
-	localCore := coreImpl
-	implMu.RUnlock()
+	localCore := engine.Core()
 
 	if localCore == nil {
 		log.Println("Error: Server not initialized for streaming")
 		return -1
 	}
+	md, ok := authorizeStream(localCore, goMethod, call, headerErr)
+	if !ok {
+		return -1
+	}
 
 	var streamId int64 = -1
 
 	switch goMethod {
 	case "/example.v1.GoGreeterService/BarBidiStream":
-		streamId = service.StartBidiStream(goMethod, func(session *service.StreamSession) {
+		streamId = service.StartBidiStreamWithMeta(goMethod, md, func(session *service.StreamSession) {
 			example_service.HandleBarBidiStream(session)
 		})
 	case "/example.v1.GoGreeterService/BidiFile":
-		streamId = service.StartBidiStream(goMethod, func(session *service.StreamSession) {
+		streamId = service.StartBidiStreamWithMeta(goMethod, md, func(session *service.StreamSession) {
 			example_service.HandleBidiFile(session)
 		})
 	default:
@@ -543,6 +806,22 @@ func InvokeBackendBidiStream(method *C.char) C.longlong {
 	return C.longlong(streamId)
 }
 
+// authorizeStream rejects a stream whose header failed to decode or whose
+// metadata fails the token check or the policy.
+func authorizeStream(core *service.CoreServiceServer, method string, call service.FfiCall, headerErr error) (metadata.MD, bool) {
+	if headerErr != nil {
+		log.Printf("Stream %s rejected: %v", method, headerErr)
+		return nil, false
+	}
+	ctx, cancel := service.FfiContext(call.Metadata, 0)
+	defer cancel()
+	if _, err := core.AuthorizeCall(ctx, method); err != nil {
+		log.Printf("Stream %s rejected: %v", method, err)
+		return nil, false
+	}
+	return call.Metadata, true
+}
+
 //export SendStreamData
 func SendStreamData(streamId C.longlong, data unsafe.Pointer, dataLen C.longlong) C.int {
 	// OPTION 1: Safe (current) - copies data into Go heap
@@ -581,13 +860,11 @@ func StreamReady(streamId C.longlong) {
 
 //export CacheGet
 func CacheGet(storeName *C.char, key *C.char) C.FfiData {
-	implMu.RLock()
-	currentImpl := coreImpl
-	implMu.RUnlock()
-
-	if currentImpl == nil || currentImpl.CacheServiceServer == nil {
+	currentImpl, end := cacheCore("/core.v1.CacheService/Get")
+	if currentImpl == nil {
 		return C.FfiData{data: nil, len: 0}
 	}
+	defer end()
 
 	goStoreName := C.GoString(storeName)
 	goKey := C.GoString(key)
@@ -610,13 +887,11 @@ func CacheGet(storeName *C.char, key *C.char) C.FfiData {
 
 //export CachePut
 func CachePut(storeName *C.char, key *C.char, data unsafe.Pointer, dataLen C.longlong, ttlSeconds C.longlong) C.int {
-	implMu.RLock()
-	currentImpl := coreImpl
-	implMu.RUnlock()
-
-	if currentImpl == nil || currentImpl.CacheServiceServer == nil {
+	currentImpl, end := cacheCore("/core.v1.CacheService/Put")
+	if currentImpl == nil {
 		return -1
 	}
+	defer end()
 
 	goStoreName := C.GoString(storeName)
 	goKey := C.GoString(key)
@@ -638,13 +913,11 @@ func CachePut(storeName *C.char, key *C.char, data unsafe.Pointer, dataLen C.lon
 
 //export CacheContains
 func CacheContains(storeName *C.char, key *C.char) C.int {
-	implMu.RLock()
-	currentImpl := coreImpl
-	implMu.RUnlock()
-
-	if currentImpl == nil || currentImpl.CacheServiceServer == nil {
+	currentImpl, end := cacheCore("/core.v1.CacheService/Contains")
+	if currentImpl == nil {
 		return -1
 	}
+	defer end()
 
 	goStoreName := C.GoString(storeName)
 	goKey := C.GoString(key)
@@ -665,13 +938,11 @@ func CacheContains(storeName *C.char, key *C.char) C.int {
 
 //export CacheDelete
 func CacheDelete(storeName *C.char, key *C.char) C.int {
-	implMu.RLock()
-	currentImpl := coreImpl
-	implMu.RUnlock()
-
-	if currentImpl == nil || currentImpl.CacheServiceServer == nil {
+	currentImpl, end := cacheCore("/core.v1.CacheService/Delete")
+	if currentImpl == nil {
 		return -1
 	}
+	defer end()
 
 	goStoreName := C.GoString(storeName)
 	goKey := C.GoString(key)
@@ -687,6 +958,22 @@ func CacheDelete(storeName *C.char, key *C.char) C.int {
 	return 0
 }
 
+// cacheCore returns the core services if the engine runs with a cache, or
+// nil. The cache call of method is registered with the engine so a graceful
+// stop lets it finish; end must be called once it returns.
+func cacheCore(method string) (*service.CoreServiceServer, func()) {
+	_, end, err := engine.BeginCall(context.Background(), method)
+	if err != nil {
+		return nil, nil
+	}
+	core := engine.Core()
+	if core == nil || core.CacheServiceServer == nil {
+		end()
+		return nil, nil
+	}
+	return core, end
+}
+
 // =============================================================================
 // Standalone Main (for testing without FFI)
 // =============================================================================
@@ -719,7 +1006,7 @@ func main() {
 	StartGrpcServer(*cArg)
 
 	select {
-	case err := <-serverErrors:
+	case err := <-engine.Errors():
 		log.Printf("Server error: %v", err)
 		os.Exit(1)
 	case sig := <-sigChan:
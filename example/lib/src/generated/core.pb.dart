@@ -812,6 +812,1105 @@ class Error extends $pb.GeneratedMessage {
   void clearGrpcCode() => $_clearField(3);
 }
 
+/// CallHeader is the request header of an FFI call, passed serialized to the
+/// InvokeBackend*WithHeader entry points. It replaces the "key=value\n"
+/// metadata text, which cannot carry binary or multi-line values.
+class CallHeader extends $pb.GeneratedMessage {
+  factory CallHeader({
+    $core.int? version,
+    $core.Iterable<MetadataEntry>? metadata,
+    $fixnum.Int64? timeoutMs,
+    $fixnum.Int64? callId,
+    TraceContext? trace,
+  }) {
+    final result = create();
+    if (version != null) result.version = version;
+    if (metadata != null) result.metadata.addAll(metadata);
+    if (timeoutMs != null) result.timeoutMs = timeoutMs;
+    if (callId != null) result.callId = callId;
+    if (trace != null) result.trace = trace;
+    return result;
+  }
+
+  CallHeader._();
+
+  factory CallHeader.fromBuffer($core.List<$core.int> data,
+          [$pb.ExtensionRegistry registry = $pb.ExtensionRegistry.EMPTY]) =>
+      create()..mergeFromBuffer(data, registry);
+  factory CallHeader.fromJson($core.String json,
+          [$pb.ExtensionRegistry registry = $pb.ExtensionRegistry.EMPTY]) =>
+      create()..mergeFromJson(json, registry);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(
+      _omitMessageNames ? '' : 'CallHeader',
+      package: const $pb.PackageName(_omitMessageNames ? '' : 'core.v1'),
+      createEmptyInstance: create)
+    ..aI(1, _omitFieldNames ? '' : 'version',
+        fieldType: $pb.PbFieldType.OU3)
+    ..pPM<MetadataEntry>(2, _omitFieldNames ? '' : 'metadata',
+        subBuilder: MetadataEntry.create)
+    ..aInt64(3, _omitFieldNames ? '' : 'timeoutMs')
+    ..a<$fixnum.Int64>(4, _omitFieldNames ? '' : 'callId', $pb.PbFieldType.OU6,
+        defaultOrMaker: $fixnum.Int64.ZERO)
+    ..aOM<TraceContext>(5, _omitFieldNames ? '' : 'trace',
+        subBuilder: TraceContext.create)
+    ..hasRequiredFields = false;
+
+  @$core.Deprecated('See https://github.com/google/protobuf.dart/issues/998.')
+  CallHeader clone() => deepCopy();
+  @$core.Deprecated('See https://github.com/google/protobuf.dart/issues/998.')
+  CallHeader copyWith(void Function(CallHeader) updates) =>
+      super.copyWith((message) => updates(message as CallHeader)) as CallHeader;
+
+  @$core.override
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static CallHeader create() => CallHeader._();
+  @$core.override
+  CallHeader createEmptyInstance() => create();
+  @$core.pragma('dart2js:noInline')
+  static CallHeader getDefault() => _defaultInstance ??=
+      $pb.GeneratedMessage.$_defaultFor<CallHeader>(create);
+  static CallHeader? _defaultInstance;
+
+  /// Header format version (currently 1). Readers reject newer versions.
+  @$pb.TagNumber(1)
+  $core.int get version => $_getIZ(0);
+  @$pb.TagNumber(1)
+  set version($core.int value) => $_setUnsignedInt32(0, value);
+  @$pb.TagNumber(1)
+  $core.bool hasVersion() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearVersion() => $_clearField(1);
+
+  /// Request metadata, in order; a key may repeat. Keys ending in "-bin"
+  /// carry binary values, as in gRPC.
+  @$pb.TagNumber(2)
+  $pb.PbList<MetadataEntry> get metadata => $_getList(1);
+
+  /// Deadline relative to the start of the call in milliseconds (0 = none).
+  @$pb.TagNumber(3)
+  $fixnum.Int64 get timeoutMs => $_getI64(2);
+  @$pb.TagNumber(3)
+  set timeoutMs($fixnum.Int64 value) => $_setInt64(2, value);
+  @$pb.TagNumber(3)
+  $core.bool hasTimeoutMs() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearTimeoutMs() => $_clearField(3);
+
+  /// Caller-chosen ID of the call (0 = none).
+  @$pb.TagNumber(4)
+  $fixnum.Int64 get callId => $_getI64(3);
+  @$pb.TagNumber(4)
+  set callId($fixnum.Int64 value) => $_setInt64(3, value);
+  @$pb.TagNumber(4)
+  $core.bool hasCallId() => $_has(3);
+  @$pb.TagNumber(4)
+  void clearCallId() => $_clearField(4);
+
+  /// W3C trace context of the caller.
+  @$pb.TagNumber(5)
+  TraceContext get trace => $_getN(4);
+  @$pb.TagNumber(5)
+  set trace(TraceContext value) => $_setField(5, value);
+  @$pb.TagNumber(5)
+  $core.bool hasTrace() => $_has(4);
+  @$pb.TagNumber(5)
+  void clearTrace() => $_clearField(5);
+  @$pb.TagNumber(5)
+  TraceContext ensureTrace() => $_ensure(4);
+}
+
+/// MetadataEntry is one metadata key/value pair.
+class MetadataEntry extends $pb.GeneratedMessage {
+  factory MetadataEntry({
+    $core.String? key,
+    $core.List<$core.int>? value,
+  }) {
+    final result = create();
+    if (key != null) result.key = key;
+    if (value != null) result.value = value;
+    return result;
+  }
+
+  MetadataEntry._();
+
+  factory MetadataEntry.fromBuffer($core.List<$core.int> data,
+          [$pb.ExtensionRegistry registry = $pb.ExtensionRegistry.EMPTY]) =>
+      create()..mergeFromBuffer(data, registry);
+  factory MetadataEntry.fromJson($core.String json,
+          [$pb.ExtensionRegistry registry = $pb.ExtensionRegistry.EMPTY]) =>
+      create()..mergeFromJson(json, registry);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(
+      _omitMessageNames ? '' : 'MetadataEntry',
+      package: const $pb.PackageName(_omitMessageNames ? '' : 'core.v1'),
+      createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'key')
+    ..a<$core.List<$core.int>>(
+        2, _omitFieldNames ? '' : 'value', $pb.PbFieldType.OY)
+    ..hasRequiredFields = false;
+
+  @$core.Deprecated('See https://github.com/google/protobuf.dart/issues/998.')
+  MetadataEntry clone() => deepCopy();
+  @$core.Deprecated('See https://github.com/google/protobuf.dart/issues/998.')
+  MetadataEntry copyWith(void Function(MetadataEntry) updates) =>
+      super.copyWith((message) => updates(message as MetadataEntry))
+          as MetadataEntry;
+
+  @$core.override
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static MetadataEntry create() => MetadataEntry._();
+  @$core.override
+  MetadataEntry createEmptyInstance() => create();
+  @$core.pragma('dart2js:noInline')
+  static MetadataEntry getDefault() => _defaultInstance ??=
+      $pb.GeneratedMessage.$_defaultFor<MetadataEntry>(create);
+  static MetadataEntry? _defaultInstance;
+
+  /// Lowercase key, as in gRPC metadata.
+  @$pb.TagNumber(1)
+  $core.String get key => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set key($core.String value) => $_setString(0, value);
+  @$pb.TagNumber(1)
+  $core.bool hasKey() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearKey() => $_clearField(1);
+
+  /// Raw value; UTF-8 text unless the key ends in "-bin".
+  @$pb.TagNumber(2)
+  $core.List<$core.int> get value => $_getN(1);
+  @$pb.TagNumber(2)
+  set value($core.List<$core.int> value) => $_setBytes(1, value);
+  @$pb.TagNumber(2)
+  $core.bool hasValue() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearValue() => $_clearField(2);
+}
+
+/// TraceContext carries W3C Trace Context headers.
+class TraceContext extends $pb.GeneratedMessage {
+  factory TraceContext({
+    $core.String? traceparent,
+    $core.String? tracestate,
+  }) {
+    final result = create();
+    if (traceparent != null) result.traceparent = traceparent;
+    if (tracestate != null) result.tracestate = tracestate;
+    return result;
+  }
+
+  TraceContext._();
+
+  factory TraceContext.fromBuffer($core.List<$core.int> data,
+          [$pb.ExtensionRegistry registry = $pb.ExtensionRegistry.EMPTY]) =>
+      create()..mergeFromBuffer(data, registry);
+  factory TraceContext.fromJson($core.String json,
+          [$pb.ExtensionRegistry registry = $pb.ExtensionRegistry.EMPTY]) =>
+      create()..mergeFromJson(json, registry);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(
+      _omitMessageNames ? '' : 'TraceContext',
+      package: const $pb.PackageName(_omitMessageNames ? '' : 'core.v1'),
+      createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'traceparent')
+    ..aOS(2, _omitFieldNames ? '' : 'tracestate')
+    ..hasRequiredFields = false;
+
+  @$core.Deprecated('See https://github.com/google/protobuf.dart/issues/998.')
+  TraceContext clone() => deepCopy();
+  @$core.Deprecated('See https://github.com/google/protobuf.dart/issues/998.')
+  TraceContext copyWith(void Function(TraceContext) updates) =>
+      super.copyWith((message) => updates(message as TraceContext))
+          as TraceContext;
+
+  @$core.override
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static TraceContext create() => TraceContext._();
+  @$core.override
+  TraceContext createEmptyInstance() => create();
+  @$core.pragma('dart2js:noInline')
+  static TraceContext getDefault() => _defaultInstance ??=
+      $pb.GeneratedMessage.$_defaultFor<TraceContext>(create);
+  static TraceContext? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get traceparent => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set traceparent($core.String value) => $_setString(0, value);
+  @$pb.TagNumber(1)
+  $core.bool hasTraceparent() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearTraceparent() => $_clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.String get tracestate => $_getSZ(1);
+  @$pb.TagNumber(2)
+  set tracestate($core.String value) => $_setString(1, value);
+  @$pb.TagNumber(2)
+  $core.bool hasTracestate() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearTracestate() => $_clearField(2);
+}
+
+/// CallResponse is the response envelope of InvokeBackendCall: the response
+/// or error of a unary call together with the header and trailer metadata
+/// its handler set (grpc.SetHeader, grpc.SetTrailer).
+class CallResponse extends $pb.GeneratedMessage {
+  factory CallResponse({
+    $core.List<$core.int>? payload,
+    Error? error,
+    $core.Iterable<MetadataEntry>? headers,
+    $core.Iterable<MetadataEntry>? trailers,
+  }) {
+    final result = create();
+    if (payload != null) result.payload = payload;
+    if (error != null) result.error = error;
+    if (headers != null) result.headers.addAll(headers);
+    if (trailers != null) result.trailers.addAll(trailers);
+    return result;
+  }
+
+  CallResponse._();
+
+  factory CallResponse.fromBuffer($core.List<$core.int> data,
+          [$pb.ExtensionRegistry registry = $pb.ExtensionRegistry.EMPTY]) =>
+      create()..mergeFromBuffer(data, registry);
+  factory CallResponse.fromJson($core.String json,
+          [$pb.ExtensionRegistry registry = $pb.ExtensionRegistry.EMPTY]) =>
+      create()..mergeFromJson(json, registry);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(
+      _omitMessageNames ? '' : 'CallResponse',
+      package: const $pb.PackageName(_omitMessageNames ? '' : 'core.v1'),
+      createEmptyInstance: create)
+    ..a<$core.List<$core.int>>(
+        1, _omitFieldNames ? '' : 'payload', $pb.PbFieldType.OY)
+    ..aOM<Error>(2, _omitFieldNames ? '' : 'error', subBuilder: Error.create)
+    ..pPM<MetadataEntry>(3, _omitFieldNames ? '' : 'headers',
+        subBuilder: MetadataEntry.create)
+    ..pPM<MetadataEntry>(4, _omitFieldNames ? '' : 'trailers',
+        subBuilder: MetadataEntry.create)
+    ..hasRequiredFields = false;
+
+  @$core.Deprecated('See https://github.com/google/protobuf.dart/issues/998.')
+  CallResponse clone() => deepCopy();
+  @$core.Deprecated('See https://github.com/google/protobuf.dart/issues/998.')
+  CallResponse copyWith(void Function(CallResponse) updates) =>
+      super.copyWith((message) => updates(message as CallResponse))
+          as CallResponse;
+
+  @$core.override
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static CallResponse create() => CallResponse._();
+  @$core.override
+  CallResponse createEmptyInstance() => create();
+  @$core.pragma('dart2js:noInline')
+  static CallResponse getDefault() => _defaultInstance ??=
+      $pb.GeneratedMessage.$_defaultFor<CallResponse>(create);
+  static CallResponse? _defaultInstance;
+
+  /// Serialized response message; empty when error is set.
+  @$pb.TagNumber(1)
+  $core.List<$core.int> get payload => $_getN(0);
+  @$pb.TagNumber(1)
+  set payload($core.List<$core.int> value) => $_setBytes(0, value);
+  @$pb.TagNumber(1)
+  $core.bool hasPayload() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearPayload() => $_clearField(1);
+
+  /// Set when the call failed.
+  @$pb.TagNumber(2)
+  Error get error => $_getN(1);
+  @$pb.TagNumber(2)
+  set error(Error value) => $_setField(2, value);
+  @$pb.TagNumber(2)
+  $core.bool hasError() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearError() => $_clearField(2);
+  @$pb.TagNumber(2)
+  Error ensureError() => $_ensure(1);
+
+  /// Header metadata; keys ending in "-bin" carry binary values.
+  @$pb.TagNumber(3)
+  $pb.PbList<MetadataEntry> get headers => $_getList(2);
+
+  /// Trailer metadata, also returned for failed calls.
+  @$pb.TagNumber(4)
+  $pb.PbList<MetadataEntry> get trailers => $_getList(3);
+}
+
+/// ListenerStatus is the state of one endpoint the engine was configured to
+/// serve on.
+class ListenerStatus extends $pb.GeneratedMessage {
+  factory ListenerStatus({
+    $core.String? network,
+    $core.String? address,
+    $core.String? boundAddress,
+    Error? error,
+    $core.String? tlsFingerprint,
+  }) {
+    final result = create();
+    if (network != null) result.network = network;
+    if (address != null) result.address = address;
+    if (boundAddress != null) result.boundAddress = boundAddress;
+    if (error != null) result.error = error;
+    if (tlsFingerprint != null) result.tlsFingerprint = tlsFingerprint;
+    return result;
+  }
+
+  ListenerStatus._();
+
+  factory ListenerStatus.fromBuffer($core.List<$core.int> data,
+          [$pb.ExtensionRegistry registry = $pb.ExtensionRegistry.EMPTY]) =>
+      create()..mergeFromBuffer(data, registry);
+  factory ListenerStatus.fromJson($core.String json,
+          [$pb.ExtensionRegistry registry = $pb.ExtensionRegistry.EMPTY]) =>
+      create()..mergeFromJson(json, registry);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(
+      _omitMessageNames ? '' : 'ListenerStatus',
+      package: const $pb.PackageName(_omitMessageNames ? '' : 'core.v1'),
+      createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'network')
+    ..aOS(2, _omitFieldNames ? '' : 'address')
+    ..aOS(3, _omitFieldNames ? '' : 'boundAddress')
+    ..aOM<Error>(4, _omitFieldNames ? '' : 'error', subBuilder: Error.create)
+    ..aOS(5, _omitFieldNames ? '' : 'tlsFingerprint')
+    ..hasRequiredFields = false;
+
+  @$core.Deprecated('See https://github.com/google/protobuf.dart/issues/998.')
+  ListenerStatus clone() => deepCopy();
+  @$core.Deprecated('See https://github.com/google/protobuf.dart/issues/998.')
+  ListenerStatus copyWith(void Function(ListenerStatus) updates) =>
+      super.copyWith((message) => updates(message as ListenerStatus))
+          as ListenerStatus;
+
+  @$core.override
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static ListenerStatus create() => ListenerStatus._();
+  @$core.override
+  ListenerStatus createEmptyInstance() => create();
+  @$core.pragma('dart2js:noInline')
+  static ListenerStatus getDefault() => _defaultInstance ??=
+      $pb.GeneratedMessage.$_defaultFor<ListenerStatus>(create);
+  static ListenerStatus? _defaultInstance;
+
+  /// "unix" or "tcp".
+  @$pb.TagNumber(1)
+  $core.String get network => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set network($core.String value) => $_setString(0, value);
+  @$pb.TagNumber(1)
+  $core.bool hasNetwork() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearNetwork() => $_clearField(1);
+
+  /// Address as configured.
+  @$pb.TagNumber(2)
+  $core.String get address => $_getSZ(1);
+  @$pb.TagNumber(2)
+  set address($core.String value) => $_setString(1, value);
+  @$pb.TagNumber(2)
+  $core.bool hasAddress() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearAddress() => $_clearField(2);
+
+  /// Address actually bound; empty if listening failed.
+  @$pb.TagNumber(3)
+  $core.String get boundAddress => $_getSZ(2);
+  @$pb.TagNumber(3)
+  set boundAddress($core.String value) => $_setString(2, value);
+  @$pb.TagNumber(3)
+  $core.bool hasBoundAddress() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearBoundAddress() => $_clearField(3);
+
+  /// Why listening failed, or the error that later ended serving.
+  @$pb.TagNumber(4)
+  Error get error => $_getN(3);
+  @$pb.TagNumber(4)
+  set error(Error value) => $_setField(4, value);
+  @$pb.TagNumber(4)
+  $core.bool hasError() => $_has(3);
+  @$pb.TagNumber(4)
+  void clearError() => $_clearField(4);
+  @$pb.TagNumber(4)
+  Error ensureError() => $_ensure(3);
+
+  /// SHA-256 fingerprint of the TLS certificate served, as colon-separated
+  /// hex; empty for a plaintext endpoint.
+  @$pb.TagNumber(5)
+  $core.String get tlsFingerprint => $_getSZ(4);
+  @$pb.TagNumber(5)
+  set tlsFingerprint($core.String value) => $_setString(4, value);
+  @$pb.TagNumber(5)
+  $core.bool hasTlsFingerprint() => $_has(4);
+  @$pb.TagNumber(5)
+  void clearTlsFingerprint() => $_clearField(5);
+}
+
+/// ServerStatus is the result of starting an engine and, when queried later,
+/// its current state.
+class ServerStatus extends $pb.GeneratedMessage {
+  factory ServerStatus({
+    $core.bool? running,
+    $core.Iterable<ListenerStatus>? listeners,
+    $core.bool? cacheEnabled,
+    Error? cacheError,
+    Error? error,
+  }) {
+    final result = create();
+    if (running != null) result.running = running;
+    if (listeners != null) result.listeners.addAll(listeners);
+    if (cacheEnabled != null) result.cacheEnabled = cacheEnabled;
+    if (cacheError != null) result.cacheError = cacheError;
+    if (error != null) result.error = error;
+    return result;
+  }
+
+  ServerStatus._();
+
+  factory ServerStatus.fromBuffer($core.List<$core.int> data,
+          [$pb.ExtensionRegistry registry = $pb.ExtensionRegistry.EMPTY]) =>
+      create()..mergeFromBuffer(data, registry);
+  factory ServerStatus.fromJson($core.String json,
+          [$pb.ExtensionRegistry registry = $pb.ExtensionRegistry.EMPTY]) =>
+      create()..mergeFromJson(json, registry);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(
+      _omitMessageNames ? '' : 'ServerStatus',
+      package: const $pb.PackageName(_omitMessageNames ? '' : 'core.v1'),
+      createEmptyInstance: create)
+    ..aOB(1, _omitFieldNames ? '' : 'running')
+    ..pPM<ListenerStatus>(2, _omitFieldNames ? '' : 'listeners',
+        subBuilder: ListenerStatus.create)
+    ..aOB(3, _omitFieldNames ? '' : 'cacheEnabled')
+    ..aOM<Error>(4, _omitFieldNames ? '' : 'cacheError',
+        subBuilder: Error.create)
+    ..aOM<Error>(5, _omitFieldNames ? '' : 'error', subBuilder: Error.create)
+    ..hasRequiredFields = false;
+
+  @$core.Deprecated('See https://github.com/google/protobuf.dart/issues/998.')
+  ServerStatus clone() => deepCopy();
+  @$core.Deprecated('See https://github.com/google/protobuf.dart/issues/998.')
+  ServerStatus copyWith(void Function(ServerStatus) updates) =>
+      super.copyWith((message) => updates(message as ServerStatus))
+          as ServerStatus;
+
+  @$core.override
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static ServerStatus create() => ServerStatus._();
+  @$core.override
+  ServerStatus createEmptyInstance() => create();
+  @$core.pragma('dart2js:noInline')
+  static ServerStatus getDefault() => _defaultInstance ??=
+      $pb.GeneratedMessage.$_defaultFor<ServerStatus>(create);
+  static ServerStatus? _defaultInstance;
+
+  /// Whether the engine is running.
+  @$pb.TagNumber(1)
+  $core.bool get running => $_getBF(0);
+  @$pb.TagNumber(1)
+  set running($core.bool value) => $_setBool(0, value);
+  @$pb.TagNumber(1)
+  $core.bool hasRunning() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearRunning() => $_clearField(1);
+
+  /// One entry per configured endpoint.
+  @$pb.TagNumber(2)
+  $pb.PbList<ListenerStatus> get listeners => $_getList(1);
+
+  /// Whether the cache service is available.
+  @$pb.TagNumber(3)
+  $core.bool get cacheEnabled => $_getBF(2);
+  @$pb.TagNumber(3)
+  set cacheEnabled($core.bool value) => $_setBool(2, value);
+  @$pb.TagNumber(3)
+  $core.bool hasCacheEnabled() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearCacheEnabled() => $_clearField(3);
+
+  /// Why the cache could not be opened, if it was requested.
+  @$pb.TagNumber(4)
+  Error get cacheError => $_getN(3);
+  @$pb.TagNumber(4)
+  set cacheError(Error value) => $_setField(4, value);
+  @$pb.TagNumber(4)
+  $core.bool hasCacheError() => $_has(3);
+  @$pb.TagNumber(4)
+  void clearCacheError() => $_clearField(4);
+  @$pb.TagNumber(4)
+  Error ensureCacheError() => $_ensure(3);
+
+  /// Why the engine did not start.
+  @$pb.TagNumber(5)
+  Error get error => $_getN(4);
+  @$pb.TagNumber(5)
+  set error(Error value) => $_setField(5, value);
+  @$pb.TagNumber(5)
+  $core.bool hasError() => $_has(4);
+  @$pb.TagNumber(5)
+  void clearError() => $_clearField(5);
+  @$pb.TagNumber(5)
+  Error ensureError() => $_ensure(4);
+}
+
+/// ShutdownReport lists, by method, what stopping an engine terminated
+/// because it was still running at the drain deadline. All fields are
+/// empty after a clean stop.
+class ShutdownReport extends $pb.GeneratedMessage {
+  factory ShutdownReport({
+    $core.Iterable<$core.String>? rpcs,
+    $core.Iterable<$core.String>? ffiCalls,
+    $core.Iterable<$core.String>? streams,
+    $core.Iterable<$core.String>? dartCalls,
+  }) {
+    final result = create();
+    if (rpcs != null) result.rpcs.addAll(rpcs);
+    if (ffiCalls != null) result.ffiCalls.addAll(ffiCalls);
+    if (streams != null) result.streams.addAll(streams);
+    if (dartCalls != null) result.dartCalls.addAll(dartCalls);
+    return result;
+  }
+
+  ShutdownReport._();
+
+  factory ShutdownReport.fromBuffer($core.List<$core.int> data,
+          [$pb.ExtensionRegistry registry = $pb.ExtensionRegistry.EMPTY]) =>
+      create()..mergeFromBuffer(data, registry);
+  factory ShutdownReport.fromJson($core.String json,
+          [$pb.ExtensionRegistry registry = $pb.ExtensionRegistry.EMPTY]) =>
+      create()..mergeFromJson(json, registry);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(
+      _omitMessageNames ? '' : 'ShutdownReport',
+      package: const $pb.PackageName(_omitMessageNames ? '' : 'core.v1'),
+      createEmptyInstance: create)
+    ..pPS(1, _omitFieldNames ? '' : 'rpcs')
+    ..pPS(2, _omitFieldNames ? '' : 'ffiCalls')
+    ..pPS(3, _omitFieldNames ? '' : 'streams')
+    ..pPS(4, _omitFieldNames ? '' : 'dartCalls')
+    ..hasRequiredFields = false;
+
+  @$core.Deprecated('See https://github.com/google/protobuf.dart/issues/998.')
+  ShutdownReport clone() => deepCopy();
+  @$core.Deprecated('See https://github.com/google/protobuf.dart/issues/998.')
+  ShutdownReport copyWith(void Function(ShutdownReport) updates) =>
+      super.copyWith((message) => updates(message as ShutdownReport))
+          as ShutdownReport;
+
+  @$core.override
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static ShutdownReport create() => ShutdownReport._();
+  @$core.override
+  ShutdownReport createEmptyInstance() => create();
+  @$core.pragma('dart2js:noInline')
+  static ShutdownReport getDefault() => _defaultInstance ??=
+      $pb.GeneratedMessage.$_defaultFor<ShutdownReport>(create);
+  static ShutdownReport? _defaultInstance;
+
+  /// gRPC calls over TCP/UDS, cancelled.
+  @$pb.TagNumber(1)
+  $pb.PbList<$core.String> get rpcs => $_getList(0);
+
+  /// Unary FFI calls, their contexts cancelled.
+  @$pb.TagNumber(2)
+  $pb.PbList<$core.String> get ffiCalls => $_getList(1);
+
+  /// FFI stream sessions, closed.
+  @$pb.TagNumber(3)
+  $pb.PbList<$core.String> get streams => $_getList(2);
+
+  /// Go to Dart callback requests, failed with UNAVAILABLE.
+  @$pb.TagNumber(4)
+  $pb.PbList<$core.String> get dartCalls => $_getList(3);
+}
+
+/// ServiceList lists the services an engine serves, as returned by the
+/// ListServices FFI export.
+class ServiceList extends $pb.GeneratedMessage {
+  factory ServiceList({
+    $core.Iterable<ServiceInfo>? services,
+  }) {
+    final result = create();
+    if (services != null) result.services.addAll(services);
+    return result;
+  }
+
+  ServiceList._();
+
+  factory ServiceList.fromBuffer($core.List<$core.int> data,
+          [$pb.ExtensionRegistry registry = $pb.ExtensionRegistry.EMPTY]) =>
+      create()..mergeFromBuffer(data, registry);
+  factory ServiceList.fromJson($core.String json,
+          [$pb.ExtensionRegistry registry = $pb.ExtensionRegistry.EMPTY]) =>
+      create()..mergeFromJson(json, registry);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(
+      _omitMessageNames ? '' : 'ServiceList',
+      package: const $pb.PackageName(_omitMessageNames ? '' : 'core.v1'),
+      createEmptyInstance: create)
+    ..pPM<ServiceInfo>(1, _omitFieldNames ? '' : 'services',
+        subBuilder: ServiceInfo.create)
+    ..hasRequiredFields = false;
+
+  @$core.Deprecated('See https://github.com/google/protobuf.dart/issues/998.')
+  ServiceList clone() => deepCopy();
+  @$core.Deprecated('See https://github.com/google/protobuf.dart/issues/998.')
+  ServiceList copyWith(void Function(ServiceList) updates) =>
+      super.copyWith((message) => updates(message as ServiceList))
+          as ServiceList;
+
+  @$core.override
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static ServiceList create() => ServiceList._();
+  @$core.override
+  ServiceList createEmptyInstance() => create();
+  @$core.pragma('dart2js:noInline')
+  static ServiceList getDefault() => _defaultInstance ??=
+      $pb.GeneratedMessage.$_defaultFor<ServiceList>(create);
+  static ServiceList? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $pb.PbList<ServiceInfo> get services => $_getList(0);
+}
+
+/// ServiceInfo describes one service.
+class ServiceInfo extends $pb.GeneratedMessage {
+  factory ServiceInfo({
+    $core.String? name,
+    $core.Iterable<MethodInfo>? methods,
+    $core.String? source,
+  }) {
+    final result = create();
+    if (name != null) result.name = name;
+    if (methods != null) result.methods.addAll(methods);
+    if (source != null) result.source = source;
+    return result;
+  }
+
+  ServiceInfo._();
+
+  factory ServiceInfo.fromBuffer($core.List<$core.int> data,
+          [$pb.ExtensionRegistry registry = $pb.ExtensionRegistry.EMPTY]) =>
+      create()..mergeFromBuffer(data, registry);
+  factory ServiceInfo.fromJson($core.String json,
+          [$pb.ExtensionRegistry registry = $pb.ExtensionRegistry.EMPTY]) =>
+      create()..mergeFromJson(json, registry);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(
+      _omitMessageNames ? '' : 'ServiceInfo',
+      package: const $pb.PackageName(_omitMessageNames ? '' : 'core.v1'),
+      createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'name')
+    ..pPM<MethodInfo>(2, _omitFieldNames ? '' : 'methods',
+        subBuilder: MethodInfo.create)
+    ..aOS(3, _omitFieldNames ? '' : 'source')
+    ..hasRequiredFields = false;
+
+  @$core.Deprecated('See https://github.com/google/protobuf.dart/issues/998.')
+  ServiceInfo clone() => deepCopy();
+  @$core.Deprecated('See https://github.com/google/protobuf.dart/issues/998.')
+  ServiceInfo copyWith(void Function(ServiceInfo) updates) =>
+      super.copyWith((message) => updates(message as ServiceInfo))
+          as ServiceInfo;
+
+  @$core.override
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static ServiceInfo create() => ServiceInfo._();
+  @$core.override
+  ServiceInfo createEmptyInstance() => create();
+  @$core.pragma('dart2js:noInline')
+  static ServiceInfo getDefault() => _defaultInstance ??=
+      $pb.GeneratedMessage.$_defaultFor<ServiceInfo>(create);
+  static ServiceInfo? _defaultInstance;
+
+  /// Fully-qualified name, e.g. "core.v1.HealthService".
+  @$pb.TagNumber(1)
+  $core.String get name => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set name($core.String value) => $_setString(0, value);
+  @$pb.TagNumber(1)
+  $core.bool hasName() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearName() => $_clearField(1);
+
+  @$pb.TagNumber(2)
+  $pb.PbList<MethodInfo> get methods => $_getList(1);
+
+  /// "grpc" for services on the gRPC server; otherwise what registered the
+  /// descriptors of a service reachable only through FFI dispatch or a
+  /// plugin (e.g. "ffi" or the plugin path).
+  @$pb.TagNumber(3)
+  $core.String get source => $_getSZ(2);
+  @$pb.TagNumber(3)
+  set source($core.String value) => $_setString(2, value);
+  @$pb.TagNumber(3)
+  $core.bool hasSource() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearSource() => $_clearField(3);
+}
+
+/// MethodInfo describes one method of a service.
+class MethodInfo extends $pb.GeneratedMessage {
+  factory MethodInfo({
+    $core.String? name,
+    $core.String? inputType,
+    $core.String? outputType,
+    $core.bool? clientStreaming,
+    $core.bool? serverStreaming,
+  }) {
+    final result = create();
+    if (name != null) result.name = name;
+    if (inputType != null) result.inputType = inputType;
+    if (outputType != null) result.outputType = outputType;
+    if (clientStreaming != null) result.clientStreaming = clientStreaming;
+    if (serverStreaming != null) result.serverStreaming = serverStreaming;
+    return result;
+  }
+
+  MethodInfo._();
+
+  factory MethodInfo.fromBuffer($core.List<$core.int> data,
+          [$pb.ExtensionRegistry registry = $pb.ExtensionRegistry.EMPTY]) =>
+      create()..mergeFromBuffer(data, registry);
+  factory MethodInfo.fromJson($core.String json,
+          [$pb.ExtensionRegistry registry = $pb.ExtensionRegistry.EMPTY]) =>
+      create()..mergeFromJson(json, registry);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(
+      _omitMessageNames ? '' : 'MethodInfo',
+      package: const $pb.PackageName(_omitMessageNames ? '' : 'core.v1'),
+      createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'name')
+    ..aOS(2, _omitFieldNames ? '' : 'inputType')
+    ..aOS(3, _omitFieldNames ? '' : 'outputType')
+    ..aOB(4, _omitFieldNames ? '' : 'clientStreaming')
+    ..aOB(5, _omitFieldNames ? '' : 'serverStreaming')
+    ..hasRequiredFields = false;
+
+  @$core.Deprecated('See https://github.com/google/protobuf.dart/issues/998.')
+  MethodInfo clone() => deepCopy();
+  @$core.Deprecated('See https://github.com/google/protobuf.dart/issues/998.')
+  MethodInfo copyWith(void Function(MethodInfo) updates) =>
+      super.copyWith((message) => updates(message as MethodInfo)) as MethodInfo;
+
+  @$core.override
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static MethodInfo create() => MethodInfo._();
+  @$core.override
+  MethodInfo createEmptyInstance() => create();
+  @$core.pragma('dart2js:noInline')
+  static MethodInfo getDefault() => _defaultInstance ??=
+      $pb.GeneratedMessage.$_defaultFor<MethodInfo>(create);
+  static MethodInfo? _defaultInstance;
+
+  /// Method name within the service, e.g. "Ping".
+  @$pb.TagNumber(1)
+  $core.String get name => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set name($core.String value) => $_setString(0, value);
+  @$pb.TagNumber(1)
+  $core.bool hasName() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearName() => $_clearField(1);
+
+  /// Fully-qualified request and response message names.
+  @$pb.TagNumber(2)
+  $core.String get inputType => $_getSZ(1);
+  @$pb.TagNumber(2)
+  set inputType($core.String value) => $_setString(1, value);
+  @$pb.TagNumber(2)
+  $core.bool hasInputType() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearInputType() => $_clearField(2);
+
+  @$pb.TagNumber(3)
+  $core.String get outputType => $_getSZ(2);
+  @$pb.TagNumber(3)
+  set outputType($core.String value) => $_setString(2, value);
+  @$pb.TagNumber(3)
+  $core.bool hasOutputType() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearOutputType() => $_clearField(3);
+
+  @$pb.TagNumber(4)
+  $core.bool get clientStreaming => $_getBF(3);
+  @$pb.TagNumber(4)
+  set clientStreaming($core.bool value) => $_setBool(3, value);
+  @$pb.TagNumber(4)
+  $core.bool hasClientStreaming() => $_has(3);
+  @$pb.TagNumber(4)
+  void clearClientStreaming() => $_clearField(4);
+
+  @$pb.TagNumber(5)
+  $core.bool get serverStreaming => $_getBF(4);
+  @$pb.TagNumber(5)
+  set serverStreaming($core.bool value) => $_setBool(4, value);
+  @$pb.TagNumber(5)
+  $core.bool hasServerStreaming() => $_has(4);
+  @$pb.TagNumber(5)
+  void clearServerStreaming() => $_clearField(5);
+}
+
+/// MethodDescription is the result of the DescribeMethod FFI export.
+class MethodDescription extends $pb.GeneratedMessage {
+  factory MethodDescription({
+    MethodInfo? method,
+    $core.String? source,
+    $core.List<$core.int>? fileDescriptorSet,
+  }) {
+    final result = create();
+    if (method != null) result.method = method;
+    if (source != null) result.source = source;
+    if (fileDescriptorSet != null) result.fileDescriptorSet = fileDescriptorSet;
+    return result;
+  }
+
+  MethodDescription._();
+
+  factory MethodDescription.fromBuffer($core.List<$core.int> data,
+          [$pb.ExtensionRegistry registry = $pb.ExtensionRegistry.EMPTY]) =>
+      create()..mergeFromBuffer(data, registry);
+  factory MethodDescription.fromJson($core.String json,
+          [$pb.ExtensionRegistry registry = $pb.ExtensionRegistry.EMPTY]) =>
+      create()..mergeFromJson(json, registry);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(
+      _omitMessageNames ? '' : 'MethodDescription',
+      package: const $pb.PackageName(_omitMessageNames ? '' : 'core.v1'),
+      createEmptyInstance: create)
+    ..aOM<MethodInfo>(1, _omitFieldNames ? '' : 'method',
+        subBuilder: MethodInfo.create)
+    ..aOS(2, _omitFieldNames ? '' : 'source')
+    ..a<$core.List<$core.int>>(
+        3, _omitFieldNames ? '' : 'fileDescriptorSet', $pb.PbFieldType.OY)
+    ..hasRequiredFields = false;
+
+  @$core.Deprecated('See https://github.com/google/protobuf.dart/issues/998.')
+  MethodDescription clone() => deepCopy();
+  @$core.Deprecated('See https://github.com/google/protobuf.dart/issues/998.')
+  MethodDescription copyWith(void Function(MethodDescription) updates) =>
+      super.copyWith((message) => updates(message as MethodDescription))
+          as MethodDescription;
+
+  @$core.override
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static MethodDescription create() => MethodDescription._();
+  @$core.override
+  MethodDescription createEmptyInstance() => create();
+  @$core.pragma('dart2js:noInline')
+  static MethodDescription getDefault() => _defaultInstance ??=
+      $pb.GeneratedMessage.$_defaultFor<MethodDescription>(create);
+  static MethodDescription? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  MethodInfo get method => $_getN(0);
+  @$pb.TagNumber(1)
+  set method(MethodInfo value) => $_setField(1, value);
+  @$pb.TagNumber(1)
+  $core.bool hasMethod() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearMethod() => $_clearField(1);
+  @$pb.TagNumber(1)
+  MethodInfo ensureMethod() => $_ensure(0);
+
+  /// Source of the service, as in ServiceInfo.
+  @$pb.TagNumber(2)
+  $core.String get source => $_getSZ(1);
+  @$pb.TagNumber(2)
+  set source($core.String value) => $_setString(1, value);
+  @$pb.TagNumber(2)
+  $core.bool hasSource() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearSource() => $_clearField(2);
+
+  /// Serialized google.protobuf.FileDescriptorSet with the file declaring
+  /// the service and its imports, dependencies first.
+  @$pb.TagNumber(3)
+  $core.List<$core.int> get fileDescriptorSet => $_getN(2);
+  @$pb.TagNumber(3)
+  set fileDescriptorSet($core.List<$core.int> value) => $_setBytes(2, value);
+  @$pb.TagNumber(3)
+  $core.bool hasFileDescriptorSet() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearFileDescriptorSet() => $_clearField(3);
+}
+
+/// HealthReport is the readiness of an engine and its subsystems, as the
+/// grpc.health.v1.Health service reports it, returned by the GetHealth FFI
+/// export.
+class HealthReport extends $pb.GeneratedMessage {
+  factory HealthReport({
+    $core.String? status,
+    $core.Iterable<ServiceHealth>? services,
+  }) {
+    final result = create();
+    if (status != null) result.status = status;
+    if (services != null) result.services.addAll(services);
+    return result;
+  }
+
+  HealthReport._();
+
+  factory HealthReport.fromBuffer($core.List<$core.int> data,
+          [$pb.ExtensionRegistry registry = $pb.ExtensionRegistry.EMPTY]) =>
+      create()..mergeFromBuffer(data, registry);
+  factory HealthReport.fromJson($core.String json,
+          [$pb.ExtensionRegistry registry = $pb.ExtensionRegistry.EMPTY]) =>
+      create()..mergeFromJson(json, registry);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(
+      _omitMessageNames ? '' : 'HealthReport',
+      package: const $pb.PackageName(_omitMessageNames ? '' : 'core.v1'),
+      createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'status')
+    ..pPM<ServiceHealth>(2, _omitFieldNames ? '' : 'services',
+        subBuilder: ServiceHealth.create)
+    ..hasRequiredFields = false;
+
+  @$core.Deprecated('See https://github.com/google/protobuf.dart/issues/998.')
+  HealthReport clone() => deepCopy();
+  @$core.Deprecated('See https://github.com/google/protobuf.dart/issues/998.')
+  HealthReport copyWith(void Function(HealthReport) updates) =>
+      super.copyWith((message) => updates(message as HealthReport))
+          as HealthReport;
+
+  @$core.override
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static HealthReport create() => HealthReport._();
+  @$core.override
+  HealthReport createEmptyInstance() => create();
+  @$core.pragma('dart2js:noInline')
+  static HealthReport getDefault() => _defaultInstance ??=
+      $pb.GeneratedMessage.$_defaultFor<HealthReport>(create);
+  static HealthReport? _defaultInstance;
+
+  /// Status of the engine as a whole (service ""): "SERVING" only if every
+  /// service below is.
+  @$pb.TagNumber(1)
+  $core.String get status => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set status($core.String value) => $_setString(0, value);
+  @$pb.TagNumber(1)
+  $core.bool hasStatus() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearStatus() => $_clearField(1);
+
+  @$pb.TagNumber(2)
+  $pb.PbList<ServiceHealth> get services => $_getList(1);
+}
+
+/// ServiceHealth is the status of one service or subsystem of an engine.
+class ServiceHealth extends $pb.GeneratedMessage {
+  factory ServiceHealth({
+    $core.String? service,
+    $core.String? status,
+    $core.String? reason,
+  }) {
+    final result = create();
+    if (service != null) result.service = service;
+    if (status != null) result.status = status;
+    if (reason != null) result.reason = reason;
+    return result;
+  }
+
+  ServiceHealth._();
+
+  factory ServiceHealth.fromBuffer($core.List<$core.int> data,
+          [$pb.ExtensionRegistry registry = $pb.ExtensionRegistry.EMPTY]) =>
+      create()..mergeFromBuffer(data, registry);
+  factory ServiceHealth.fromJson($core.String json,
+          [$pb.ExtensionRegistry registry = $pb.ExtensionRegistry.EMPTY]) =>
+      create()..mergeFromJson(json, registry);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(
+      _omitMessageNames ? '' : 'ServiceHealth',
+      package: const $pb.PackageName(_omitMessageNames ? '' : 'core.v1'),
+      createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'service')
+    ..aOS(2, _omitFieldNames ? '' : 'status')
+    ..aOS(3, _omitFieldNames ? '' : 'reason')
+    ..hasRequiredFields = false;
+
+  @$core.Deprecated('See https://github.com/google/protobuf.dart/issues/998.')
+  ServiceHealth clone() => deepCopy();
+  @$core.Deprecated('See https://github.com/google/protobuf.dart/issues/998.')
+  ServiceHealth copyWith(void Function(ServiceHealth) updates) =>
+      super.copyWith((message) => updates(message as ServiceHealth))
+          as ServiceHealth;
+
+  @$core.override
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static ServiceHealth create() => ServiceHealth._();
+  @$core.override
+  ServiceHealth createEmptyInstance() => create();
+  @$core.pragma('dart2js:noInline')
+  static ServiceHealth getDefault() => _defaultInstance ??=
+      $pb.GeneratedMessage.$_defaultFor<ServiceHealth>(create);
+  static ServiceHealth? _defaultInstance;
+
+  /// Service name as passed to grpc.health.v1.Health/Check, e.g.
+  /// "core.v1.CacheService".
+  @$pb.TagNumber(1)
+  $core.String get service => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set service($core.String value) => $_setString(0, value);
+  @$pb.TagNumber(1)
+  $core.bool hasService() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearService() => $_clearField(1);
+
+  /// grpc.health.v1 serving status: "SERVING", "NOT_SERVING" or "UNKNOWN".
+  @$pb.TagNumber(2)
+  $core.String get status => $_getSZ(1);
+  @$pb.TagNumber(2)
+  set status($core.String value) => $_setString(1, value);
+  @$pb.TagNumber(2)
+  $core.bool hasStatus() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearStatus() => $_clearField(2);
+
+  /// Why the service is not serving, if known.
+  @$pb.TagNumber(3)
+  $core.String get reason => $_getSZ(2);
+  @$pb.TagNumber(3)
+  set reason($core.String value) => $_setString(2, value);
+  @$pb.TagNumber(3)
+  $core.bool hasReason() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearReason() => $_clearField(3);
+}
+
 const $core.bool _omitFieldNames =
     $core.bool.fromEnvironment('protobuf.omit_field_names');
 const $core.bool _omitMessageNames =
@@ -60,6 +60,24 @@ static void invoke_stream_callback(StreamCallback cb, long long streamId, char m
     }
 }
 
+// Completion callback signature (for InvokeBackendAsync)
+typedef void (*CallCompleteCallback)(long long callId, void* data, long long len);
+
+static void invoke_call_complete_callback(CallCompleteCallback cb, long long callId, void* data, long long len) {
+    if (cb) {
+        cb(callId, data, len);
+    }
+}
+
+// Serve error callback signature (serialized core.v1.ListenerStatus)
+typedef void (*ServeErrorCallback)(void* data, long long len);
+
+static void invoke_serve_error_callback(ServeErrorCallback cb, void* data, long long len) {
+    if (cb) {
+        cb(data, len);
+    }
+}
+
 #line 1 "cgo-generated-wrapper"
 
 
@@ -117,15 +135,34 @@ extern "C" {
 #endif
 
 extern int StartGrpcServer(struct CoreArgument cArg);
+extern FfiData StartGrpcServerWithStatus(struct CoreArgument cArg, int failOnListenError);
+extern FfiData GetServerStatus();
+extern FfiData ListServices();
+extern FfiData DescribeMethod(char* method);
+extern FfiData GetHealth();
+extern void RegisterServeErrorCallback(ServeErrorCallback callback);
 extern int StopGrpcServer();
+extern FfiData StopGrpcServerWithTimeout(long long int timeoutMs);
 extern FfiData InvokeBackend(char* method, void* data, long long int dataLen);
+extern FfiData InvokeBackendWithMeta(char* method, void* data, long long int dataLen, void* metaData, long long int metaLen);
+extern FfiData InvokeBackendWithHeader(char* method, void* data, long long int dataLen, void* header, long long int headerLen);
+extern FfiData InvokeBackendCall(char* method, void* data, long long int dataLen, void* header, long long int headerLen);
+extern int InvokeBackendAsync(long long int callId, char* method, void* data, long long int dataLen, void* header, long long int headerLen);
+extern void CancelBackendCall(long long int callId);
+extern void RegisterCallCompleteCallback(CallCompleteCallback callback);
 extern void FreeFfiData(void* data);
 extern void RegisterDartCallback(InvokeDartCallback callback);
 extern void SendFfiResponse(long long int requestId, void* data, long long int dataLen);
 extern void RegisterStreamCallback(StreamCallback callback);
 extern long long int InvokeBackendServerStream(char* method, void* data, long long int dataLen);
+extern long long int InvokeBackendServerStreamWithMeta(char* method, void* data, long long int dataLen, void* metaData, long long int metaLen);
+extern long long int InvokeBackendServerStreamWithHeader(char* method, void* data, long long int dataLen, void* header, long long int headerLen);
 extern long long int InvokeBackendClientStream(char* method);
+extern long long int InvokeBackendClientStreamWithMeta(char* method, void* metaData, long long int metaLen);
+extern long long int InvokeBackendClientStreamWithHeader(char* method, void* header, long long int headerLen);
 extern long long int InvokeBackendBidiStream(char* method);
+extern long long int InvokeBackendBidiStreamWithMeta(char* method, void* metaData, long long int metaLen);
+extern long long int InvokeBackendBidiStreamWithHeader(char* method, void* header, long long int headerLen);
 extern int SendStreamData(long long int streamId, void* data, long long int dataLen);
 extern void CloseStream(long long int streamId);
 extern void CloseStreamInput(long long int streamId);
@@ -7,6 +7,7 @@ require (
 	github.com/mattn/go-sqlite3 v1.14.33
 	google.golang.org/grpc v1.68.1
 	google.golang.org/protobuf v1.36.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -0,0 +1,118 @@
+// Package descset loads service definitions for the synurang command-line
+// tools, either from FileDescriptorSet files or by running protoc on .proto
+// sources.
+package descset
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// SplitList splits a comma-separated flag value, dropping empty entries.
+func SplitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// Load reads FileDescriptorSet files (protoc --include_imports
+// --descriptor_set_out) and merges them into one registry.
+func Load(paths []string) (*protoregistry.Files, error) {
+	set := &descriptorpb.FileDescriptorSet{}
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		part := &descriptorpb.FileDescriptorSet{}
+		if err := proto.Unmarshal(data, part); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		for _, f := range part.File {
+			if !seen[f.GetName()] {
+				seen[f.GetName()] = true
+				set.File = append(set.File, f)
+			}
+		}
+	}
+	return protodesc.NewFiles(set)
+}
+
+// Compile runs protoc on the given .proto files and returns the resulting
+// descriptor set path. The caller removes the file when done.
+func Compile(protoFiles, importPaths []string) (string, error) {
+	out, err := os.CreateTemp("", "synurang-*.desc")
+	if err != nil {
+		return "", err
+	}
+	out.Close()
+
+	args := []string{"--include_imports", "--descriptor_set_out=" + out.Name()}
+	for _, dir := range importPaths {
+		args = append(args, "-I"+dir)
+	}
+	if len(importPaths) == 0 {
+		// Default to each file's own directory, like protoc users usually do
+		seen := make(map[string]bool)
+		for _, f := range protoFiles {
+			dir := filepath.Dir(f)
+			if !seen[dir] {
+				seen[dir] = true
+				args = append(args, "-I"+dir)
+			}
+		}
+	}
+	args = append(args, protoFiles...)
+
+	cmd := exec.Command("protoc", args...)
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		os.Remove(out.Name())
+		return "", fmt.Errorf("protoc failed: %w", err)
+	}
+	return out.Name(), nil
+}
+
+// Services returns the services in files whose full or short name is listed
+// in names, or all services if names is empty.
+func Services(files *protoregistry.Files, names []string) ([]protoreflect.ServiceDescriptor, error) {
+	var all []protoreflect.ServiceDescriptor
+	files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		for i := 0; i < fd.Services().Len(); i++ {
+			all = append(all, fd.Services().Get(i))
+		}
+		return true
+	})
+	if len(names) == 0 {
+		return all, nil
+	}
+
+	var selected []protoreflect.ServiceDescriptor
+	for _, name := range names {
+		found := false
+		for _, sd := range all {
+			if string(sd.FullName()) == name || string(sd.Name()) == name {
+				selected = append(selected, sd)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("service %s not found in descriptor sets", name)
+		}
+	}
+	return selected, nil
+}
@@ -45,12 +45,26 @@ class HealthServiceClient extends $grpc.Client {
     return $createUnaryCall(_$ping, request, options: options);
   }
 
+  /// Status of the engine serving this call, with the addresses its
+  /// listeners actually bound (e.g. for EngineTcpPort "0").
+  $grpc.ResponseFuture<$1.ServerStatus> getServerStatus(
+    $0.Empty request, {
+    $grpc.CallOptions? options,
+  }) {
+    return $createUnaryCall(_$getServerStatus, request, options: options);
+  }
+
   // method descriptors
 
   static final _$ping = $grpc.ClientMethod<$0.Empty, $1.PingResponse>(
       '/core.v1.HealthService/Ping',
       ($0.Empty value) => value.writeToBuffer(),
       $1.PingResponse.fromBuffer);
+  static final _$getServerStatus =
+      $grpc.ClientMethod<$0.Empty, $1.ServerStatus>(
+          '/core.v1.HealthService/GetServerStatus',
+          ($0.Empty value) => value.writeToBuffer(),
+          $1.ServerStatus.fromBuffer);
 }
 
 @$pb.GrpcServiceName('core.v1.HealthService')
@@ -65,6 +79,13 @@ abstract class HealthServiceBase extends $grpc.Service {
         false,
         ($core.List<$core.int> value) => $0.Empty.fromBuffer(value),
         ($1.PingResponse value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.Empty, $1.ServerStatus>(
+        'GetServerStatus',
+        getServerStatus_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.Empty.fromBuffer(value),
+        ($1.ServerStatus value) => value.writeToBuffer()));
   }
 
   $async.Future<$1.PingResponse> ping_Pre(
@@ -73,6 +94,14 @@ abstract class HealthServiceBase extends $grpc.Service {
   }
 
   $async.Future<$1.PingResponse> ping($grpc.ServiceCall call, $0.Empty request);
+
+  $async.Future<$1.ServerStatus> getServerStatus_Pre(
+      $grpc.ServiceCall $call, $async.Future<$0.Empty> $request) async {
+    return getServerStatus($call, await $request);
+  }
+
+  $async.Future<$1.ServerStatus> getServerStatus(
+      $grpc.ServiceCall call, $0.Empty request);
 }
 
 /// =============================================================================
@@ -186,3 +186,324 @@ const Error$json = {
 final $typed_data.Uint8List errorDescriptor = $convert.base64Decode(
     'CgVFcnJvchISCgRjb2RlGAEgASgFUgRjb2RlEhgKB21lc3NhZ2UYAiABKAlSB21lc3NhZ2USGw'
     'oJZ3JwY19jb2RlGAMgASgFUghncnBjQ29kZQ==');
+
+@$core.Deprecated('Use callHeaderDescriptor instead')
+const CallHeader$json = {
+  '1': 'CallHeader',
+  '2': [
+    {'1': 'version', '3': 1, '4': 1, '5': 13, '10': 'version'},
+    {
+      '1': 'metadata',
+      '3': 2,
+      '4': 3,
+      '5': 11,
+      '6': '.core.v1.MetadataEntry',
+      '10': 'metadata'
+    },
+    {'1': 'timeout_ms', '3': 3, '4': 1, '5': 3, '10': 'timeoutMs'},
+    {'1': 'call_id', '3': 4, '4': 1, '5': 4, '10': 'callId'},
+    {
+      '1': 'trace',
+      '3': 5,
+      '4': 1,
+      '5': 11,
+      '6': '.core.v1.TraceContext',
+      '10': 'trace'
+    },
+  ],
+};
+
+/// Descriptor for `CallHeader`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List callHeaderDescriptor = $convert.base64Decode(
+    'CgpDYWxsSGVhZGVyEhgKB3ZlcnNpb24YASABKA1SB3ZlcnNpb24SMgoIbWV0YWRhdGEYAiADKA'
+    'syFi5jb3JlLnYxLk1ldGFkYXRhRW50cnlSCG1ldGFkYXRhEh0KCnRpbWVvdXRfbXMYAyABKANS'
+    'CXRpbWVvdXRNcxIXCgdjYWxsX2lkGAQgASgEUgZjYWxsSWQSKwoFdHJhY2UYBSABKAsyFS5jb3'
+    'JlLnYxLlRyYWNlQ29udGV4dFIFdHJhY2U=');
+
+@$core.Deprecated('Use metadataEntryDescriptor instead')
+const MetadataEntry$json = {
+  '1': 'MetadataEntry',
+  '2': [
+    {'1': 'key', '3': 1, '4': 1, '5': 9, '10': 'key'},
+    {'1': 'value', '3': 2, '4': 1, '5': 12, '10': 'value'},
+  ],
+};
+
+/// Descriptor for `MetadataEntry`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List metadataEntryDescriptor = $convert.base64Decode(
+    'Cg1NZXRhZGF0YUVudHJ5EhAKA2tleRgBIAEoCVIDa2V5EhQKBXZhbHVlGAIgASgMUgV2YWx1ZQ'
+    '==');
+
+@$core.Deprecated('Use traceContextDescriptor instead')
+const TraceContext$json = {
+  '1': 'TraceContext',
+  '2': [
+    {'1': 'traceparent', '3': 1, '4': 1, '5': 9, '10': 'traceparent'},
+    {'1': 'tracestate', '3': 2, '4': 1, '5': 9, '10': 'tracestate'},
+  ],
+};
+
+/// Descriptor for `TraceContext`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List traceContextDescriptor = $convert.base64Decode(
+    'CgxUcmFjZUNvbnRleHQSIAoLdHJhY2VwYXJlbnQYASABKAlSC3RyYWNlcGFyZW50Eh4KCnRyYW'
+    'Nlc3RhdGUYAiABKAlSCnRyYWNlc3RhdGU=');
+
+@$core.Deprecated('Use callResponseDescriptor instead')
+const CallResponse$json = {
+  '1': 'CallResponse',
+  '2': [
+    {'1': 'payload', '3': 1, '4': 1, '5': 12, '10': 'payload'},
+    {
+      '1': 'error',
+      '3': 2,
+      '4': 1,
+      '5': 11,
+      '6': '.core.v1.Error',
+      '10': 'error'
+    },
+    {
+      '1': 'headers',
+      '3': 3,
+      '4': 3,
+      '5': 11,
+      '6': '.core.v1.MetadataEntry',
+      '10': 'headers'
+    },
+    {
+      '1': 'trailers',
+      '3': 4,
+      '4': 3,
+      '5': 11,
+      '6': '.core.v1.MetadataEntry',
+      '10': 'trailers'
+    },
+  ],
+};
+
+/// Descriptor for `CallResponse`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List callResponseDescriptor = $convert.base64Decode(
+    'CgxDYWxsUmVzcG9uc2USGAoHcGF5bG9hZBgBIAEoDFIHcGF5bG9hZBIkCgVlcnJvchgCIAEoCz'
+    'IOLmNvcmUudjEuRXJyb3JSBWVycm9yEjAKB2hlYWRlcnMYAyADKAsyFi5jb3JlLnYxLk1ldGFk'
+    'YXRhRW50cnlSB2hlYWRlcnMSMgoIdHJhaWxlcnMYBCADKAsyFi5jb3JlLnYxLk1ldGFkYXRhRW'
+    '50cnlSCHRyYWlsZXJz');
+
+@$core.Deprecated('Use listenerStatusDescriptor instead')
+const ListenerStatus$json = {
+  '1': 'ListenerStatus',
+  '2': [
+    {'1': 'network', '3': 1, '4': 1, '5': 9, '10': 'network'},
+    {'1': 'address', '3': 2, '4': 1, '5': 9, '10': 'address'},
+    {'1': 'bound_address', '3': 3, '4': 1, '5': 9, '10': 'boundAddress'},
+    {
+      '1': 'error',
+      '3': 4,
+      '4': 1,
+      '5': 11,
+      '6': '.core.v1.Error',
+      '10': 'error'
+    },
+    {'1': 'tls_fingerprint', '3': 5, '4': 1, '5': 9, '10': 'tlsFingerprint'},
+  ],
+};
+
+/// Descriptor for `ListenerStatus`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List listenerStatusDescriptor = $convert.base64Decode(
+    'Cg5MaXN0ZW5lclN0YXR1cxIYCgduZXR3b3JrGAEgASgJUgduZXR3b3JrEhgKB2FkZHJlc3MYAi'
+    'ABKAlSB2FkZHJlc3MSIwoNYm91bmRfYWRkcmVzcxgDIAEoCVIMYm91bmRBZGRyZXNzEiQKBWVy'
+    'cm9yGAQgASgLMg4uY29yZS52MS5FcnJvclIFZXJyb3ISJwoPdGxzX2ZpbmdlcnByaW50GAUgAS'
+    'gJUg50bHNGaW5nZXJwcmludA==');
+
+@$core.Deprecated('Use serverStatusDescriptor instead')
+const ServerStatus$json = {
+  '1': 'ServerStatus',
+  '2': [
+    {'1': 'running', '3': 1, '4': 1, '5': 8, '10': 'running'},
+    {
+      '1': 'listeners',
+      '3': 2,
+      '4': 3,
+      '5': 11,
+      '6': '.core.v1.ListenerStatus',
+      '10': 'listeners'
+    },
+    {'1': 'cache_enabled', '3': 3, '4': 1, '5': 8, '10': 'cacheEnabled'},
+    {
+      '1': 'cache_error',
+      '3': 4,
+      '4': 1,
+      '5': 11,
+      '6': '.core.v1.Error',
+      '10': 'cacheError'
+    },
+    {
+      '1': 'error',
+      '3': 5,
+      '4': 1,
+      '5': 11,
+      '6': '.core.v1.Error',
+      '10': 'error'
+    },
+  ],
+};
+
+/// Descriptor for `ServerStatus`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List serverStatusDescriptor = $convert.base64Decode(
+    'CgxTZXJ2ZXJTdGF0dXMSGAoHcnVubmluZxgBIAEoCFIHcnVubmluZxI1CglsaXN0ZW5lcnMYAi'
+    'ADKAsyFy5jb3JlLnYxLkxpc3RlbmVyU3RhdHVzUglsaXN0ZW5lcnMSIwoNY2FjaGVfZW5hYmxl'
+    'ZBgDIAEoCFIMY2FjaGVFbmFibGVkEi8KC2NhY2hlX2Vycm9yGAQgASgLMg4uY29yZS52MS5Fcn'
+    'JvclIKY2FjaGVFcnJvchIkCgVlcnJvchgFIAEoCzIOLmNvcmUudjEuRXJyb3JSBWVycm9y');
+
+@$core.Deprecated('Use shutdownReportDescriptor instead')
+const ShutdownReport$json = {
+  '1': 'ShutdownReport',
+  '2': [
+    {'1': 'rpcs', '3': 1, '4': 3, '5': 9, '10': 'rpcs'},
+    {'1': 'ffi_calls', '3': 2, '4': 3, '5': 9, '10': 'ffiCalls'},
+    {'1': 'streams', '3': 3, '4': 3, '5': 9, '10': 'streams'},
+    {'1': 'dart_calls', '3': 4, '4': 3, '5': 9, '10': 'dartCalls'},
+  ],
+};
+
+/// Descriptor for `ShutdownReport`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List shutdownReportDescriptor = $convert.base64Decode(
+    'Cg5TaHV0ZG93blJlcG9ydBISCgRycGNzGAEgAygJUgRycGNzEhsKCWZmaV9jYWxscxgCIAMoCV'
+    'IIZmZpQ2FsbHMSGAoHc3RyZWFtcxgDIAMoCVIHc3RyZWFtcxIdCgpkYXJ0X2NhbGxzGAQgAygJ'
+    'UglkYXJ0Q2FsbHM=');
+
+@$core.Deprecated('Use serviceListDescriptor instead')
+const ServiceList$json = {
+  '1': 'ServiceList',
+  '2': [
+    {
+      '1': 'services',
+      '3': 1,
+      '4': 3,
+      '5': 11,
+      '6': '.core.v1.ServiceInfo',
+      '10': 'services'
+    },
+  ],
+};
+
+/// Descriptor for `ServiceList`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List serviceListDescriptor = $convert.base64Decode(
+    'CgtTZXJ2aWNlTGlzdBIwCghzZXJ2aWNlcxgBIAMoCzIULmNvcmUudjEuU2VydmljZUluZm9SCH'
+    'NlcnZpY2Vz');
+
+@$core.Deprecated('Use serviceInfoDescriptor instead')
+const ServiceInfo$json = {
+  '1': 'ServiceInfo',
+  '2': [
+    {'1': 'name', '3': 1, '4': 1, '5': 9, '10': 'name'},
+    {
+      '1': 'methods',
+      '3': 2,
+      '4': 3,
+      '5': 11,
+      '6': '.core.v1.MethodInfo',
+      '10': 'methods'
+    },
+    {'1': 'source', '3': 3, '4': 1, '5': 9, '10': 'source'},
+  ],
+};
+
+/// Descriptor for `ServiceInfo`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List serviceInfoDescriptor = $convert.base64Decode(
+    'CgtTZXJ2aWNlSW5mbxISCgRuYW1lGAEgASgJUgRuYW1lEi0KB21ldGhvZHMYAiADKAsyEy5jb3'
+    'JlLnYxLk1ldGhvZEluZm9SB21ldGhvZHMSFgoGc291cmNlGAMgASgJUgZzb3VyY2U=');
+
+@$core.Deprecated('Use methodInfoDescriptor instead')
+const MethodInfo$json = {
+  '1': 'MethodInfo',
+  '2': [
+    {'1': 'name', '3': 1, '4': 1, '5': 9, '10': 'name'},
+    {'1': 'input_type', '3': 2, '4': 1, '5': 9, '10': 'inputType'},
+    {'1': 'output_type', '3': 3, '4': 1, '5': 9, '10': 'outputType'},
+    {
+      '1': 'client_streaming',
+      '3': 4,
+      '4': 1,
+      '5': 8,
+      '10': 'clientStreaming'
+    },
+    {
+      '1': 'server_streaming',
+      '3': 5,
+      '4': 1,
+      '5': 8,
+      '10': 'serverStreaming'
+    },
+  ],
+};
+
+/// Descriptor for `MethodInfo`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List methodInfoDescriptor = $convert.base64Decode(
+    'CgpNZXRob2RJbmZvEhIKBG5hbWUYASABKAlSBG5hbWUSHQoKaW5wdXRfdHlwZRgCIAEoCVIJaW'
+    '5wdXRUeXBlEh8KC291dHB1dF90eXBlGAMgASgJUgpvdXRwdXRUeXBlEikKEGNsaWVudF9zdHJl'
+    'YW1pbmcYBCABKAhSD2NsaWVudFN0cmVhbWluZxIpChBzZXJ2ZXJfc3RyZWFtaW5nGAUgASgIUg'
+    '9zZXJ2ZXJTdHJlYW1pbmc=');
+
+@$core.Deprecated('Use methodDescriptionDescriptor instead')
+const MethodDescription$json = {
+  '1': 'MethodDescription',
+  '2': [
+    {
+      '1': 'method',
+      '3': 1,
+      '4': 1,
+      '5': 11,
+      '6': '.core.v1.MethodInfo',
+      '10': 'method'
+    },
+    {'1': 'source', '3': 2, '4': 1, '5': 9, '10': 'source'},
+    {
+      '1': 'file_descriptor_set',
+      '3': 3,
+      '4': 1,
+      '5': 12,
+      '10': 'fileDescriptorSet'
+    },
+  ],
+};
+
+/// Descriptor for `MethodDescription`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List methodDescriptionDescriptor = $convert.base64Decode(
+    'ChFNZXRob2REZXNjcmlwdGlvbhIrCgZtZXRob2QYASABKAsyEy5jb3JlLnYxLk1ldGhvZEluZm'
+    '9SBm1ldGhvZBIWCgZzb3VyY2UYAiABKAlSBnNvdXJjZRIuChNmaWxlX2Rlc2NyaXB0b3Jfc2V0'
+    'GAMgASgMUhFmaWxlRGVzY3JpcHRvclNldA==');
+
+@$core.Deprecated('Use healthReportDescriptor instead')
+const HealthReport$json = {
+  '1': 'HealthReport',
+  '2': [
+    {'1': 'status', '3': 1, '4': 1, '5': 9, '10': 'status'},
+    {
+      '1': 'services',
+      '3': 2,
+      '4': 3,
+      '5': 11,
+      '6': '.core.v1.ServiceHealth',
+      '10': 'services'
+    },
+  ],
+};
+
+/// Descriptor for `HealthReport`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List healthReportDescriptor = $convert.base64Decode(
+    'CgxIZWFsdGhSZXBvcnQSFgoGc3RhdHVzGAEgASgJUgZzdGF0dXMSMgoIc2VydmljZXMYAiADKA'
+    'syFi5jb3JlLnYxLlNlcnZpY2VIZWFsdGhSCHNlcnZpY2Vz');
+
+@$core.Deprecated('Use serviceHealthDescriptor instead')
+const ServiceHealth$json = {
+  '1': 'ServiceHealth',
+  '2': [
+    {'1': 'service', '3': 1, '4': 1, '5': 9, '10': 'service'},
+    {'1': 'status', '3': 2, '4': 1, '5': 9, '10': 'status'},
+    {'1': 'reason', '3': 3, '4': 1, '5': 9, '10': 'reason'},
+  ],
+};
+
+/// Descriptor for `ServiceHealth`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List serviceHealthDescriptor = $convert.base64Decode(
+    'Cg1TZXJ2aWNlSGVhbHRoEhgKB3NlcnZpY2UYASABKAlSB3NlcnZpY2USFgoGc3RhdHVzGAIgAS'
+    'gJUgZzdGF0dXMSFgoGcmVhc29uGAMgASgJUgZyZWFzb24=');
@@ -14,6 +14,12 @@ class HealthServiceFfi {
     return PingResponse.fromBuffer(resultBytes);
   }
 
+  static Future<ServerStatus> GetServerStatus(Empty request) async {
+    final bytes = request.writeToBuffer();
+    final resultBytes = await synurang.invokeBackendAsync('/core.v1.HealthService/GetServerStatus', bytes);
+    return ServerStatus.fromBuffer(resultBytes);
+  }
+
 }
 
 class CacheServiceFfi {
@@ -16,6 +16,8 @@
 //
 // Threading:
 // - Unary RPCs:     Helper isolate (via _CoreIsolateManager)
+// - Async unary:    Go goroutine, completed on the main isolate (via
+//                   NativeCallable.listener callback)
 // - Stream init:    Helper isolate (via _CoreIsolateManager)
 // - Stream data:    Main isolate (via NativeCallable.listener callback)
 // - Cache ops:      Helper isolate (via _CoreIsolateManager)
@@ -48,6 +50,7 @@ import 'dart:typed_data';
 import 'dart:developer' as developer;
 
 import 'package:ffi/ffi.dart';
+import 'package:fixnum/fixnum.dart';
 import 'package:grpc/grpc.dart';
 import 'package:protobuf/protobuf.dart' show GeneratedMessage;
 import 'package:protobuf/well_known_types/google/protobuf/any.pb.dart' as pb_any;
@@ -76,7 +79,11 @@ class FfiError implements Exception {
   final String message;
   final int grpcCode;
 
-  const FfiError(this.message, this.grpcCode);
+  /// Trailer metadata the handler set before failing (see
+  /// [invokeBackendCallAsync]).
+  final Map<String, String> trailers;
+
+  const FfiError(this.message, this.grpcCode, [this.trailers = const {}]);
 
   @override
   String toString() => 'FfiError($grpcCode): $message';
@@ -97,6 +104,8 @@ class _StartRequest {
   final String token;
   final bool enableCache;
   final int streamTimeout;
+  final bool withStatus; // StartGrpcServerWithStatus
+  final bool failOnListenError;
 
   const _StartRequest(
       this.id,
@@ -108,12 +117,27 @@ class _StartRequest {
       this.viewTcpPort,
       this.token,
       this.enableCache,
-      this.streamTimeout);
+      this.streamTimeout,
+      {this.withStatus = false,
+      this.failOnListenError = false});
+}
+
+class _ServerStatusResponse {
+  final int id;
+  final Uint8List status; // serialized core.v1.ServerStatus
+  const _ServerStatusResponse(this.id, this.status);
 }
 
 class _StopRequest {
   final int id;
-  const _StopRequest(this.id);
+  final int? timeoutMs; // StopGrpcServerWithTimeout
+  const _StopRequest(this.id, {this.timeoutMs});
+}
+
+class _ShutdownReportResponse {
+  final int id;
+  final Uint8List report; // serialized core.v1.ShutdownReport
+  const _ShutdownReportResponse(this.id, this.report);
 }
 
 class _Response {
@@ -129,13 +153,27 @@ class _InvokeBackendRequest {
   const _InvokeBackendRequest(this.id, this.method, this.data);
 }
 
-class _InvokeBackendWithMetaRequest {
+class _InvokeBackendWithHeaderRequest {
+  final int id;
+  final String method;
+  final Uint8List data;
+  final Uint8List header; // serialized core.v1.CallHeader
+  const _InvokeBackendWithHeaderRequest(
+      this.id, this.method, this.data, this.header);
+}
+
+class _InvokeBackendCallRequest {
   final int id;
   final String method;
   final Uint8List data;
-  final Uint8List? metadata; // key=value\n encoded
-  const _InvokeBackendWithMetaRequest(
-      this.id, this.method, this.data, this.metadata);
+  final Uint8List? header; // serialized core.v1.CallHeader
+  const _InvokeBackendCallRequest(this.id, this.method, this.data, this.header);
+}
+
+class _InvokeBackendCallResponse {
+  final int id;
+  final FfiCallResult result;
+  const _InvokeBackendCallResponse(this.id, this.result);
 }
 
 class _InvokeBackendResponse {
@@ -212,19 +250,22 @@ class _ServerStreamRequest {
   final int id;
   final String method;
   final Uint8List data;
-  const _ServerStreamRequest(this.id, this.method, this.data);
+  final Uint8List? header; // serialized core.v1.CallHeader
+  const _ServerStreamRequest(this.id, this.method, this.data, this.header);
 }
 
 class _ClientStreamRequest {
   final int id;
   final String method;
-  const _ClientStreamRequest(this.id, this.method);
+  final Uint8List? header;
+  const _ClientStreamRequest(this.id, this.method, this.header);
 }
 
 class _BidiStreamRequest {
   final int id;
   final String method;
-  const _BidiStreamRequest(this.id, this.method);
+  final Uint8List? header;
+  const _BidiStreamRequest(this.id, this.method, this.header);
 }
 
 class _StreamIdResponse {
@@ -260,11 +301,17 @@ void resetCoreState() {
 /// All parameters are optional with sensible defaults:
 /// - [storagePath]: Path for persistent storage (default: empty)
 /// - [cachePath]: Path for cache database (default: empty, cache disabled)
-/// - [engineSocketPath]: Unix domain socket path for gRPC (default: empty)
-/// - [engineTcpPort]: TCP port for gRPC server (default: empty)
+/// - [engineSocketPath]: Unix domain socket path for gRPC (default: empty).
+///   `auto` creates a uniquely named socket in the temp directory, `@` a
+///   Linux abstract socket.
+/// - [engineTcpPort]: TCP port for gRPC server (default: empty). `0` picks a
+///   free port; [startGrpcServerWithStatusAsync] and [getServerStatus]
+///   report the addresses actually bound.
 /// - [viewSocketPath]: Unix domain socket for view service (default: empty)
 /// - [viewTcpPort]: TCP port for view service (default: empty)
-/// - [token]: Authentication token (default: empty)
+/// - [token]: Authentication token (default: empty). When set, FFI calls
+///   from this isolate carry it automatically, as the Go side checks it on
+///   every transport.
 Future<int> startGrpcServerAsync({
   String storagePath = '',
   String cachePath = '',
@@ -276,6 +323,7 @@ Future<int> startGrpcServerAsync({
   bool enableCache = false,
   int streamTimeout = 0,
 }) async {
+  _ffiToken = token;
   return _CoreIsolateManager.instance.sendRequest<int>((id) => _StartRequest(
       id,
       storagePath,
@@ -289,16 +337,158 @@ Future<int> startGrpcServerAsync({
       streamTimeout));
 }
 
+/// Start the Go gRPC server like [startGrpcServerAsync], returning which
+/// endpoints were bound (with their actual addresses) or failed, the cache
+/// state and, in [pb.ServerStatus.error], why the server did not start.
+///
+/// With [failOnListenError] an endpoint that cannot be bound fails the start
+/// instead of being skipped.
+Future<pb.ServerStatus> startGrpcServerWithStatusAsync({
+  String storagePath = '',
+  String cachePath = '',
+  String engineSocketPath = '',
+  String engineTcpPort = '',
+  String viewSocketPath = '',
+  String viewTcpPort = '',
+  String token = '',
+  bool enableCache = false,
+  int streamTimeout = 0,
+  bool failOnListenError = false,
+}) async {
+  final status = await _CoreIsolateManager.instance
+      .sendRequest<pb.ServerStatus>((id) => _StartRequest(
+          id,
+          storagePath,
+          cachePath,
+          engineSocketPath,
+          engineTcpPort,
+          viewSocketPath,
+          viewTcpPort,
+          token,
+          enableCache,
+          streamTimeout,
+          withStatus: true,
+          failOnListenError: failOnListenError));
+  if (!status.hasError()) _ffiToken = token;
+  return status;
+}
+
+/// Current state of the Go gRPC server, including the errors that ended a
+/// listener after it started.
+pb.ServerStatus getServerStatus() {
+  final ffiData = _ffi.GetServerStatus();
+  if (ffiData.data == nullptr) return pb.ServerStatus();
+  try {
+    return pb.ServerStatus.fromBuffer(
+        ffiData.data.cast<Uint8>().asTypedList(ffiData.len));
+  } finally {
+    _ffi.FreeFfiData(ffiData.data);
+  }
+}
+
+/// Every service of the Go engine: those served over gRPC and those only
+/// reachable through FFI dispatch or plugins, as server reflection describes
+/// them.
+pb.ServiceList listServices() {
+  final ffiData = _ffi.ListServices();
+  if (ffiData.data == nullptr) return pb.ServiceList();
+  try {
+    return pb.ServiceList.fromBuffer(
+        ffiData.data.cast<Uint8>().asTypedList(ffiData.len));
+  } finally {
+    _ffi.FreeFfiData(ffiData.data);
+  }
+}
+
+/// Description of [method] (`/package.Service/Method`), with the
+/// FileDescriptorSet declaring it if known, or null if no service has it.
+pb.MethodDescription? describeMethod(String method) {
+  final methodPtr = method.toNativeUtf8().cast<Char>();
+  final ffiData = _ffi.DescribeMethod(methodPtr);
+  calloc.free(methodPtr);
+  if (ffiData.data == nullptr) return null;
+  try {
+    return pb.MethodDescription.fromBuffer(
+        ffiData.data.cast<Uint8>().asTypedList(ffiData.len));
+  } finally {
+    _ffi.FreeFfiData(ffiData.data);
+  }
+}
+
+/// Readiness of the Go engine and of each service it depends on (the cache
+/// database, the view connection, loaded plugins), as its
+/// grpc.health.v1.Health service reports it. A `status` other than
+/// `SERVING` means the app runs in degraded mode; the services that are not
+/// serving carry the reason.
+pb.HealthReport getHealth() {
+  final ffiData = _ffi.GetHealth();
+  if (ffiData.data == nullptr) return pb.HealthReport();
+  try {
+    return pb.HealthReport.fromBuffer(
+        ffiData.data.cast<Uint8>().asTypedList(ffiData.len));
+  } finally {
+    _ffi.FreeFfiData(ffiData.data);
+  }
+}
+
+typedef ServeErrorCallbackNative = Void Function(
+    Pointer<Void> data, Int64 len);
+
+NativeCallable<ServeErrorCallbackNative>? _serveErrorHandle;
+StreamController<pb.ListenerStatus>? _serveErrors;
+
+/// Listeners of the Go gRPC server that stop serving after it started, e.g.
+/// because their socket was closed or removed.
+Stream<pb.ListenerStatus> get serveErrors {
+  _serveErrors ??= StreamController<pb.ListenerStatus>.broadcast(
+    onListen: () {
+      _serveErrorHandle =
+          NativeCallable<ServeErrorCallbackNative>.listener(_handleServeError);
+      _ffi.RegisterServeErrorCallback(_serveErrorHandle!.nativeFunction);
+    },
+    onCancel: () {
+      _ffi.RegisterServeErrorCallback(nullptr);
+      _serveErrorHandle?.close();
+      _serveErrorHandle = null;
+    },
+  );
+  return _serveErrors!.stream;
+}
+
+void _handleServeError(Pointer<Void> data, int len) {
+  if (data == nullptr) return;
+  try {
+    _serveErrors?.add(pb.ListenerStatus.fromBuffer(
+        Uint8List.fromList(data.cast<Uint8>().asTypedList(len))));
+  } finally {
+    _ffi.FreeFfiData(data);
+  }
+}
+
 /// Stop the Go gRPC server
 Future<int> stopGrpcServerAsync() async {
+  _ffiToken = '';
   return _CoreIsolateManager.instance
       .sendRequest<int>((id) => _StopRequest(id));
 }
 
+/// Stop the Go gRPC server gracefully: new calls are rejected, and the RPCs,
+/// FFI calls and streams and Go to Dart requests in progress may finish for
+/// [timeout] before they are terminated ([Duration.zero] terminates them at
+/// once). Returns what had to be terminated.
+Future<pb.ShutdownReport> shutdownGrpcServerAsync(
+    {Duration timeout = const Duration(seconds: 5)}) async {
+  _ffiToken = '';
+  return _CoreIsolateManager.instance.sendRequest<pb.ShutdownReport>(
+      (id) => _StopRequest(id, timeoutMs: timeout.inMilliseconds),
+      timeout: timeout + const Duration(seconds: 30));
+}
+
 /// Invoke a Go backend method via FFI.
 ///
 /// Optional parameters (zero-overhead when not used):
-/// - [metadata]: Request metadata (e.g., auth tokens)
+/// - [metadata]: Request metadata (e.g., auth tokens). Values of keys ending
+///   in `-bin` are base64, as with gRPC over the network.
 /// - [timeout]: Per-call timeout (deadline enforcement in Go)
 Future<Uint8List> invokeBackendAsync(
   String method,
@@ -306,26 +496,104 @@ Future<Uint8List> invokeBackendAsync(
   Map<String, String>? metadata,
   Duration? timeout,
 }) async {
-  // Fast path: no metadata or timeout
-  if (metadata == null && timeout == null) {
+  final header = _encodeCallHeader(metadata, timeout);
+
+  // Fast path: no metadata, timeout or token
+  if (header == null) {
     return _CoreIsolateManager.instance.sendRequest<Uint8List>(
         (id) => _InvokeBackendRequest(id, method, data));
   }
 
-  // Encode metadata as key=value\n format
-  final metaBuffer = StringBuffer();
+  return _CoreIsolateManager.instance.sendRequest<Uint8List>(
+      (id) => _InvokeBackendWithHeaderRequest(id, method, data, header));
+}
+
+/// Result of [invokeBackendCallAsync].
+class FfiCallResult {
+  /// Serialized response message.
+  final Uint8List data;
+
+  /// Header metadata the handler set (`grpc.SetHeader`).
+  final Map<String, String> headers;
+
+  /// Trailer metadata the handler set (`grpc.SetTrailer`).
+  final Map<String, String> trailers;
+
+  const FfiCallResult(this.data, this.headers, this.trailers);
+}
+
+/// Invoke a Go backend method via FFI, returning the response together with
+/// the header and trailer metadata its handler set, as a gRPC call over the
+/// network would. Failures throw [FfiError] with the trailers attached.
+///
+/// Takes the same optional parameters as [invokeBackendAsync].
+Future<FfiCallResult> invokeBackendCallAsync(
+  String method,
+  Uint8List data, {
+  Map<String, String>? metadata,
+  Duration? timeout,
+}) {
+  final header = _encodeCallHeader(metadata, timeout);
+  return _CoreIsolateManager.instance.sendRequest<FfiCallResult>(
+      (id) => _InvokeBackendCallRequest(id, method, data, header));
+}
+
+/// Unpack a core.v1.CallResponse, throwing [FfiError] if the call failed.
+FfiCallResult _callResult(pb.CallResponse response) {
+  final trailers = _decodeMetadata(response.trailers);
+  if (response.hasError()) {
+    throw FfiError(response.error.message, response.error.grpcCode, trailers);
+  }
+  return FfiCallResult(Uint8List.fromList(response.payload),
+      _decodeMetadata(response.headers), trailers);
+}
+
+/// Decode response metadata into the map form of grpc-dart. Values of `-bin`
+/// keys are base64 and repeated keys are joined with ",", as over HTTP/2.
+Map<String, String> _decodeMetadata(List<pb.MetadataEntry> entries) {
+  final metadata = <String, String>{};
+  for (final entry in entries) {
+    final value = entry.key.endsWith('-bin')
+        ? base64.encode(entry.value)
+        : utf8.decode(entry.value, allowMalformed: true);
+    metadata.update(entry.key, (v) => '$v,$value', ifAbsent: () => value);
+  }
+  return metadata;
+}
+
+/// Token passed to [startGrpcServerAsync], attached to FFI calls.
+String _ffiToken = '';
+
+/// Version of the core.v1.CallHeader layout written by this runtime.
+const int _callHeaderVersion = 1;
+
+/// Encode a serialized core.v1.CallHeader, adding the server token unless
+/// [metadata] carries its own authorization. Returns null if there is
+/// nothing to send.
+Uint8List? _encodeCallHeader(Map<String, String>? metadata,
+    [Duration? timeout]) {
+  final header = pb.CallHeader(version: _callHeaderVersion);
   if (timeout != null) {
-    metaBuffer.write('__timeout_ms=${timeout.inMilliseconds}\n');
+    header.timeoutMs = Int64(timeout.inMilliseconds);
   }
+  var hasAuth = false;
   if (metadata != null) {
     for (final entry in metadata.entries) {
-      metaBuffer.write('${entry.key}=${entry.value}\n');
+      final key = entry.key.toLowerCase();
+      hasAuth |= key == 'authorization';
+      header.metadata.add(pb.MetadataEntry(
+          key: key,
+          value: key.endsWith('-bin')
+              ? base64.decode(base64.normalize(entry.value))
+              : utf8.encode(entry.value)));
     }
   }
-  final metaBytes = Uint8List.fromList(utf8.encode(metaBuffer.toString()));
-
-  return _CoreIsolateManager.instance.sendRequest<Uint8List>(
-      (id) => _InvokeBackendWithMetaRequest(id, method, data, metaBytes));
+  if (_ffiToken.isNotEmpty && !hasAuth) {
+    header.metadata.add(pb.MetadataEntry(
+        key: 'authorization', value: utf8.encode('Bearer $_ffiToken')));
+  }
+  if (timeout == null && header.metadata.isEmpty) return null;
+  return header.writeToBuffer();
 }
 
 // =============================================================================
@@ -379,7 +647,9 @@ final Map<int, FFIServerStreamResult> _pendingStreamResults = {};
 /// Server streaming: Go sends multiple responses.
 /// Returns [FFIServerStreamResult] with stream and trailers access.
 FFIServerStreamResult invokeBackendServerStreamWithTrailers(
-    String method, Uint8List data) {
+    String method, Uint8List data,
+    {Map<String, String>? metadata}) {
+  final header = _encodeCallHeader(metadata);
   _ensureStreamCallbackRegistered();
   final controller = StreamController<Uint8List>();
   late FFIServerStreamResult result;
@@ -395,7 +665,8 @@ FFIServerStreamResult invokeBackendServerStreamWithTrailers(
   );
 
   _CoreIsolateManager.instance
-      .sendRequest<int>((id) => _ServerStreamRequest(id, method, data))
+      .sendRequest<int>(
+          (id) => _ServerStreamRequest(id, method, data, header))
       .then((int streamId) {
     if (streamId < 0) {
       controller.addError(Exception('Failed to start server stream'));
@@ -428,12 +699,17 @@ FFIServerStreamResult invokeBackendServerStreamWithTrailers(
 }
 
 /// Server streaming: Go sends multiple responses (simple API without trailers).
-Stream<Uint8List> invokeBackendServerStream(String method, Uint8List data) {
+///
+/// [metadata] reaches the Go handler as StreamSession.Metadata.
+Stream<Uint8List> invokeBackendServerStream(String method, Uint8List data,
+    {Map<String, String>? metadata}) {
   _ensureStreamCallbackRegistered();
+  final header = _encodeCallHeader(metadata);
   final controller = StreamController<Uint8List>();
 
   _CoreIsolateManager.instance
-      .sendRequest<int>((id) => _ServerStreamRequest(id, method, data))
+      .sendRequest<int>(
+          (id) => _ServerStreamRequest(id, method, data, header))
       .then((int streamId) {
     if (streamId < 0) {
       controller.addError(Exception('Failed to start server stream'));
@@ -463,13 +739,15 @@ Stream<Uint8List> invokeBackendServerStream(String method, Uint8List data) {
 /// Note: Stream initialization is performed on a helper isolate to avoid
 /// blocking the main UI thread.
 Future<Uint8List> invokeBackendClientStream(
-    String method, Stream<Uint8List> dataStream) async {
+    String method, Stream<Uint8List> dataStream,
+    {Map<String, String>? metadata}) async {
   _ensureStreamCallbackRegistered();
   final completer = Completer<Uint8List>();
+  final header = _encodeCallHeader(metadata);
 
   // Start the client stream on helper isolate (non-blocking)
   final int streamId = await _CoreIsolateManager.instance
-      .sendRequest<int>((id) => _ClientStreamRequest(id, method));
+      .sendRequest<int>((id) => _ClientStreamRequest(id, method, header));
 
   if (streamId < 0) {
     throw Exception('Failed to start client stream');
@@ -519,13 +797,15 @@ Future<Uint8List> invokeBackendClientStream(
 /// blocking the main UI thread. Stream data callbacks still run on the main
 /// isolate via NativeCallable.listener.
 Stream<Uint8List> invokeBackendBidiStream(
-    String method, Stream<Uint8List> dataStream) {
+    String method, Stream<Uint8List> dataStream,
+    {Map<String, String>? metadata}) {
   _ensureStreamCallbackRegistered();
   final controller = StreamController<Uint8List>();
+  final header = _encodeCallHeader(metadata);
 
   // Start the bidi stream on helper isolate (non-blocking)
   _CoreIsolateManager.instance
-      .sendRequest<int>((id) => _BidiStreamRequest(id, method))
+      .sendRequest<int>((id) => _BidiStreamRequest(id, method, header))
       .then((int streamId) {
     if (streamId < 0) {
       controller.addError(Exception('Failed to start bidi stream'));
@@ -850,6 +1130,20 @@ class _CoreIsolateManager {
       _completeZeroCopy(data.id, data.address, data.len);
       return;
     }
+    if (data is _InvokeBackendCallResponse) {
+      _completeRequest<FfiCallResult>(data.id, data.result);
+      return;
+    }
+    if (data is _ServerStatusResponse) {
+      _completeRequest<pb.ServerStatus>(
+          data.id, pb.ServerStatus.fromBuffer(data.status));
+      return;
+    }
+    if (data is _ShutdownReportResponse) {
+      _completeRequest<pb.ShutdownReport>(
+          data.id, pb.ShutdownReport.fromBuffer(data.report));
+      return;
+    }
     // Cache Responses
     if (data is _CacheGetResponse) {
       _completeZeroCopy(data.id, data.address, data.len, allowNull: true);
@@ -962,7 +1256,19 @@ void _handleIsolateMessage(dynamic data, SendPort sendPort) {
     cArg.ref.enableCache = data.enableCache ? 1 : 0;
     cArg.ref.streamTimeout = data.streamTimeout;
 
-    final int result = _ffi.StartGrpcServer(cArg.ref);
+    Uint8List? status;
+    int result = 0;
+    if (data.withStatus) {
+      final ffiData = _ffi.StartGrpcServerWithStatus(
+          cArg.ref, data.failOnListenError ? 1 : 0);
+      status = ffiData.data == nullptr
+          ? Uint8List(0)
+          : Uint8List.fromList(
+              ffiData.data.cast<Uint8>().asTypedList(ffiData.len));
+      if (ffiData.data != nullptr) _ffi.FreeFfiData(ffiData.data);
+    } else {
+      result = _ffi.StartGrpcServer(cArg.ref);
+    }
     calloc.free(cArg.ref.storagePath);
     calloc.free(cArg.ref.cachePath);
     calloc.free(cArg.ref.engineSocketPath);
@@ -972,10 +1278,22 @@ void _handleIsolateMessage(dynamic data, SendPort sendPort) {
     calloc.free(cArg.ref.token);
     calloc.free(cArg);
 
-    sendPort.send(_Response(data.id, result));
+    sendPort.send(status != null
+        ? _ServerStatusResponse(data.id, status)
+        : _Response(data.id, result));
     return;
   }
   if (data is _StopRequest) {
+    if (data.timeoutMs != null) {
+      final ffiData = _ffi.StopGrpcServerWithTimeout(data.timeoutMs!);
+      final report = ffiData.data == nullptr
+          ? Uint8List(0)
+          : Uint8List.fromList(
+              ffiData.data.cast<Uint8>().asTypedList(ffiData.len));
+      if (ffiData.data != nullptr) _ffi.FreeFfiData(ffiData.data);
+      sendPort.send(_ShutdownReportResponse(data.id, report));
+      return;
+    }
     final int result = _ffi.StopGrpcServer();
     sendPort.send(_Response(data.id, result));
     return;
@@ -1012,10 +1330,20 @@ void _handleIsolateMessage(dynamic data, SendPort sendPort) {
     }
     return;
   }
-  if (data is _InvokeBackendWithMetaRequest) {
+  if (data is _InvokeBackendCallRequest) {
+    try {
+      final response =
+          _invokeBackendCallRaw(data.method, data.data, data.header);
+      sendPort.send(_InvokeBackendCallResponse(data.id, _callResult(response)));
+    } catch (e) {
+      sendPort.send(_ErrorResponse(data.id, e));
+    }
+    return;
+  }
+  if (data is _InvokeBackendWithHeaderRequest) {
     try {
       final ffiData =
-          _invokeBackendWithMetaRaw(data.method, data.data, data.metadata);
+          _invokeBackendWithHeaderRaw(data.method, data.data, data.header);
 
       if (ffiData.data == nullptr) {
         sendPort.send(_InvokeBackendResponse(data.id, 0, 0));
@@ -1145,14 +1473,24 @@ void _handleIsolateMessage(dynamic data, SendPort sendPort) {
       final dataList = dataPtr.asTypedList(data.data.length);
       dataList.setAll(0, data.data);
 
-      final streamId = _ffi.InvokeBackendServerStream(
-        methodPtr.cast(),
-        dataPtr.cast(),
-        data.data.length,
-      );
+      final header = _copyToNative(data.header);
+      final streamId = header == null
+          ? _ffi.InvokeBackendServerStream(
+              methodPtr.cast(),
+              dataPtr.cast(),
+              data.data.length,
+            )
+          : _ffi.InvokeBackendServerStreamWithHeader(
+              methodPtr.cast(),
+              dataPtr.cast(),
+              data.data.length,
+              header.cast(),
+              data.header!.length,
+            );
 
       calloc.free(methodPtr);
       calloc.free(dataPtr);
+      if (header != null) calloc.free(header);
 
       sendPort.send(_StreamIdResponse(data.id, streamId));
     } catch (e) {
@@ -1163,8 +1501,13 @@ void _handleIsolateMessage(dynamic data, SendPort sendPort) {
   if (data is _ClientStreamRequest) {
     try {
       final methodPtr = data.method.toNativeUtf8();
-      final streamId = _ffi.InvokeBackendClientStream(methodPtr.cast());
+      final header = _copyToNative(data.header);
+      final streamId = header == null
+          ? _ffi.InvokeBackendClientStream(methodPtr.cast())
+          : _ffi.InvokeBackendClientStreamWithHeader(
+              methodPtr.cast(), header.cast(), data.header!.length);
       calloc.free(methodPtr);
+      if (header != null) calloc.free(header);
 
       sendPort.send(_StreamIdResponse(data.id, streamId));
     } catch (e) {
@@ -1175,8 +1518,13 @@ void _handleIsolateMessage(dynamic data, SendPort sendPort) {
   if (data is _BidiStreamRequest) {
     try {
       final methodPtr = data.method.toNativeUtf8();
-      final streamId = _ffi.InvokeBackendBidiStream(methodPtr.cast());
+      final header = _copyToNative(data.header);
+      final streamId = header == null
+          ? _ffi.InvokeBackendBidiStream(methodPtr.cast())
+          : _ffi.InvokeBackendBidiStreamWithHeader(
+              methodPtr.cast(), header.cast(), data.header!.length);
       calloc.free(methodPtr);
+      if (header != null) calloc.free(header);
 
       sendPort.send(_StreamIdResponse(data.id, streamId));
     } catch (e) {
@@ -1270,34 +1618,62 @@ FfiData _invokeBackendRaw(String method, Uint8List data) {
   return ffiData;
 }
 
-FfiData _invokeBackendWithMetaRaw(
-    String method, Uint8List data, Uint8List? metadata) {
+/// Copy [bytes] to native memory the caller frees, or null if there are none.
+Pointer<Uint8>? _copyToNative(Uint8List? bytes) {
+  if (bytes == null || bytes.isEmpty) return null;
+  final ptr = calloc<Uint8>(bytes.length);
+  ptr.asTypedList(bytes.length).setAll(0, bytes);
+  return ptr;
+}
+
+FfiData _invokeBackendWithHeaderRaw(
+    String method, Uint8List data, Uint8List header) {
   final methodPtr = method.toNativeUtf8().cast<Char>();
   final dataPtr = calloc<Uint8>(data.length);
   final dataList = dataPtr.asTypedList(data.length);
   dataList.setAll(0, data);
 
-  Pointer<Uint8> metaPtr = nullptr.cast();
-  int metaLen = 0;
-  if (metadata != null && metadata.isNotEmpty) {
-    metaPtr = calloc<Uint8>(metadata.length);
-    final metaList = metaPtr.asTypedList(metadata.length);
-    metaList.setAll(0, metadata);
-    metaLen = metadata.length;
-  }
-
-  final ffiData = _ffi.InvokeBackendWithMeta(methodPtr, dataPtr.cast<Void>(),
-      data.length, metaPtr.cast<Void>(), metaLen);
+  final headerPtr = _copyToNative(header);
+  final ffiData = _ffi.InvokeBackendWithHeader(methodPtr, dataPtr.cast<Void>(),
+      data.length, (headerPtr ?? nullptr).cast<Void>(), header.length);
 
   calloc.free(methodPtr);
   calloc.free(dataPtr);
-  if (metaLen > 0) calloc.free(metaPtr);
+  if (headerPtr != null) calloc.free(headerPtr);
   return ffiData;
 }
 
+pb.CallResponse _invokeBackendCallRaw(
+    String method, Uint8List data, Uint8List? header) {
+  final methodPtr = method.toNativeUtf8().cast<Char>();
+  final dataPtr = calloc<Uint8>(data.length);
+  final dataList = dataPtr.asTypedList(data.length);
+  dataList.setAll(0, data);
+
+  final headerPtr = _copyToNative(header);
+  final ffiData = _ffi.InvokeBackendCall(methodPtr, dataPtr.cast<Void>(),
+      data.length, (headerPtr ?? nullptr).cast<Void>(), header?.length ?? 0);
+
+  calloc.free(methodPtr);
+  calloc.free(dataPtr);
+  if (headerPtr != null) calloc.free(headerPtr);
+
+  if (ffiData.data == nullptr) return pb.CallResponse();
+  try {
+    // Copy out of C memory before parsing; the envelope is freed below
+    return pb.CallResponse.fromBuffer(Uint8List.fromList(
+        ffiData.data.cast<Uint8>().asTypedList(ffiData.len)));
+  } finally {
+    _ffi.FreeFfiData(ffiData.data);
+  }
+}
+
 /// Invoke Go backend synchronously (for main thread use)
 Uint8List invokeBackend(String method, Uint8List data) {
-  final ffiData = _invokeBackendRaw(method, data);
+  final header = _encodeCallHeader(null);
+  final ffiData = header == null
+      ? _invokeBackendRaw(method, data)
+      : _invokeBackendWithHeaderRaw(method, data, header);
   if (ffiData.data == nullptr) {
     return Uint8List(0);
   }
@@ -1387,6 +1763,96 @@ void registerDartHandler(DartHandler handler) {
   _ensureStreamCallbackRegistered();
 }
 
+// =============================================================================
+// Asynchronous Unary Calls (InvokeBackendAsync)
+// =============================================================================
+
+typedef CallCompleteCallbackNative = Void Function(
+    Int64 callId, Pointer<Void> data, Int64 len);
+
+NativeCallable<CallCompleteCallbackNative>? _callCompleteHandle;
+
+/// Calls started with [startBackendCall] awaiting their callback
+final Map<int, Completer<FfiCallResult>> _pendingCalls = {};
+int _nextCallId = 0;
+
+/// A unary call started with [startBackendCall].
+class FfiBackendCall {
+  /// Call ID, unique within the process.
+  final int id;
+
+  /// Completes with the response or fails with [FfiError]. A cancelled call
+  /// fails with [StatusCode.cancelled].
+  final Future<FfiCallResult> result;
+
+  FfiBackendCall._(this.id, this.result);
+
+  /// Cancel the handler's context in Go. [result] still completes, usually
+  /// with a cancellation error. No-op once the call has finished.
+  void cancel() => _ffi.CancelBackendCall(id);
+}
+
+/// Start a unary call that runs on its own goroutine in Go and completes
+/// through a callback, so no isolate is blocked while the handler runs and
+/// the call can be cancelled with [FfiBackendCall.cancel].
+///
+/// Takes the same optional parameters as [invokeBackendAsync].
+FfiBackendCall startBackendCall(
+  String method,
+  Uint8List data, {
+  Map<String, String>? metadata,
+  Duration? timeout,
+}) {
+  _ensureCallCompleteCallbackRegistered();
+  final id = ++_nextCallId;
+  final completer = Completer<FfiCallResult>();
+  _pendingCalls[id] = completer;
+
+  final header = _encodeCallHeader(metadata, timeout);
+  final methodPtr = method.toNativeUtf8().cast<Char>();
+  final dataPtr = _copyToNative(data);
+  final headerPtr = _copyToNative(header);
+  final rc = _ffi.InvokeBackendAsync(
+      id,
+      methodPtr,
+      (dataPtr ?? nullptr).cast<Void>(),
+      data.length,
+      (headerPtr ?? nullptr).cast<Void>(),
+      header?.length ?? 0);
+  calloc.free(methodPtr);
+  if (dataPtr != null) calloc.free(dataPtr);
+  if (headerPtr != null) calloc.free(headerPtr);
+
+  if (rc != 0) {
+    _pendingCalls.remove(id);
+    completer.completeError(const FfiError(
+        'InvokeBackendAsync rejected the call', StatusCode.internal));
+  }
+  return FfiBackendCall._(id, completer.future);
+}
+
+void _ensureCallCompleteCallbackRegistered() {
+  if (_callCompleteHandle != null) return;
+  _callCompleteHandle =
+      NativeCallable<CallCompleteCallbackNative>.listener(_handleCallComplete);
+  _ffi.RegisterCallCompleteCallback(_callCompleteHandle!.nativeFunction);
+}
+
+void _handleCallComplete(int callId, Pointer<Void> data, int len) {
+  final completer = _pendingCalls.remove(callId);
+  try {
+    final response = data == nullptr
+        ? pb.CallResponse()
+        : pb.CallResponse.fromBuffer(
+            Uint8List.fromList(data.cast<Uint8>().asTypedList(len)));
+    completer?.complete(_callResult(response));
+  } catch (e) {
+    completer?.completeError(e);
+  } finally {
+    if (data != nullptr) _ffi.FreeFfiData(data);
+  }
+}
+
 // =============================================================================
 // Stream Callback Registration (Go -> Dart streaming data)
 // =============================================================================
@@ -1525,6 +1991,129 @@ void _handleDartCallback(
   }
 }
 
+// =============================================================================
+// SynurangPlugin - Libraries loaded through the Synurang ABI (see ABI.md)
+// =============================================================================
+
+typedef _SynurangInvokeC = Pointer<Uint8> Function(
+    Pointer<Char> method, Pointer<Uint8> data, Int dataLen, Pointer<Int> respLen);
+typedef _SynurangInvokeDart = Pointer<Uint8> Function(
+    Pointer<Char> method, Pointer<Uint8> data, int dataLen, Pointer<Int> respLen);
+typedef _SynurangFreeC = Void Function(Pointer<Uint8> ptr);
+typedef _SynurangFreeDart = void Function(Pointer<Uint8> ptr);
+typedef _SynurangInitC = Pointer<Uint8> Function(
+    Pointer<Uint8> config, Int configLen, Pointer<Int> respLen);
+typedef _SynurangInitDart = Pointer<Uint8> Function(
+    Pointer<Uint8> config, int configLen, Pointer<Int> respLen);
+typedef _SynurangShutdownC = Int Function(LongLong timeoutMs);
+typedef _SynurangShutdownDart = int Function(int timeoutMs);
+
+/// A plugin or engine library driven through the unified Synurang ABI
+/// (`Synurang_Invoke`, `Synurang_Init`, ...), the same ABI Go hosts use.
+///
+/// Calls run synchronously on the calling isolate.
+///
+/// ```dart
+/// final plugin = SynurangPlugin.open('./libplugin.so');
+/// final resp = plugin.invoke('/pkg.Service/Method', request.writeToBuffer());
+/// plugin.close();
+/// ```
+class SynurangPlugin {
+  final DynamicLibrary _lib;
+  final _SynurangInvokeDart _invoke;
+  final _SynurangFreeDart _free;
+  final _SynurangShutdownDart? _shutdown;
+  bool _closed = false;
+
+  SynurangPlugin._(this._lib, this._invoke, this._free, this._shutdown);
+
+  /// Load the library at [path] and initialize it with [config] (for engine
+  /// libraries, the JSON encoding of the engine configuration).
+  factory SynurangPlugin.open(String path, {Uint8List? config}) {
+    final lib = DynamicLibrary.open(path);
+    final invoke = lib.lookupFunction<_SynurangInvokeC, _SynurangInvokeDart>(
+        'Synurang_Invoke');
+    final free =
+        lib.lookupFunction<_SynurangFreeC, _SynurangFreeDart>('Synurang_Free');
+    final shutdown = lib.providesSymbol('Synurang_Shutdown')
+        ? lib.lookupFunction<_SynurangShutdownC, _SynurangShutdownDart>(
+            'Synurang_Shutdown')
+        : null;
+    final plugin = SynurangPlugin._(lib, invoke, free, shutdown);
+
+    if (lib.providesSymbol('Synurang_Init')) {
+      final init =
+          lib.lookupFunction<_SynurangInitC, _SynurangInitDart>('Synurang_Init');
+      final data = config ?? Uint8List(0);
+      final dataPtr = calloc<Uint8>(data.isEmpty ? 1 : data.length);
+      dataPtr.asTypedList(data.length).setAll(0, data);
+      final respLen = calloc<Int>();
+      try {
+        plugin._takeResponse(init(dataPtr, data.length, respLen), respLen.value);
+      } finally {
+        calloc.free(dataPtr);
+        calloc.free(respLen);
+      }
+    }
+    return plugin;
+  }
+
+  /// Whether [symbol] is exported by the library.
+  bool providesSymbol(String symbol) => _lib.providesSymbol(symbol);
+
+  /// Invoke a unary method by its full name ("/pkg.Service/Method").
+  /// Throws [FfiError] if the library reports an error.
+  Uint8List invoke(String method, Uint8List data) {
+    if (_closed) {
+      throw const FfiError('plugin is closed', 9); // FAILED_PRECONDITION
+    }
+    final methodPtr = method.toNativeUtf8().cast<Char>();
+    final dataPtr = calloc<Uint8>(data.isEmpty ? 1 : data.length);
+    dataPtr.asTypedList(data.length).setAll(0, data);
+    final respLen = calloc<Int>();
+    try {
+      return _takeResponse(
+          _invoke(methodPtr, dataPtr, data.length, respLen), respLen.value);
+    } finally {
+      calloc.free(methodPtr);
+      calloc.free(dataPtr);
+      calloc.free(respLen);
+    }
+  }
+
+  /// Shut the library down (Synurang_Shutdown). The library itself stays
+  /// mapped, since Dart cannot unload a DynamicLibrary. Throws [FfiError]
+  /// if a shutdown hook failed or [timeout] expired.
+  void close({Duration timeout = const Duration(seconds: 5)}) {
+    if (_closed) return;
+    _closed = true;
+    final shutdown = _shutdown;
+    if (shutdown == null) return;
+    switch (shutdown(timeout.inMilliseconds)) {
+      case 1:
+        throw const FfiError('plugin shutdown failed', 2); // UNKNOWN
+      case 2:
+        throw const FfiError('plugin shutdown timed out', 4); // DEADLINE_EXCEEDED
+    }
+  }
+
+  /// Decode a [status:1][payload] response and free it with Synurang_Free.
+  Uint8List _takeResponse(Pointer<Uint8> ptr, int len) {
+    if (ptr == nullptr) return Uint8List(0);
+    try {
+      final bytes = ptr.asTypedList(len);
+      if (len == 0) return Uint8List(0);
+      final payload = Uint8List.fromList(bytes.sublist(1));
+      if (bytes[0] != 0) {
+        throw FfiError(utf8.decode(payload, allowMalformed: true), 2); // UNKNOWN
+      }
+      return payload;
+    } finally {
+      _free(ptr);
+    }
+  }
+}
+
 // =============================================================================
 // FfiClientChannel - gRPC ClientChannel implementation for FFI transport
 // =============================================================================
@@ -1576,6 +2165,7 @@ class _FfiClientCall<Q, R> extends ClientCall<Q, R> {
   final Stream<Q> _requests;
   final _headers = Completer<Map<String, String>>();
   final _trailers = Completer<Map<String, String>>();
+  FfiBackendCall? _call;
 
   _FfiClientCall(this._method, this._requests, CallOptions options)
       : super(_method, _requests, options);
@@ -1600,14 +2190,21 @@ class _FfiClientCall<Q, R> extends ClientCall<Q, R> {
       }
 
       final data = request.writeToBuffer();
-      final responseBytes = await invokeBackendAsync(_method.path, data);
-      final response = _method.responseDeserializer(responseBytes);
-
-      _headers.complete({});
-      _trailers.complete({});
+      final call = _call = startBackendCall(_method.path, data,
+          metadata: options.metadata.isEmpty ? null : options.metadata,
+          timeout: options.timeout);
+      final result = await call.result;
+      final response = _method.responseDeserializer(result.data);
+
+      _headers.complete(result.headers);
+      _trailers.complete(result.trailers);
       yield response;
     } catch (e) {
       if (!_headers.isCompleted) _headers.complete({});
+      if (e is FfiError) {
+        if (!_trailers.isCompleted) _trailers.complete(e.trailers);
+        throw GrpcError.custom(e.grpcCode, e.message, null, null, e.trailers);
+      }
       if (!_trailers.isCompleted) _trailers.complete({});
       if (e is GrpcError) {
         rethrow;
@@ -1617,7 +2214,7 @@ class _FfiClientCall<Q, R> extends ClientCall<Q, R> {
   }
 
   @override
-  Future<void> cancel() async {}
+  Future<void> cancel() async => _call?.cancel();
 
   @override
   Future<Map<String, String>> get headers => _headers.future;
@@ -36,6 +36,76 @@ class SynurangBindings {
   late final _StartGrpcServer =
       _StartGrpcServerPtr.asFunction<int Function(CoreArgument)>();
 
+  FfiData StartGrpcServerWithStatus(
+    CoreArgument cArg,
+    int failOnListenError,
+  ) {
+    return _StartGrpcServerWithStatus(
+      cArg,
+      failOnListenError,
+    );
+  }
+
+  late final _StartGrpcServerWithStatusPtr =
+      _lookup<ffi.NativeFunction<FfiData Function(CoreArgument, ffi.Int)>>(
+          'StartGrpcServerWithStatus');
+  late final _StartGrpcServerWithStatus = _StartGrpcServerWithStatusPtr
+      .asFunction<FfiData Function(CoreArgument, int)>();
+
+  FfiData GetServerStatus() {
+    return _GetServerStatus();
+  }
+
+  late final _GetServerStatusPtr =
+      _lookup<ffi.NativeFunction<FfiData Function()>>('GetServerStatus');
+  late final _GetServerStatus =
+      _GetServerStatusPtr.asFunction<FfiData Function()>();
+
+  FfiData ListServices() {
+    return _ListServices();
+  }
+
+  late final _ListServicesPtr =
+      _lookup<ffi.NativeFunction<FfiData Function()>>('ListServices');
+  late final _ListServices =
+      _ListServicesPtr.asFunction<FfiData Function()>();
+
+  FfiData DescribeMethod(
+    ffi.Pointer<ffi.Char> method,
+  ) {
+    return _DescribeMethod(
+      method,
+    );
+  }
+
+  late final _DescribeMethodPtr =
+      _lookup<ffi.NativeFunction<FfiData Function(ffi.Pointer<ffi.Char>)>>(
+          'DescribeMethod');
+  late final _DescribeMethod =
+      _DescribeMethodPtr.asFunction<FfiData Function(ffi.Pointer<ffi.Char>)>();
+
+  FfiData GetHealth() {
+    return _GetHealth();
+  }
+
+  late final _GetHealthPtr =
+      _lookup<ffi.NativeFunction<FfiData Function()>>('GetHealth');
+  late final _GetHealth = _GetHealthPtr.asFunction<FfiData Function()>();
+
+  void RegisterServeErrorCallback(
+    ServeErrorCallback callback,
+  ) {
+    return _RegisterServeErrorCallback(
+      callback,
+    );
+  }
+
+  late final _RegisterServeErrorCallbackPtr =
+      _lookup<ffi.NativeFunction<ffi.Void Function(ServeErrorCallback)>>(
+          'RegisterServeErrorCallback');
+  late final _RegisterServeErrorCallback = _RegisterServeErrorCallbackPtr
+      .asFunction<void Function(ServeErrorCallback)>();
+
   int StopGrpcServer() {
     return _StopGrpcServer();
   }
@@ -44,6 +114,20 @@ class SynurangBindings {
       _lookup<ffi.NativeFunction<ffi.Int Function()>>('StopGrpcServer');
   late final _StopGrpcServer = _StopGrpcServerPtr.asFunction<int Function()>();
 
+  FfiData StopGrpcServerWithTimeout(
+    int timeoutMs,
+  ) {
+    return _StopGrpcServerWithTimeout(
+      timeoutMs,
+    );
+  }
+
+  late final _StopGrpcServerWithTimeoutPtr =
+      _lookup<ffi.NativeFunction<FfiData Function(ffi.LongLong)>>(
+          'StopGrpcServerWithTimeout');
+  late final _StopGrpcServerWithTimeout =
+      _StopGrpcServerWithTimeoutPtr.asFunction<FfiData Function(int)>();
+
   FfiData InvokeBackend(
     ffi.Pointer<ffi.Char> method,
     ffi.Pointer<ffi.Void> data,
@@ -91,6 +175,121 @@ class SynurangBindings {
       FfiData Function(ffi.Pointer<ffi.Char>, ffi.Pointer<ffi.Void>, int,
           ffi.Pointer<ffi.Void>, int)>();
 
+  FfiData InvokeBackendWithHeader(
+    ffi.Pointer<ffi.Char> method,
+    ffi.Pointer<ffi.Void> data,
+    int dataLen,
+    ffi.Pointer<ffi.Void> header,
+    int headerLen,
+  ) {
+    return _InvokeBackendWithHeader(
+      method,
+      data,
+      dataLen,
+      header,
+      headerLen,
+    );
+  }
+
+  late final _InvokeBackendWithHeaderPtr = _lookup<
+      ffi.NativeFunction<
+          FfiData Function(
+              ffi.Pointer<ffi.Char>,
+              ffi.Pointer<ffi.Void>,
+              ffi.LongLong,
+              ffi.Pointer<ffi.Void>,
+              ffi.LongLong)>>('InvokeBackendWithHeader');
+  late final _InvokeBackendWithHeader = _InvokeBackendWithHeaderPtr.asFunction<
+      FfiData Function(ffi.Pointer<ffi.Char>, ffi.Pointer<ffi.Void>, int,
+          ffi.Pointer<ffi.Void>, int)>();
+
+  FfiData InvokeBackendCall(
+    ffi.Pointer<ffi.Char> method,
+    ffi.Pointer<ffi.Void> data,
+    int dataLen,
+    ffi.Pointer<ffi.Void> header,
+    int headerLen,
+  ) {
+    return _InvokeBackendCall(
+      method,
+      data,
+      dataLen,
+      header,
+      headerLen,
+    );
+  }
+
+  late final _InvokeBackendCallPtr = _lookup<
+      ffi.NativeFunction<
+          FfiData Function(
+              ffi.Pointer<ffi.Char>,
+              ffi.Pointer<ffi.Void>,
+              ffi.LongLong,
+              ffi.Pointer<ffi.Void>,
+              ffi.LongLong)>>('InvokeBackendCall');
+  late final _InvokeBackendCall = _InvokeBackendCallPtr.asFunction<
+      FfiData Function(ffi.Pointer<ffi.Char>, ffi.Pointer<ffi.Void>, int,
+          ffi.Pointer<ffi.Void>, int)>();
+
+  int InvokeBackendAsync(
+    int callId,
+    ffi.Pointer<ffi.Char> method,
+    ffi.Pointer<ffi.Void> data,
+    int dataLen,
+    ffi.Pointer<ffi.Void> header,
+    int headerLen,
+  ) {
+    return _InvokeBackendAsync(
+      callId,
+      method,
+      data,
+      dataLen,
+      header,
+      headerLen,
+    );
+  }
+
+  late final _InvokeBackendAsyncPtr = _lookup<
+      ffi.NativeFunction<
+          ffi.Int Function(
+              ffi.LongLong,
+              ffi.Pointer<ffi.Char>,
+              ffi.Pointer<ffi.Void>,
+              ffi.LongLong,
+              ffi.Pointer<ffi.Void>,
+              ffi.LongLong)>>('InvokeBackendAsync');
+  late final _InvokeBackendAsync = _InvokeBackendAsyncPtr.asFunction<
+      int Function(int, ffi.Pointer<ffi.Char>, ffi.Pointer<ffi.Void>, int,
+          ffi.Pointer<ffi.Void>, int)>();
+
+  void CancelBackendCall(
+    int callId,
+  ) {
+    return _CancelBackendCall(
+      callId,
+    );
+  }
+
+  late final _CancelBackendCallPtr =
+      _lookup<ffi.NativeFunction<ffi.Void Function(ffi.LongLong)>>(
+          'CancelBackendCall');
+  late final _CancelBackendCall =
+      _CancelBackendCallPtr.asFunction<void Function(int)>();
+
+  void RegisterCallCompleteCallback(
+    CallCompleteCallback callback,
+  ) {
+    return _RegisterCallCompleteCallback(
+      callback,
+    );
+  }
+
+  late final _RegisterCallCompleteCallbackPtr =
+      _lookup<ffi.NativeFunction<ffi.Void Function(CallCompleteCallback)>>(
+          'RegisterCallCompleteCallback');
+  late final _RegisterCallCompleteCallback = _RegisterCallCompleteCallbackPtr
+      .asFunction<void Function(CallCompleteCallback)>();
+
   void FreeFfiData(
     ffi.Pointer<ffi.Void> data,
   ) {
@@ -200,6 +399,144 @@ class SynurangBindings {
   late final _InvokeBackendBidiStream = _InvokeBackendBidiStreamPtr.asFunction<
       int Function(ffi.Pointer<ffi.Char>)>();
 
+  int InvokeBackendServerStreamWithMeta(
+    ffi.Pointer<ffi.Char> method,
+    ffi.Pointer<ffi.Void> data,
+    int dataLen,
+    ffi.Pointer<ffi.Void> metaData,
+    int metaLen,
+  ) {
+    return _InvokeBackendServerStreamWithMeta(
+      method,
+      data,
+      dataLen,
+      metaData,
+      metaLen,
+    );
+  }
+
+  late final _InvokeBackendServerStreamWithMetaPtr = _lookup<
+      ffi.NativeFunction<
+          ffi.LongLong Function(
+              ffi.Pointer<ffi.Char>,
+              ffi.Pointer<ffi.Void>,
+              ffi.LongLong,
+              ffi.Pointer<ffi.Void>,
+              ffi.LongLong)>>('InvokeBackendServerStreamWithMeta');
+  late final _InvokeBackendServerStreamWithMeta =
+      _InvokeBackendServerStreamWithMetaPtr.asFunction<
+          int Function(ffi.Pointer<ffi.Char>, ffi.Pointer<ffi.Void>, int,
+              ffi.Pointer<ffi.Void>, int)>();
+
+  int InvokeBackendClientStreamWithMeta(
+    ffi.Pointer<ffi.Char> method,
+    ffi.Pointer<ffi.Void> metaData,
+    int metaLen,
+  ) {
+    return _InvokeBackendClientStreamWithMeta(
+      method,
+      metaData,
+      metaLen,
+    );
+  }
+
+  late final _InvokeBackendClientStreamWithMetaPtr = _lookup<
+      ffi.NativeFunction<
+          ffi.LongLong Function(ffi.Pointer<ffi.Char>, ffi.Pointer<ffi.Void>,
+              ffi.LongLong)>>('InvokeBackendClientStreamWithMeta');
+  late final _InvokeBackendClientStreamWithMeta =
+      _InvokeBackendClientStreamWithMetaPtr.asFunction<
+          int Function(ffi.Pointer<ffi.Char>, ffi.Pointer<ffi.Void>, int)>();
+
+  int InvokeBackendBidiStreamWithMeta(
+    ffi.Pointer<ffi.Char> method,
+    ffi.Pointer<ffi.Void> metaData,
+    int metaLen,
+  ) {
+    return _InvokeBackendBidiStreamWithMeta(
+      method,
+      metaData,
+      metaLen,
+    );
+  }
+
+  late final _InvokeBackendBidiStreamWithMetaPtr = _lookup<
+      ffi.NativeFunction<
+          ffi.LongLong Function(ffi.Pointer<ffi.Char>, ffi.Pointer<ffi.Void>,
+              ffi.LongLong)>>('InvokeBackendBidiStreamWithMeta');
+  late final _InvokeBackendBidiStreamWithMeta =
+      _InvokeBackendBidiStreamWithMetaPtr.asFunction<
+          int Function(ffi.Pointer<ffi.Char>, ffi.Pointer<ffi.Void>, int)>();
+
+  int InvokeBackendServerStreamWithHeader(
+    ffi.Pointer<ffi.Char> method,
+    ffi.Pointer<ffi.Void> data,
+    int dataLen,
+    ffi.Pointer<ffi.Void> header,
+    int headerLen,
+  ) {
+    return _InvokeBackendServerStreamWithHeader(
+      method,
+      data,
+      dataLen,
+      header,
+      headerLen,
+    );
+  }
+
+  late final _InvokeBackendServerStreamWithHeaderPtr = _lookup<
+      ffi.NativeFunction<
+          ffi.LongLong Function(
+              ffi.Pointer<ffi.Char>,
+              ffi.Pointer<ffi.Void>,
+              ffi.LongLong,
+              ffi.Pointer<ffi.Void>,
+              ffi.LongLong)>>('InvokeBackendServerStreamWithHeader');
+  late final _InvokeBackendServerStreamWithHeader =
+      _InvokeBackendServerStreamWithHeaderPtr.asFunction<
+          int Function(ffi.Pointer<ffi.Char>, ffi.Pointer<ffi.Void>, int,
+              ffi.Pointer<ffi.Void>, int)>();
+
+  int InvokeBackendClientStreamWithHeader(
+    ffi.Pointer<ffi.Char> method,
+    ffi.Pointer<ffi.Void> header,
+    int headerLen,
+  ) {
+    return _InvokeBackendClientStreamWithHeader(
+      method,
+      header,
+      headerLen,
+    );
+  }
+
+  late final _InvokeBackendClientStreamWithHeaderPtr = _lookup<
+      ffi.NativeFunction<
+          ffi.LongLong Function(ffi.Pointer<ffi.Char>, ffi.Pointer<ffi.Void>,
+              ffi.LongLong)>>('InvokeBackendClientStreamWithHeader');
+  late final _InvokeBackendClientStreamWithHeader =
+      _InvokeBackendClientStreamWithHeaderPtr.asFunction<
+          int Function(ffi.Pointer<ffi.Char>, ffi.Pointer<ffi.Void>, int)>();
+
+  int InvokeBackendBidiStreamWithHeader(
+    ffi.Pointer<ffi.Char> method,
+    ffi.Pointer<ffi.Void> header,
+    int headerLen,
+  ) {
+    return _InvokeBackendBidiStreamWithHeader(
+      method,
+      header,
+      headerLen,
+    );
+  }
+
+  late final _InvokeBackendBidiStreamWithHeaderPtr = _lookup<
+      ffi.NativeFunction<
+          ffi.LongLong Function(ffi.Pointer<ffi.Char>, ffi.Pointer<ffi.Void>,
+              ffi.LongLong)>>('InvokeBackendBidiStreamWithHeader');
+  late final _InvokeBackendBidiStreamWithHeader =
+      _InvokeBackendBidiStreamWithHeaderPtr.asFunction<
+          int Function(ffi.Pointer<ffi.Char>, ffi.Pointer<ffi.Void>, int)>();
+
   int SendStreamData(
     int streamId,
     ffi.Pointer<ffi.Void> data,
@@ -377,6 +714,17 @@ typedef StreamCallback = ffi.Pointer<
     ffi.NativeFunction<
         ffi.Void Function(ffi.LongLong streamId, ffi.Char msgType,
             ffi.Pointer<ffi.Void> data, ffi.LongLong len)>>;
+
+/// Completion callback signature (for InvokeBackendAsync)
+typedef CallCompleteCallback = ffi.Pointer<
+    ffi.NativeFunction<
+        ffi.Void Function(ffi.LongLong callId, ffi.Pointer<ffi.Void> data,
+            ffi.LongLong len)>>;
+
+/// Serve error callback signature (serialized core.v1.ListenerStatus)
+typedef ServeErrorCallback = ffi.Pointer<
+    ffi.NativeFunction<
+        ffi.Void Function(ffi.Pointer<ffi.Void> data, ffi.LongLong len)>>;
 typedef GoInt64 = ffi.LongLong;
 typedef DartGoInt64 = int;
 typedef GoInt = GoInt64;
@@ -651,6 +651,892 @@ func (x *Error) GetGrpcCode() int32 {
 	return 0
 }
 
+// CallHeader is the request header of an FFI call, passed serialized to the
+// InvokeBackend*WithHeader entry points. It replaces the "key=value\n"
+// metadata text, which cannot carry binary or multi-line values.
+type CallHeader struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Header format version (currently 1). Readers reject newer versions.
+	Version uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	// Request metadata, in order; a key may repeat. Keys ending in "-bin"
+	// carry binary values, as in gRPC.
+	Metadata []*MetadataEntry `protobuf:"bytes,2,rep,name=metadata,proto3" json:"metadata,omitempty"`
+	// Deadline relative to the start of the call in milliseconds (0 = none).
+	TimeoutMs int64 `protobuf:"varint,3,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"`
+	// Caller-chosen ID of the call (0 = none).
+	CallId uint64 `protobuf:"varint,4,opt,name=call_id,json=callId,proto3" json:"call_id,omitempty"`
+	// W3C trace context of the caller.
+	Trace         *TraceContext `protobuf:"bytes,5,opt,name=trace,proto3" json:"trace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CallHeader) Reset() {
+	*x = CallHeader{}
+	mi := &file_core_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CallHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CallHeader) ProtoMessage() {}
+
+func (x *CallHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_core_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CallHeader.ProtoReflect.Descriptor instead.
+func (*CallHeader) Descriptor() ([]byte, []int) {
+	return file_core_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CallHeader) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *CallHeader) GetMetadata() []*MetadataEntry {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *CallHeader) GetTimeoutMs() int64 {
+	if x != nil {
+		return x.TimeoutMs
+	}
+	return 0
+}
+
+func (x *CallHeader) GetCallId() uint64 {
+	if x != nil {
+		return x.CallId
+	}
+	return 0
+}
+
+func (x *CallHeader) GetTrace() *TraceContext {
+	if x != nil {
+		return x.Trace
+	}
+	return nil
+}
+
+// MetadataEntry is one metadata key/value pair.
+type MetadataEntry struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Lowercase key, as in gRPC metadata.
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// Raw value; UTF-8 text unless the key ends in "-bin".
+	Value         []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MetadataEntry) Reset() {
+	*x = MetadataEntry{}
+	mi := &file_core_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MetadataEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetadataEntry) ProtoMessage() {}
+
+func (x *MetadataEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_core_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetadataEntry.ProtoReflect.Descriptor instead.
+func (*MetadataEntry) Descriptor() ([]byte, []int) {
+	return file_core_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *MetadataEntry) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *MetadataEntry) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+// TraceContext carries W3C Trace Context headers.
+type TraceContext struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Traceparent   string                 `protobuf:"bytes,1,opt,name=traceparent,proto3" json:"traceparent,omitempty"`
+	Tracestate    string                 `protobuf:"bytes,2,opt,name=tracestate,proto3" json:"tracestate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TraceContext) Reset() {
+	*x = TraceContext{}
+	mi := &file_core_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TraceContext) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TraceContext) ProtoMessage() {}
+
+func (x *TraceContext) ProtoReflect() protoreflect.Message {
+	mi := &file_core_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TraceContext.ProtoReflect.Descriptor instead.
+func (*TraceContext) Descriptor() ([]byte, []int) {
+	return file_core_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *TraceContext) GetTraceparent() string {
+	if x != nil {
+		return x.Traceparent
+	}
+	return ""
+}
+
+func (x *TraceContext) GetTracestate() string {
+	if x != nil {
+		return x.Tracestate
+	}
+	return ""
+}
+
+// CallResponse is the response envelope of InvokeBackendCall: the response
+// or error of a unary call together with the header and trailer metadata
+// its handler set (grpc.SetHeader, grpc.SetTrailer).
+type CallResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Serialized response message; empty when error is set.
+	Payload []byte `protobuf:"bytes,1,opt,name=payload,proto3" json:"payload,omitempty"`
+	// Set when the call failed.
+	Error *Error `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	// Header metadata; keys ending in "-bin" carry binary values.
+	Headers []*MetadataEntry `protobuf:"bytes,3,rep,name=headers,proto3" json:"headers,omitempty"`
+	// Trailer metadata, also returned for failed calls.
+	Trailers      []*MetadataEntry `protobuf:"bytes,4,rep,name=trailers,proto3" json:"trailers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CallResponse) Reset() {
+	*x = CallResponse{}
+	mi := &file_core_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CallResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CallResponse) ProtoMessage() {}
+
+func (x *CallResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_core_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CallResponse.ProtoReflect.Descriptor instead.
+func (*CallResponse) Descriptor() ([]byte, []int) {
+	return file_core_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CallResponse) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *CallResponse) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+func (x *CallResponse) GetHeaders() []*MetadataEntry {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+func (x *CallResponse) GetTrailers() []*MetadataEntry {
+	if x != nil {
+		return x.Trailers
+	}
+	return nil
+}
+
+// ListenerStatus is the state of one endpoint the engine was configured to
+// serve on.
+type ListenerStatus struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// "unix" or "tcp".
+	Network string `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	// Address as configured.
+	Address string `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	// Address actually bound; empty if listening failed.
+	BoundAddress string `protobuf:"bytes,3,opt,name=bound_address,json=boundAddress,proto3" json:"bound_address,omitempty"`
+	// Why listening failed, or the error that later ended serving.
+	Error *Error `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	// SHA-256 fingerprint of the TLS certificate served, as colon-separated
+	// hex; empty for a plaintext endpoint.
+	TlsFingerprint string `protobuf:"bytes,5,opt,name=tls_fingerprint,json=tlsFingerprint,proto3" json:"tls_fingerprint,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListenerStatus) Reset() {
+	*x = ListenerStatus{}
+	mi := &file_core_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListenerStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListenerStatus) ProtoMessage() {}
+
+func (x *ListenerStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_core_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListenerStatus.ProtoReflect.Descriptor instead.
+func (*ListenerStatus) Descriptor() ([]byte, []int) {
+	return file_core_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListenerStatus) GetNetwork() string {
+	if x != nil {
+		return x.Network
+	}
+	return ""
+}
+
+func (x *ListenerStatus) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *ListenerStatus) GetBoundAddress() string {
+	if x != nil {
+		return x.BoundAddress
+	}
+	return ""
+}
+
+func (x *ListenerStatus) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+func (x *ListenerStatus) GetTlsFingerprint() string {
+	if x != nil {
+		return x.TlsFingerprint
+	}
+	return ""
+}
+
+// ServerStatus is the result of starting an engine and, when queried later,
+// its current state.
+type ServerStatus struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether the engine is running.
+	Running bool `protobuf:"varint,1,opt,name=running,proto3" json:"running,omitempty"`
+	// One entry per configured endpoint.
+	Listeners []*ListenerStatus `protobuf:"bytes,2,rep,name=listeners,proto3" json:"listeners,omitempty"`
+	// Whether the cache service is available.
+	CacheEnabled bool `protobuf:"varint,3,opt,name=cache_enabled,json=cacheEnabled,proto3" json:"cache_enabled,omitempty"`
+	// Why the cache could not be opened, if it was requested.
+	CacheError *Error `protobuf:"bytes,4,opt,name=cache_error,json=cacheError,proto3" json:"cache_error,omitempty"`
+	// Why the engine did not start.
+	Error         *Error `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ServerStatus) Reset() {
+	*x = ServerStatus{}
+	mi := &file_core_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServerStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerStatus) ProtoMessage() {}
+
+func (x *ServerStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_core_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerStatus.ProtoReflect.Descriptor instead.
+func (*ServerStatus) Descriptor() ([]byte, []int) {
+	return file_core_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ServerStatus) GetRunning() bool {
+	if x != nil {
+		return x.Running
+	}
+	return false
+}
+
+func (x *ServerStatus) GetListeners() []*ListenerStatus {
+	if x != nil {
+		return x.Listeners
+	}
+	return nil
+}
+
+func (x *ServerStatus) GetCacheEnabled() bool {
+	if x != nil {
+		return x.CacheEnabled
+	}
+	return false
+}
+
+func (x *ServerStatus) GetCacheError() *Error {
+	if x != nil {
+		return x.CacheError
+	}
+	return nil
+}
+
+func (x *ServerStatus) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+// ShutdownReport lists, by method, what stopping an engine terminated
+// because it was still running at the drain deadline. All fields are
+// empty after a clean stop.
+type ShutdownReport struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// gRPC calls over TCP/UDS, cancelled.
+	Rpcs []string `protobuf:"bytes,1,rep,name=rpcs,proto3" json:"rpcs,omitempty"`
+	// Unary FFI calls, their contexts cancelled.
+	FfiCalls []string `protobuf:"bytes,2,rep,name=ffi_calls,json=ffiCalls,proto3" json:"ffi_calls,omitempty"`
+	// FFI stream sessions, closed.
+	Streams []string `protobuf:"bytes,3,rep,name=streams,proto3" json:"streams,omitempty"`
+	// Go to Dart callback requests, failed with UNAVAILABLE.
+	DartCalls     []string `protobuf:"bytes,4,rep,name=dart_calls,json=dartCalls,proto3" json:"dart_calls,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShutdownReport) Reset() {
+	*x = ShutdownReport{}
+	mi := &file_core_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShutdownReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShutdownReport) ProtoMessage() {}
+
+func (x *ShutdownReport) ProtoReflect() protoreflect.Message {
+	mi := &file_core_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShutdownReport.ProtoReflect.Descriptor instead.
+func (*ShutdownReport) Descriptor() ([]byte, []int) {
+	return file_core_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ShutdownReport) GetRpcs() []string {
+	if x != nil {
+		return x.Rpcs
+	}
+	return nil
+}
+
+func (x *ShutdownReport) GetFfiCalls() []string {
+	if x != nil {
+		return x.FfiCalls
+	}
+	return nil
+}
+
+func (x *ShutdownReport) GetStreams() []string {
+	if x != nil {
+		return x.Streams
+	}
+	return nil
+}
+
+func (x *ShutdownReport) GetDartCalls() []string {
+	if x != nil {
+		return x.DartCalls
+	}
+	return nil
+}
+
+// ServiceList lists the services an engine serves, as returned by the
+// ListServices FFI export.
+type ServiceList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Services      []*ServiceInfo         `protobuf:"bytes,1,rep,name=services,proto3" json:"services,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ServiceList) Reset() {
+	*x = ServiceList{}
+	mi := &file_core_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServiceList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServiceList) ProtoMessage() {}
+
+func (x *ServiceList) ProtoReflect() protoreflect.Message {
+	mi := &file_core_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServiceList.ProtoReflect.Descriptor instead.
+func (*ServiceList) Descriptor() ([]byte, []int) {
+	return file_core_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ServiceList) GetServices() []*ServiceInfo {
+	if x != nil {
+		return x.Services
+	}
+	return nil
+}
+
+// ServiceInfo describes one service.
+type ServiceInfo struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Fully-qualified name, e.g. "core.v1.HealthService".
+	Name    string        `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Methods []*MethodInfo `protobuf:"bytes,2,rep,name=methods,proto3" json:"methods,omitempty"`
+	// "grpc" for services on the gRPC server; otherwise what registered the
+	// descriptors of a service reachable only through FFI dispatch or a
+	// plugin (e.g. "ffi" or the plugin path).
+	Source        string `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ServiceInfo) Reset() {
+	*x = ServiceInfo{}
+	mi := &file_core_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServiceInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServiceInfo) ProtoMessage() {}
+
+func (x *ServiceInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_core_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServiceInfo.ProtoReflect.Descriptor instead.
+func (*ServiceInfo) Descriptor() ([]byte, []int) {
+	return file_core_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ServiceInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ServiceInfo) GetMethods() []*MethodInfo {
+	if x != nil {
+		return x.Methods
+	}
+	return nil
+}
+
+func (x *ServiceInfo) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+// MethodInfo describes one method of a service.
+type MethodInfo struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Method name within the service, e.g. "Ping".
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Fully-qualified request and response message names.
+	InputType       string `protobuf:"bytes,2,opt,name=input_type,json=inputType,proto3" json:"input_type,omitempty"`
+	OutputType      string `protobuf:"bytes,3,opt,name=output_type,json=outputType,proto3" json:"output_type,omitempty"`
+	ClientStreaming bool   `protobuf:"varint,4,opt,name=client_streaming,json=clientStreaming,proto3" json:"client_streaming,omitempty"`
+	ServerStreaming bool   `protobuf:"varint,5,opt,name=server_streaming,json=serverStreaming,proto3" json:"server_streaming,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *MethodInfo) Reset() {
+	*x = MethodInfo{}
+	mi := &file_core_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MethodInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MethodInfo) ProtoMessage() {}
+
+func (x *MethodInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_core_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MethodInfo.ProtoReflect.Descriptor instead.
+func (*MethodInfo) Descriptor() ([]byte, []int) {
+	return file_core_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *MethodInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MethodInfo) GetInputType() string {
+	if x != nil {
+		return x.InputType
+	}
+	return ""
+}
+
+func (x *MethodInfo) GetOutputType() string {
+	if x != nil {
+		return x.OutputType
+	}
+	return ""
+}
+
+func (x *MethodInfo) GetClientStreaming() bool {
+	if x != nil {
+		return x.ClientStreaming
+	}
+	return false
+}
+
+func (x *MethodInfo) GetServerStreaming() bool {
+	if x != nil {
+		return x.ServerStreaming
+	}
+	return false
+}
+
+// MethodDescription is the result of the DescribeMethod FFI export.
+type MethodDescription struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Method *MethodInfo            `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
+	// Source of the service, as in ServiceInfo.
+	Source string `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	// Serialized google.protobuf.FileDescriptorSet with the file declaring
+	// the service and its imports, dependencies first.
+	FileDescriptorSet []byte `protobuf:"bytes,3,opt,name=file_descriptor_set,json=fileDescriptorSet,proto3" json:"file_descriptor_set,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *MethodDescription) Reset() {
+	*x = MethodDescription{}
+	mi := &file_core_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MethodDescription) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MethodDescription) ProtoMessage() {}
+
+func (x *MethodDescription) ProtoReflect() protoreflect.Message {
+	mi := &file_core_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MethodDescription.ProtoReflect.Descriptor instead.
+func (*MethodDescription) Descriptor() ([]byte, []int) {
+	return file_core_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *MethodDescription) GetMethod() *MethodInfo {
+	if x != nil {
+		return x.Method
+	}
+	return nil
+}
+
+func (x *MethodDescription) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *MethodDescription) GetFileDescriptorSet() []byte {
+	if x != nil {
+		return x.FileDescriptorSet
+	}
+	return nil
+}
+
+// HealthReport is the readiness of an engine and its subsystems, as the
+// grpc.health.v1.Health service reports it, returned by the GetHealth FFI
+// export.
+type HealthReport struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Status of the engine as a whole (service ""): "SERVING" only if every
+	// service below is.
+	Status        string           `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Services      []*ServiceHealth `protobuf:"bytes,2,rep,name=services,proto3" json:"services,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HealthReport) Reset() {
+	*x = HealthReport{}
+	mi := &file_core_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthReport) ProtoMessage() {}
+
+func (x *HealthReport) ProtoReflect() protoreflect.Message {
+	mi := &file_core_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthReport.ProtoReflect.Descriptor instead.
+func (*HealthReport) Descriptor() ([]byte, []int) {
+	return file_core_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *HealthReport) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *HealthReport) GetServices() []*ServiceHealth {
+	if x != nil {
+		return x.Services
+	}
+	return nil
+}
+
+// ServiceHealth is the status of one service or subsystem of an engine.
+type ServiceHealth struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Service name as passed to grpc.health.v1.Health/Check, e.g.
+	// "core.v1.CacheService".
+	Service string `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	// grpc.health.v1 serving status: "SERVING", "NOT_SERVING" or "UNKNOWN".
+	Status string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	// Why the service is not serving, if known.
+	Reason        string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ServiceHealth) Reset() {
+	*x = ServiceHealth{}
+	mi := &file_core_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServiceHealth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServiceHealth) ProtoMessage() {}
+
+func (x *ServiceHealth) ProtoReflect() protoreflect.Message {
+	mi := &file_core_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServiceHealth.ProtoReflect.Descriptor instead.
+func (*ServiceHealth) Descriptor() ([]byte, []int) {
+	return file_core_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ServiceHealth) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+func (x *ServiceHealth) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ServiceHealth) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
 var File_core_proto protoreflect.FileDescriptor
 
 const file_core_proto_rawDesc = "" +
@@ -702,9 +1588,76 @@ const file_core_proto_rawDesc = "" +
 	"\x05Error\x12\x12\n" +
 	"\x04code\x18\x01 \x01(\x05R\x04code\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1b\n" +
-	"\tgrpc_code\x18\x03 \x01(\x05R\bgrpcCode2F\n" +
+	"\tgrpc_code\x18\x03 \x01(\x05R\bgrpcCode\"\xbf\x01\n" +
+	"\n" +
+	"CallHeader\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\rR\aversion\x122\n" +
+	"\bmetadata\x18\x02 \x03(\v2\x16.core.v1.MetadataEntryR\bmetadata\x12\x1d\n" +
+	"\n" +
+	"timeout_ms\x18\x03 \x01(\x03R\ttimeoutMs\x12\x17\n" +
+	"\acall_id\x18\x04 \x01(\x04R\x06callId\x12+\n" +
+	"\x05trace\x18\x05 \x01(\v2\x15.core.v1.TraceContextR\x05trace\"7\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\fR\x05value\"P\n" +
+	"\fTraceContext\x12 \n" +
+	"\vtraceparent\x18\x01 \x01(\tR\vtraceparent\x12\x1e\n" +
+	"\n" +
+	"tracestate\x18\x02 \x01(\tR\n" +
+	"tracestate\"\xb4\x01\n" +
+	"\fCallResponse\x12\x18\n" +
+	"\apayload\x18\x01 \x01(\fR\apayload\x12$\n" +
+	"\x05error\x18\x02 \x01(\v2\x0e.core.v1.ErrorR\x05error\x120\n" +
+	"\aheaders\x18\x03 \x03(\v2\x16.core.v1.MetadataEntryR\aheaders\x122\n" +
+	"\btrailers\x18\x04 \x03(\v2\x16.core.v1.MetadataEntryR\btrailers\"\xb8\x01\n" +
+	"\x0eListenerStatus\x12\x18\n" +
+	"\anetwork\x18\x01 \x01(\tR\anetwork\x12\x18\n" +
+	"\aaddress\x18\x02 \x01(\tR\aaddress\x12#\n" +
+	"\rbound_address\x18\x03 \x01(\tR\fboundAddress\x12$\n" +
+	"\x05error\x18\x04 \x01(\v2\x0e.core.v1.ErrorR\x05error\x12'\n" +
+	"\x0ftls_fingerprint\x18\x05 \x01(\tR\x0etlsFingerprint\"\xdb\x01\n" +
+	"\fServerStatus\x12\x18\n" +
+	"\arunning\x18\x01 \x01(\bR\arunning\x125\n" +
+	"\tlisteners\x18\x02 \x03(\v2\x17.core.v1.ListenerStatusR\tlisteners\x12#\n" +
+	"\rcache_enabled\x18\x03 \x01(\bR\fcacheEnabled\x12/\n" +
+	"\vcache_error\x18\x04 \x01(\v2\x0e.core.v1.ErrorR\n" +
+	"cacheError\x12$\n" +
+	"\x05error\x18\x05 \x01(\v2\x0e.core.v1.ErrorR\x05error\"z\n" +
+	"\x0eShutdownReport\x12\x12\n" +
+	"\x04rpcs\x18\x01 \x03(\tR\x04rpcs\x12\x1b\n" +
+	"\tffi_calls\x18\x02 \x03(\tR\bffiCalls\x12\x18\n" +
+	"\astreams\x18\x03 \x03(\tR\astreams\x12\x1d\n" +
+	"\n" +
+	"dart_calls\x18\x04 \x03(\tR\tdartCalls\"?\n" +
+	"\vServiceList\x120\n" +
+	"\bservices\x18\x01 \x03(\v2\x14.core.v1.ServiceInfoR\bservices\"h\n" +
+	"\vServiceInfo\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12-\n" +
+	"\amethods\x18\x02 \x03(\v2\x13.core.v1.MethodInfoR\amethods\x12\x16\n" +
+	"\x06source\x18\x03 \x01(\tR\x06source\"\xb6\x01\n" +
+	"\n" +
+	"MethodInfo\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"input_type\x18\x02 \x01(\tR\tinputType\x12\x1f\n" +
+	"\voutput_type\x18\x03 \x01(\tR\n" +
+	"outputType\x12)\n" +
+	"\x10client_streaming\x18\x04 \x01(\bR\x0fclientStreaming\x12)\n" +
+	"\x10server_streaming\x18\x05 \x01(\bR\x0fserverStreaming\"\x88\x01\n" +
+	"\x11MethodDescription\x12+\n" +
+	"\x06method\x18\x01 \x01(\v2\x13.core.v1.MethodInfoR\x06method\x12\x16\n" +
+	"\x06source\x18\x02 \x01(\tR\x06source\x12.\n" +
+	"\x13file_descriptor_set\x18\x03 \x01(\fR\x11fileDescriptorSet\"Z\n" +
+	"\fHealthReport\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x122\n" +
+	"\bservices\x18\x02 \x03(\v2\x16.core.v1.ServiceHealthR\bservices\"Y\n" +
+	"\rServiceHealth\x12\x18\n" +
+	"\aservice\x18\x01 \x01(\tR\aservice\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason2\x88\x01\n" +
 	"\rHealthService\x125\n" +
-	"\x04Ping\x12\x16.google.protobuf.Empty\x1a\x15.core.v1.PingResponse2\x8a\x05\n" +
+	"\x04Ping\x12\x16.google.protobuf.Empty\x1a\x15.core.v1.PingResponse\x12@\n" +
+	"\x0fGetServerStatus\x12\x16.google.protobuf.Empty\x1a\x15.core.v1.ServerStatus2\x8a\x05\n" +
 	"\fCacheService\x12:\n" +
 	"\x03Get\x12\x18.core.v1.GetCacheRequest\x1a\x19.core.v1.GetCacheResponse\x127\n" +
 	"\x03Put\x12\x18.core.v1.PutCacheRequest\x1a\x16.google.protobuf.Empty\x12=\n" +
@@ -729,7 +1682,7 @@ func file_core_proto_rawDescGZIP() []byte {
 	return file_core_proto_rawDescData
 }
 
-var file_core_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_core_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
 var file_core_proto_goTypes = []any{
 	(*PingResponse)(nil),         // 0: core.v1.PingResponse
 	(*SetMaxEntriesRequest)(nil), // 1: core.v1.SetMaxEntriesRequest
@@ -743,39 +1696,67 @@ var file_core_proto_goTypes = []any{
 	(*DeleteCacheRequest)(nil),   // 9: core.v1.DeleteCacheRequest
 	(*ClearCacheRequest)(nil),    // 10: core.v1.ClearCacheRequest
 	(*Error)(nil),                // 11: core.v1.Error
-	(*timestamp.Timestamp)(nil),  // 12: google.protobuf.Timestamp
-	(*empty.Empty)(nil),          // 13: google.protobuf.Empty
-	(*wrappers.BoolValue)(nil),   // 14: google.protobuf.BoolValue
+	(*CallHeader)(nil),           // 12: core.v1.CallHeader
+	(*MetadataEntry)(nil),        // 13: core.v1.MetadataEntry
+	(*TraceContext)(nil),         // 14: core.v1.TraceContext
+	(*CallResponse)(nil),         // 15: core.v1.CallResponse
+	(*ListenerStatus)(nil),       // 16: core.v1.ListenerStatus
+	(*ServerStatus)(nil),         // 17: core.v1.ServerStatus
+	(*ShutdownReport)(nil),       // 18: core.v1.ShutdownReport
+	(*ServiceList)(nil),          // 19: core.v1.ServiceList
+	(*ServiceInfo)(nil),          // 20: core.v1.ServiceInfo
+	(*MethodInfo)(nil),           // 21: core.v1.MethodInfo
+	(*MethodDescription)(nil),    // 22: core.v1.MethodDescription
+	(*HealthReport)(nil),         // 23: core.v1.HealthReport
+	(*ServiceHealth)(nil),        // 24: core.v1.ServiceHealth
+	(*timestamp.Timestamp)(nil),  // 25: google.protobuf.Timestamp
+	(*empty.Empty)(nil),          // 26: google.protobuf.Empty
+	(*wrappers.BoolValue)(nil),   // 27: google.protobuf.BoolValue
 }
 var file_core_proto_depIdxs = []int32{
-	12, // 0: core.v1.PingResponse.timestamp:type_name -> google.protobuf.Timestamp
-	13, // 1: core.v1.HealthService.Ping:input_type -> google.protobuf.Empty
-	5,  // 2: core.v1.CacheService.Get:input_type -> core.v1.GetCacheRequest
-	8,  // 3: core.v1.CacheService.Put:input_type -> core.v1.PutCacheRequest
-	9,  // 4: core.v1.CacheService.Delete:input_type -> core.v1.DeleteCacheRequest
-	10, // 5: core.v1.CacheService.Clear:input_type -> core.v1.ClearCacheRequest
-	5,  // 6: core.v1.CacheService.Contains:input_type -> core.v1.GetCacheRequest
-	5,  // 7: core.v1.CacheService.Keys:input_type -> core.v1.GetCacheRequest
-	1,  // 8: core.v1.CacheService.SetMaxEntries:input_type -> core.v1.SetMaxEntriesRequest
-	2,  // 9: core.v1.CacheService.SetMaxBytes:input_type -> core.v1.SetMaxBytesRequest
-	3,  // 10: core.v1.CacheService.GetStats:input_type -> core.v1.GetStatsRequest
-	13, // 11: core.v1.CacheService.Compact:input_type -> google.protobuf.Empty
-	0,  // 12: core.v1.HealthService.Ping:output_type -> core.v1.PingResponse
-	6,  // 13: core.v1.CacheService.Get:output_type -> core.v1.GetCacheResponse
-	13, // 14: core.v1.CacheService.Put:output_type -> google.protobuf.Empty
-	13, // 15: core.v1.CacheService.Delete:output_type -> google.protobuf.Empty
-	13, // 16: core.v1.CacheService.Clear:output_type -> google.protobuf.Empty
-	14, // 17: core.v1.CacheService.Contains:output_type -> google.protobuf.BoolValue
-	7,  // 18: core.v1.CacheService.Keys:output_type -> core.v1.GetCacheKeysResponse
-	13, // 19: core.v1.CacheService.SetMaxEntries:output_type -> google.protobuf.Empty
-	13, // 20: core.v1.CacheService.SetMaxBytes:output_type -> google.protobuf.Empty
-	4,  // 21: core.v1.CacheService.GetStats:output_type -> core.v1.GetStatsResponse
-	13, // 22: core.v1.CacheService.Compact:output_type -> google.protobuf.Empty
-	12, // [12:23] is the sub-list for method output_type
-	1,  // [1:12] is the sub-list for method input_type
-	1,  // [1:1] is the sub-list for extension type_name
-	1,  // [1:1] is the sub-list for extension extendee
-	0,  // [0:1] is the sub-list for field type_name
+	25, // 0: core.v1.PingResponse.timestamp:type_name -> google.protobuf.Timestamp
+	13, // 1: core.v1.CallHeader.metadata:type_name -> core.v1.MetadataEntry
+	14, // 2: core.v1.CallHeader.trace:type_name -> core.v1.TraceContext
+	11, // 3: core.v1.CallResponse.error:type_name -> core.v1.Error
+	13, // 4: core.v1.CallResponse.headers:type_name -> core.v1.MetadataEntry
+	13, // 5: core.v1.CallResponse.trailers:type_name -> core.v1.MetadataEntry
+	11, // 6: core.v1.ListenerStatus.error:type_name -> core.v1.Error
+	16, // 7: core.v1.ServerStatus.listeners:type_name -> core.v1.ListenerStatus
+	11, // 8: core.v1.ServerStatus.cache_error:type_name -> core.v1.Error
+	11, // 9: core.v1.ServerStatus.error:type_name -> core.v1.Error
+	20, // 10: core.v1.ServiceList.services:type_name -> core.v1.ServiceInfo
+	21, // 11: core.v1.ServiceInfo.methods:type_name -> core.v1.MethodInfo
+	21, // 12: core.v1.MethodDescription.method:type_name -> core.v1.MethodInfo
+	24, // 13: core.v1.HealthReport.services:type_name -> core.v1.ServiceHealth
+	26, // 14: core.v1.HealthService.Ping:input_type -> google.protobuf.Empty
+	26, // 15: core.v1.HealthService.GetServerStatus:input_type -> google.protobuf.Empty
+	5,  // 16: core.v1.CacheService.Get:input_type -> core.v1.GetCacheRequest
+	8,  // 17: core.v1.CacheService.Put:input_type -> core.v1.PutCacheRequest
+	9,  // 18: core.v1.CacheService.Delete:input_type -> core.v1.DeleteCacheRequest
+	10, // 19: core.v1.CacheService.Clear:input_type -> core.v1.ClearCacheRequest
+	5,  // 20: core.v1.CacheService.Contains:input_type -> core.v1.GetCacheRequest
+	5,  // 21: core.v1.CacheService.Keys:input_type -> core.v1.GetCacheRequest
+	1,  // 22: core.v1.CacheService.SetMaxEntries:input_type -> core.v1.SetMaxEntriesRequest
+	2,  // 23: core.v1.CacheService.SetMaxBytes:input_type -> core.v1.SetMaxBytesRequest
+	3,  // 24: core.v1.CacheService.GetStats:input_type -> core.v1.GetStatsRequest
+	26, // 25: core.v1.CacheService.Compact:input_type -> google.protobuf.Empty
+	0,  // 26: core.v1.HealthService.Ping:output_type -> core.v1.PingResponse
+	17, // 27: core.v1.HealthService.GetServerStatus:output_type -> core.v1.ServerStatus
+	6,  // 28: core.v1.CacheService.Get:output_type -> core.v1.GetCacheResponse
+	26, // 29: core.v1.CacheService.Put:output_type -> google.protobuf.Empty
+	26, // 30: core.v1.CacheService.Delete:output_type -> google.protobuf.Empty
+	26, // 31: core.v1.CacheService.Clear:output_type -> google.protobuf.Empty
+	27, // 32: core.v1.CacheService.Contains:output_type -> google.protobuf.BoolValue
+	7,  // 33: core.v1.CacheService.Keys:output_type -> core.v1.GetCacheKeysResponse
+	26, // 34: core.v1.CacheService.SetMaxEntries:output_type -> google.protobuf.Empty
+	26, // 35: core.v1.CacheService.SetMaxBytes:output_type -> google.protobuf.Empty
+	4,  // 36: core.v1.CacheService.GetStats:output_type -> core.v1.GetStatsResponse
+	26, // 37: core.v1.CacheService.Compact:output_type -> google.protobuf.Empty
+	26, // [26:38] is the sub-list for method output_type
+	14, // [14:26] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
 }
 
 func init() { file_core_proto_init() }
@@ -789,7 +1770,7 @@ func file_core_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_core_proto_rawDesc), len(file_core_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   12,
+			NumMessages:   25,
 			NumExtensions: 0,
 			NumServices:   2,
 		},
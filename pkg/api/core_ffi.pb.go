@@ -43,6 +43,16 @@ func Invoke(s FfiServer, ctx context.Context, method string, data []byte) ([]byt
 			return nil, err
 		}
 		return proto.Marshal(resp)
+	case "/core.v1.HealthService/GetServerStatus":
+		req := &empty.Empty{}
+		if err := proto.Unmarshal(data, req); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal request: %w", err)
+		}
+		resp, err := s.GetServerStatus(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return proto.Marshal(resp)
 	case "/core.v1.CacheService/Get":
 		req := &GetCacheRequest{}
 		if err := proto.Unmarshal(data, req); err != nil {
@@ -181,6 +191,30 @@ func InvokeFfi(s FfiServer, ctx context.Context, method string, data []byte) (un
 			return nil, 0, err
 		}
 		return cPtr, int64(size), nil
+	case "/core.v1.HealthService/GetServerStatus":
+		req := &empty.Empty{}
+		if err := proto.Unmarshal(data, req); err != nil {
+			return nil, 0, fmt.Errorf("failed to unmarshal request: %w", err)
+		}
+		resp, err := s.GetServerStatus(ctx, req)
+		if err != nil {
+			return nil, 0, err
+		}
+		// Zero-copy: allocate C memory and serialize directly
+		size := proto.Size(resp)
+		if size == 0 {
+			return nil, 0, nil
+		}
+		cPtr := C.malloc(C.size_t(size))
+		if cPtr == nil {
+			return nil, 0, fmt.Errorf("failed to allocate memory for response")
+		}
+		buf := unsafe.Slice((*byte)(cPtr), size)
+		if _, err := (proto.MarshalOptions{}).MarshalAppend(buf[:0], resp); err != nil {
+			C.free(cPtr)
+			return nil, 0, err
+		}
+		return cPtr, int64(size), nil
 	case "/core.v1.CacheService/Get":
 		req := &GetCacheRequest{}
 		if err := proto.Unmarshal(data, req); err != nil {
@@ -448,6 +482,14 @@ func (i *ffiInvoker) Invoke(ctx context.Context, method string, req, reply proto
 		// Use proto.Merge to avoid copying mutex in MessageState
 		proto.Merge(reply.(proto.Message), resp)
 		return nil
+	case "/core.v1.HealthService/GetServerStatus":
+		resp, err := i.server.GetServerStatus(ctx, req.(*empty.Empty))
+		if err != nil {
+			return err
+		}
+		// Use proto.Merge to avoid copying mutex in MessageState
+		proto.Merge(reply.(proto.Message), resp)
+		return nil
 	case "/core.v1.CacheService/Get":
 		resp, err := i.server.Get(ctx, req.(*GetCacheRequest))
 		if err != nil {
@@ -21,7 +21,8 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	HealthService_Ping_FullMethodName = "/core.v1.HealthService/Ping"
+	HealthService_Ping_FullMethodName            = "/core.v1.HealthService/Ping"
+	HealthService_GetServerStatus_FullMethodName = "/core.v1.HealthService/GetServerStatus"
 )
 
 // HealthServiceClient is the client API for HealthService service.
@@ -33,6 +34,9 @@ const (
 // =============================================================================
 type HealthServiceClient interface {
 	Ping(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*PingResponse, error)
+	// Status of the engine serving this call, with the addresses its
+	// listeners actually bound (e.g. for EngineTcpPort "0").
+	GetServerStatus(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ServerStatus, error)
 }
 
 type healthServiceClient struct {
@@ -53,6 +57,16 @@ func (c *healthServiceClient) Ping(ctx context.Context, in *empty.Empty, opts ..
 	return out, nil
 }
 
+func (c *healthServiceClient) GetServerStatus(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ServerStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ServerStatus)
+	err := c.cc.Invoke(ctx, HealthService_GetServerStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // HealthServiceServer is the server API for HealthService service.
 // All implementations must embed UnimplementedHealthServiceServer
 // for forward compatibility.
@@ -62,6 +76,9 @@ func (c *healthServiceClient) Ping(ctx context.Context, in *empty.Empty, opts ..
 // =============================================================================
 type HealthServiceServer interface {
 	Ping(context.Context, *empty.Empty) (*PingResponse, error)
+	// Status of the engine serving this call, with the addresses its
+	// listeners actually bound (e.g. for EngineTcpPort "0").
+	GetServerStatus(context.Context, *empty.Empty) (*ServerStatus, error)
 	mustEmbedUnimplementedHealthServiceServer()
 }
 
@@ -75,6 +92,9 @@ type UnimplementedHealthServiceServer struct{}
 func (UnimplementedHealthServiceServer) Ping(context.Context, *empty.Empty) (*PingResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method Ping not implemented")
 }
+func (UnimplementedHealthServiceServer) GetServerStatus(context.Context, *empty.Empty) (*ServerStatus, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetServerStatus not implemented")
+}
 func (UnimplementedHealthServiceServer) mustEmbedUnimplementedHealthServiceServer() {}
 func (UnimplementedHealthServiceServer) testEmbeddedByValue()                       {}
 
@@ -114,6 +134,24 @@ func _HealthService_Ping_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _HealthService_GetServerStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HealthServiceServer).GetServerStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HealthService_GetServerStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HealthServiceServer).GetServerStatus(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // HealthService_ServiceDesc is the grpc.ServiceDesc for HealthService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -125,6 +163,10 @@ var HealthService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Ping",
 			Handler:    _HealthService_Ping_Handler,
 		},
+		{
+			MethodName: "GetServerStatus",
+			Handler:    _HealthService_GetServerStatus_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "core.proto",
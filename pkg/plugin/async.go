@@ -0,0 +1,111 @@
+package plugin
+
+/*
+#include <stdlib.h>
+
+typedef void (*synurang_async_callback_func)(unsigned long long callId, char* data, int dataLen);
+
+static void call_async_callback(void* fn, unsigned long long callId, char* data, int dataLen) {
+    ((synurang_async_callback_func)fn)(callId, data, dataLen);
+}
+*/
+import "C"
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"unsafe"
+)
+
+var (
+	asyncMu    sync.Mutex
+	asyncCalls = make(map[uint64]context.CancelFunc) // call ID -> cancel
+)
+
+// Synurang_InvokeAsync starts a unary call on its own goroutine, routed by
+// full method name like Synurang_Invoke, and returns immediately. When the
+// handler finishes, callback(callId, data, dataLen) receives the framed
+// [status:1][payload...] result; data is only valid during the callback.
+// Returns 0 if the call was accepted (the callback then runs exactly once),
+// 1 if callId is already in use or callback is NULL.
+//
+//export Synurang_InvokeAsync
+func Synurang_InvokeAsync(callId C.ulonglong, method *C.char, data *C.char, dataLen C.int, callback unsafe.Pointer) C.int {
+	return startAsync(context.Background(), callId, method, data, dataLen, callback)
+}
+
+// Synurang_InvokeAsyncWithHeader is Synurang_InvokeAsync with a serialized
+// core.v1.CallHeader (see Synurang_InvokeWithHeader).
+//
+//export Synurang_InvokeAsyncWithHeader
+func Synurang_InvokeAsyncWithHeader(callId C.ulonglong, method *C.char, header *C.char, headerLen C.int, data *C.char, dataLen C.int, callback unsafe.Pointer) C.int {
+	ctx := withCallHeader(context.Background(), cToBytes(header, headerLen))
+	return startAsync(ctx, callId, method, data, dataLen, callback)
+}
+
+// startAsync starts an asynchronous call whose handler context derives
+// from parent.
+func startAsync(parent context.Context, callId C.ulonglong, method *C.char, data *C.char, dataLen C.int, callback unsafe.Pointer) C.int {
+	if callback == nil {
+		return 1
+	}
+	id := uint64(callId)
+	ctx, cancel := context.WithCancel(parent)
+
+	asyncMu.Lock()
+	if _, exists := asyncCalls[id]; exists {
+		asyncMu.Unlock()
+		cancel()
+		return 1
+	}
+	asyncCalls[id] = cancel
+	asyncMu.Unlock()
+
+	m := C.GoString(method)
+	d := cToBytes(data, dataLen)
+	go func() {
+		result := invokeAsync(ctx, m, d)
+
+		// Forget the call before reporting it, so a late cancel is a no-op
+		asyncMu.Lock()
+		delete(asyncCalls, id)
+		asyncMu.Unlock()
+		cancel()
+
+		cData := C.CBytes(result)
+		C.call_async_callback(callback, callId, (*C.char)(cData), C.int(len(result)))
+		C.free(cData)
+	}()
+	return 0
+}
+
+// invokeAsync runs one asynchronous call and frames its result. A panic is
+// reported as an error rather than crashing the host.
+func invokeAsync(ctx context.Context, method string, data []byte) (result []byte) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = frameError(fmt.Errorf("panic in plugin: %v", r))
+		}
+	}()
+
+	res, err := invokeRouted(ctx, method, data)
+	if err != nil {
+		return frameError(err)
+	}
+	return frameData(res)
+}
+
+// Synurang_CancelCall cancels the context of a call started with
+// Synurang_InvokeAsync. The callback still runs, typically with the
+// handler's cancellation error. Unknown or finished call IDs are ignored.
+//
+//export Synurang_CancelCall
+func Synurang_CancelCall(callId C.ulonglong) {
+	asyncMu.Lock()
+	cancel := asyncCalls[uint64(callId)]
+	asyncMu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+}
@@ -0,0 +1,86 @@
+package plugin
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+)
+
+// Batch wire format (all integers little-endian uint32):
+//
+//	Request:  [count] then count x ([methodLen][method][dataLen][data])
+//	Response: [count] then count x ([status:1][len][payload])
+//
+// Per-item status uses the same convention as Synurang_Invoke_<Service>:
+// 0 = success (payload is protobuf response), 1 = error (payload is message).
+
+var errBadBatch = errors.New("malformed batch request")
+
+// BatchCall is a single method/payload pair decoded from a batch request.
+type BatchCall struct {
+	Method string
+	Data   []byte
+}
+
+// DecodeBatch parses a batch request produced by the host.
+func DecodeBatch(data []byte) ([]BatchCall, error) {
+	count, data, ok := readUint32(data)
+	if !ok {
+		return nil, errBadBatch
+	}
+	// Each call needs at least 8 bytes of length prefixes.
+	if uint64(count)*8 > uint64(len(data)) {
+		return nil, errBadBatch
+	}
+	calls := make([]BatchCall, 0, count)
+	for i := uint32(0); i < count; i++ {
+		var method, payload []byte
+		if method, data, ok = readChunk(data); !ok {
+			return nil, errBadBatch
+		}
+		if payload, data, ok = readChunk(data); !ok {
+			return nil, errBadBatch
+		}
+		calls = append(calls, BatchCall{Method: string(method), Data: payload})
+	}
+	return calls, nil
+}
+
+// InvokeBatch decodes a batch request, dispatches each call through invoke in
+// order and encodes the per-call results. Used by generated
+// Synurang_InvokeBatch_<Service> exports and by Synurang_InvokeBatch.
+func InvokeBatch(ctx context.Context, data []byte, invoke func(context.Context, string, []byte) ([]byte, error)) ([]byte, error) {
+	calls, err := DecodeBatch(data)
+	if err != nil {
+		return nil, err
+	}
+
+	out := binary.LittleEndian.AppendUint32(nil, uint32(len(calls)))
+	for _, call := range calls {
+		res, err := invoke(ctx, call.Method, call.Data)
+		if err != nil {
+			out = append(out, 1)
+			res = []byte(err.Error())
+		} else {
+			out = append(out, 0)
+		}
+		out = binary.LittleEndian.AppendUint32(out, uint32(len(res)))
+		out = append(out, res...)
+	}
+	return out, nil
+}
+
+func readUint32(data []byte) (uint32, []byte, bool) {
+	if len(data) < 4 {
+		return 0, data, false
+	}
+	return binary.LittleEndian.Uint32(data), data[4:], true
+}
+
+func readChunk(data []byte) ([]byte, []byte, bool) {
+	n, data, ok := readUint32(data)
+	if !ok || uint64(n) > uint64(len(data)) {
+		return nil, data, false
+	}
+	return data[:n], data[n:], true
+}
@@ -0,0 +1,76 @@
+package plugin
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+var (
+	descMu    sync.Mutex
+	descFiles []protoreflect.FileDescriptor
+)
+
+// RegisterDescriptors records the proto files a plugin was built against so
+// the host can check them for wire compatibility at load time. Generated
+// plugin code registers its own file; call it for any extra files the host
+// should see. This should be called in the plugin's init() function.
+func RegisterDescriptors(files ...protoreflect.FileDescriptor) {
+	descMu.Lock()
+	defer descMu.Unlock()
+	descFiles = append(descFiles, files...)
+}
+
+// descriptorSet returns the registered files and their transitive imports,
+// dependencies first.
+func descriptorSet() *descriptorpb.FileDescriptorSet {
+	descMu.Lock()
+	files := append([]protoreflect.FileDescriptor(nil), descFiles...)
+	descMu.Unlock()
+
+	set := &descriptorpb.FileDescriptorSet{}
+	seen := make(map[string]bool)
+	var add func(fd protoreflect.FileDescriptor)
+	add = func(fd protoreflect.FileDescriptor) {
+		if seen[fd.Path()] {
+			return
+		}
+		seen[fd.Path()] = true
+		imports := fd.Imports()
+		for i := 0; i < imports.Len(); i++ {
+			add(imports.Get(i).FileDescriptor)
+		}
+		set.File = append(set.File, protodesc.ToFileDescriptorProto(fd))
+	}
+	for _, fd := range files {
+		add(fd)
+	}
+	return set
+}
+
+// Synurang_Descriptors returns a serialized FileDescriptorSet with the
+// plugin's proto files. The caller must free the result with Synurang_Free.
+// Returns NULL with respLen 0 if nothing is registered.
+//
+//export Synurang_Descriptors
+func Synurang_Descriptors(respLen *C.int) *C.char {
+	*respLen = 0
+	set := descriptorSet()
+	if len(set.File) == 0 {
+		return nil
+	}
+	data, err := proto.Marshal(set)
+	if err != nil {
+		return nil
+	}
+	*respLen = C.int(len(data))
+	return (*C.char)(C.CBytes(data))
+}
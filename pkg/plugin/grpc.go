@@ -0,0 +1,151 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+// RegisterGRPCService exposes a regular gRPC service implementation through
+// the generic Synurang_Invoke and Synurang_Stream_Open exports, using the
+// handlers in desc (e.g. pb.MyService_ServiceDesc). This lets a library that
+// already registers services on a grpc.Server, such as a Synurang engine,
+// serve the same implementations to plugin hosts. Interceptors do not apply;
+// see RegisterGRPCServiceWithInterceptors.
+func RegisterGRPCService(desc *grpc.ServiceDesc, impl any) {
+	RegisterGRPCServiceWithInterceptors(desc, impl, nil, nil)
+}
+
+// RegisterGRPCServiceWithInterceptors is RegisterGRPCService with unary and
+// stream interceptors run around every call, as grpc.UnaryInterceptor and
+// grpc.StreamInterceptor do on a grpc.Server. Either may be nil.
+func RegisterGRPCServiceWithInterceptors(desc *grpc.ServiceDesc, impl any, unary grpc.UnaryServerInterceptor, stream grpc.StreamServerInterceptor) {
+	a := &grpcAdapter{desc: desc, impl: impl, unary: unary, stream: stream}
+	registerRoute(desc.ServiceName, serviceRoute{invoke: a.invoke, openContext: a.open})
+}
+
+// Registrar is a grpc.ServiceRegistrar that calls RegisterGRPCService for
+// every service registered on it.
+type Registrar struct{}
+
+// RegisterService implements grpc.ServiceRegistrar.
+func (Registrar) RegisterService(desc *grpc.ServiceDesc, impl any) {
+	RegisterGRPCService(desc, impl)
+}
+
+var _ grpc.ServiceRegistrar = Registrar{}
+
+type grpcAdapter struct {
+	desc   *grpc.ServiceDesc
+	impl   any
+	unary  grpc.UnaryServerInterceptor
+	stream grpc.StreamServerInterceptor
+}
+
+func methodName(fullMethod string) string {
+	return fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+}
+
+func (a *grpcAdapter) invoke(ctx context.Context, method string, data []byte) ([]byte, error) {
+	name := methodName(method)
+	for _, md := range a.desc.Methods {
+		if md.MethodName != name {
+			continue
+		}
+		dec := func(v any) error {
+			return proto.Unmarshal(data, v.(proto.Message))
+		}
+		resp, err := md.Handler(a.impl, ctx, dec, a.unary)
+		if err != nil {
+			return nil, err
+		}
+		return proto.Marshal(resp.(proto.Message))
+	}
+	return nil, fmt.Errorf("unknown method: %s", method)
+}
+
+func (a *grpcAdapter) open(ctx context.Context, method string) uint64 {
+	name := methodName(method)
+	for i := range a.desc.Streams {
+		sd := &a.desc.Streams[i]
+		if sd.StreamName != name {
+			continue
+		}
+		handle, ps := newStream(ctx, method)
+		go func() {
+			defer ps.CloseRecvCh()
+			defer ps.Cancel()
+
+			// Recover from panics in service methods
+			defer func() {
+				if r := recover(); r != nil {
+					trySendErr(ps.ErrCh, fmt.Errorf("panic in plugin: %v", r))
+				}
+			}()
+
+			var err error
+			ss := &grpcServerStream{ps: ps}
+			if a.stream != nil {
+				info := &grpc.StreamServerInfo{FullMethod: method, IsClientStream: sd.ClientStreams, IsServerStream: sd.ServerStreams}
+				err = a.stream(a.impl, ss, info, sd.Handler)
+			} else {
+				err = sd.Handler(a.impl, ss)
+			}
+			if err != nil && err != io.EOF {
+				trySendErr(ps.ErrCh, err)
+			}
+		}()
+		return handle
+	}
+	return 0
+}
+
+// trySendErr sends err to ch without blocking.
+func trySendErr(ch chan<- error, err error) {
+	select {
+	case ch <- err:
+	default:
+	}
+}
+
+// grpcServerStream adapts a PluginStream to grpc.ServerStream for handlers
+// generated by protoc-gen-go-grpc.
+type grpcServerStream struct {
+	ps *PluginStream
+}
+
+func (s *grpcServerStream) Context() context.Context { return s.ps.Ctx }
+
+func (s *grpcServerStream) SendMsg(m any) error {
+	data, err := proto.Marshal(m.(proto.Message))
+	if err != nil {
+		return err
+	}
+	select {
+	case s.ps.RecvCh <- data:
+		return nil
+	case <-s.ps.Ctx.Done():
+		return s.ps.Ctx.Err()
+	}
+}
+
+func (s *grpcServerStream) RecvMsg(m any) error {
+	select {
+	case data, ok := <-s.ps.SendCh:
+		if !ok {
+			return io.EOF
+		}
+		return proto.Unmarshal(data, m.(proto.Message))
+	case <-s.ps.Ctx.Done():
+		return s.ps.Ctx.Err()
+	}
+}
+
+func (s *grpcServerStream) SetHeader(metadata.MD) error  { return nil }
+func (s *grpcServerStream) SendHeader(metadata.MD) error { return nil }
+func (s *grpcServerStream) SetTrailer(metadata.MD)       {}
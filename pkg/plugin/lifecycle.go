@@ -0,0 +1,143 @@
+package plugin
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// InitFunc receives the configuration bytes the host passed to LoadPlugin.
+// Returning an error aborts the load.
+type InitFunc func(cfg []byte) error
+
+// ShutdownFunc is called before the host unloads the plugin.
+// The context carries the host's shutdown deadline, if any.
+type ShutdownFunc func(ctx context.Context) error
+
+var (
+	lifecycleMu   sync.Mutex
+	initHooks     []InitFunc
+	shutdownHooks []ShutdownFunc
+)
+
+// OnInit registers fn to run when the host loads the plugin.
+// Hooks run in registration order; the first error stops the sequence.
+// This should be called in the plugin's init() function.
+func OnInit(fn InitFunc) {
+	lifecycleMu.Lock()
+	defer lifecycleMu.Unlock()
+	initHooks = append(initHooks, fn)
+}
+
+// OnShutdown registers fn to run before the host unloads the plugin.
+// Hooks run in reverse registration order.
+// This should be called in the plugin's init() function.
+func OnShutdown(fn ShutdownFunc) {
+	lifecycleMu.Lock()
+	defer lifecycleMu.Unlock()
+	shutdownHooks = append(shutdownHooks, fn)
+}
+
+func runInitHooks(cfg []byte) (err error) {
+	lifecycleMu.Lock()
+	hooks := append([]InitFunc(nil), initHooks...)
+	lifecycleMu.Unlock()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic in init hook: %v", r)
+		}
+	}()
+
+	for _, hook := range hooks {
+		if err := hook(cfg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runShutdownHooks(ctx context.Context) error {
+	lifecycleMu.Lock()
+	hooks := append([]ShutdownFunc(nil), shutdownHooks...)
+	lifecycleMu.Unlock()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					errs = append(errs, fmt.Errorf("panic in shutdown hook: %v", r))
+				}
+			}()
+			if err := hooks[i](ctx); err != nil {
+				errs = append(errs, err)
+			}
+		}()
+	}
+	return errors.Join(errs...)
+}
+
+// Synurang_Init runs the registered init hooks with the host configuration.
+// Response format: [status:1][payload...], status=1 carries the error message.
+//
+//export Synurang_Init
+func Synurang_Init(config *C.char, configLen C.int, respLen *C.int) *C.char {
+	if err := runInitHooks(cToBytes(config, configLen)); err != nil {
+		errBytes := []byte(err.Error())
+		result := make([]byte, 1+len(errBytes))
+		result[0] = 1 // error status
+		copy(result[1:], errBytes)
+		*respLen = C.int(len(result))
+		return (*C.char)(C.CBytes(result))
+	}
+
+	*respLen = 1
+	return (*C.char)(C.CBytes([]byte{0}))
+}
+
+// Synurang_Shutdown runs the registered shutdown hooks, bounded by timeoutMs
+// (0 means no deadline). Returns 0 on success, 1 if a hook failed and 2 if the
+// deadline expired.
+//
+//export Synurang_Shutdown
+func Synurang_Shutdown(timeoutMs C.longlong) C.int {
+	ctx := context.Background()
+	if timeoutMs > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(timeoutMs)*time.Millisecond)
+		defer cancel()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- runShutdownHooks(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return 1
+		}
+		return 0
+	case <-ctx.Done():
+		return 2
+	}
+}
+
+// Synurang_SetLogSink installs the host callback that receives records from
+// LogHandler. The host calls it before Synurang_Init so init hooks can log,
+// and with a nil sink before unloading. token is passed back on every call.
+//
+//export Synurang_SetLogSink
+func Synurang_SetLogSink(sinkFn unsafe.Pointer, token C.ulonglong) {
+	setLogSink(sinkFn, token)
+}
@@ -0,0 +1,206 @@
+package plugin
+
+/*
+#include <stdlib.h>
+
+typedef void (*synurang_log_sink_func)(unsigned long long token, int level, char* record, int recordLen);
+
+static void call_log_sink(void* fn, unsigned long long token, int level, char* record, int recordLen) {
+    ((synurang_log_sink_func)fn)(token, level, record, recordLen);
+}
+*/
+import "C"
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// logSink is the host callback installed by Synurang_SetLogSink.
+type logSink struct {
+	fn    unsafe.Pointer
+	token C.ulonglong
+}
+
+var (
+	logSinkMu sync.RWMutex
+	sink      *logSink
+	logName   string
+)
+
+func setLogSink(fn unsafe.Pointer, token C.ulonglong) {
+	logSinkMu.Lock()
+	defer logSinkMu.Unlock()
+	if fn == nil {
+		sink = nil
+		return
+	}
+	sink = &logSink{fn: fn, token: token}
+}
+
+// SetName sets the plugin name attached to every forwarded log record.
+// If unset, the host labels records with the name it loaded the plugin under.
+func SetName(name string) {
+	logSinkMu.Lock()
+	defer logSinkMu.Unlock()
+	logName = name
+}
+
+// logRecord is the JSON wire format of a forwarded record.
+// Groups are flattened into dotted attribute keys.
+type logRecord struct {
+	Time    time.Time `json:"time"`
+	Level   int       `json:"level"`
+	Message string    `json:"msg"`
+	Plugin  string    `json:"plugin,omitempty"`
+	Attrs   []logAttr `json:"attrs,omitempty"`
+}
+
+type logAttr struct {
+	Key   string `json:"k"`
+	Value any    `json:"v"`
+}
+
+// LogHandler is an slog.Handler that forwards records to the host's log sink.
+// Until the host registers a sink (older hosts never do) records are written
+// to stderr as text.
+//
+// Typical use in the plugin's init():
+//
+//	slog.SetDefault(slog.New(plugin.NewLogHandler(nil)))
+//
+// which also routes the standard log package through the host.
+type LogHandler struct {
+	opts     slog.HandlerOptions
+	attrs    []logAttr
+	group    string
+	fallback slog.Handler
+}
+
+// NewLogHandler returns a handler forwarding to the host. opts may be nil;
+// opts.Level filters records before they cross the ABI.
+func NewLogHandler(opts *slog.HandlerOptions) *LogHandler {
+	h := &LogHandler{}
+	if opts != nil {
+		h.opts = *opts
+	}
+	h.fallback = slog.NewTextHandler(os.Stderr, &h.opts)
+	return h
+}
+
+// Enabled reports whether records at level are forwarded.
+func (h *LogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	minLevel := slog.LevelInfo
+	if h.opts.Level != nil {
+		minLevel = h.opts.Level.Level()
+	}
+	return level >= minLevel
+}
+
+// Handle forwards r to the host sink, or to stderr if none is registered.
+func (h *LogHandler) Handle(ctx context.Context, r slog.Record) error {
+	logSinkMu.RLock()
+	s, name := sink, logName
+	logSinkMu.RUnlock()
+
+	if s == nil {
+		return h.fallback.Handle(ctx, r)
+	}
+
+	rec := logRecord{
+		Time:    r.Time,
+		Level:   int(r.Level),
+		Message: r.Message,
+		Plugin:  name,
+		Attrs:   append([]logAttr(nil), h.attrs...),
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		rec.Attrs = appendAttr(rec.Attrs, h.group, a)
+		return true
+	})
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to encode log record: %w", err)
+	}
+	cData := C.CBytes(data)
+	defer C.free(cData)
+	C.call_log_sink(s.fn, s.token, C.int(r.Level), (*C.char)(cData), C.int(len(data)))
+	return nil
+}
+
+// WithAttrs returns a handler that adds attrs to every record.
+func (h *LogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.attrs = append([]logAttr(nil), h.attrs...)
+	for _, a := range attrs {
+		h2.attrs = appendAttr(h2.attrs, h.group, a)
+	}
+	h2.fallback = h.fallback.WithAttrs(attrs)
+	return &h2
+}
+
+// WithGroup returns a handler that qualifies later attribute keys with name.
+func (h *LogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.group = joinKey(h.group, name)
+	h2.fallback = h.fallback.WithGroup(name)
+	return &h2
+}
+
+// appendAttr flattens a into dst, resolving LogValuers and expanding groups.
+func appendAttr(dst []logAttr, prefix string, a slog.Attr) []logAttr {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return dst
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		prefix = joinKey(prefix, a.Key)
+		for _, ga := range a.Value.Group() {
+			dst = appendAttr(dst, prefix, ga)
+		}
+		return dst
+	}
+
+	var v any
+	switch a.Value.Kind() {
+	case slog.KindDuration:
+		v = a.Value.Duration().String()
+	case slog.KindTime:
+		v = a.Value.Time().Format(time.RFC3339Nano)
+	case slog.KindAny:
+		switch x := a.Value.Any().(type) {
+		case error:
+			v = x.Error()
+		case json.Marshaler:
+			v = x
+		case fmt.Stringer:
+			v = x.String()
+		default:
+			if _, err := json.Marshal(x); err != nil {
+				v = fmt.Sprint(x)
+			} else {
+				v = x
+			}
+		}
+	default:
+		v = a.Value.Any()
+	}
+	return append(dst, logAttr{Key: joinKey(prefix, a.Key), Value: v})
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
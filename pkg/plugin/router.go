@@ -0,0 +1,178 @@
+package plugin
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// InvokeFunc handles a unary call for one service.
+type InvokeFunc func(ctx context.Context, method string, data []byte) ([]byte, error)
+
+// StreamOpenFunc opens a stream for one service and returns its handle,
+// or 0 if the stream could not be opened.
+type StreamOpenFunc func(method string) uint64
+
+type serviceRoute struct {
+	invoke InvokeFunc
+	open   StreamOpenFunc
+
+	// openContext, if set, replaces open and receives the call header
+	// (see CallHeader) in its context.
+	openContext func(ctx context.Context, method string) uint64
+}
+
+var (
+	routesMu sync.RWMutex
+	routes   = make(map[string]serviceRoute) // full service name -> handlers
+)
+
+// RegisterService routes calls for the service with the given full proto name
+// ("pkg.Service") through the generic Synurang_Invoke, Synurang_InvokeBatch
+// and Synurang_Stream_Open exports. open may be nil for services without
+// streaming methods. Generated plugin code registers every service it
+// exports; this should be called in the plugin's init() function.
+func RegisterService(fullName string, invoke InvokeFunc, open StreamOpenFunc) {
+	registerRoute(fullName, serviceRoute{invoke: invoke, open: open})
+}
+
+func registerRoute(fullName string, r serviceRoute) {
+	routesMu.Lock()
+	defer routesMu.Unlock()
+	routes[fullName] = r
+}
+
+type callHeaderKey struct{}
+
+// CallHeader returns the serialized core.v1.CallHeader (metadata, timeout,
+// trace context) the host passed with the call through
+// Synurang_InvokeWithHeader, Synurang_InvokeAsyncWithHeader or
+// Synurang_Stream_OpenWithHeader, or nil. Streams only receive it for
+// services of RegisterGRPCService.
+func CallHeader(ctx context.Context) []byte {
+	header, _ := ctx.Value(callHeaderKey{}).([]byte)
+	return header
+}
+
+// withCallHeader returns ctx carrying header for CallHeader.
+func withCallHeader(ctx context.Context, header []byte) context.Context {
+	if len(header) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, callHeaderKey{}, header)
+}
+
+// UnregisterService removes a service registered with RegisterService or
+// RegisterGRPCService. Later calls to it fail with "unknown method".
+func UnregisterService(fullName string) {
+	routesMu.Lock()
+	defer routesMu.Unlock()
+	delete(routes, fullName)
+}
+
+// route finds the handlers for a full method name ("/pkg.Service/Method").
+func route(method string) (serviceRoute, bool) {
+	name, _, ok := strings.Cut(strings.TrimPrefix(method, "/"), "/")
+	if !ok {
+		return serviceRoute{}, false
+	}
+	routesMu.RLock()
+	defer routesMu.RUnlock()
+	r, ok := routes[name]
+	return r, ok
+}
+
+// invokeRouted dispatches a unary call by its full method name.
+func invokeRouted(ctx context.Context, method string, data []byte) ([]byte, error) {
+	r, ok := route(method)
+	if !ok || r.invoke == nil {
+		return nil, fmt.Errorf("unknown method: %s", method)
+	}
+	return r.invoke(ctx, method, data)
+}
+
+// Synurang_Invoke calls a unary method of any registered service, routed by
+// its full method name. Same response format as Synurang_Invoke_<Service>:
+// [status:1][payload...].
+//
+//export Synurang_Invoke
+func Synurang_Invoke(method *C.char, data *C.char, dataLen C.int, respLen *C.int) *C.char {
+	return invokeFramed(context.Background(), method, data, dataLen, respLen)
+}
+
+// Synurang_InvokeWithHeader is Synurang_Invoke with a serialized
+// core.v1.CallHeader, e.g. the credentials of the call, which handlers read
+// with CallHeader.
+//
+//export Synurang_InvokeWithHeader
+func Synurang_InvokeWithHeader(method *C.char, header *C.char, headerLen C.int, data *C.char, dataLen C.int, respLen *C.int) *C.char {
+	ctx := withCallHeader(context.Background(), cToBytes(header, headerLen))
+	return invokeFramed(ctx, method, data, dataLen, respLen)
+}
+
+// invokeFramed runs a routed unary call and returns its framed result.
+func invokeFramed(ctx context.Context, method *C.char, data *C.char, dataLen C.int, respLen *C.int) *C.char {
+	res, err := invokeRouted(ctx, C.GoString(method), cToBytes(data, dataLen))
+	var result []byte
+	if err != nil {
+		result = frameError(err)
+	} else {
+		result = frameData(res)
+	}
+	*respLen = C.int(len(result))
+	return (*C.char)(C.CBytes(result))
+}
+
+// Synurang_InvokeBatch runs a batch whose calls may target any registered
+// service. The layout is documented in batch.go.
+//
+//export Synurang_InvokeBatch
+func Synurang_InvokeBatch(data *C.char, dataLen C.int, respLen *C.int) *C.char {
+	res, err := InvokeBatch(context.Background(), cToBytes(data, dataLen), invokeRouted)
+	var result []byte
+	if err != nil {
+		result = frameError(err)
+	} else {
+		result = frameData(res)
+	}
+	*respLen = C.int(len(result))
+	return (*C.char)(C.CBytes(result))
+}
+
+// Synurang_Stream_Open opens a stream on any registered service, routed by
+// its full method name. Returns 0 for unknown methods.
+//
+//export Synurang_Stream_Open
+func Synurang_Stream_Open(method *C.char) C.ulonglong {
+	return C.ulonglong(openRouted(context.Background(), C.GoString(method)))
+}
+
+// Synurang_Stream_OpenWithHeader is Synurang_Stream_Open with a serialized
+// core.v1.CallHeader (see Synurang_InvokeWithHeader).
+//
+//export Synurang_Stream_OpenWithHeader
+func Synurang_Stream_OpenWithHeader(method *C.char, header *C.char, headerLen C.int) C.ulonglong {
+	ctx := withCallHeader(context.Background(), cToBytes(header, headerLen))
+	return C.ulonglong(openRouted(ctx, C.GoString(method)))
+}
+
+// openRouted opens a stream by its full method name, returning 0 for
+// unknown methods.
+func openRouted(ctx context.Context, method string) uint64 {
+	r, ok := route(method)
+	switch {
+	case !ok:
+		return 0
+	case r.openContext != nil:
+		return r.openContext(ctx, method)
+	case r.open != nil:
+		return r.open(method)
+	}
+	return 0
+}
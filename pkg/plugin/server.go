@@ -36,12 +36,19 @@ type PluginStream struct {
 	CloseSend bool
 	CloseRecv bool
 	Mu        sync.Mutex
+
+	subscribed bool // set once Synurang_Stream_Subscribe took over delivery
 }
 
 // NewStream creates a new stream and registers it globally.
 // Used by generated code in Synurang_Stream_<Service>_Open.
 func NewStream(method string) (uint64, *PluginStream) {
-	ctx, cancel := context.WithCancel(context.Background())
+	return newStream(context.Background(), method)
+}
+
+// newStream is NewStream with the stream's context derived from parent.
+func newStream(parent context.Context, method string) (uint64, *PluginStream) {
+	ctx, cancel := context.WithCancel(parent)
 	ps := &PluginStream{
 		Ctx:    ctx,
 		Cancel: cancel,
@@ -115,101 +122,106 @@ func Synurang_Stream_Recv(handle C.ulonglong, respLen *C.int, status *C.int) *C.
 		return nil
 	}
 
+	result, st := stream.recvNext()
+	*status = C.int(st)
+	if result == nil {
+		return nil
+	}
+	*respLen = C.int(len(result))
+	return (*C.char)(C.CBytes(result))
+}
+
+// Synurang_Stream_Subscribe switches a stream to push delivery: instead of the
+// host blocking a thread in Synurang_Stream_Recv, the plugin calls
+// callback(token, status, data, dataLen) for every message, then once more
+// with the final EOF or error. Returns 0 on success, 1 for an unknown or
+// already subscribed handle.
+//
+//export Synurang_Stream_Subscribe
+func Synurang_Stream_Subscribe(handle C.ulonglong, callback unsafe.Pointer, token C.ulonglong) C.int {
+	stream := getStream(handle)
+	if stream == nil || callback == nil {
+		return 1
+	}
+	stream.Mu.Lock()
+	already := stream.subscribed
+	stream.subscribed = true
+	stream.Mu.Unlock()
+	if already {
+		return 1
+	}
+	go stream.pushToCallback(callback, token)
+	return 0
+}
+
+// recvNext blocks until the handler produces a message, fails, or the stream
+// ends. It returns the framed result ([status][payload]) and the ABI recv
+// status: 0 with a result, 1 for EOF.
+func (ps *PluginStream) recvNext() ([]byte, int) {
 	// Priority 1: Check for data in RecvCh (non-blocking)
 	// This ensures we don't miss data when context is also cancelled
 	select {
-	case data, ok := <-stream.RecvCh:
-		if !ok {
-			// Channel closed - check for pending error
-			select {
-			case err := <-stream.ErrCh:
-				errBytes := []byte(err.Error())
-				result := make([]byte, 1+len(errBytes))
-				result[0] = 1
-				copy(result[1:], errBytes)
-				*respLen = C.int(len(result))
-				*status = 0
-				return (*C.char)(C.CBytes(result))
-			default:
-				*status = 1 // EOF - no error pending
-				return nil
-			}
-		}
-		result := make([]byte, 1+len(data))
-		result[0] = 0
-		copy(result[1:], data)
-		*respLen = C.int(len(result))
-		*status = 0
-		return (*C.char)(C.CBytes(result))
+	case data, ok := <-ps.RecvCh:
+		return ps.recvResult(data, ok)
 	default:
 		// No data immediately available, fall through to blocking select
 	}
 
 	// Priority 2: Check for error (non-blocking)
 	select {
-	case err := <-stream.ErrCh:
-		errBytes := []byte(err.Error())
-		result := make([]byte, 1+len(errBytes))
-		result[0] = 1
-		copy(result[1:], errBytes)
-		*respLen = C.int(len(result))
-		*status = 0
-		return (*C.char)(C.CBytes(result))
+	case err := <-ps.ErrCh:
+		return frameError(err), 0
 	default:
 	}
 
 	// Priority 3: Blocking wait - data, error, or cancellation
 	select {
-	case data, ok := <-stream.RecvCh:
-		if !ok {
-			// Channel closed - check for pending error
-			select {
-			case err := <-stream.ErrCh:
-				errBytes := []byte(err.Error())
-				result := make([]byte, 1+len(errBytes))
-				result[0] = 1
-				copy(result[1:], errBytes)
-				*respLen = C.int(len(result))
-				*status = 0
-				return (*C.char)(C.CBytes(result))
-			default:
-				*status = 1 // EOF - no error pending
-				return nil
-			}
-		}
-		result := make([]byte, 1+len(data))
-		result[0] = 0
-		copy(result[1:], data)
-		*respLen = C.int(len(result))
-		*status = 0
-		return (*C.char)(C.CBytes(result))
-	case err := <-stream.ErrCh:
-		errBytes := []byte(err.Error())
-		result := make([]byte, 1+len(errBytes))
-		result[0] = 1
-		copy(result[1:], errBytes)
-		*respLen = C.int(len(result))
-		*status = 0
-		return (*C.char)(C.CBytes(result))
-	case <-stream.Ctx.Done():
+	case data, ok := <-ps.RecvCh:
+		return ps.recvResult(data, ok)
+	case err := <-ps.ErrCh:
+		return frameError(err), 0
+	case <-ps.Ctx.Done():
 		// Context cancelled - but check one more time for data that arrived
 		select {
-		case data, ok := <-stream.RecvCh:
+		case data, ok := <-ps.RecvCh:
 			if ok {
-				result := make([]byte, 1+len(data))
-				result[0] = 0
-				copy(result[1:], data)
-				*respLen = C.int(len(result))
-				*status = 0
-				return (*C.char)(C.CBytes(result))
+				return frameData(data), 0
 			}
 		default:
 		}
-		*status = 1 // EOF due to cancellation
-		return nil
+		return nil, 1 // EOF due to cancellation
+	}
+}
+
+// recvResult handles a receive from RecvCh; a closed channel reports a
+// pending handler error, or EOF if there is none.
+func (ps *PluginStream) recvResult(data []byte, ok bool) ([]byte, int) {
+	if ok {
+		return frameData(data), 0
+	}
+	select {
+	case err := <-ps.ErrCh:
+		return frameError(err), 0
+	default:
+		return nil, 1 // EOF - no error pending
 	}
 }
 
+func frameData(data []byte) []byte {
+	result := make([]byte, 1+len(data))
+	result[0] = 0
+	copy(result[1:], data)
+	return result
+}
+
+func frameError(err error) []byte {
+	errBytes := []byte(err.Error())
+	result := make([]byte, 1+len(errBytes))
+	result[0] = 1
+	copy(result[1:], errBytes)
+	return result
+}
+
 // closeSendCh safely closes the send channel
 func (ps *PluginStream) closeSendCh() {
 	ps.Mu.Lock()
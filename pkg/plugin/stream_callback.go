@@ -0,0 +1,37 @@
+package plugin
+
+/*
+#include <stdlib.h>
+
+typedef void (*synurang_stream_callback_func)(unsigned long long token, int status, char* data, int dataLen);
+
+static void call_stream_callback(void* fn, unsigned long long token, int status, char* data, int dataLen) {
+    ((synurang_stream_callback_func)fn)(token, status, data, dataLen);
+}
+*/
+import "C"
+
+import "unsafe"
+
+// pushToCallback delivers every result of ps to the host callback until the
+// stream ends, using the same status codes and framing as Synurang_Stream_Recv.
+// The data pointer is only valid for the duration of the callback.
+func (ps *PluginStream) pushToCallback(fn unsafe.Pointer, token C.ulonglong) {
+	for {
+		result, status := ps.recvNext()
+
+		var cData unsafe.Pointer
+		if result != nil {
+			cData = C.CBytes(result)
+		}
+		C.call_stream_callback(fn, token, C.int(status), (*C.char)(cData), C.int(len(result)))
+		if cData != nil {
+			C.free(cData)
+		}
+
+		// EOF, or an error frame: the stream produces nothing further
+		if status != 0 || (len(result) > 0 && result[0] == 1) {
+			return
+		}
+	}
+}
@@ -643,6 +643,24 @@ func (s *CacheServiceServer) Compact(ctx context.Context, _ *emptypb.Empty) (*em
 	return &emptypb.Empty{}, nil
 }
 
+// CheckWritable reports whether the cache database is open and accepts
+// writes, without changing it.
+func (s *CacheServiceServer) CheckWritable(ctx context.Context) error {
+	if s.closed.Load() {
+		return fmt.Errorf("cache is closed")
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("cache database: %w", err)
+	}
+	defer tx.Rollback()
+	// Deletes nothing, but opens a write transaction
+	if _, err := tx.ExecContext(ctx, "DELETE FROM cache_entries WHERE 0"); err != nil {
+		return fmt.Errorf("cache database is not writable: %w", err)
+	}
+	return nil
+}
+
 // Close gracefully shuts down the cache service.
 func (s *CacheServiceServer) Close() {
 	if s.closed.Swap(true) {
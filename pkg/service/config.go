@@ -1,18 +1,67 @@
 package service
 
-import "time"
+import (
+	"os"
+	"time"
+)
 
 // Config holds server configuration
 type Config struct {
-	EngineSocketPath string        // UDS path for engine gRPC server
-	EngineTcpPort    string        // TCP port for engine gRPC server
-	ViewSocketPath   string        // UDS path for Dart gRPC server
-	ViewTcpPort      string        // TCP port for Dart gRPC server
-	Token            string        // JWT token for authentication
-	CachePath        string        // Path to cache directory
-	EnableCache      bool          // Enable cache service (requires SQLite)
-	StreamTimeout    time.Duration // Timeout for streaming RPCs
+	EngineSocketPath  string        // UDS path for engine gRPC server (or AutoSocketPath, AbstractSocketPath)
+	EngineTcpPort     string        // TCP port for engine gRPC server ("0" picks a free port)
+	ViewSocketPath    string        // UDS path for Dart gRPC server
+	ViewTcpPort       string        // TCP port for Dart gRPC server
+	Token             string        // Bearer token callers must present (unless JWTKeys is set) and sent to Dart
+	CachePath         string        // Path to cache directory
+	EnableCache       bool          // Enable cache service (requires SQLite)
+	StreamTimeout     time.Duration // Timeout for streaming RPCs
+	FailOnListenError bool          // Fail Start if an endpoint cannot be listened on
+	ShutdownTimeout   time.Duration // How long Stop lets calls finish (0: DefaultShutdownTimeout, <0: none)
+	HealthInterval    time.Duration // How often grpc.health.v1 Watch re-checks; Check reuses reports up to half as old (0: DefaultHealthInterval)
+
+	// JWT authentication: with JWTKeys set, callers on every transport must
+	// present a JWT signed by one of the keys instead of Token
+	JWTKeys     []JWTKey      // Verification keys, selected by the token's "kid" (rotation)
+	JWTIssuer   string        // Required "iss" claim, if set
+	JWTAudience string        // Value the "aud" claim must contain, if set
+	JWTLeeway   time.Duration // Clock skew allowed when checking "exp" and "nbf"
+
+	// Authorization of authenticated calls on every transport. Health
+	// checks (grpc.health.v1.Health) are never checked, so probes work.
+	Policy           *Policy // Per-method rules; nil allows every method
+	PolicyFile       string  // JSON or YAML policy file, used instead of Policy (see LoadPolicy)
+	PublicReflection bool    // Serve server reflection without the token and policy checks
+
+	// Access to the EngineSocketPath socket
+	SocketMode  os.FileMode    // Permissions of the socket file, e.g. 0600 (0: left to the umask)
+	SocketOwner string         // Owner of the socket file: "user", "user:group" or ":group", by name or id
+	SocketPeers *PeerAllowlist // Accept only clients whose SO_PEERCRED uid, gid or pid is listed (Linux)
+
+	// TLS of the TCP listener; the UDS listener stays plaintext, secured by
+	// the socket access options above
+	TLSCertFile     string // PEM certificate chain
+	TLSKeyFile      string // PEM private key of TLSCertFile
+	TLSClientCAFile string // PEM CAs that must have signed the client certificate (mTLS)
+	TLSAutoCert     bool   // Without TLSCertFile, generate a self-signed certificate and log its fingerprint
+
+	// TLS of the TCP connection to the Dart gRPC server
+	ViewTLS           bool   // Connect to ViewTcpPort with TLS
+	ViewTLSCAFile     string // PEM CAs trusted for the Dart server (default: system roots)
+	ViewTLSCertFile   string // PEM client certificate for mTLS
+	ViewTLSKeyFile    string // PEM private key of ViewTLSCertFile
+	ViewTLSServerName string // Name the Dart server certificate must match (default: "localhost")
 }
 
-// DartCallback is the function signature for calling Dart from Go
+// EngineSocketPath values that make Start pick a free socket; Engine.Status
+// reports the path chosen.
+const (
+	// AutoSocketPath creates a uniquely named socket file in os.TempDir().
+	AutoSocketPath = "auto"
+	// AbstractSocketPath listens on a uniquely named Linux abstract socket,
+	// which has no file and vanishes with the process.
+	AbstractSocketPath = "@"
+)
+
+// DartCallback is the function the default engine uses to call Dart from Go.
+// Other engines use Engine.SetDartCallback.
 var DartCallback func(method string, data []byte) ([]byte, error)
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/ivere27/synurang/pkg/api"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultShutdownTimeout is how long Engine.Stop lets calls in flight finish
+// when Config.ShutdownTimeout is zero.
+const DefaultShutdownTimeout = 5 * time.Second
+
+// errStopping is returned for calls made while an engine stops.
+var errStopping = status.Error(codes.Unavailable, "engine is stopping")
+
+// ShutdownReport lists, by method, what Engine.Shutdown terminated because it
+// was still running at the drain deadline. It is empty after a clean stop.
+type ShutdownReport struct {
+	RPCs      []string // gRPC calls over TCP/UDS, cancelled
+	FfiCalls  []string // unary FFI calls, their contexts cancelled
+	Streams   []string // FFI stream sessions, closed
+	DartCalls []string // Go -> Dart requests, failed with Unavailable
+}
+
+// Forced reports whether anything had to be terminated.
+func (r ShutdownReport) Forced() bool {
+	return len(r.RPCs)+len(r.FfiCalls)+len(r.Streams)+len(r.DartCalls) > 0
+}
+
+func (r ShutdownReport) String() string {
+	if !r.Forced() {
+		return "clean"
+	}
+	var parts []string
+	for _, kind := range []struct {
+		name    string
+		methods []string
+	}{
+		{"rpcs", r.RPCs},
+		{"ffi calls", r.FfiCalls},
+		{"streams", r.Streams},
+		{"dart calls", r.DartCalls},
+	} {
+		if len(kind.methods) > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s %v", len(kind.methods), kind.name, kind.methods))
+		}
+	}
+	return "forced " + strings.Join(parts, ", ")
+}
+
+// Proto converts r to the ShutdownReport returned over FFI.
+func (r ShutdownReport) Proto() *pb.ShutdownReport {
+	return &pb.ShutdownReport{
+		Rpcs:      r.RPCs,
+		FfiCalls:  r.FfiCalls,
+		Streams:   r.Streams,
+		DartCalls: r.DartCalls,
+	}
+}
+
+// callTracker counts the calls of one kind in progress on an engine, so
+// Shutdown can wait for them and cancel the ones still running at its
+// deadline.
+type callTracker struct {
+	mu     sync.Mutex
+	closed bool // reject new calls
+	next   uint64
+	calls  map[uint64]trackedCall
+	idle   chan struct{} // closed when the last call ends; nil if none is waiting
+}
+
+type trackedCall struct {
+	method string
+	cancel context.CancelFunc
+}
+
+// begin registers a call of method. The returned context is cancelled when
+// the call is forced to end; end must be called once it returns. begin
+// fails with Unavailable once the tracker is closed.
+func (t *callTracker) begin(ctx context.Context, method string) (context.Context, func(), error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return ctx, nil, errStopping
+	}
+	if t.calls == nil {
+		t.calls = make(map[uint64]trackedCall)
+	}
+	t.next++
+	id := t.next
+	ctx, cancel := context.WithCancel(ctx)
+	t.calls[id] = trackedCall{method: method, cancel: cancel}
+
+	var once sync.Once
+	return ctx, func() {
+		once.Do(func() {
+			cancel()
+			t.mu.Lock()
+			delete(t.calls, id)
+			if len(t.calls) == 0 && t.idle != nil {
+				close(t.idle)
+				t.idle = nil
+			}
+			t.mu.Unlock()
+		})
+	}, nil
+}
+
+// setClosed sets whether begin rejects new calls.
+func (t *callTracker) setClosed(closed bool) {
+	t.mu.Lock()
+	t.closed = closed
+	t.mu.Unlock()
+}
+
+// wait waits until no call is in progress or ctx is done, and reports
+// which happened first.
+func (t *callTracker) wait(ctx context.Context) bool {
+	t.mu.Lock()
+	if len(t.calls) == 0 {
+		t.mu.Unlock()
+		return true
+	}
+	if t.idle == nil {
+		t.idle = make(chan struct{})
+	}
+	idle := t.idle
+	t.mu.Unlock()
+
+	select {
+	case <-idle:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// cancelAll cancels the contexts of the calls in progress and returns their
+// methods, sorted.
+func (t *callTracker) cancelAll() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var methods []string
+	for _, c := range t.calls {
+		c.cancel()
+		methods = append(methods, c.method)
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// BeginCall registers an FFI call of method with the engine, so a graceful
+// stop lets it finish. The returned context is cancelled if the stop has to
+// force the call to end, and end must be called once the call returns. It
+// fails with Unavailable while the engine is stopping.
+func (e *Engine) BeginCall(ctx context.Context, method string) (context.Context, func(), error) {
+	return e.ffiCalls.begin(ctx, method)
+}
+
+// BeginFfiCall is BeginCall for FFI entry points that dispatch to the core
+// services themselves: it also authorizes the call of method with
+// AuthorizeCall, so ctx should come from FfiContext. It returns the core
+// services and the handler context, and end must be called once the call
+// returns. It fails with Unavailable if the engine is not running.
+func (e *Engine) BeginFfiCall(ctx context.Context, method string) (*CoreServiceServer, context.Context, func(), error) {
+	ctx, end, err := e.BeginCall(ctx, method)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	core := e.Core()
+	if core == nil {
+		end()
+		return nil, nil, nil, status.Error(codes.Unavailable, "Server implementation not initialized")
+	}
+	if ctx, err = core.AuthorizeCall(ctx, method); err != nil {
+		end()
+		return nil, nil, nil, err
+	}
+	return core, ctx, end, nil
+}
+
+// FfiUnaryInterceptor checks and tracks unary calls that reach the core
+// services in-process without the gRPC server, e.g. through
+// plugin.RegisterGRPCServiceWithInterceptors: each call is authorized over
+// TransportFFI and registered like BeginFfiCall, so the policy applies and
+// a graceful stop drains it.
+func (e *Engine) FfiUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	_, ctx, end, err := e.BeginFfiCall(withFfiTransport(ctx), info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+	return handler(ctx, req)
+}
+
+// FfiStreamInterceptor is FfiUnaryInterceptor for streaming calls.
+func (e *Engine) FfiStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	_, ctx, end, err := e.BeginFfiCall(withFfiTransport(ss.Context()), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	defer end()
+	return handler(srv, &trackedStream{ServerStream: ss, ctx: ctx})
+}
+
+// callDart runs the Dart callback cb as a call Shutdown drains. If Shutdown
+// gives up on it, callDart returns Unavailable without waiting for Dart.
+func (e *Engine) callDart(cb DartCallbackFunc, method string, data []byte) ([]byte, error) {
+	ctx, end, err := e.dartCalls.begin(context.Background(), method)
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+
+	type result struct {
+		data []byte
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		data, err := cb(method, data)
+		done <- result{data, err}
+	}()
+	select {
+	case r := <-done:
+		return r.data, r.err
+	case <-ctx.Done():
+		return nil, errStopping
+	}
+}
+
+// waitSessions waits until every stream session is closed or ctx is done,
+// and reports which happened first.
+func (e *Engine) waitSessions(ctx context.Context) bool {
+	for {
+		e.sessionsMu.RLock()
+		n := len(e.sessions)
+		e.sessionsMu.RUnlock()
+		if n == 0 {
+			return true
+		}
+		select {
+		case <-e.sessionClosed:
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+// closeSessions closes every stream session and returns their methods,
+// sorted.
+func (e *Engine) closeSessions() []string {
+	e.sessionsMu.RLock()
+	var (
+		ids     []int64
+		methods []string
+	)
+	for id, s := range e.sessions {
+		ids = append(ids, id)
+		methods = append(methods, s.Method)
+	}
+	e.sessionsMu.RUnlock()
+	for _, id := range ids {
+		e.CloseStreamSession(id)
+	}
+	sort.Strings(methods)
+	return methods
+}
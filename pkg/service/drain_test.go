@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestCallTracker(t *testing.T) {
+	var tr callTracker
+	_, endA, err := tr.begin(context.Background(), "a")
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	ctxB, endB, _ := tr.begin(context.Background(), "b")
+
+	tr.setClosed(true)
+	if _, _, err := tr.begin(context.Background(), "c"); status.Code(err) != codes.Unavailable {
+		t.Errorf("begin on a closed tracker = %v, want Unavailable", err)
+	}
+
+	endA()
+	endA() // end is idempotent
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if tr.wait(ctx) {
+		t.Error("wait returned with call b in progress")
+	}
+
+	if got := tr.cancelAll(); !reflect.DeepEqual(got, []string{"b"}) {
+		t.Errorf("cancelAll = %v, want [b]", got)
+	}
+	if ctxB.Err() == nil {
+		t.Error("cancelAll did not cancel call b")
+	}
+	go endB()
+	if !tr.wait(context.Background()) {
+		t.Error("wait did not return once the calls ended")
+	}
+}
+
+func TestEngine_ShutdownDrains(t *testing.T) {
+	e := NewEngine()
+	if _, err := e.Start(&Config{}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	e.RegisterServerStreamHandler("test/drain", func([]byte) HandlerFunc {
+		return func(*StreamSession) {}
+	})
+
+	ctx, end, err := e.BeginCall(context.Background(), "/test/Slow")
+	if err != nil {
+		t.Fatalf("BeginCall: %v", err)
+	}
+
+	type result struct {
+		report ShutdownReport
+		ok     bool
+	}
+	done := make(chan result, 1)
+	go func() {
+		report, ok := e.Shutdown(context.Background())
+		done <- result{report, ok}
+	}()
+	for !e.draining.Load() {
+		time.Sleep(time.Millisecond)
+	}
+
+	if _, _, err := e.BeginCall(context.Background(), "/test/New"); status.Code(err) != codes.Unavailable {
+		t.Errorf("BeginCall while stopping = %v, want Unavailable", err)
+	}
+	if id := e.HandleServerStreamWithMeta("test/drain", nil, nil); id != -1 {
+		t.Errorf("stream started while stopping (stream %d)", id)
+	}
+	select {
+	case <-done:
+		t.Fatal("Shutdown returned with a call in progress")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if ctx.Err() != nil {
+		t.Error("graceful stop cancelled the call in progress")
+	}
+	end()
+	r := <-done
+	if !r.ok || r.report.Forced() {
+		t.Errorf("Shutdown = %v, %v; want a clean stop", r.report, r.ok)
+	}
+	if e.Core() != nil {
+		t.Error("engine still running after Shutdown")
+	}
+	if _, end, err := e.BeginCall(context.Background(), "/test/After"); err != nil {
+		t.Errorf("BeginCall after Shutdown: %v", err)
+	} else {
+		end()
+	}
+}
+
+func TestEngine_ShutdownForces(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "drain.sock")
+	e := NewEngine()
+	blockDesc := &grpc.ServiceDesc{
+		ServiceName: "test.Block",
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: "Wait",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				in := new(emptypb.Empty)
+				if err := dec(in); err != nil {
+					return nil, err
+				}
+				info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/test.Block/Wait"}
+				return interceptor(ctx, in, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+					<-ctx.Done()
+					return nil, ctx.Err()
+				})
+			},
+		}},
+	}
+	_, err := e.Start(&Config{EngineSocketPath: path}, func(srv *grpc.Server, _ *CoreServiceServer) {
+		srv.RegisterService(blockDesc, struct{}{})
+	})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	// One of each: an RPC, an FFI call, a stream session and a Dart request
+	conn, err := grpc.Dial("unix://"+path, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer conn.Close()
+	rpcErr := make(chan error, 1)
+	go func() {
+		rpcErr <- conn.Invoke(context.Background(), "/test.Block/Wait", &emptypb.Empty{}, &emptypb.Empty{})
+	}()
+
+	ffiCtx, end, err := e.BeginCall(context.Background(), "/test/Ffi")
+	if err != nil {
+		t.Fatalf("BeginCall: %v", err)
+	}
+	defer end()
+
+	e.NewStreamSession("/test/Stream", StreamTypeServerStream)
+
+	block := make(chan struct{})
+	defer close(block)
+	e.SetDartCallback(func(string, []byte) ([]byte, error) {
+		<-block
+		return nil, nil
+	})
+	dartErr := make(chan error, 1)
+	core := e.Core()
+	go func() {
+		dartErr <- core.InvokeDart("/dart/Block", &emptypb.Empty{}, &emptypb.Empty{})
+	}()
+
+	for {
+		e.rpcs.mu.Lock()
+		e.dartCalls.mu.Lock()
+		n := len(e.rpcs.calls) + len(e.dartCalls.calls)
+		e.dartCalls.mu.Unlock()
+		e.rpcs.mu.Unlock()
+		if n == 2 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	report, ok := e.Shutdown(ctx)
+	if !ok {
+		t.Fatal("Shutdown reported the engine was not running")
+	}
+	want := ShutdownReport{
+		RPCs:      []string{"/test.Block/Wait"},
+		FfiCalls:  []string{"/test/Ffi"},
+		Streams:   []string{"/test/Stream"},
+		DartCalls: []string{"/dart/Block"},
+	}
+	if !reflect.DeepEqual(report, want) {
+		t.Errorf("report = %+v, want %+v", report, want)
+	}
+	if got := report.Proto().GetDartCalls(); !reflect.DeepEqual(got, want.DartCalls) {
+		t.Errorf("Proto().DartCalls = %v", got)
+	}
+
+	if ffiCtx.Err() == nil {
+		t.Error("FFI call not cancelled")
+	}
+	if err := <-rpcErr; err == nil {
+		t.Error("RPC succeeded after a forced stop")
+	}
+	if err := <-dartErr; status.Code(err) != codes.Unavailable {
+		t.Errorf("InvokeDart = %v, want Unavailable", err)
+	}
+	if e.Core() != nil {
+		t.Error("engine still running after Shutdown")
+	}
+}
@@ -0,0 +1,493 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// ErrEngineStarted is returned by Engine.Start if the engine is running.
+var ErrEngineStarted = errors.New("engine already started")
+
+// pendingCallsTimeout bounds how long Engine.Shutdown waits for cancelled
+// asynchronous FFI calls to deliver their results.
+const pendingCallsTimeout = 5 * time.Second
+
+// Engine is one isolated synurang instance. It owns its gRPC server and
+// listeners, the core services (and so the cache), the FFI stream sessions
+// and stream handler registry, the Dart callbacks and the asynchronous FFI
+// calls in flight, so several engines can run side by side in one process.
+//
+// The package-level stream and callback functions act on the default engine
+// (see DefaultEngine), which is what the legacy single-engine FFI exports use.
+type Engine struct {
+	lifecycle sync.Mutex // serializes Start and Stop
+
+	mu        sync.RWMutex
+	cfg       *Config
+	core      *CoreServiceServer
+	srv       *grpc.Server
+	listeners []net.Listener
+	errs      chan error
+	status    Status
+
+	// Calls in progress, which Shutdown drains
+	draining      atomic.Bool
+	rpcs          callTracker   // gRPC calls over TCP/UDS
+	ffiCalls      callTracker   // unary FFI calls (BeginCall)
+	dartCalls     callTracker   // Go -> Dart FFI callback requests
+	sessionClosed chan struct{} // signalled by CloseStreamSession
+
+	sessions   map[int64]*StreamSession
+	sessionsMu sync.RWMutex
+	timeout    atomic.Int64 // stream ready timeout (time.Duration)
+
+	serverStreamHandlers map[string]ServerStreamHandler
+	clientStreamHandlers map[string]ClientStreamHandler
+	bidiStreamHandlers   map[string]BidiStreamHandler
+	handlersMu           sync.RWMutex
+
+	descriptors descriptorRegistry // services served outside the gRPC server
+
+	healthMu     sync.RWMutex
+	healthChecks map[string]HealthCheck
+	healthGen    uint64            // bumped when healthChecks change
+	plugins      map[string]Plugin // by source, see RegisterPlugin
+
+	callbackMu        sync.RWMutex
+	streamCallback    StreamCallback
+	streamCallbackFfi StreamCallbackFfi
+	dartCallback      DartCallbackFunc
+	serveError        func(ListenerStatus)
+
+	// Calls tracks the asynchronous FFI calls (InvokeBackendAsync) in flight.
+	// Shutdown cancels them once the drain is over.
+	Calls PendingCalls
+}
+
+// NewEngine creates a stopped engine with no stream handlers registered.
+func NewEngine() *Engine {
+	return &Engine{
+		sessions:             make(map[int64]*StreamSession),
+		serverStreamHandlers: make(map[string]ServerStreamHandler),
+		clientStreamHandlers: make(map[string]ClientStreamHandler),
+		bidiStreamHandlers:   make(map[string]BidiStreamHandler),
+		sessionClosed:        make(chan struct{}, 1),
+	}
+}
+
+var defaultEngine = NewEngine()
+
+// DefaultEngine returns the engine used by the package-level functions.
+func DefaultEngine() *Engine {
+	return defaultEngine
+}
+
+// Start listens on the UDS and TCP endpoints of cfg, creates the core
+// services and serves them together with the services of registrars. With no
+// endpoint the engine runs in FFI-only mode. Listen failures are logged and
+// the endpoint skipped, unless cfg.FailOnListenError is set: then Start
+// closes the endpoints it did bind and fails with the first listen error.
+//
+// The returned Status reports every endpoint and the cache, also on failure.
+func (e *Engine) Start(cfg *Config, registrars ...ServiceRegistrar) (Status, error) {
+	e.lifecycle.Lock()
+	defer e.lifecycle.Unlock()
+	if e.Server() != nil {
+		st := e.Status()
+		st.Err = ErrEngineStarted
+		return st, ErrEngineStarted
+	}
+
+	e.SetStreamTimeout(cfg.StreamTimeout)
+
+	endpoints := listen(cfg)
+	var tlsCfg *serverTLS
+	for i := range endpoints {
+		ep := &endpoints[i]
+		if ep.status.Network != "tcp" || ep.listener == nil {
+			continue
+		}
+		// Never serve plaintext TCP when TLS was asked for
+		var err error
+		if tlsCfg, err = loadServerTLS(cfg); err != nil {
+			log.Printf("Engine - TCP listener disabled: %v", err)
+			ep.listener.Close()
+			ep.listener = nil
+			ep.status.BoundAddress = ""
+			ep.status.Err = err
+		} else if tlsCfg != nil {
+			ep.status.TLSFingerprint = tlsCfg.fingerprint
+		}
+	}
+	var (
+		st        Status
+		listeners []net.Listener
+	)
+	for _, ep := range endpoints {
+		st.Listeners = append(st.Listeners, ep.status)
+		if ep.listener != nil {
+			listeners = append(listeners, ep.listener)
+		} else if cfg.FailOnListenError && st.Err == nil {
+			st.Err = ep.status.Err
+		}
+	}
+	if st.Err != nil {
+		closeListeners(listeners)
+		return st, st.Err
+	}
+
+	core := newCoreService(cfg, e)
+	if core.policyErr != nil {
+		core.Close()
+		closeListeners(listeners)
+		st.Err = core.policyErr
+		return st, st.Err
+	}
+	var tlsConfig *tls.Config
+	if tlsCfg != nil {
+		tlsConfig = tlsCfg.config
+	}
+	srv := newGrpcServer(core, cfg, tlsConfig, registrars...)
+	errs := make(chan error, len(listeners)+1)
+	st.Running = true
+	st.CacheEnabled = core.CacheServiceServer != nil
+	st.CacheErr = core.cacheErr
+
+	e.mu.Lock()
+	e.cfg = cfg
+	e.core = core
+	e.srv = srv
+	e.listeners = listeners
+	e.errs = errs
+	e.status = st
+	e.status.Listeners = append([]ListenerStatus(nil), st.Listeners...)
+	e.mu.Unlock()
+
+	if len(listeners) == 0 {
+		log.Println("Engine running in FFI-only mode")
+	}
+	for i, ep := range endpoints {
+		if ep.listener == nil {
+			continue
+		}
+		go func(i int, l net.Listener) {
+			log.Printf("Engine serving on %s", l.Addr().String())
+			if err := srv.Serve(l); err != nil {
+				log.Printf("Server error: %v", err)
+				e.serveFailed(srv, i, err)
+				errs <- err
+			}
+		}(i, ep.listener)
+	}
+	return st, nil
+}
+
+// serveFailed records that Serve of listener i of srv ended with err and
+// reports it to the serve error callback.
+func (e *Engine) serveFailed(srv *grpc.Server, i int, err error) {
+	e.mu.Lock()
+	if e.srv != srv {
+		// Stopped (and maybe restarted) meanwhile
+		e.mu.Unlock()
+		return
+	}
+	e.status.Listeners[i].Err = err
+	ls := e.status.Listeners[i]
+	e.mu.Unlock()
+
+	e.callbackMu.RLock()
+	cb := e.serveError
+	e.callbackMu.RUnlock()
+	if cb != nil {
+		cb(ls)
+	}
+}
+
+// endpoint is the result of listening on one configured endpoint.
+type endpoint struct {
+	address  string // address passed to net.Listen
+	status   ListenerStatus
+	listener net.Listener // nil if listening failed
+}
+
+// socketSeq numbers the sockets named by socketPath.
+var socketSeq atomic.Int64
+
+// socketPath resolves AutoSocketPath and AbstractSocketPath to a unique
+// socket name; other paths are returned as is.
+func socketPath(path string) string {
+	name := fmt.Sprintf("synurang-%d-%d.sock", os.Getpid(), socketSeq.Add(1))
+	switch path {
+	case AutoSocketPath:
+		return filepath.Join(os.TempDir(), name)
+	case AbstractSocketPath:
+		return "@" + name
+	}
+	return path
+}
+
+// isAbstractSocket reports whether path names a Linux abstract socket,
+// which has no file to stat or remove.
+func isAbstractSocket(path string) bool {
+	return strings.HasPrefix(path, "@")
+}
+
+// listen opens the endpoints of cfg concurrently. The result is in
+// configuration order, UDS first.
+func listen(cfg *Config) []endpoint {
+	var (
+		endpoints []endpoint
+		wg        sync.WaitGroup
+	)
+	if cfg.EngineSocketPath != "" {
+		path := socketPath(cfg.EngineSocketPath)
+		if !isAbstractSocket(path) {
+			if _, err := os.Stat(path); err == nil {
+				os.Remove(path)
+			}
+		}
+		endpoints = append(endpoints, endpoint{
+			address: path,
+			status:  ListenerStatus{Network: "unix", Address: cfg.EngineSocketPath},
+		})
+	}
+	if cfg.EngineTcpPort != "" {
+		address := ":" + cfg.EngineTcpPort
+		endpoints = append(endpoints, endpoint{
+			address: address,
+			status:  ListenerStatus{Network: "tcp", Address: address},
+		})
+	}
+
+	for i := range endpoints {
+		wg.Add(1)
+		go func(ep *endpoint) {
+			defer wg.Done()
+			var (
+				l   net.Listener
+				err error
+			)
+			if ep.status.Network == "unix" && !isAbstractSocket(ep.address) {
+				l, err = listenUnix(ep.address, cfg)
+			} else {
+				l, err = net.Listen(ep.status.Network, ep.address)
+			}
+			if err != nil {
+				log.Printf("Failed to listen on %s: %v", ep.status.Network, err)
+				ep.status.Err = err
+				return
+			}
+			ep.listener = l
+			ep.status.BoundAddress = l.Addr().String()
+		}(&endpoints[i])
+	}
+	wg.Wait()
+	return endpoints
+}
+
+// closeListeners closes listeners and removes their unix socket files.
+func closeListeners(listeners []net.Listener) {
+	for _, l := range listeners {
+		l.Close()
+		if addr := l.Addr(); addr.Network() == "unix" && !isAbstractSocket(addr.String()) {
+			os.Remove(addr.String())
+		}
+	}
+}
+
+// Stop shuts the engine down, letting the calls in progress finish for
+// ShutdownTimeout (see Shutdown). It reports false if the engine was not
+// running.
+func (e *Engine) Stop() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), e.ShutdownTimeout())
+	defer cancel()
+	_, ok := e.Shutdown(ctx)
+	return ok
+}
+
+// ShutdownTimeout returns how long Stop lets calls finish: the
+// Config.ShutdownTimeout the engine was started with, or
+// DefaultShutdownTimeout.
+func (e *Engine) ShutdownTimeout() time.Duration {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.cfg != nil && e.cfg.ShutdownTimeout != 0 {
+		return e.cfg.ShutdownTimeout
+	}
+	return DefaultShutdownTimeout
+}
+
+// Shutdown stops the engine gracefully. New gRPC calls, FFI calls and FFI
+// streams are rejected at once, while the calls, stream sessions and Dart
+// callback requests in progress may finish until ctx is done. What is still
+// running then is terminated, in order: listeners, RPCs, FFI calls, Dart
+// requests and stream sessions. Last the core services close, and with them
+// the cache.
+//
+// The report lists what was terminated. Shutdown reports false if the engine
+// was not running.
+func (e *Engine) Shutdown(ctx context.Context) (ShutdownReport, bool) {
+	e.lifecycle.Lock()
+	defer e.lifecycle.Unlock()
+
+	var report ShutdownReport
+	e.mu.RLock()
+	core, srv, listeners := e.core, e.srv, e.listeners
+	e.mu.RUnlock()
+	if srv == nil {
+		return report, false
+	}
+
+	e.draining.Store(true)
+	core.stopWatches()
+	e.rpcs.setClosed(true)
+	e.ffiCalls.setClosed(true)
+	defer func() {
+		e.rpcs.setClosed(false)
+		e.ffiCalls.setClosed(false)
+		e.dartCalls.setClosed(false)
+		e.draining.Store(false)
+	}()
+
+	// GracefulStop closes the listeners and waits for the RPCs in progress
+	stopped := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(stopped)
+	}()
+
+	drained := false
+	select {
+	case <-stopped:
+		drained = e.ffiCalls.wait(ctx) && e.waitSessions(ctx) && e.dartCalls.wait(ctx)
+	case <-ctx.Done():
+	}
+	closeListeners(listeners)
+	if !drained {
+		report.RPCs = e.rpcs.cancelAll()
+		srv.Stop()
+		<-stopped
+		report.FfiCalls = e.ffiCalls.cancelAll()
+		e.dartCalls.setClosed(true)
+		report.DartCalls = e.dartCalls.cancelAll()
+		report.Streams = e.closeSessions()
+	}
+
+	// Pending async calls fail with Canceled (or Unavailable if they had
+	// not reached a handler yet) and deliver their callbacks
+	if !e.Calls.CancelAll(pendingCallsTimeout) {
+		log.Printf("Engine - %d async calls still running after %v", e.Calls.Len(), pendingCallsTimeout)
+	}
+
+	core.Close()
+
+	e.mu.Lock()
+	e.cfg, e.core, e.srv, e.listeners = nil, nil, nil, nil
+	e.status = Status{}
+	e.mu.Unlock()
+
+	log.Printf("Engine stopped: %v", report)
+	return report, true
+}
+
+// Core returns the core services of a running engine, or nil.
+func (e *Engine) Core() *CoreServiceServer {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.core
+}
+
+// Server returns the gRPC server of a running engine, or nil.
+func (e *Engine) Server() *grpc.Server {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.srv
+}
+
+// Listeners returns the listeners the engine is serving on.
+func (e *Engine) Listeners() []net.Listener {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return append([]net.Listener(nil), e.listeners...)
+}
+
+// Status returns the state of the engine: the status Start returned, with
+// the errors that later ended a listener's Serve. A stopped engine reports
+// the zero Status.
+func (e *Engine) Status() Status {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	st := e.status
+	st.Listeners = append([]ListenerStatus(nil), e.status.Listeners...)
+	return st
+}
+
+// Errors returns the channel receiving the errors that end a listener's
+// Serve. It is nil until the engine is first started.
+func (e *Engine) Errors() <-chan error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.errs
+}
+
+// SetStreamTimeout sets how long stream handlers wait for Dart's ready
+// signal; 0 waits indefinitely.
+func (e *Engine) SetStreamTimeout(d time.Duration) {
+	e.timeout.Store(int64(d))
+}
+
+// SetStreamCallback sets the callback for sending stream data to Dart (1 copy).
+func (e *Engine) SetStreamCallback(cb StreamCallback) {
+	e.callbackMu.Lock()
+	e.streamCallback = cb
+	e.callbackMu.Unlock()
+}
+
+// SetStreamCallbackFfi sets the zero-copy callback for FFI mode.
+func (e *Engine) SetStreamCallbackFfi(cb StreamCallbackFfi) {
+	e.callbackMu.Lock()
+	e.streamCallbackFfi = cb
+	e.callbackMu.Unlock()
+}
+
+// SetServeErrorCallback sets the function called, from the serving
+// goroutine, when an error ends a listener's Serve after Start succeeded.
+func (e *Engine) SetServeErrorCallback(cb func(ListenerStatus)) {
+	e.callbackMu.Lock()
+	e.serveError = cb
+	e.callbackMu.Unlock()
+}
+
+// SetDartCallback sets the function the engine uses to call Dart over FFI.
+// On the default engine this is the package-level DartCallback.
+func (e *Engine) SetDartCallback(cb DartCallbackFunc) {
+	if e == defaultEngine {
+		DartCallback = cb
+		return
+	}
+	e.callbackMu.Lock()
+	e.dartCallback = cb
+	e.callbackMu.Unlock()
+}
+
+// DartCallback returns the function set with SetDartCallback, or nil.
+func (e *Engine) DartCallback() DartCallbackFunc {
+	if e == defaultEngine {
+		return DartCallback
+	}
+	e.callbackMu.RLock()
+	defer e.callbackMu.RUnlock()
+	return e.dartCallback
+}
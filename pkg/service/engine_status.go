@@ -0,0 +1,68 @@
+package service
+
+import (
+	"errors"
+
+	pb "github.com/ivere27/synurang/pkg/api"
+
+	"google.golang.org/grpc/codes"
+)
+
+// ListenerStatus describes one endpoint an engine was configured to serve on.
+type ListenerStatus struct {
+	Network        string // "unix" or "tcp"
+	Address        string // as configured
+	BoundAddress   string // actually bound; empty if listening failed
+	TLSFingerprint string // of the TLS certificate served (see Fingerprint); empty if plaintext
+	Err            error  // why listening failed, or the error that ended Serve
+}
+
+// Status is the result of Engine.Start and, from Engine.Status, the state of
+// the engine afterwards.
+type Status struct {
+	Running      bool
+	Listeners    []ListenerStatus // in configuration order: UDS, then TCP
+	CacheEnabled bool
+	CacheErr     error // why the cache could not be opened, if requested
+	Err          error // why Start failed
+}
+
+// Proto converts st to the ServerStatus returned over FFI. Start failures
+// map to FailedPrecondition (already started) or Unavailable, listener and
+// cache errors to Unavailable.
+func (st Status) Proto() *pb.ServerStatus {
+	out := &pb.ServerStatus{
+		Running:      st.Running,
+		Listeners:    make([]*pb.ListenerStatus, len(st.Listeners)),
+		CacheEnabled: st.CacheEnabled,
+		CacheError:   statusError(st.CacheErr, codes.Unavailable),
+	}
+	for i, l := range st.Listeners {
+		out.Listeners[i] = l.Proto()
+	}
+	if errors.Is(st.Err, ErrEngineStarted) {
+		out.Error = statusError(st.Err, codes.FailedPrecondition)
+	} else {
+		out.Error = statusError(st.Err, codes.Unavailable)
+	}
+	return out
+}
+
+// Proto converts l to the ListenerStatus returned over FFI.
+func (l ListenerStatus) Proto() *pb.ListenerStatus {
+	return &pb.ListenerStatus{
+		Network:        l.Network,
+		Address:        l.Address,
+		BoundAddress:   l.BoundAddress,
+		TlsFingerprint: l.TLSFingerprint,
+		Error:          statusError(l.Err, codes.Unavailable),
+	}
+}
+
+// statusError converts err to a pb.Error with the given gRPC code, or nil.
+func statusError(err error, code codes.Code) *pb.Error {
+	if err == nil {
+		return nil
+	}
+	return &pb.Error{Message: err.Error(), GrpcCode: int32(code)}
+}
@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/ivere27/synurang/pkg/api"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestEngine_Isolation(t *testing.T) {
+	a, b := NewEngine(), NewEngine()
+
+	var mu sync.Mutex
+	got := map[*Engine][]byte{}
+	for _, e := range []*Engine{a, b} {
+		e := e
+		e.SetStreamCallback(func(streamId int64, msgType byte, data []byte) {
+			if msgType == StreamMsgData {
+				mu.Lock()
+				got[e] = append(got[e], data...)
+				mu.Unlock()
+			}
+		})
+	}
+
+	done := make(chan struct{})
+	a.RegisterServerStreamHandler("test/engine", func(data []byte) HandlerFunc {
+		return func(s *StreamSession) {
+			defer close(done)
+			s.SendFromStream(data)
+		}
+	})
+
+	if id := b.HandleServerStreamWithMeta("test/engine", nil, nil); id != -1 {
+		t.Fatalf("engine b ran a handler registered on engine a (stream %d)", id)
+	}
+	id := a.HandleServerStreamWithMeta("test/engine", []byte{7}, nil)
+	if id <= 0 {
+		t.Fatalf("HandleServerStreamWithMeta = %d", id)
+	}
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	if string(got[a]) != "\x07" || len(got[b]) != 0 {
+		t.Errorf("stream data went to the wrong engine: a=%v b=%v", got[a], got[b])
+	}
+
+	s := b.NewStreamSession("test/engine", StreamTypeClientStream)
+	defer b.CloseStreamSession(s.ID)
+	if a.GetStreamSession(s.ID) != nil || GetStreamSession(s.ID) != nil {
+		t.Error("session of engine b is visible from another engine")
+	}
+	if err := a.SendToStream(s.ID, []byte{1}); err == nil {
+		t.Error("SendToStream on engine a reached a session of engine b")
+	}
+}
+
+func TestEngine_StartStop(t *testing.T) {
+	dir := t.TempDir()
+
+	var wg sync.WaitGroup
+	for _, name := range []string{"a.sock", "b.sock"} {
+		path := filepath.Join(dir, name)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e := NewEngine()
+			if _, err := e.Start(&Config{EngineSocketPath: path}); err != nil {
+				t.Errorf("Start: %v", err)
+				return
+			}
+			if _, err := e.Start(&Config{}); err != ErrEngineStarted {
+				t.Errorf("second Start = %v, want ErrEngineStarted", err)
+			}
+			if e.Core() == nil || e.Core().Engine() != e {
+				t.Error("Core does not belong to the engine")
+			}
+
+			conn, err := grpc.Dial("unix://"+path, grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if err != nil {
+				t.Errorf("Dial: %v", err)
+			} else {
+				if _, err := pb.NewHealthServiceClient(conn).Ping(context.Background(), &emptypb.Empty{}); err != nil {
+					t.Errorf("Ping %s: %v", path, err)
+				}
+				conn.Close()
+			}
+
+			if !e.Stop() {
+				t.Error("Stop reported the engine was not running")
+			}
+			if e.Stop() || e.Core() != nil {
+				t.Error("engine still running after Stop")
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestEngine_StartStatus(t *testing.T) {
+	badSocket := filepath.Join(t.TempDir(), "missing", "engine.sock")
+
+	e := NewEngine()
+	serveErrs := make(chan ListenerStatus, 1)
+	e.SetServeErrorCallback(func(ls ListenerStatus) { serveErrs <- ls })
+
+	st, err := e.Start(&Config{EngineSocketPath: badSocket, EngineTcpPort: "0"})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer e.Stop()
+	if !st.Running || len(st.Listeners) != 2 || st.CacheEnabled || st.CacheErr != nil {
+		t.Fatalf("status = %+v", st)
+	}
+	if uds := st.Listeners[0]; uds.Network != "unix" || uds.Address != badSocket || uds.BoundAddress != "" || uds.Err == nil {
+		t.Errorf("unix listener = %+v, want a listen error", uds)
+	}
+	tcp := st.Listeners[1]
+	if tcp.Network != "tcp" || tcp.Address != ":0" || tcp.BoundAddress == "" || tcp.Err != nil {
+		t.Errorf("tcp listener = %+v, want bound", tcp)
+	}
+	if code := st.Proto().Listeners[0].Error.GetGrpcCode(); code != int32(codes.Unavailable) {
+		t.Errorf("listen error code = %d, want Unavailable", code)
+	}
+
+	// Closing the listener behind the server's back ends its Serve
+	e.Listeners()[0].Close()
+	select {
+	case ls := <-serveErrs:
+		if ls.Network != "tcp" || ls.Err == nil {
+			t.Errorf("serve error = %+v", ls)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("serve error callback not called")
+	}
+	if got := e.Status().Listeners[1]; got.Err == nil || got.BoundAddress != tcp.BoundAddress {
+		t.Errorf("Status listener after serve error = %+v", got)
+	}
+
+	st, err = e.Start(&Config{})
+	if err != ErrEngineStarted || !st.Running {
+		t.Errorf("second Start = %+v, %v", st, err)
+	}
+	if code := st.Proto().Error.GetGrpcCode(); code != int32(codes.FailedPrecondition) {
+		t.Errorf("already started code = %d, want FailedPrecondition", code)
+	}
+
+	e.Stop()
+	if st := e.Status(); st.Running || len(st.Listeners) != 0 {
+		t.Errorf("Status after Stop = %+v", st)
+	}
+}
+
+func TestEngine_FailOnListenError(t *testing.T) {
+	badSocket := filepath.Join(t.TempDir(), "missing", "engine.sock")
+
+	e := NewEngine()
+	st, err := e.Start(&Config{EngineSocketPath: badSocket, EngineTcpPort: "0", FailOnListenError: true})
+	if err == nil {
+		e.Stop()
+		t.Fatal("Start succeeded with an unbindable socket")
+	}
+	if st.Running || st.Err != err || e.Server() != nil || e.Core() != nil {
+		t.Errorf("failed Start left the engine running: %+v", st)
+	}
+
+	// The TCP endpoint was bound, then released
+	addr := st.Listeners[1].BoundAddress
+	if addr == "" {
+		t.Fatalf("tcp listener = %+v", st.Listeners[1])
+	}
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		t.Errorf("tcp endpoint not released: %v", err)
+	} else {
+		l.Close()
+	}
+
+	if st, err := e.Start(&Config{}); err != nil || !st.Running {
+		t.Errorf("Start after a failed Start = %+v, %v", st, err)
+	}
+	e.Stop()
+}
+
+func TestEngine_DynamicEndpoints(t *testing.T) {
+	paths := []string{AutoSocketPath}
+	if runtime.GOOS == "linux" {
+		paths = append(paths, AbstractSocketPath)
+	}
+	for _, path := range paths {
+		t.Run(path, func(t *testing.T) {
+			// Two engines with the same configuration must not collide
+			a, b := NewEngine(), NewEngine()
+			cfg := &Config{EngineSocketPath: path, EngineTcpPort: "0"}
+			stA, err := a.Start(cfg)
+			if err != nil {
+				t.Fatalf("Start a: %v", err)
+			}
+			defer a.Stop()
+			stB, err := b.Start(cfg)
+			if err != nil {
+				t.Fatalf("Start b: %v", err)
+			}
+			defer b.Stop()
+			for i := range stA.Listeners {
+				if stA.Listeners[i].Err != nil || stB.Listeners[i].Err != nil ||
+					stA.Listeners[i].BoundAddress == stB.Listeners[i].BoundAddress {
+					t.Fatalf("listeners a=%+v b=%+v", stA.Listeners[i], stB.Listeners[i])
+				}
+			}
+			if got := stA.Listeners[0].Address; got != path {
+				t.Errorf("configured address = %q, want %q", got, path)
+			}
+
+			// Both bound endpoints serve the engine's status
+			for _, target := range []string{"unix:" + stA.Listeners[0].BoundAddress, stA.Listeners[1].BoundAddress} {
+				conn, err := grpc.Dial(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+				if err != nil {
+					t.Fatalf("Dial %s: %v", target, err)
+				}
+				got, err := pb.NewHealthServiceClient(conn).GetServerStatus(context.Background(), &emptypb.Empty{})
+				conn.Close()
+				if err != nil {
+					t.Fatalf("GetServerStatus via %s: %v", target, err)
+				}
+				if !proto.Equal(got, stA.Proto()) {
+					t.Errorf("GetServerStatus via %s = %v, want %v", target, got, stA.Proto())
+				}
+			}
+
+			socket := stA.Listeners[0].BoundAddress
+			a.Stop()
+			if path == AutoSocketPath {
+				if _, err := os.Stat(socket); !os.IsNotExist(err) {
+					t.Errorf("socket %s left behind after Stop: %v", socket, err)
+				}
+			}
+		})
+	}
+}
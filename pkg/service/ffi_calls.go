@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// PendingCalls tracks the asynchronous unary FFI calls (InvokeBackendAsync)
+// in flight, keyed by the caller-chosen call ID, so they can be cancelled
+// individually or all at once when the server stops.
+type PendingCalls struct {
+	mu    sync.Mutex
+	calls map[int64]context.CancelFunc
+	wg    sync.WaitGroup
+}
+
+// Add registers call id with the cancel func of its context. It reports
+// false if id is already in flight. Each successful Add must be paired with
+// a Done once the call's result has been delivered.
+func (p *PendingCalls) Add(id int64, cancel context.CancelFunc) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, exists := p.calls[id]; exists {
+		return false
+	}
+	if p.calls == nil {
+		p.calls = make(map[int64]context.CancelFunc)
+	}
+	p.calls[id] = cancel
+	p.wg.Add(1)
+	return true
+}
+
+// Done forgets call id; a later Cancel with the same ID is a no-op.
+func (p *PendingCalls) Done(id int64) {
+	p.mu.Lock()
+	cancel, ok := p.calls[id]
+	delete(p.calls, id)
+	p.mu.Unlock()
+	if ok {
+		cancel()
+		p.wg.Done()
+	}
+}
+
+// Cancel cancels the context of call id. It reports whether the call was in
+// flight; its result is still delivered, typically as codes.Canceled.
+func (p *PendingCalls) Cancel(id int64) bool {
+	p.mu.Lock()
+	cancel, ok := p.calls[id]
+	p.mu.Unlock()
+	if ok {
+		cancel()
+	}
+	return ok
+}
+
+// Len returns the number of calls in flight.
+func (p *PendingCalls) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.calls)
+}
+
+// CancelAll cancels every call in flight and waits up to timeout for their
+// results to be delivered. It reports whether all of them were.
+func (p *PendingCalls) CancelAll(timeout time.Duration) bool {
+	p.mu.Lock()
+	for _, cancel := range p.calls {
+		cancel()
+	}
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPendingCalls(t *testing.T) {
+	var p PendingCalls
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if !p.Add(1, cancel) {
+		t.Fatal("expected the first call to be accepted")
+	}
+	if p.Add(1, func() {}) {
+		t.Error("expected a duplicate call ID to be rejected")
+	}
+	if n := p.Len(); n != 1 {
+		t.Errorf("expected 1 call in flight, got %d", n)
+	}
+
+	if !p.Cancel(1) {
+		t.Error("expected Cancel to find the call")
+	}
+	if ctx.Err() == nil {
+		t.Error("expected the call's context to be cancelled")
+	}
+
+	p.Done(1)
+	if p.Cancel(1) {
+		t.Error("expected Cancel after Done to be a no-op")
+	}
+	if n := p.Len(); n != 0 {
+		t.Errorf("expected no calls in flight, got %d", n)
+	}
+}
+
+func TestPendingCalls_CancelAll(t *testing.T) {
+	var p PendingCalls
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.Add(7, cancel)
+	go func() {
+		<-ctx.Done()
+		p.Done(7)
+	}()
+	if !p.CancelAll(time.Second) {
+		t.Fatal("expected the cancelled call to finish")
+	}
+
+	p.Add(8, func() {})
+	if p.CancelAll(10 * time.Millisecond) {
+		t.Error("expected CancelAll to time out on a call that never finishes")
+	}
+	p.Done(8)
+}
@@ -0,0 +1,297 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/ivere27/synurang/pkg/api"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// FfiTimeoutKey is the reserved metadata key carrying the call timeout in
+// milliseconds. It is consumed by ParseFfiMetadata and never reaches handlers.
+const FfiTimeoutKey = "__timeout_ms"
+
+// ParseFfiMetadata parses the "key=value\n" metadata sent with
+// InvokeBackendWithMeta and the stream entry points. Keys are lowercased like
+// gRPC metadata keys; repeated keys keep every value. It returns the metadata
+// (nil if empty) and the timeout in milliseconds (0 = none).
+func ParseFfiMetadata(data []byte) (metadata.MD, int64) {
+	if len(data) == 0 {
+		return nil, 0
+	}
+	var md metadata.MD
+	var timeoutMs int64
+	for _, line := range bytes.Split(data, []byte{'\n'}) {
+		idx := bytes.IndexByte(line, '=')
+		if idx <= 0 {
+			continue
+		}
+		key := strings.ToLower(string(line[:idx]))
+		value := string(line[idx+1:])
+		if key == FfiTimeoutKey {
+			timeoutMs, _ = strconv.ParseInt(value, 10, 64)
+			continue
+		}
+		if md == nil {
+			md = metadata.MD{}
+		}
+		md.Append(key, value)
+	}
+	return md, timeoutMs
+}
+
+// CallHeaderVersion is the newest pb.CallHeader version ParseCallHeader
+// understands. Version 0 (unset) is read as 1.
+const CallHeaderVersion = 1
+
+// FfiCall is the decoded request header of an FFI call.
+type FfiCall struct {
+	Metadata  metadata.MD
+	TimeoutMs int64
+	CallID    uint64
+}
+
+// ParseCallHeader decodes a serialized pb.CallHeader, the binary-safe
+// successor of the "key=value\n" format. Trace context is folded into the
+// metadata as the W3C "traceparent"/"tracestate" keys, which is where tracing
+// interceptors look for it on gRPC calls. An empty header is valid.
+func ParseCallHeader(data []byte) (FfiCall, error) {
+	var call FfiCall
+	if len(data) == 0 {
+		return call, nil
+	}
+	var h pb.CallHeader
+	if err := proto.Unmarshal(data, &h); err != nil {
+		return call, status.Errorf(codes.InvalidArgument, "malformed call header: %v", err)
+	}
+	if h.Version > CallHeaderVersion {
+		return call, status.Errorf(codes.InvalidArgument, "unsupported call header version %d", h.Version)
+	}
+
+	for _, e := range h.Metadata {
+		if e.Key == "" {
+			return call, status.Error(codes.InvalidArgument, "call header has an empty metadata key")
+		}
+		if call.Metadata == nil {
+			call.Metadata = metadata.MD{}
+		}
+		call.Metadata.Append(strings.ToLower(e.Key), string(e.Value))
+	}
+	if t := h.Trace; t != nil && t.Traceparent != "" {
+		if call.Metadata == nil {
+			call.Metadata = metadata.MD{}
+		}
+		call.Metadata.Set("traceparent", t.Traceparent)
+		if t.Tracestate != "" {
+			call.Metadata.Set("tracestate", t.Tracestate)
+		}
+	}
+	call.TimeoutMs = h.TimeoutMs
+	call.CallID = h.CallId
+	return call, nil
+}
+
+// Context returns the handler context for the call, like FfiContext, with
+// the call ID available through CallIDFromContext.
+func (c FfiCall) Context() (context.Context, context.CancelFunc) {
+	return c.ContextFrom(context.Background())
+}
+
+// ContextFrom is Context with a context derived from parent, e.g. that of a
+// call through the Synurang ABI.
+func (c FfiCall) ContextFrom(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := ffiContext(parent, c.Metadata, c.TimeoutMs)
+	if c.CallID != 0 {
+		ctx = context.WithValue(ctx, callIDKey{}, c.CallID)
+	}
+	return ctx, cancel
+}
+
+type callIDKey struct{}
+
+// CallIDFromContext returns the caller-chosen ID of the FFI call handling
+// ctx, if the call header carried one.
+func CallIDFromContext(ctx context.Context) (uint64, bool) {
+	id, ok := ctx.Value(callIDKey{}).(uint64)
+	return id, ok
+}
+
+// FfiContext builds the handler context for an FFI call: md becomes incoming
+// gRPC metadata, as it would for a call over the network, and timeoutMs (if
+// positive) becomes the deadline. TransportFromContext reports TransportFFI
+// for it. The returned cancel must always be called.
+func FfiContext(md metadata.MD, timeoutMs int64) (context.Context, context.CancelFunc) {
+	return ffiContext(context.Background(), md, timeoutMs)
+}
+
+func ffiContext(parent context.Context, md metadata.MD, timeoutMs int64) (context.Context, context.CancelFunc) {
+	ctx := withFfiTransport(parent)
+	if len(md) > 0 {
+		ctx = metadata.NewIncomingContext(ctx, md)
+	}
+	if timeoutMs > 0 {
+		return context.WithTimeout(ctx, time.Duration(timeoutMs)*time.Millisecond)
+	}
+	return context.WithCancel(ctx)
+}
+
+// withFfiTransport marks ctx as the context of an FFI call.
+func withFfiTransport(ctx context.Context) context.Context {
+	return context.WithValue(ctx, transportKey{}, TransportFFI)
+}
+
+// flattenMetadata converts md to the single-valued form of
+// StreamSession.Metadata, keeping the first value of each key.
+func flattenMetadata(md metadata.MD) map[string]string {
+	if len(md) == 0 {
+		return nil
+	}
+	flat := make(map[string]string, len(md))
+	for k, v := range md {
+		if len(v) > 0 {
+			flat[k] = v[0]
+		}
+	}
+	return flat
+}
+
+// FfiTransportStream is the grpc.ServerTransportStream of a unary FFI call.
+// It collects the metadata a handler sets with grpc.SetHeader, grpc.SendHeader
+// and grpc.SetTrailer, which would otherwise fail on the FFI path, so it can
+// be returned to the caller in a pb.CallResponse.
+type FfiTransportStream struct {
+	method string
+
+	mu         sync.Mutex
+	header     metadata.MD
+	trailer    metadata.MD
+	headerSent bool
+}
+
+// NewFfiTransportStream returns a transport stream for method and ctx with
+// the stream installed, as grpc-go does for calls over the network.
+func NewFfiTransportStream(ctx context.Context, method string) (context.Context, *FfiTransportStream) {
+	s := &FfiTransportStream{method: method}
+	return grpc.NewContextWithServerTransportStream(ctx, s), s
+}
+
+var _ grpc.ServerTransportStream = (*FfiTransportStream)(nil)
+
+// Method implements grpc.ServerTransportStream.
+func (s *FfiTransportStream) Method() string { return s.method }
+
+// SetHeader implements grpc.ServerTransportStream. Like grpc-go, it fails once
+// the header has been sent.
+func (s *FfiTransportStream) SetHeader(md metadata.MD) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.headerSent {
+		return status.Error(codes.Internal, "transport: the header has already been sent")
+	}
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+// SendHeader implements grpc.ServerTransportStream. There is no wire to write
+// to; the header is returned with the response.
+func (s *FfiTransportStream) SendHeader(md metadata.MD) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.headerSent {
+		return status.Error(codes.Internal, "transport: the header has already been sent")
+	}
+	s.header = metadata.Join(s.header, md)
+	s.headerSent = true
+	return nil
+}
+
+// SetTrailer implements grpc.ServerTransportStream.
+func (s *FfiTransportStream) SetTrailer(md metadata.MD) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.trailer = metadata.Join(s.trailer, md)
+	return nil
+}
+
+// Header returns the header metadata set so far.
+func (s *FfiTransportStream) Header() metadata.MD {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.header.Copy()
+}
+
+// Trailer returns the trailer metadata set so far.
+func (s *FfiTransportStream) Trailer() metadata.MD {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.trailer.Copy()
+}
+
+// NewCallResponse builds the response envelope of a unary FFI call from its
+// serialized response or error and the metadata collected by stream (which
+// may be nil).
+func NewCallResponse(payload []byte, err error, stream *FfiTransportStream) *pb.CallResponse {
+	resp := &pb.CallResponse{}
+	if err != nil {
+		resp.Error = ErrorProto(err)
+	} else {
+		resp.Payload = payload
+	}
+	if stream != nil {
+		resp.Headers = metadataEntries(stream.Header())
+		resp.Trailers = metadataEntries(stream.Trailer())
+	}
+	return resp
+}
+
+// ErrorProto converts err to the pb.Error returned by failed FFI calls,
+// preferring a pb.Error attached to the status as a detail. Context errors
+// map to Canceled and DeadlineExceeded as they do in grpc-go.
+func ErrorProto(err error) *pb.Error {
+	st, ok := status.FromError(err)
+	if !ok && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)) {
+		st = status.FromContextError(err)
+	}
+	if ok {
+		for _, detail := range st.Details() {
+			if e, ok := detail.(*pb.Error); ok {
+				return e
+			}
+		}
+	}
+	return &pb.Error{
+		Message:  err.Error(),
+		GrpcCode: int32(st.Code()),
+	}
+}
+
+// metadataEntries flattens md into entries sorted by key, keeping the order of
+// repeated values.
+func metadataEntries(md metadata.MD) []*pb.MetadataEntry {
+	if len(md) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(md))
+	for k := range md {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var entries []*pb.MetadataEntry
+	for _, k := range keys {
+		for _, v := range md[k] {
+			entries = append(entries, &pb.MetadataEntry{Key: k, Value: []byte(v)})
+		}
+	}
+	return entries
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/ivere27/synurang/pkg/api"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestParseFfiMetadata(t *testing.T) {
+	md, timeoutMs := ParseFfiMetadata([]byte("__timeout_ms=250\nAuthorization=Bearer t\nx-tag=a\nx-tag=b\nbogus\n=x\n"))
+	if timeoutMs != 250 {
+		t.Errorf("expected timeout 250, got %d", timeoutMs)
+	}
+	if got := md.Get("authorization"); len(got) != 1 || got[0] != "Bearer t" {
+		t.Errorf("unexpected authorization %v", got)
+	}
+	if got := md.Get("x-tag"); len(got) != 2 {
+		t.Errorf("expected both x-tag values, got %v", got)
+	}
+	if len(md) != 2 {
+		t.Errorf("expected malformed lines and the timeout to be dropped, got %v", md)
+	}
+
+	if md, timeoutMs := ParseFfiMetadata(nil); md != nil || timeoutMs != 0 {
+		t.Errorf("expected nothing for empty input, got %v, %d", md, timeoutMs)
+	}
+}
+
+func TestParseCallHeader(t *testing.T) {
+	data, _ := proto.Marshal(&pb.CallHeader{
+		Version: CallHeaderVersion,
+		Metadata: []*pb.MetadataEntry{
+			{Key: "X-Tag", Value: []byte("a")},
+			{Key: "x-tag", Value: []byte("b")},
+			{Key: "x-blob-bin", Value: []byte{0, '\n', '=', 0xff}},
+		},
+		TimeoutMs: 250,
+		CallId:    42,
+		Trace:     &pb.TraceContext{Traceparent: "00-abc-def-01", Tracestate: "k=v"},
+	})
+	call, err := ParseCallHeader(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if call.TimeoutMs != 250 || call.CallID != 42 {
+		t.Errorf("unexpected timeout %d or call ID %d", call.TimeoutMs, call.CallID)
+	}
+	if got := call.Metadata.Get("x-tag"); len(got) != 2 {
+		t.Errorf("expected both x-tag values, got %v", got)
+	}
+	if got := call.Metadata.Get("x-blob-bin"); len(got) != 1 || got[0] != "\x00\n=\xff" {
+		t.Errorf("binary value not preserved: %q", got)
+	}
+	if got := call.Metadata.Get("traceparent"); len(got) != 1 || got[0] != "00-abc-def-01" {
+		t.Errorf("expected trace context in metadata, got %v", got)
+	}
+
+	ctx, cancel := call.Context()
+	defer cancel()
+	if id, ok := CallIDFromContext(ctx); !ok || id != 42 {
+		t.Errorf("expected call ID 42 in context, got %d", id)
+	}
+
+	if call, err := ParseCallHeader(nil); err != nil || call.Metadata != nil {
+		t.Errorf("expected an empty call for empty input, got %v, %v", call, err)
+	}
+
+	newer, _ := proto.Marshal(&pb.CallHeader{Version: CallHeaderVersion + 1})
+	emptyKey, _ := proto.Marshal(&pb.CallHeader{Metadata: []*pb.MetadataEntry{{Value: []byte("x")}}})
+	for name, data := range map[string][]byte{
+		"malformed":   {0xff, 0xff},
+		"new version": newer,
+		"empty key":   emptyKey,
+	} {
+		if _, err := ParseCallHeader(data); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s: expected InvalidArgument, got %v", name, err)
+		}
+	}
+}
+
+func TestFfiContext(t *testing.T) {
+	ctx, cancel := FfiContext(metadata.Pairs("k", "v"), 50)
+	defer cancel()
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || md.Get("k")[0] != "v" {
+		t.Errorf("expected incoming metadata, got %v", md)
+	}
+	if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) > 50*time.Millisecond {
+		t.Errorf("expected a 50ms deadline, got %v", deadline)
+	}
+}
+
+func TestFfiTransportStream(t *testing.T) {
+	ctx, stream := NewFfiTransportStream(context.Background(), "/test/Method")
+	if m, ok := grpc.Method(ctx); !ok || m != "/test/Method" {
+		t.Errorf("expected the method in context, got %q", m)
+	}
+
+	if err := grpc.SetHeader(ctx, metadata.Pairs("x-h", "1")); err != nil {
+		t.Fatalf("SetHeader: %v", err)
+	}
+	if err := grpc.SendHeader(ctx, metadata.Pairs("x-h", "2")); err != nil {
+		t.Fatalf("SendHeader: %v", err)
+	}
+	if err := grpc.SetHeader(ctx, metadata.Pairs("x-late", "1")); err == nil {
+		t.Error("expected SetHeader to fail after SendHeader")
+	}
+	if err := grpc.SetTrailer(ctx, metadata.Pairs("x-t-bin", "\x00\x01")); err != nil {
+		t.Fatalf("SetTrailer: %v", err)
+	}
+
+	resp := NewCallResponse(nil, status.Error(codes.NotFound, "missing"), stream)
+	if resp.Error.GetGrpcCode() != int32(codes.NotFound) || resp.Payload != nil {
+		t.Errorf("unexpected error envelope %v", resp)
+	}
+	if len(resp.Headers) != 2 || string(resp.Headers[1].Value) != "2" {
+		t.Errorf("expected both header values in order, got %v", resp.Headers)
+	}
+	if len(resp.Trailers) != 1 || string(resp.Trailers[0].Value) != "\x00\x01" {
+		t.Errorf("expected the binary trailer, got %v", resp.Trailers)
+	}
+
+	if resp := NewCallResponse([]byte("ok"), nil, nil); string(resp.Payload) != "ok" || resp.Error != nil {
+		t.Errorf("unexpected success envelope %v", resp)
+	}
+}
+
+func TestErrorProto(t *testing.T) {
+	st, _ := status.New(codes.Internal, "wrapped").WithDetails(&pb.Error{Code: 7, Message: "detail"})
+	if e := ErrorProto(st.Err()); e.Code != 7 || e.Message != "detail" {
+		t.Errorf("expected the attached pb.Error, got %v", e)
+	}
+	if e := ErrorProto(status.Error(codes.PermissionDenied, "no")); e.GrpcCode != int32(codes.PermissionDenied) {
+		t.Errorf("unexpected code %d", e.GrpcCode)
+	}
+	if e := ErrorProto(context.Canceled); e.GrpcCode != int32(codes.Canceled) {
+		t.Errorf("expected Canceled for a context error, got %d", e.GrpcCode)
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	s := &CoreServiceServer{cfg: &Config{Token: "secret"}}
+
+	tests := []struct {
+		name string
+		meta string
+		code codes.Code
+	}{
+		{"valid", "authorization=Bearer secret\n", codes.OK},
+		{"missing metadata", "", codes.Unauthenticated},
+		{"missing token", "x-other=1\n", codes.Unauthenticated},
+		{"invalid token", "authorization=Bearer wrong\n", codes.Unauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := FfiContext(ParseFfiMetadata([]byte(tt.meta)))
+			defer cancel()
+			if code := status.Code(s.Authorize(ctx)); code != tt.code {
+				t.Errorf("expected %v, got %v", tt.code, code)
+			}
+		})
+	}
+
+	open := &CoreServiceServer{cfg: &Config{}}
+	ctx, cancel := FfiContext(nil, 0)
+	defer cancel()
+	if err := open.Authorize(ctx); err != nil {
+		t.Errorf("expected no check without a token, got %v", err)
+	}
+}
+
+func TestHandleServerStreamWithMeta_Metadata(t *testing.T) {
+	got := make(chan map[string]string, 1)
+	RegisterServerStreamHandler("test/meta_stream", func(data []byte) HandlerFunc {
+		return func(session *StreamSession) {
+			got <- session.Metadata
+		}
+	})
+	defer UnregisterAllStreamHandlers()
+
+	md, _ := ParseFfiMetadata([]byte("x-user=alice\n"))
+	if id := HandleServerStreamWithMeta("test/meta_stream", nil, md); id < 0 {
+		t.Fatal("expected the stream to start")
+	}
+	select {
+	case meta := <-got:
+		if meta["x-user"] != "alice" {
+			t.Errorf("unexpected session metadata %v", meta)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("handler did not run")
+	}
+}
@@ -16,6 +16,7 @@ import (
 // RequestHandler manages async request/response patterns for FFI calls
 type RequestHandler struct {
 	pending   map[int64]chan []byte
+	failed    map[int64]error // by FailResponse, read once ch is closed
 	pendingMu sync.Mutex
 	nextId    int64
 	timeout   time.Duration
@@ -25,6 +26,7 @@ type RequestHandler struct {
 func NewRequestHandler(timeout time.Duration) *RequestHandler {
 	return &RequestHandler{
 		pending: make(map[int64]chan []byte),
+		failed:  make(map[int64]error),
 		timeout: timeout,
 	}
 }
@@ -48,8 +50,15 @@ func (h *RequestHandler) CreateRequest() (int64, chan []byte) {
 func (h *RequestHandler) WaitForResponse(requestId int64, ch chan []byte) ([]byte, error) {
 	timer := time.NewTimer(h.timeout)
 	select {
-	case resp := <-ch:
+	case resp, ok := <-ch:
 		timer.Stop()
+		if !ok {
+			h.pendingMu.Lock()
+			err := h.failed[requestId]
+			delete(h.failed, requestId)
+			h.pendingMu.Unlock()
+			return nil, err
+		}
 		return resp, nil
 	case <-timer.C:
 		h.pendingMu.Lock()
@@ -75,6 +84,21 @@ func (h *RequestHandler) HandleResponse(requestId int64, data []byte) {
 	}
 }
 
+// FailResponse makes the request requestId fail with err instead of
+// waiting for a response, e.g. one that cannot be read.
+func (h *RequestHandler) FailResponse(requestId int64, err error) {
+	h.pendingMu.Lock()
+	defer h.pendingMu.Unlock()
+	ch, ok := h.pending[requestId]
+	if !ok {
+		log.Printf("Warning: Failed unknown request ID %d: %v", requestId, err)
+		return
+	}
+	delete(h.pending, requestId)
+	h.failed[requestId] = err
+	close(ch)
+}
+
 // CleanupPending clears all pending requests (for hot-reload cleanup)
 func (h *RequestHandler) CleanupPending() {
 	h.pendingMu.Lock()
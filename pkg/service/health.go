@@ -2,10 +2,17 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"time"
 
 	empty "github.com/golang/protobuf/ptypes/empty"
 	pb "github.com/ivere27/synurang/pkg/api"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -20,3 +27,246 @@ func (s *CoreServiceServer) Ping(ctx context.Context, req *empty.Empty) (*pb.Pin
 		Version:   "0.1.0",
 	}, nil
 }
+
+// GetServerStatus returns the status of the engine serving the call, with the
+// addresses its listeners actually bound
+func (s *CoreServiceServer) GetServerStatus(ctx context.Context, req *empty.Empty) (*pb.ServerStatus, error) {
+	return s.engine.Status().Proto(), nil
+}
+
+// =============================================================================
+// grpc.health.v1.Health Implementation
+// =============================================================================
+
+// HealthView is the grpc.health.v1 service name of the connection to the
+// Dart view server (Config.ViewSocketPath or ViewTcpPort).
+const HealthView = "synurang.view"
+
+// DefaultHealthInterval is how often Watch re-checks the statuses when
+// Config.HealthInterval is zero.
+const DefaultHealthInterval = 5 * time.Second
+
+// HealthCheck reports whether a subsystem can serve: nil means SERVING.
+type HealthCheck func(ctx context.Context) error
+
+// ServiceHealth is the grpc.health.v1 status of one service of an engine.
+type ServiceHealth struct {
+	Service string
+	Status  healthpb.HealthCheckResponse_ServingStatus
+	Err     error // why it is not serving
+}
+
+// HealthReport is the status of an engine as a whole (the "" service of
+// grpc.health.v1) and of each of its services, sorted by name.
+type HealthReport struct {
+	Status   healthpb.HealthCheckResponse_ServingStatus
+	Services []ServiceHealth
+}
+
+// Proto converts r to the HealthReport returned over FFI.
+func (r HealthReport) Proto() *pb.HealthReport {
+	p := &pb.HealthReport{Status: r.Status.String()}
+	for _, s := range r.Services {
+		h := &pb.ServiceHealth{Service: s.Service, Status: s.Status.String()}
+		if s.Err != nil {
+			h.Reason = s.Err.Error()
+		}
+		p.Services = append(p.Services, h)
+	}
+	return p
+}
+
+// status returns the status of service ("" for the engine), and false if
+// the engine has no such service.
+func (r HealthReport) status(service string) (healthpb.HealthCheckResponse_ServingStatus, bool) {
+	if service == "" {
+		return r.Status, true
+	}
+	for _, s := range r.Services {
+		if s.Service == service {
+			return s.Status, true
+		}
+	}
+	return healthpb.HealthCheckResponse_SERVICE_UNKNOWN, false
+}
+
+// RegisterHealthCheck makes check decide the health status of service, e.g.
+// of a subsystem of the host. RegisterPlugin registers the checks of loaded
+// plugins. The engine as a whole is only SERVING while every check passes.
+func (e *Engine) RegisterHealthCheck(service string, check HealthCheck) {
+	e.healthMu.Lock()
+	defer e.healthMu.Unlock()
+	if e.healthChecks == nil {
+		e.healthChecks = make(map[string]HealthCheck)
+	}
+	e.healthChecks[service] = check
+	e.healthGen++
+}
+
+// UnregisterHealthCheck removes the check of service.
+func (e *Engine) UnregisterHealthCheck(service string) {
+	e.healthMu.Lock()
+	delete(e.healthChecks, service)
+	e.healthGen++
+	e.healthMu.Unlock()
+}
+
+// Health checks the services of the engine: core.v1.HealthService, the
+// cache (opened and writable) if enabled, the connection to the Dart view
+// server if configured, and those of RegisterHealthCheck. A stopped or
+// stopping engine is NOT_SERVING.
+func (e *Engine) Health(ctx context.Context) HealthReport {
+	core := e.Core()
+	if core == nil {
+		return HealthReport{Status: healthpb.HealthCheckResponse_NOT_SERVING}
+	}
+	return core.health(ctx)
+}
+
+func (s *CoreServiceServer) health(ctx context.Context) HealthReport {
+	e := s.engine
+	services := []ServiceHealth{checked("core.v1.HealthService", nil)}
+	if s.cfg.EnableCache && s.cfg.CachePath != "" {
+		err := s.cacheErr
+		if s.CacheServiceServer != nil {
+			err = s.CacheServiceServer.CheckWritable(ctx)
+		}
+		services = append(services, checked("core.v1.CacheService", err))
+	}
+	if s.cfg.ViewSocketPath != "" || s.cfg.ViewTcpPort != "" {
+		services = append(services, s.viewHealth())
+	}
+
+	e.healthMu.RLock()
+	checks := make(map[string]HealthCheck, len(e.healthChecks))
+	for name, check := range e.healthChecks {
+		checks[name] = check
+	}
+	e.healthMu.RUnlock()
+	for name, check := range checks {
+		services = append(services, checked(name, check(ctx)))
+	}
+
+	report := HealthReport{Status: healthpb.HealthCheckResponse_SERVING}
+	stopping := e.draining.Load()
+	for i := range services {
+		h := &services[i]
+		if stopping {
+			h.Status, h.Err = healthpb.HealthCheckResponse_NOT_SERVING, errStopping
+		}
+		if h.Status != healthpb.HealthCheckResponse_SERVING {
+			report.Status = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+	}
+	sort.Slice(services, func(i, j int) bool { return services[i].Service < services[j].Service })
+	report.Services = services
+	return report
+}
+
+// sharedHealth returns a health report at most half a health interval old,
+// computed for all Check calls and Watch streams together, so that frequent
+// probes do not each open a write transaction on the cache database. A
+// stopping engine, or a change of the registered health checks, is reported
+// at once.
+func (s *CoreServiceServer) sharedHealth() HealthReport {
+	interval := s.healthInterval()
+	s.engine.healthMu.RLock()
+	gen := s.engine.healthGen
+	s.engine.healthMu.RUnlock()
+	s.reportMu.Lock()
+	defer s.reportMu.Unlock()
+	if s.reportAt.IsZero() || time.Since(s.reportAt) >= interval/2 || s.reportGen != gen || s.engine.draining.Load() {
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+		s.report, s.reportAt, s.reportGen = s.health(ctx), time.Now(), gen
+		cancel()
+	}
+	return s.report
+}
+
+// healthInterval is Config.HealthInterval or its default.
+func (s *CoreServiceServer) healthInterval() time.Duration {
+	if s.cfg.HealthInterval <= 0 {
+		return DefaultHealthInterval
+	}
+	return s.cfg.HealthInterval
+}
+
+// checked is the status of service whose check returned err.
+func checked(service string, err error) ServiceHealth {
+	if err != nil {
+		return ServiceHealth{Service: service, Status: healthpb.HealthCheckResponse_NOT_SERVING, Err: err}
+	}
+	return ServiceHealth{Service: service, Status: healthpb.HealthCheckResponse_SERVING}
+}
+
+// viewHealth maps the state of the connection to the Dart view server:
+// READY is SERVING and a connection still being set up UNKNOWN. An idle
+// connection is asked to connect.
+func (s *CoreServiceServer) viewHealth() ServiceHealth {
+	h := ServiceHealth{Service: HealthView, Status: healthpb.HealthCheckResponse_NOT_SERVING}
+	if s.dartConn == nil {
+		h.Err = s.viewErr
+		return h
+	}
+	state := s.dartConn.GetState()
+	switch state {
+	case connectivity.Ready:
+		h.Status = healthpb.HealthCheckResponse_SERVING
+		return h
+	case connectivity.Idle:
+		s.dartConn.Connect()
+		fallthrough
+	case connectivity.Connecting:
+		h.Status = healthpb.HealthCheckResponse_UNKNOWN
+	}
+	h.Err = fmt.Errorf("connection to the view server is %s", state)
+	return h
+}
+
+// healthServer implements grpc.health.v1.Health with the statuses of
+// CoreServiceServer.health.
+type healthServer struct {
+	healthpb.UnimplementedHealthServer
+	s *CoreServiceServer
+}
+
+// Check returns the status of req.Service from the report it shares with
+// Watch, or NotFound for a service the engine does not report.
+func (h *healthServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	st, ok := h.s.sharedHealth().status(req.GetService())
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown service %s", req.GetService())
+	}
+	return &healthpb.HealthCheckResponse{Status: st}, nil
+}
+
+// Watch sends the status of req.Service, then every change of it, checking
+// every Config.HealthInterval against the report shared by all watchers.
+// When the engine stops it sends NOT_SERVING and ends the stream, so
+// watchers do not hold up the drain.
+func (h *healthServer) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	ticker := time.NewTicker(h.s.healthInterval())
+	defer ticker.Stop()
+
+	last := healthpb.HealthCheckResponse_ServingStatus(-1)
+	send := func(st healthpb.HealthCheckResponse_ServingStatus) error {
+		if st == last {
+			return nil
+		}
+		last = st
+		return stream.Send(&healthpb.HealthCheckResponse{Status: st})
+	}
+	for {
+		st, _ := h.s.sharedHealth().status(req.GetService())
+		if err := send(st); err != nil {
+			return err
+		}
+		select {
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		case <-h.s.stopping:
+			return send(healthpb.HealthCheckResponse_NOT_SERVING)
+		case <-ticker.C:
+		}
+	}
+}
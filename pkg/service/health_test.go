@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// startHealthEngine starts an engine on a socket with cfg and returns a
+// grpc.health.v1 client of it.
+func startHealthEngine(t *testing.T, cfg *Config) (*Engine, healthpb.HealthClient) {
+	t.Helper()
+	cfg.EngineSocketPath = filepath.Join(t.TempDir(), "health.sock")
+	e := NewEngine()
+	if _, err := e.Start(cfg); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() { e.Stop() })
+	conn, err := grpc.Dial("unix://"+cfg.EngineSocketPath, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return e, healthpb.NewHealthClient(conn)
+}
+
+func checkHealth(t *testing.T, client healthpb.HealthClient, service string) (healthpb.HealthCheckResponse_ServingStatus, error) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+	return resp.GetStatus(), err
+}
+
+func TestEngine_Health(t *testing.T) {
+	// No Dart view server listens on viewSocket yet
+	viewSocket := filepath.Join(t.TempDir(), "view.sock")
+	e, client := startHealthEngine(t, &Config{
+		CachePath:      t.TempDir(),
+		EnableCache:    true,
+		ViewSocketPath: viewSocket,
+		HealthInterval: 100 * time.Millisecond,
+	})
+	var pluginUp atomic.Bool
+	e.RegisterHealthCheck("plugin.so", func(context.Context) error {
+		if !pluginUp.Load() {
+			return errors.New("plugin crashed")
+		}
+		return nil
+	})
+
+	for service, want := range map[string]healthpb.HealthCheckResponse_ServingStatus{
+		"":                      healthpb.HealthCheckResponse_NOT_SERVING,
+		"core.v1.HealthService": healthpb.HealthCheckResponse_SERVING,
+		"core.v1.CacheService":  healthpb.HealthCheckResponse_SERVING,
+		"plugin.so":             healthpb.HealthCheckResponse_NOT_SERVING,
+	} {
+		if st, err := checkHealth(t, client, service); err != nil || st != want {
+			t.Errorf("Check(%q) = %v, %v; want %v", service, st, err, want)
+		}
+	}
+	if _, err := checkHealth(t, client, "test.Missing"); status.Code(err) != codes.NotFound {
+		t.Errorf("Check(unknown service) = %v, want NotFound", err)
+	}
+	if st, _ := checkHealth(t, client, HealthView); st == healthpb.HealthCheckResponse_SERVING {
+		t.Error("view connection SERVING without a view server")
+	}
+
+	// Once Dart serves and the plugin recovers, the engine is ready
+	lis, err := net.Listen("unix", viewSocket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	view := grpc.NewServer()
+	go view.Serve(lis)
+	defer view.Stop()
+	pluginUp.Store(true)
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		st, err := checkHealth(t, client, "")
+		if err == nil && st == healthpb.HealthCheckResponse_SERVING {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("engine not SERVING with its view connected: %v, %v (%+v)", st, err, e.Health(context.Background()))
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	// A cache that cannot be written puts the engine in degraded mode
+	e.Core().CacheServiceServer.db.Close()
+	report := e.Health(context.Background()).Proto()
+	if report.GetStatus() != "NOT_SERVING" {
+		t.Errorf("report status = %s", report.GetStatus())
+	}
+	for _, s := range report.GetServices() {
+		if s.GetService() == "core.v1.CacheService" && (s.GetStatus() != "NOT_SERVING" || s.GetReason() == "") {
+			t.Errorf("cache health = %v", s)
+		}
+	}
+}
+
+func TestEngine_HealthWithAuth(t *testing.T) {
+	_, client := startHealthEngine(t, &Config{
+		Token:  "secret",
+		Policy: &Policy{Default: PolicyDeny},
+	})
+	if st, err := checkHealth(t, client, ""); err != nil || st != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("Check without a token = %v, %v; want SERVING", st, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	if resp, err := stream.Recv(); err != nil || resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("Watch without a token = %v, %v; want SERVING", resp.GetStatus(), err)
+	}
+}
+
+func TestEngine_HealthWatch(t *testing.T) {
+	e, client := startHealthEngine(t, &Config{HealthInterval: 10 * time.Millisecond})
+	var healthy atomic.Bool
+	healthy.Store(true)
+	e.RegisterHealthCheck("plugin.so", func(context.Context) error {
+		if !healthy.Load() {
+			return errors.New("plugin stopped")
+		}
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{Service: "plugin.so"})
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	recv := func(want healthpb.HealthCheckResponse_ServingStatus) {
+		t.Helper()
+		resp, err := stream.Recv()
+		if err != nil || resp.GetStatus() != want {
+			t.Fatalf("Recv = %v, %v; want %v", resp.GetStatus(), err, want)
+		}
+	}
+	recv(healthpb.HealthCheckResponse_SERVING)
+	healthy.Store(false)
+	recv(healthpb.HealthCheckResponse_NOT_SERVING)
+	healthy.Store(true)
+	recv(healthpb.HealthCheckResponse_SERVING)
+
+	unknown, err := client.Watch(ctx, &healthpb.HealthCheckRequest{Service: "test.Missing"})
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	if resp, err := unknown.Recv(); err != nil || resp.GetStatus() != healthpb.HealthCheckResponse_SERVICE_UNKNOWN {
+		t.Errorf("Watch(unknown service) = %v, %v", resp.GetStatus(), err)
+	}
+
+	// Stopping ends the watches instead of waiting for them
+	start := time.Now()
+	e.Stop()
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("Stop took %v with health watches open", d)
+	}
+	recv(healthpb.HealthCheckResponse_NOT_SERVING)
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Errorf("Recv after Stop = %v, want EOF", err)
+	}
+}
+
+func TestEngine_HealthWatchShared(t *testing.T) {
+	const interval = 40 * time.Millisecond
+	e, client := startHealthEngine(t, &Config{HealthInterval: interval})
+	var checks atomic.Int32
+	e.RegisterHealthCheck("plugin.so", func(context.Context) error {
+		checks.Add(1)
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	const watchers = 8
+	for i := 0; i < watchers; i++ {
+		stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{Service: "plugin.so"})
+		if err != nil {
+			t.Fatalf("Watch: %v", err)
+		}
+		if _, err := stream.Recv(); err != nil {
+			t.Fatalf("Recv: %v", err)
+		}
+	}
+	checks.Store(0)
+	time.Sleep(10 * interval)
+
+	// Unshared, every watcher would check once per interval
+	if n := checks.Load(); n >= watchers*10/2 {
+		t.Errorf("%d checks for %d watchers over 10 intervals", n, watchers)
+	}
+}
+
+func TestEngine_HealthCheckShared(t *testing.T) {
+	e, client := startHealthEngine(t, &Config{
+		CachePath:      t.TempDir(),
+		EnableCache:    true,
+		HealthInterval: time.Hour,
+	})
+	var checks atomic.Int32
+	e.RegisterHealthCheck("plugin.so", func(context.Context) error {
+		checks.Add(1)
+		return nil
+	})
+
+	// Probes within half an interval reuse one report, so neither the
+	// checks nor the cache's write transaction run per probe
+	for i := 0; i < 20; i++ {
+		if st, err := checkHealth(t, client, ""); err != nil || st != healthpb.HealthCheckResponse_SERVING {
+			t.Fatalf("Check = %v, %v", st, err)
+		}
+	}
+	if n := checks.Load(); n != 1 {
+		t.Errorf("%d checks for 20 probes, want 1", n)
+	}
+
+	// A stopping engine is reported at once
+	e.draining.Store(true)
+	defer e.draining.Store(false)
+	if st, err := checkHealth(t, client, ""); err != nil || st != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("Check while stopping = %v, %v; want NOT_SERVING", st, err)
+	}
+}
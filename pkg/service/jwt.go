@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"math"
+	"strings"
+	"time"
+)
+
+// JWTKey verifies JWT signatures: Secret for HMAC (HS256, HS384, HS512),
+// PublicKey for Ed25519 (EdDSA). Several keys with different IDs let keys
+// be rotated without rejecting tokens signed by the previous one.
+type JWTKey struct {
+	ID        string            // "kid" header of the tokens it verifies
+	Secret    []byte            // HMAC secret
+	PublicKey ed25519.PublicKey // Ed25519 public key
+}
+
+// Claims are the verified claims of the JWT a call was authenticated with.
+// Handlers get them with ClaimsFromContext, stream handlers from
+// StreamSession.Claims.
+type Claims struct {
+	Subject   string
+	Issuer    string
+	Audience  []string
+	ExpiresAt time.Time // zero if the token has no "exp"
+	NotBefore time.Time // zero if the token has no "nbf"
+	IssuedAt  time.Time // zero if the token has no "iat"
+	ID        string
+	KeyID     string                 // ID of the key that verified the signature
+	Raw       map[string]interface{} // every claim, as decoded from JSON
+}
+
+type claimsKey struct{}
+
+// ClaimsFromContext returns the JWT claims of the call ctx belongs to, if
+// it was authenticated with a JWT.
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	c, ok := ctx.Value(claimsKey{}).(*Claims)
+	return c, ok
+}
+
+// NewContextWithClaims returns ctx carrying c, as a handler sees it after
+// JWT authentication. It is useful to test handlers.
+func NewContextWithClaims(ctx context.Context, c *Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, c)
+}
+
+// jwtHashes are the hashes of the HMAC algorithms.
+var jwtHashes = map[string]func() hash.Hash{
+	"HS256": sha256.New,
+	"HS384": sha512.New384,
+	"HS512": sha512.New,
+}
+
+// jwtVerifier checks JWTs against the keys and claims required by a Config.
+type jwtVerifier struct {
+	keys     []JWTKey
+	issuer   string
+	audience string
+	leeway   time.Duration
+	now      func() time.Time
+}
+
+func newJWTVerifier(cfg *Config) *jwtVerifier {
+	if len(cfg.JWTKeys) == 0 {
+		return nil
+	}
+	return &jwtVerifier{
+		keys:     cfg.JWTKeys,
+		issuer:   cfg.JWTIssuer,
+		audience: cfg.JWTAudience,
+		leeway:   cfg.JWTLeeway,
+		now:      time.Now,
+	}
+}
+
+// verify checks the signature and the time, issuer and audience claims of
+// token, and returns its claims.
+func (v *jwtVerifier) verify(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("malformed header: %w", err)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.New("malformed signature")
+	}
+
+	key, err := v.verifySignature(header.Alg, header.Kid, parts[0]+"."+parts[1], sig)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := map[string]interface{}{}
+	if err := decodeSegment(parts[1], &raw); err != nil {
+		return nil, fmt.Errorf("malformed claims: %w", err)
+	}
+	c, err := parseClaims(raw)
+	if err != nil {
+		return nil, err
+	}
+	c.KeyID = key.ID
+	return c, v.checkClaims(c)
+}
+
+// verifySignature finds the key for kid and alg that signed signed with
+// sig. A token without "kid" is tried against every key of its algorithm.
+func (v *jwtVerifier) verifySignature(alg, kid, signed string, sig []byte) (*JWTKey, error) {
+	newHash, isHMAC := jwtHashes[alg]
+	if !isHMAC && alg != "EdDSA" {
+		return nil, fmt.Errorf("unsupported algorithm %q", alg)
+	}
+
+	found := false
+	for i := range v.keys {
+		k := &v.keys[i]
+		if kid != "" && k.ID != kid {
+			continue
+		}
+		switch {
+		case isHMAC && len(k.Secret) > 0:
+			found = true
+			mac := hmac.New(newHash, k.Secret)
+			mac.Write([]byte(signed))
+			if hmac.Equal(sig, mac.Sum(nil)) {
+				return k, nil
+			}
+		case !isHMAC && len(k.PublicKey) == ed25519.PublicKeySize:
+			found = true
+			if ed25519.Verify(k.PublicKey, []byte(signed), sig) {
+				return k, nil
+			}
+		}
+	}
+	if !found {
+		if kid != "" {
+			return nil, fmt.Errorf("unknown key %q for %s", kid, alg)
+		}
+		return nil, fmt.Errorf("no key for %s", alg)
+	}
+	return nil, errors.New("invalid signature")
+}
+
+// checkClaims applies the expiry, not-before, issuer and audience checks.
+func (v *jwtVerifier) checkClaims(c *Claims) error {
+	now := v.now()
+	if !c.ExpiresAt.IsZero() && !now.Before(c.ExpiresAt.Add(v.leeway)) {
+		return errors.New("token expired")
+	}
+	if !c.NotBefore.IsZero() && now.Add(v.leeway).Before(c.NotBefore) {
+		return errors.New("token not valid yet")
+	}
+	if v.issuer != "" && c.Issuer != v.issuer {
+		return fmt.Errorf("unexpected issuer %q", c.Issuer)
+	}
+	if v.audience != "" {
+		for _, aud := range c.Audience {
+			if aud == v.audience {
+				return nil
+			}
+		}
+		return errors.New("token not issued for this audience")
+	}
+	return nil
+}
+
+// parseClaims reads the registered claims of raw.
+func parseClaims(raw map[string]interface{}) (*Claims, error) {
+	c := &Claims{Raw: raw}
+	var err error
+	for name, dst := range map[string]*string{"sub": &c.Subject, "iss": &c.Issuer, "jti": &c.ID} {
+		if v, ok := raw[name]; ok {
+			s, isString := v.(string)
+			if !isString {
+				return nil, fmt.Errorf("claim %q is not a string", name)
+			}
+			*dst = s
+		}
+	}
+	for name, dst := range map[string]*time.Time{"exp": &c.ExpiresAt, "nbf": &c.NotBefore, "iat": &c.IssuedAt} {
+		if *dst, err = numericDate(raw, name); err != nil {
+			return nil, err
+		}
+	}
+	switch aud := raw["aud"].(type) {
+	case nil:
+	case string:
+		c.Audience = []string{aud}
+	case []interface{}:
+		for _, a := range aud {
+			s, ok := a.(string)
+			if !ok {
+				return nil, errors.New(`claim "aud" is not a string array`)
+			}
+			c.Audience = append(c.Audience, s)
+		}
+	default:
+		return nil, errors.New(`claim "aud" is not a string or string array`)
+	}
+	return c, nil
+}
+
+// numericDate reads the NumericDate claim name (seconds since the epoch).
+func numericDate(raw map[string]interface{}, name string) (time.Time, error) {
+	v, ok := raw[name]
+	if !ok {
+		return time.Time{}, nil
+	}
+	secs, ok := v.(float64)
+	if !ok {
+		return time.Time{}, fmt.Errorf("claim %q is not a number", name)
+	}
+	whole, frac := math.Modf(secs)
+	return time.Unix(int64(whole), int64(frac*1e9)), nil
+}
+
+// decodeSegment decodes the base64url JSON segment s into v.
+func decodeSegment(s string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/ivere27/synurang/pkg/api"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// signJWT returns a token with header and claims, signed with an HMAC-SHA256
+// secret or an Ed25519 private key.
+func signJWT(t *testing.T, header, claims map[string]interface{}, key interface{}) string {
+	t.Helper()
+	segment := func(v interface{}) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signed := segment(header) + "." + segment(claims)
+	var sig []byte
+	switch k := key.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, k)
+		mac.Write([]byte(signed))
+		sig = mac.Sum(nil)
+	case ed25519.PrivateKey:
+		sig = ed25519.Sign(k, []byte(signed))
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestJWTVerifier(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, otherPriv, _ := ed25519.GenerateKey(rand.Reader)
+	now := time.Unix(1700000000, 0)
+	v := newJWTVerifier(&Config{
+		JWTKeys: []JWTKey{
+			{ID: "old", Secret: []byte("old-secret")},
+			{ID: "new", Secret: []byte("new-secret")},
+			{ID: "ed", PublicKey: pub},
+		},
+		JWTIssuer:   "issuer",
+		JWTAudience: "synurang",
+		JWTLeeway:   time.Minute,
+	})
+	v.now = func() time.Time { return now }
+
+	claims := func(extra map[string]interface{}) map[string]interface{} {
+		c := map[string]interface{}{
+			"sub": "alice",
+			"iss": "issuer",
+			"aud": []string{"other", "synurang"},
+			"exp": now.Add(time.Hour).Unix(),
+			"nbf": now.Add(-time.Hour).Unix(),
+		}
+		for k, v := range extra {
+			if v == nil {
+				delete(c, k)
+			} else {
+				c[k] = v
+			}
+		}
+		return c
+	}
+	hs := func(kid string) map[string]interface{} {
+		return map[string]interface{}{"alg": "HS256", "kid": kid}
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		err   string // substring of the error, "" if valid
+	}{
+		{"HMAC", signJWT(t, hs("new"), claims(nil), []byte("new-secret")), ""},
+		{"rotated key", signJWT(t, hs("old"), claims(nil), []byte("old-secret")), ""},
+		{"no kid", signJWT(t, map[string]interface{}{"alg": "HS256"}, claims(nil), []byte("old-secret")), ""},
+		{"Ed25519", signJWT(t, map[string]interface{}{"alg": "EdDSA", "kid": "ed"}, claims(nil), priv), ""},
+		{"string audience", signJWT(t, hs("new"), claims(map[string]interface{}{"aud": "synurang"}), []byte("new-secret")), ""},
+		{"expired within leeway", signJWT(t, hs("new"), claims(map[string]interface{}{"exp": now.Add(-30 * time.Second).Unix()}), []byte("new-secret")), ""},
+		{"wrong secret", signJWT(t, hs("new"), claims(nil), []byte("old-secret")), "invalid signature"},
+		{"wrong Ed25519 key", signJWT(t, map[string]interface{}{"alg": "EdDSA"}, claims(nil), otherPriv), "invalid signature"},
+		{"unknown kid", signJWT(t, hs("gone"), claims(nil), []byte("new-secret")), "unknown key"},
+		{"HMAC kid for EdDSA", signJWT(t, map[string]interface{}{"alg": "EdDSA", "kid": "new"}, claims(nil), priv), "unknown key"},
+		{"alg none", signJWT(t, map[string]interface{}{"alg": "none"}, claims(nil), nil), "unsupported algorithm"},
+		{"expired", signJWT(t, hs("new"), claims(map[string]interface{}{"exp": now.Add(-2 * time.Minute).Unix()}), []byte("new-secret")), "expired"},
+		{"not yet valid", signJWT(t, hs("new"), claims(map[string]interface{}{"nbf": now.Add(2 * time.Minute).Unix()}), []byte("new-secret")), "not valid yet"},
+		{"wrong issuer", signJWT(t, hs("new"), claims(map[string]interface{}{"iss": "evil"}), []byte("new-secret")), "issuer"},
+		{"wrong audience", signJWT(t, hs("new"), claims(map[string]interface{}{"aud": "other"}), []byte("new-secret")), "audience"},
+		{"no audience", signJWT(t, hs("new"), claims(map[string]interface{}{"aud": nil}), []byte("new-secret")), "audience"},
+		{"malformed", "a.b", "malformed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := v.verify(tt.token)
+			if tt.err == "" {
+				if err != nil {
+					t.Fatalf("verify: %v", err)
+				}
+				if c.Subject != "alice" || c.Issuer != "issuer" || c.ExpiresAt.IsZero() {
+					t.Errorf("claims = %+v", c)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("verify = %v, want an error containing %q", err, tt.err)
+			}
+		})
+	}
+
+	c, err := v.verify(signJWT(t, hs("old"), claims(map[string]interface{}{"role": "admin"}), []byte("old-secret")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.KeyID != "old" || c.Raw["role"] != "admin" || len(c.Audience) != 2 {
+		t.Errorf("claims = %+v", c)
+	}
+}
+
+func TestAuthenticate_JWT(t *testing.T) {
+	secret := []byte("secret")
+	token := signJWT(t, map[string]interface{}{"alg": "HS256"}, map[string]interface{}{
+		"sub": "alice",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}, secret)
+
+	// Token is ignored once JWT keys are set
+	s := newCoreService(&Config{Token: "static", JWTKeys: []JWTKey{{Secret: secret}}}, NewEngine())
+	for meta, code := range map[string]codes.Code{
+		"authorization=Bearer " + token + "\n": codes.OK,
+		"authorization=" + token + "\n":        codes.Unauthenticated,
+		"authorization=Bearer static\n":        codes.Unauthenticated,
+		"":                                     codes.Unauthenticated,
+	} {
+		ctx, cancel := FfiContext(ParseFfiMetadata([]byte(meta)))
+		ctx, err := s.Authenticate(ctx)
+		cancel()
+		if status.Code(err) != code {
+			t.Errorf("Authenticate(%q) = %v, want %v", meta, err, code)
+			continue
+		}
+		if c, ok := ClaimsFromContext(ctx); (code == codes.OK) != ok || (ok && c.Subject != "alice") {
+			t.Errorf("Authenticate(%q) claims = %+v, %v", meta, c, ok)
+		}
+	}
+}
+
+func TestEngine_JWTClaims(t *testing.T) {
+	secret := []byte("secret")
+	token := signJWT(t, map[string]interface{}{"alg": "HS256"}, map[string]interface{}{"sub": "alice"}, secret)
+
+	// A service that reports the subject of its caller
+	subjects := make(chan string, 1)
+	whoDesc := &grpc.ServiceDesc{
+		ServiceName: "test.Who",
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: "Am",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				in := new(emptypb.Empty)
+				if err := dec(in); err != nil {
+					return nil, err
+				}
+				info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/test.Who/Am"}
+				return interceptor(ctx, in, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+					c, _ := ClaimsFromContext(ctx)
+					subjects <- c.Subject
+					return &emptypb.Empty{}, nil
+				})
+			},
+		}},
+	}
+
+	path := filepath.Join(t.TempDir(), "jwt.sock")
+	e := NewEngine()
+	if _, err := e.Start(&Config{EngineSocketPath: path, JWTKeys: []JWTKey{{Secret: secret}}}, func(srv *grpc.Server, _ *CoreServiceServer) {
+		srv.RegisterService(whoDesc, struct{}{})
+	}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer e.Stop()
+
+	conn, err := grpc.Dial("unix://"+path, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := pb.NewHealthServiceClient(conn).Ping(ctx, &emptypb.Empty{}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Ping without a token = %v, want Unauthenticated", err)
+	}
+	authCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+	if err := conn.Invoke(authCtx, "/test.Who/Am", &emptypb.Empty{}, &emptypb.Empty{}); err != nil {
+		t.Fatalf("Invoke over UDS: %v", err)
+	}
+	if got := <-subjects; got != "alice" {
+		t.Errorf("handler subject = %q, want alice", got)
+	}
+
+	// FFI streams carry the claims on the session
+	sessionClaims := make(chan *Claims, 1)
+	e.RegisterServerStreamHandler("test/jwt", func([]byte) HandlerFunc {
+		return func(s *StreamSession) { sessionClaims <- s.Claims }
+	})
+	if e.HandleServerStreamWithMeta("test/jwt", nil, metadata.Pairs("authorization", "Bearer "+token)) < 0 {
+		t.Fatal("stream did not start")
+	}
+	if c := <-sessionClaims; c == nil || c.Subject != "alice" {
+		t.Errorf("StreamSession.Claims = %+v", c)
+	}
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/credentials/local"
+	"google.golang.org/grpc/peer"
+)
+
+// PeerCredentials identify the process at the other end of a Unix socket
+// connection, as the kernel reports it with SO_PEERCRED (Linux). Handlers of
+// calls over the engine socket get them as the peer.AuthInfo of their
+// context; see PeerCredentialsFromContext.
+type PeerCredentials struct {
+	credentials.CommonAuthInfo
+	UID uint32
+	GID uint32
+	PID int32
+}
+
+// AuthType implements credentials.AuthInfo.
+func (*PeerCredentials) AuthType() string { return "peercred" }
+
+// PeerCredentialsFromContext returns the credentials of the client process
+// of the UDS call handling ctx.
+func PeerCredentialsFromContext(ctx context.Context) (*PeerCredentials, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, false
+	}
+	c, ok := p.AuthInfo.(*PeerCredentials)
+	return c, ok
+}
+
+// PeerAllowlist admits Unix socket clients whose uid, gid or pid is listed.
+type PeerAllowlist struct {
+	UIDs []uint32
+	GIDs []uint32
+	PIDs []int32
+}
+
+// Allows reports whether c matches one of the entries of a.
+func (a *PeerAllowlist) Allows(c *PeerCredentials) bool {
+	return containsAny(a.UIDs, c.UID) || containsAny(a.GIDs, c.GID) || containsAny(a.PIDs, c.PID)
+}
+
+// serverCredentials secures the connections of an engine's gRPC server: TCP
+// with TLS if configured, Unix sockets by the peer credentials of the
+// client, checked against peers if set.
+type serverCredentials struct {
+	credentials.TransportCredentials // TCP: TLS or plaintext
+	peers                            *PeerAllowlist
+	local                            credentials.TransportCredentials
+}
+
+// newServerCredentials returns the credentials of a server serving TCP
+// with TLS config, or plaintext if config is nil, and checking Unix socket
+// clients against peers, if not nil.
+func newServerCredentials(config *tls.Config, peers *PeerAllowlist) credentials.TransportCredentials {
+	c := &serverCredentials{
+		TransportCredentials: insecure.NewCredentials(),
+		peers:                peers,
+		local:                local.NewCredentials(),
+	}
+	if config != nil {
+		c.TransportCredentials = credentials.NewTLS(config)
+	}
+	return c
+}
+
+func (c *serverCredentials) ServerHandshake(conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	if conn.LocalAddr().Network() != "unix" {
+		return c.TransportCredentials.ServerHandshake(conn)
+	}
+	creds, err := readPeerCredentials(conn)
+	if err != nil {
+		if c.peers != nil {
+			log.Printf("Engine - rejected UDS client: %v", err)
+			return nil, nil, fmt.Errorf("peer credentials unavailable: %w", err)
+		}
+		return c.local.ServerHandshake(conn) // nothing to check
+	}
+	if c.peers != nil && !c.peers.Allows(creds) {
+		log.Printf("Engine - rejected UDS client uid=%d gid=%d pid=%d", creds.UID, creds.GID, creds.PID)
+		return nil, nil, fmt.Errorf("peer uid=%d gid=%d pid=%d not allowed", creds.UID, creds.GID, creds.PID)
+	}
+	creds.SecurityLevel = credentials.PrivacyAndIntegrity // as local credentials
+	return conn, creds, nil
+}
+
+func (c *serverCredentials) Clone() credentials.TransportCredentials {
+	return &serverCredentials{
+		TransportCredentials: c.TransportCredentials.Clone(),
+		peers:                c.peers,
+		local:                c.local.Clone(),
+	}
+}
+
+// listenUnix listens on the unix socket path. With Config.SocketMode or
+// SocketOwner set, it binds in a private (0700) directory next to path,
+// applies them there and only then moves the socket into place, so no
+// client can connect before they apply.
+func listenUnix(path string, cfg *Config) (net.Listener, error) {
+	if cfg.SocketMode == 0 && cfg.SocketOwner == "" {
+		return net.Listen("unix", path)
+	}
+	dir, err := os.MkdirTemp(filepath.Dir(path), ".synurang-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create socket directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	bound := filepath.Join(dir, "s")
+	l, err := net.Listen("unix", bound)
+	if err != nil {
+		return nil, err
+	}
+	ul := l.(*net.UnixListener)
+	ul.SetUnlinkOnClose(false)
+	if err := setSocketAccess(bound, cfg); err != nil {
+		l.Close()
+		return nil, err
+	}
+	if err := os.Rename(bound, path); err != nil {
+		l.Close()
+		return nil, fmt.Errorf("failed to move socket into place: %w", err)
+	}
+	return &movedUnixListener{UnixListener: ul, addr: &net.UnixAddr{Name: path, Net: "unix"}}, nil
+}
+
+// movedUnixListener is a unix listener whose socket file was renamed to
+// addr after binding. It reports addr and removes that file on Close.
+type movedUnixListener struct {
+	*net.UnixListener
+	addr *net.UnixAddr
+}
+
+func (l *movedUnixListener) Addr() net.Addr { return l.addr }
+
+func (l *movedUnixListener) Close() error {
+	err := l.UnixListener.Close()
+	os.Remove(l.addr.Name)
+	return err
+}
+
+// setSocketAccess applies Config.SocketMode and SocketOwner to the socket
+// file path.
+func setSocketAccess(path string, cfg *Config) error {
+	if cfg.SocketOwner != "" {
+		uid, gid, err := lookupOwner(cfg.SocketOwner)
+		if err != nil {
+			return err
+		}
+		if err := os.Chown(path, uid, gid); err != nil {
+			return fmt.Errorf("failed to set socket owner: %w", err)
+		}
+	}
+	if cfg.SocketMode != 0 {
+		if err := os.Chmod(path, cfg.SocketMode); err != nil {
+			return fmt.Errorf("failed to set socket mode: %w", err)
+		}
+	}
+	return nil
+}
+
+// lookupOwner resolves "user", "user:group" or ":group", by name or
+// numeric id, to the uid and gid for os.Chown (-1 for the part not given).
+func lookupOwner(owner string) (uid, gid int, err error) {
+	uid, gid = -1, -1
+	name, group, _ := strings.Cut(owner, ":")
+	if name != "" {
+		if uid, err = strconv.Atoi(name); err != nil {
+			u, err := user.Lookup(name)
+			if err != nil {
+				return 0, 0, fmt.Errorf("socket owner: %w", err)
+			}
+			uid, _ = strconv.Atoi(u.Uid)
+		}
+	}
+	if group != "" {
+		if gid, err = strconv.Atoi(group); err != nil {
+			g, err := user.LookupGroup(group)
+			if err != nil {
+				return 0, 0, fmt.Errorf("socket group: %w", err)
+			}
+			gid, _ = strconv.Atoi(g.Gid)
+		}
+	}
+	return uid, gid, nil
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// startPeerEngine starts an engine on a socket with cfg's access options,
+// serving test.Peer/Who, which sends the caller's peer credentials to who.
+func startPeerEngine(t *testing.T, cfg *Config, who chan<- *PeerCredentials) *grpc.ClientConn {
+	t.Helper()
+	desc := &grpc.ServiceDesc{
+		ServiceName: "test.Peer",
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: "Who",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				in := new(emptypb.Empty)
+				if err := dec(in); err != nil {
+					return nil, err
+				}
+				c, _ := PeerCredentialsFromContext(ctx)
+				who <- c
+				return &emptypb.Empty{}, nil
+			},
+		}},
+	}
+	cfg.EngineSocketPath = filepath.Join(t.TempDir(), "peer.sock")
+	e := NewEngine()
+	if _, err := e.Start(cfg, func(srv *grpc.Server, _ *CoreServiceServer) {
+		srv.RegisterService(desc, struct{}{})
+	}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() { e.Stop() })
+
+	conn, err := grpc.Dial("unix://"+cfg.EngineSocketPath, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func callWho(conn *grpc.ClientConn) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return conn.Invoke(ctx, "/test.Peer/Who", &emptypb.Empty{}, &emptypb.Empty{})
+}
+
+func TestEngine_SocketPeerCredentials(t *testing.T) {
+	uid, gid := uint32(os.Getuid()), uint32(os.Getgid())
+	who := make(chan *PeerCredentials, 1)
+	cfg := &Config{
+		SocketMode:  0600,
+		SocketOwner: fmt.Sprintf("%d:%d", uid, gid),
+		SocketPeers: &PeerAllowlist{UIDs: []uint32{uid}},
+	}
+	conn := startPeerEngine(t, cfg, who)
+
+	info, err := os.Stat(cfg.EngineSocketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("socket mode = %o, want 600", perm)
+	}
+
+	if err := callWho(conn); err != nil {
+		t.Fatalf("call from an allowed uid: %v", err)
+	}
+	c := <-who
+	if c == nil || c.UID != uid || c.GID != gid || c.PID != int32(os.Getpid()) {
+		t.Errorf("peer credentials = %+v, want uid %d gid %d pid %d", c, uid, gid, os.Getpid())
+	}
+	if c != nil && c.AuthType() != "peercred" {
+		t.Errorf("AuthType = %q", c.AuthType())
+	}
+}
+
+func TestEngine_SocketPeerRejected(t *testing.T) {
+	conn := startPeerEngine(t, &Config{
+		SocketPeers: &PeerAllowlist{UIDs: []uint32{uint32(os.Getuid()) + 1}, PIDs: []int32{1}},
+	}, make(chan *PeerCredentials, 1))
+	if err := callWho(conn); status.Code(err) != codes.Unavailable {
+		t.Errorf("call from a process not allowed = %v, want Unavailable", err)
+	}
+}
+
+func TestEngine_SocketOwnerError(t *testing.T) {
+	e := NewEngine()
+	st, err := e.Start(&Config{
+		EngineSocketPath:  filepath.Join(t.TempDir(), "owner.sock"),
+		SocketOwner:       "no-such-user-synurang",
+		FailOnListenError: true,
+	})
+	if err == nil {
+		e.Stop()
+		t.Fatal("Start with an unknown socket owner succeeded")
+	}
+	if _, statErr := os.Stat(st.Listeners[0].Address); !os.IsNotExist(statErr) {
+		t.Errorf("socket file left behind: %v", statErr)
+	}
+}
+
+func TestListenUnix_PrivateBind(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "engine.sock")
+	l, err := listenUnix(path, &Config{SocketMode: 0600})
+	if err != nil {
+		t.Fatalf("listenUnix: %v", err)
+	}
+	if l.Addr().String() != path {
+		t.Errorf("Addr = %s, want %s", l.Addr(), path)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("socket mode = %o, want 600", perm)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("%d entries next to the socket, want only the socket", len(entries))
+	}
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	conn.Close()
+
+	l.Close()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("socket file left after Close: %v", err)
+	}
+}
+
+func TestLookupOwner(t *testing.T) {
+	tests := []struct {
+		owner    string
+		uid, gid int
+	}{
+		{"1000", 1000, -1},
+		{":50", -1, 50},
+		{"0:0", 0, 0},
+		{"root", 0, -1},
+	}
+	for _, tt := range tests {
+		uid, gid, err := lookupOwner(tt.owner)
+		if err != nil || uid != tt.uid || gid != tt.gid {
+			t.Errorf("lookupOwner(%q) = %d, %d, %v; want %d, %d", tt.owner, uid, gid, err, tt.uid, tt.gid)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package service
+
+import (
+	"errors"
+	"net"
+)
+
+// readPeerCredentials is only implemented on Linux, where SO_PEERCRED
+// exists; elsewhere a PeerAllowlist rejects every client.
+func readPeerCredentials(net.Conn) (*PeerCredentials, error) {
+	return nil, errors.New("SO_PEERCRED is not supported on this platform")
+}
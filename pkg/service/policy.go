@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/grpc/peer"
+	"gopkg.in/yaml.v3"
+)
+
+// Transport is how a call reached the engine.
+type Transport string
+
+const (
+	TransportFFI Transport = "ffi" // in-process FFI call
+	TransportUDS Transport = "uds" // gRPC over the Unix domain socket
+	TransportTCP Transport = "tcp" // gRPC over TCP
+)
+
+type transportKey struct{}
+
+// TransportFromContext returns the transport of the call handling ctx, or ""
+// if ctx is not a handler context.
+func TransportFromContext(ctx context.Context) Transport {
+	if t, ok := ctx.Value(transportKey{}).(Transport); ok {
+		return t
+	}
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	if p.Addr.Network() == "unix" {
+		return TransportUDS
+	}
+	return TransportTCP
+}
+
+// Policy decides which authenticated callers may call which methods. The
+// first rule matching a method applies; methods no rule matches are allowed
+// unless Default is PolicyDeny.
+//
+// In JSON (or YAML, see LoadPolicy):
+//
+//	{
+//	  "default": "deny",
+//	  "rules": [
+//	    {"methods": ["/core.v1.CacheService/Clear", "/core.v1.CacheService/Compact"],
+//	     "transports": ["ffi"]},
+//	    {"methods": ["/core.v1.CacheService/*"], "roles": ["cache"]},
+//	    {"methods": ["/core.v1.HealthService/*"]}
+//	  ]
+//	}
+type Policy struct {
+	Rules   []PolicyRule `json:"rules"`
+	Default string       `json:"default,omitempty"` // PolicyAllow (the default) or PolicyDeny
+}
+
+// Policy.Default values.
+const (
+	PolicyAllow = "allow"
+	PolicyDeny  = "deny"
+)
+
+// PolicyRule sets the requirements of the methods it matches. A caller
+// must meet all of them; a rule without requirements allows every caller.
+type PolicyRule struct {
+	// Methods are full method names ("/core.v1.CacheService/Clear") or
+	// path.Match patterns ("/core.v1.CacheService/*"); "*" matches all.
+	Methods    []string    `json:"methods"`
+	Roles      []string    `json:"roles,omitempty"`      // The caller's JWT "roles" must include one of these
+	Scopes     []string    `json:"scopes,omitempty"`     // The caller's JWT "scope" must include all of these
+	Transports []Transport `json:"transports,omitempty"` // The call must arrive over one of these
+	Deny       bool        `json:"deny,omitempty"`       // Reject every call
+}
+
+// LoadPolicy reads a policy file: YAML if its extension is .yaml or .yml,
+// JSON otherwise.
+func LoadPolicy(name string) (*Policy, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy: %w", err)
+	}
+	if ext := strings.ToLower(filepath.Ext(name)); ext == ".yaml" || ext == ".yml" {
+		var v interface{}
+		if err := yaml.Unmarshal(data, &v); err != nil {
+			return nil, fmt.Errorf("invalid policy %s: %w", name, err)
+		}
+		if data, err = json.Marshal(v); err != nil {
+			return nil, fmt.Errorf("invalid policy %s: %w", name, err)
+		}
+	}
+	p := &Policy{}
+	dec := json.NewDecoder(strings.NewReader(string(data)))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(p); err != nil {
+		return nil, fmt.Errorf("invalid policy %s: %w", name, err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid policy %s: %w", name, err)
+	}
+	return p, nil
+}
+
+// configPolicy returns the policy of cfg: the file PolicyFile if set,
+// Policy otherwise.
+func configPolicy(cfg *Config) (*Policy, error) {
+	if cfg.PolicyFile != "" {
+		return LoadPolicy(cfg.PolicyFile)
+	}
+	if cfg.Policy != nil {
+		if err := cfg.Policy.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid policy: %w", err)
+		}
+	}
+	return cfg.Policy, nil
+}
+
+// Validate checks the default, method patterns and transports of p.
+func (p *Policy) Validate() error {
+	if p.Default != "" && p.Default != PolicyAllow && p.Default != PolicyDeny {
+		return fmt.Errorf("default must be %q or %q, not %q", PolicyAllow, PolicyDeny, p.Default)
+	}
+	for i, r := range p.Rules {
+		if len(r.Methods) == 0 {
+			return fmt.Errorf("rule %d has no methods", i)
+		}
+		for _, m := range r.Methods {
+			if _, err := path.Match(m, ""); err != nil {
+				return fmt.Errorf("rule %d: bad method pattern %q", i, m)
+			}
+		}
+		for _, t := range r.Transports {
+			if t != TransportFFI && t != TransportUDS && t != TransportTCP {
+				return fmt.Errorf("rule %d: unknown transport %q", i, t)
+			}
+		}
+	}
+	return nil
+}
+
+// Check returns why p denies the call of method over t by the caller with
+// claims (nil without JWT authentication), or nil if it is allowed.
+func (p *Policy) Check(method string, t Transport, claims *Claims) error {
+	r := p.rule(method)
+	if r == nil {
+		if p.Default == PolicyDeny {
+			return errors.New("no policy rule allows the method")
+		}
+		return nil
+	}
+	if r.Deny {
+		return errors.New("method denied by policy")
+	}
+	if len(r.Transports) > 0 && !containsAny(r.Transports, t) {
+		return fmt.Errorf("method not allowed over %q", t)
+	}
+	if len(r.Roles) > 0 && !containsAny(claims.Roles(), r.Roles...) {
+		return fmt.Errorf("method requires one of the roles %v", r.Roles)
+	}
+	scopes := claims.Scopes()
+	for _, s := range r.Scopes {
+		if !containsAny(scopes, s) {
+			return fmt.Errorf("method requires the scope %q", s)
+		}
+	}
+	return nil
+}
+
+// rule returns the first rule matching method, or nil.
+func (p *Policy) rule(method string) *PolicyRule {
+	for i := range p.Rules {
+		for _, m := range p.Rules[i].Methods {
+			if ok, _ := path.Match(m, method); ok || m == "*" {
+				return &p.Rules[i]
+			}
+		}
+	}
+	return nil
+}
+
+// containsAny reports whether list holds one of values.
+func containsAny[T comparable](list []T, values ...T) bool {
+	for _, v := range values {
+		for _, l := range list {
+			if l == v {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Roles returns the "roles" claim, a string or an array of strings.
+func (c *Claims) Roles() []string {
+	if c == nil {
+		return nil
+	}
+	return stringsClaim(c.Raw["roles"], false)
+}
+
+// Scopes returns the space-separated "scope" claim, or the "scp" array some
+// issuers use instead.
+func (c *Claims) Scopes() []string {
+	if c == nil {
+		return nil
+	}
+	if s, ok := c.Raw["scope"]; ok {
+		return stringsClaim(s, true)
+	}
+	return stringsClaim(c.Raw["scp"], true)
+}
+
+// stringsClaim converts a string (split on spaces if split is set) or an
+// array claim to strings, ignoring non-string values.
+func stringsClaim(v interface{}, split bool) []string {
+	switch v := v.(type) {
+	case string:
+		if split {
+			return strings.Fields(v)
+		}
+		return []string{v}
+	case []interface{}:
+		var out []string
+		for _, e := range v {
+			if s, ok := e.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/ivere27/synurang/pkg/api"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const testPolicyYAML = `# Admin methods only in-process
+default: deny
+rules:
+  - methods:
+      - /core.v1.CacheService/Clear
+      - "/core.v1.CacheService/Compact"
+    transports: [ffi]
+    roles: [admin]
+  - methods: ['/core.v1.CacheService/*']
+    scopes: [cache.read, cache.write]   # both
+  - methods: ["/core.v1.HealthService/*"]
+  - methods: [/test.Secret/*]
+    deny: true
+`
+
+const testPolicyJSON = `{
+  "default": "deny",
+  "rules": [
+    {"methods": ["/core.v1.CacheService/Clear", "/core.v1.CacheService/Compact"],
+     "transports": ["ffi"], "roles": ["admin"]},
+    {"methods": ["/core.v1.CacheService/*"], "scopes": ["cache.read", "cache.write"]},
+    {"methods": ["/core.v1.HealthService/*"]},
+    {"methods": ["/test.Secret/*"], "deny": true}
+  ]
+}`
+
+func writePolicy(t *testing.T, name, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadPolicy(t *testing.T) {
+	fromYAML, err := LoadPolicy(writePolicy(t, "policy.yaml", testPolicyYAML))
+	if err != nil {
+		t.Fatalf("LoadPolicy(yaml): %v", err)
+	}
+	fromJSON, err := LoadPolicy(writePolicy(t, "policy.json", testPolicyJSON))
+	if err != nil {
+		t.Fatalf("LoadPolicy(json): %v", err)
+	}
+	if !reflect.DeepEqual(fromYAML, fromJSON) {
+		t.Errorf("YAML policy = %+v, JSON policy = %+v", fromYAML, fromJSON)
+	}
+
+	for name, data := range map[string]string{
+		"bad.json":      `{"rules": [{"methods": ["/a/*"], "transport": ["ffi"]}]}`,
+		"transport.yml": "rules:\n  - methods: [/a/b]\n    transports: [http]\n",
+		"default.yaml":  "default: maybe\n",
+		"pattern.yaml":  "rules:\n  - methods: ['/a/[']\n",
+		"indent.yaml":   "rules:\n  - methods: [/a/b]\n      roles: [x]\n",
+		"unknown.yaml":  "rules:\n  - method: [/a/b]\n",
+		"tab.yaml":      "rules:\n\t- methods: [/a/b]\n",
+	} {
+		if _, err := LoadPolicy(writePolicy(t, name, data)); err == nil {
+			t.Errorf("LoadPolicy(%s) succeeded", name)
+		}
+	}
+}
+
+func TestPolicyCheck(t *testing.T) {
+	p, err := LoadPolicy(writePolicy(t, "policy.yaml", testPolicyYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin := &Claims{Raw: map[string]interface{}{"roles": []interface{}{"user", "admin"}}}
+	reader := &Claims{Raw: map[string]interface{}{"scope": "cache.read cache.write"}}
+	partial := &Claims{Raw: map[string]interface{}{"scp": []interface{}{"cache.read"}}}
+
+	tests := []struct {
+		method    string
+		transport Transport
+		claims    *Claims
+		allowed   bool
+	}{
+		{"/core.v1.CacheService/Clear", TransportFFI, admin, true},
+		{"/core.v1.CacheService/Clear", TransportTCP, admin, false},
+		{"/core.v1.CacheService/Compact", TransportFFI, reader, false},
+		{"/core.v1.CacheService/Get", TransportTCP, reader, true},
+		{"/core.v1.CacheService/Get", TransportUDS, partial, false},
+		{"/core.v1.CacheService/Get", TransportFFI, nil, false},
+		{"/core.v1.HealthService/Ping", TransportTCP, nil, true},
+		{"/test.Secret/Get", TransportFFI, admin, false},
+		{"/test.Other/Get", TransportFFI, admin, false}, // default deny
+	}
+	for _, tt := range tests {
+		err := p.Check(tt.method, tt.transport, tt.claims)
+		if (err == nil) != tt.allowed {
+			t.Errorf("Check(%s, %s) = %v, want allowed=%v", tt.method, tt.transport, err, tt.allowed)
+		}
+	}
+
+	open := &Policy{Rules: []PolicyRule{{Methods: []string{"*"}, Transports: []Transport{TransportUDS}}}}
+	if err := open.Check("/a/b", TransportTCP, nil); err == nil {
+		t.Error(`"*" rule did not match`)
+	}
+	if err := (&Policy{}).Check("/a/b", TransportTCP, nil); err != nil {
+		t.Errorf("empty policy denied a call: %v", err)
+	}
+}
+
+func TestEngine_Policy(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "policy.sock")
+	e := NewEngine()
+	_, err := e.Start(&Config{
+		EngineSocketPath: socket,
+		Policy: &Policy{Rules: []PolicyRule{
+			{Methods: []string{"/core.v1.HealthService/Ping"}, Transports: []Transport{TransportFFI}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer e.Stop()
+
+	conn, err := grpc.Dial("unix://"+socket, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = pb.NewHealthServiceClient(conn).Ping(ctx, &emptypb.Empty{})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Ping over UDS = %v, want PermissionDenied", err)
+	}
+
+	ffiCtx, ffiCancel := FfiContext(nil, 0)
+	defer ffiCancel()
+	if TransportFromContext(ffiCtx) != TransportFFI {
+		t.Errorf("TransportFromContext(FfiContext) = %q", TransportFromContext(ffiCtx))
+	}
+	if _, err := e.Core().AuthorizeCall(ffiCtx, "/core.v1.HealthService/Ping"); err != nil {
+		t.Errorf("Ping over FFI: %v", err)
+	}
+}
+
+func TestEngine_PolicyLoadError(t *testing.T) {
+	e := NewEngine()
+	_, err := e.Start(&Config{PolicyFile: writePolicy(t, "policy.json", `{"default": "sometimes"}`)})
+	if err == nil {
+		e.Stop()
+		t.Fatal("Start with an invalid policy succeeded")
+	}
+	if !strings.Contains(err.Error(), "default") {
+		t.Errorf("Start = %v", err)
+	}
+	if e.Core() != nil {
+		t.Error("engine running after a failed start")
+	}
+}
+
+func TestEngine_FfiInterceptors(t *testing.T) {
+	e := NewEngine()
+	_, err := e.Start(&Config{
+		EngineSocketPath: filepath.Join(t.TempDir(), "policy.sock"),
+		Policy: &Policy{Rules: []PolicyRule{
+			{Methods: []string{"/core.v1.HealthService/Ping"}, Deny: true},
+			{Methods: []string{"*"}, Transports: []Transport{TransportFFI}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer e.Stop()
+
+	call := func(method string) (Transport, error) {
+		var transport Transport
+		_, err := e.FfiUnaryInterceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				transport = TransportFromContext(ctx)
+				return nil, nil
+			})
+		return transport, err
+	}
+	if _, err := call("/core.v1.HealthService/Ping"); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("denied method = %v, want PermissionDenied", err)
+	}
+	if transport, err := call("/core.v1.HealthService/GetServerStatus"); err != nil || transport != TransportFFI {
+		t.Errorf("allowed method = %q, %v", transport, err)
+	}
+
+	e.Stop()
+	if _, err := call("/core.v1.HealthService/GetServerStatus"); status.Code(err) != codes.Unavailable {
+		t.Errorf("call after Stop = %v, want Unavailable", err)
+	}
+}
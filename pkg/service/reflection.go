@@ -0,0 +1,379 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	pb "github.com/ivere27/synurang/pkg/api"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionv1alpha "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Sources of the services ListServices reports. Services registered with
+// RegisterServiceDescriptors or RegisterDescriptorSet carry the source they
+// were registered under, e.g. the path of the plugin that serves them.
+const (
+	SourceGRPC = "grpc" // registered on the engine's gRPC server
+	SourceFFI  = "ffi"  // dispatched in-process only (conventional)
+)
+
+// descriptorSource holds the services registered under one source and the
+// files describing them.
+type descriptorSource struct {
+	services []protoreflect.ServiceDescriptor
+	files    *protoregistry.Files
+}
+
+// descriptorRegistry describes services the gRPC server does not serve:
+// those reachable only through FFI dispatch or loaded plugins.
+type descriptorRegistry struct {
+	mu      sync.RWMutex
+	sources map[string]*descriptorSource
+}
+
+// RegisterServiceDescriptors adds services to those described under source,
+// so that server reflection, ListServices and DescribeMethod report them.
+// Use it for services reachable only through FFI dispatch, e.g.
+//
+//	engine.RegisterServiceDescriptors(service.SourceFFI,
+//	    pb.File_greeter_proto.Services().ByName("GreeterService"))
+func (e *Engine) RegisterServiceDescriptors(source string, services ...protoreflect.ServiceDescriptor) error {
+	r := &e.descriptors
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	src := r.sources[source]
+	if src == nil {
+		src = &descriptorSource{files: new(protoregistry.Files)}
+	}
+	for _, sd := range services {
+		if err := addFile(src.files, sd.ParentFile()); err != nil {
+			return fmt.Errorf("service %s: %w", sd.FullName(), err)
+		}
+		src.services = replaceService(src.services, sd)
+	}
+	if r.sources == nil {
+		r.sources = make(map[string]*descriptorSource)
+	}
+	r.sources[source] = src
+	return nil
+}
+
+// RegisterDescriptorSet describes every service of the serialized
+// FileDescriptorSet data under source, replacing what source described
+// before. Hosts that load plugins use RegisterPlugin, which calls it.
+func (e *Engine) RegisterDescriptorSet(source string, data []byte) error {
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(data, set); err != nil {
+		return fmt.Errorf("descriptor set of %s: %w", source, err)
+	}
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		return fmt.Errorf("descriptor set of %s: %w", source, err)
+	}
+	src := &descriptorSource{files: files}
+	files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		for i := 0; i < fd.Services().Len(); i++ {
+			src.services = append(src.services, fd.Services().Get(i))
+		}
+		return true
+	})
+
+	r := &e.descriptors
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.sources == nil {
+		r.sources = make(map[string]*descriptorSource)
+	}
+	r.sources[source] = src
+	return nil
+}
+
+// UnregisterServiceDescriptors forgets the services registered under
+// source, e.g. when its plugin is closed.
+func (e *Engine) UnregisterServiceDescriptors(source string) {
+	e.descriptors.mu.Lock()
+	delete(e.descriptors.sources, source)
+	e.descriptors.mu.Unlock()
+}
+
+// Plugin is a loaded plugin whose services the engine describes and whose
+// health it reports, as *synurang.Plugin implements it.
+type Plugin interface {
+	DescriptorSet() ([]byte, error)
+	CheckHealth(ctx context.Context) error
+	OnClose(fn func())
+}
+
+// RegisterPlugin describes the services of p under source, usually its
+// path, like RegisterDescriptorSet, and makes p.CheckHealth the health check
+// of source (see RegisterHealthCheck). Both are unregistered when p closes,
+// unless another plugin was registered under source since.
+func (e *Engine) RegisterPlugin(source string, p Plugin) error {
+	data, err := p.DescriptorSet()
+	if err != nil {
+		return fmt.Errorf("descriptor set of %s: %w", source, err)
+	}
+	if err := e.RegisterDescriptorSet(source, data); err != nil {
+		return err
+	}
+	e.healthMu.Lock()
+	if e.plugins == nil {
+		e.plugins = make(map[string]Plugin)
+	}
+	e.plugins[source] = p
+	e.healthMu.Unlock()
+	e.RegisterHealthCheck(source, p.CheckHealth)
+
+	p.OnClose(func() {
+		e.healthMu.Lock()
+		current := e.plugins[source] == p
+		if current {
+			delete(e.plugins, source)
+			delete(e.healthChecks, source)
+			e.healthGen++
+		}
+		e.healthMu.Unlock()
+		if current {
+			e.UnregisterServiceDescriptors(source)
+		}
+	})
+	return nil
+}
+
+// addFile registers fd and its transitive imports in files, skipping those
+// already there.
+func addFile(files *protoregistry.Files, fd protoreflect.FileDescriptor) error {
+	if _, err := files.FindFileByPath(fd.Path()); err == nil {
+		return nil
+	}
+	for i := 0; i < fd.Imports().Len(); i++ {
+		if err := addFile(files, fd.Imports().Get(i).FileDescriptor); err != nil {
+			return err
+		}
+	}
+	return files.RegisterFile(fd)
+}
+
+func replaceService(services []protoreflect.ServiceDescriptor, sd protoreflect.ServiceDescriptor) []protoreflect.ServiceDescriptor {
+	for i, s := range services {
+		if s.FullName() == sd.FullName() {
+			services[i] = sd
+			return services
+		}
+	}
+	return append(services, sd)
+}
+
+// sourceNames returns the registered sources in a stable order.
+func (r *descriptorRegistry) sourceNames() []string {
+	names := make([]string, 0, len(r.sources))
+	for name := range r.sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// describedService is a service with the source that serves it; desc is
+// nil for gRPC services without registered descriptors.
+type describedService struct {
+	name   string
+	source string
+	info   grpc.ServiceInfo
+	desc   protoreflect.ServiceDescriptor
+}
+
+// services returns every service of the engine, by name: those of srv and
+// the registered ones srv does not serve.
+func (e *Engine) services(srv *grpc.Server) []describedService {
+	var list []describedService
+	seen := make(map[string]bool)
+	if srv != nil {
+		for name, info := range srv.GetServiceInfo() {
+			s := describedService{name: name, source: SourceGRPC, info: info}
+			if d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(name)); err == nil {
+				s.desc, _ = d.(protoreflect.ServiceDescriptor)
+			}
+			list = append(list, s)
+			seen[name] = true
+		}
+	}
+
+	r := &e.descriptors
+	r.mu.RLock()
+	for _, source := range r.sourceNames() {
+		for _, sd := range r.sources[source].services {
+			name := string(sd.FullName())
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			list = append(list, describedService{name: name, source: source, info: serviceInfo(sd), desc: sd})
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(list, func(i, j int) bool { return list[i].name < list[j].name })
+	return list
+}
+
+func serviceInfo(sd protoreflect.ServiceDescriptor) grpc.ServiceInfo {
+	info := grpc.ServiceInfo{Metadata: sd.ParentFile().Path()}
+	for i := 0; i < sd.Methods().Len(); i++ {
+		m := sd.Methods().Get(i)
+		info.Methods = append(info.Methods, grpc.MethodInfo{
+			Name:           string(m.Name()),
+			IsClientStream: m.IsStreamingClient(),
+			IsServerStream: m.IsStreamingServer(),
+		})
+	}
+	return info
+}
+
+func methodInfo(name string, info grpc.MethodInfo, desc protoreflect.ServiceDescriptor) *pb.MethodInfo {
+	m := &pb.MethodInfo{
+		Name:            name,
+		ClientStreaming: info.IsClientStream,
+		ServerStreaming: info.IsServerStream,
+	}
+	if desc != nil {
+		if md := desc.Methods().ByName(protoreflect.Name(name)); md != nil {
+			m.InputType = string(md.Input().FullName())
+			m.OutputType = string(md.Output().FullName())
+		}
+	}
+	return m
+}
+
+// ListServices describes every service of the engine: those its gRPC
+// server serves (source "grpc") and those registered for FFI dispatch or
+// plugins, sorted by name. Request and response types are only known for
+// services whose descriptors are linked in or registered.
+func (e *Engine) ListServices() *pb.ServiceList {
+	list := &pb.ServiceList{}
+	for _, s := range e.services(e.Server()) {
+		info := &pb.ServiceInfo{Name: s.name, Source: s.source}
+		for _, m := range s.info.Methods {
+			info.Methods = append(info.Methods, methodInfo(m.Name, m, s.desc))
+		}
+		list.Services = append(list.Services, info)
+	}
+	return list
+}
+
+// DescribeMethod describes method, given as "/package.Service/Method", with
+// the file declaring its service and that file's imports (dependencies
+// first) when its descriptors are known. It fails with NotFound for a
+// method no service of the engine has.
+func (e *Engine) DescribeMethod(method string) (*pb.MethodDescription, error) {
+	service, name, ok := strings.Cut(strings.TrimPrefix(method, "/"), "/")
+	if ok {
+		for _, s := range e.services(e.Server()) {
+			if s.name != service {
+				continue
+			}
+			for _, m := range s.info.Methods {
+				if m.Name != name {
+					continue
+				}
+				desc := &pb.MethodDescription{Method: methodInfo(name, m, s.desc), Source: s.source}
+				if s.desc != nil {
+					set := &descriptorpb.FileDescriptorSet{}
+					appendFile(set, s.desc.ParentFile(), make(map[string]bool))
+					data, err := proto.Marshal(set)
+					if err != nil {
+						return nil, status.Errorf(codes.Internal, "failed to marshal descriptors of %s: %v", method, err)
+					}
+					desc.FileDescriptorSet = data
+				}
+				return desc, nil
+			}
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "unknown method %s", method)
+}
+
+// appendFile appends fd to set after its imports not yet in seen.
+func appendFile(set *descriptorpb.FileDescriptorSet, fd protoreflect.FileDescriptor, seen map[string]bool) {
+	if seen[fd.Path()] {
+		return
+	}
+	seen[fd.Path()] = true
+	for i := 0; i < fd.Imports().Len(); i++ {
+		appendFile(set, fd.Imports().Get(i).FileDescriptor, seen)
+	}
+	set.File = append(set.File, protodesc.ToFileDescriptorProto(fd))
+}
+
+// reflectionServices lists the services of srv and those registered with e
+// to server reflection.
+type reflectionServices struct {
+	srv *grpc.Server
+	e   *Engine
+}
+
+func (r reflectionServices) GetServiceInfo() map[string]grpc.ServiceInfo {
+	infos := make(map[string]grpc.ServiceInfo)
+	for _, s := range r.e.services(r.srv) {
+		infos[s.name] = s.info
+	}
+	return infos
+}
+
+// reflectionResolver finds descriptors linked into the binary, then those
+// registered with the engine.
+type reflectionResolver struct{ e *Engine }
+
+func (r reflectionResolver) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	if fd, err := protoregistry.GlobalFiles.FindFileByPath(path); err == nil {
+		return fd, nil
+	}
+	reg := &r.e.descriptors
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	for _, source := range reg.sourceNames() {
+		if fd, err := reg.sources[source].files.FindFileByPath(path); err == nil {
+			return fd, nil
+		}
+	}
+	return nil, protoregistry.NotFound
+}
+
+func (r reflectionResolver) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	if d, err := protoregistry.GlobalFiles.FindDescriptorByName(name); err == nil {
+		return d, nil
+	}
+	reg := &r.e.descriptors
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	for _, source := range reg.sourceNames() {
+		if d, err := reg.sources[source].files.FindDescriptorByName(name); err == nil {
+			return d, nil
+		}
+	}
+	return nil, protoregistry.NotFound
+}
+
+// registerReflection registers the grpc.reflection services (v1 and
+// v1alpha) on srv, describing its services and those registered with e.
+func registerReflection(srv *grpc.Server, e *Engine) {
+	opts := reflection.ServerOptions{
+		Services:           reflectionServices{srv, e},
+		DescriptorResolver: reflectionResolver{e},
+	}
+	reflectionv1.RegisterServerReflectionServer(srv, reflection.NewServerV1(opts))
+	reflectionv1alpha.RegisterServerReflectionServer(srv, reflection.NewServer(opts))
+}
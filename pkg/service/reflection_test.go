@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pb "github.com/ivere27/synurang/pkg/api"
+	"github.com/ivere27/synurang/pkg/synurang"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// testServiceFile declares service (one unary method Echo and one server
+// streaming method Watch, both on google.protobuf.Empty) in file path.
+func testServiceFile(path, pkg, service string) *descriptorpb.FileDescriptorProto {
+	return &descriptorpb.FileDescriptorProto{
+		Name:       proto.String(path),
+		Package:    proto.String(pkg),
+		Dependency: []string{"google/protobuf/empty.proto"},
+		Syntax:     proto.String("proto3"),
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String(service),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				{Name: proto.String("Echo"), InputType: proto.String(".google.protobuf.Empty"), OutputType: proto.String(".google.protobuf.Empty")},
+				{Name: proto.String("Watch"), InputType: proto.String(".google.protobuf.Empty"), OutputType: proto.String(".google.protobuf.Empty"), ServerStreaming: proto.Bool(true)},
+			},
+		}},
+	}
+}
+
+// startReflectionEngine starts an engine on a socket describing test.Ffi as
+// an FFI-only service and test.Plugin as served by plugin.so.
+func startReflectionEngine(t *testing.T) (*Engine, *grpc.ClientConn) {
+	t.Helper()
+	e := NewEngine()
+	fd, err := protodesc.NewFile(testServiceFile("test/ffi.proto", "test", "Ffi"), protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.RegisterServiceDescriptors(SourceFFI, fd.Services().Get(0)); err != nil {
+		t.Fatalf("RegisterServiceDescriptors: %v", err)
+	}
+	set, err := proto.Marshal(&descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{
+		protodesc.ToFileDescriptorProto(emptypb.File_google_protobuf_empty_proto),
+		testServiceFile("test/plugin.proto", "test", "Plugin"),
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.RegisterDescriptorSet("plugin.so", set); err != nil {
+		t.Fatalf("RegisterDescriptorSet: %v", err)
+	}
+	if err := e.RegisterDescriptorSet("broken.so", []byte("not a descriptor set")); err == nil {
+		t.Error("RegisterDescriptorSet accepted garbage")
+	}
+
+	socket := filepath.Join(t.TempDir(), "reflection.sock")
+	if _, err := e.Start(&Config{EngineSocketPath: socket}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() { e.Stop() })
+	conn, err := grpc.Dial("unix://"+socket, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return e, conn
+}
+
+func TestEngine_Reflection(t *testing.T) {
+	_, conn := startReflectionEngine(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := reflectionv1.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		t.Fatalf("ServerReflectionInfo: %v", err)
+	}
+	ask := func(req *reflectionv1.ServerReflectionRequest) *reflectionv1.ServerReflectionResponse {
+		t.Helper()
+		if err := stream.Send(req); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv: %v", err)
+		}
+		return resp
+	}
+
+	resp := ask(&reflectionv1.ServerReflectionRequest{
+		MessageRequest: &reflectionv1.ServerReflectionRequest_ListServices{},
+	})
+	names := make(map[string]bool)
+	for _, s := range resp.GetListServicesResponse().GetService() {
+		names[s.GetName()] = true
+	}
+	for _, want := range []string{"core.v1.HealthService", "grpc.reflection.v1.ServerReflection", "test.Ffi", "test.Plugin"} {
+		if !names[want] {
+			t.Errorf("reflection does not list %s: %v", want, names)
+		}
+	}
+
+	for _, symbol := range []string{"test.Ffi", "test.Plugin.Echo", "core.v1.HealthService"} {
+		resp = ask(&reflectionv1.ServerReflectionRequest{
+			MessageRequest: &reflectionv1.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: symbol},
+		})
+		if len(resp.GetFileDescriptorResponse().GetFileDescriptorProto()) == 0 {
+			t.Errorf("no file for %s: %v", symbol, resp.GetErrorResponse())
+		}
+	}
+}
+
+func TestEngine_ListServices(t *testing.T) {
+	e, _ := startReflectionEngine(t)
+	services := make(map[string]*pb.ServiceInfo)
+	for _, s := range e.ListServices().GetServices() {
+		services[s.GetName()] = s
+	}
+	for name, source := range map[string]string{
+		"core.v1.HealthService": SourceGRPC,
+		"test.Ffi":              SourceFFI,
+		"test.Plugin":           "plugin.so",
+	} {
+		if s := services[name]; s.GetSource() != source {
+			t.Errorf("%s has source %q, want %q", name, s.GetSource(), source)
+		}
+	}
+	if m := services["test.Plugin"].GetMethods(); len(m) != 2 || m[1].GetName() != "Watch" || !m[1].GetServerStreaming() || m[1].GetInputType() != "google.protobuf.Empty" {
+		t.Errorf("test.Plugin methods = %v", m)
+	}
+
+	desc, err := e.DescribeMethod("/core.v1.HealthService/Ping")
+	if err != nil {
+		t.Fatalf("DescribeMethod: %v", err)
+	}
+	if desc.GetMethod().GetOutputType() != "core.v1.PingResponse" || desc.GetSource() != SourceGRPC {
+		t.Errorf("Ping = %v", desc)
+	}
+
+	desc, err = e.DescribeMethod("/test.Ffi/Echo")
+	if err != nil {
+		t.Fatalf("DescribeMethod: %v", err)
+	}
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(desc.GetFileDescriptorSet(), set); err != nil {
+		t.Fatal(err)
+	}
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		t.Fatalf("file descriptor set of test.Ffi/Echo: %v", err)
+	}
+	if _, err := files.FindDescriptorByName(protoreflect.FullName("test.Ffi.Echo")); err != nil {
+		t.Errorf("test.Ffi.Echo not in its file descriptor set: %v", err)
+	}
+
+	if _, err := e.DescribeMethod("/test.Ffi/Missing"); status.Code(err) != codes.NotFound {
+		t.Errorf("DescribeMethod(missing) = %v, want NotFound", err)
+	}
+	e.UnregisterServiceDescriptors("plugin.so")
+	if _, err := e.DescribeMethod("/test.Plugin/Echo"); status.Code(err) != codes.NotFound {
+		t.Errorf("DescribeMethod after unregister = %v, want NotFound", err)
+	}
+}
+
+func TestEngine_ReflectionAuth(t *testing.T) {
+	listServices := func(public bool) error {
+		socket := filepath.Join(t.TempDir(), "reflection.sock")
+		e := NewEngine()
+		if _, err := e.Start(&Config{EngineSocketPath: socket, Token: "secret", PublicReflection: public}); err != nil {
+			t.Fatalf("Start: %v", err)
+		}
+		defer e.Stop()
+		conn, err := grpc.Dial("unix://"+socket, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			t.Fatalf("Dial: %v", err)
+		}
+		defer conn.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		stream, err := reflectionv1.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(&reflectionv1.ServerReflectionRequest{
+			MessageRequest: &reflectionv1.ServerReflectionRequest_ListServices{},
+		}); err != nil {
+			return err
+		}
+		_, err = stream.Recv()
+		return err
+	}
+
+	if err := listServices(false); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("reflection without a token = %v, want Unauthenticated", err)
+	}
+	if err := listServices(true); err != nil {
+		t.Errorf("reflection with PublicReflection: %v", err)
+	}
+}
+
+var _ Plugin = (*synurang.Plugin)(nil)
+
+// fakePlugin is a Plugin describing test.Plugin whose health is err.
+type fakePlugin struct {
+	err     error
+	onClose []func()
+}
+
+func (p *fakePlugin) DescriptorSet() ([]byte, error) {
+	return proto.Marshal(&descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{
+		protodesc.ToFileDescriptorProto(emptypb.File_google_protobuf_empty_proto),
+		testServiceFile("test/plugin.proto", "test", "Plugin"),
+	}})
+}
+
+func (p *fakePlugin) CheckHealth(context.Context) error { return p.err }
+
+func (p *fakePlugin) OnClose(fn func()) { p.onClose = append(p.onClose, fn) }
+
+func (p *fakePlugin) close() {
+	for _, fn := range p.onClose {
+		fn()
+	}
+}
+
+func TestEngine_RegisterPlugin(t *testing.T) {
+	e, client := startHealthEngine(t, &Config{})
+	registered := func() bool {
+		for _, s := range e.ListServices().GetServices() {
+			if s.GetName() == "test.Plugin" {
+				return s.GetSource() == "plugin.so"
+			}
+		}
+		return false
+	}
+
+	old := &fakePlugin{err: errors.New("plugin crashed")}
+	if err := e.RegisterPlugin("plugin.so", old); err != nil {
+		t.Fatalf("RegisterPlugin: %v", err)
+	}
+	if !registered() {
+		t.Error("test.Plugin not listed after RegisterPlugin")
+	}
+	if st, err := checkHealth(t, client, "plugin.so"); err != nil || st != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("Check(plugin.so) = %v, %v; want NOT_SERVING", st, err)
+	}
+
+	// A reloaded plugin replaces old, whose late close must not remove it
+	reloaded := &fakePlugin{}
+	if err := e.RegisterPlugin("plugin.so", reloaded); err != nil {
+		t.Fatalf("RegisterPlugin: %v", err)
+	}
+	old.close()
+	if !registered() {
+		t.Error("closing a replaced plugin unregistered its successor")
+	}
+	if st, err := checkHealth(t, client, "plugin.so"); err != nil || st != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("Check(plugin.so) = %v, %v; want SERVING", st, err)
+	}
+
+	reloaded.close()
+	if registered() {
+		t.Error("test.Plugin still listed after the plugin closed")
+	}
+	if _, err := checkHealth(t, client, "plugin.so"); status.Code(err) != codes.NotFound {
+		t.Errorf("Check(plugin.so) after close = %v, want NotFound", err)
+	}
+}
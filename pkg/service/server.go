@@ -3,9 +3,11 @@ package service
 import (
 	"context"
 	"crypto/subtle"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"log"
+	"strings"
 	"sync"
 	"time"
 
@@ -14,6 +16,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
@@ -29,20 +32,44 @@ type MessageFactory func() proto.Message
 type CoreServiceServer struct {
 	pb.UnimplementedHealthServiceServer
 	*CacheServiceServer
-	cfg      *Config
-	mu       sync.RWMutex
-	dartConn *grpc.ClientConn // gRPC client to Dart (for UDS/TCP mode)
+	cfg       *Config
+	engine    *Engine // owner of the stream sessions and Dart callback
+	mu        sync.RWMutex
+	dartConn  *grpc.ClientConn // gRPC client to Dart (for UDS/TCP mode)
+	cacheErr  error            // why the cache could not be opened
+	viewErr   error            // why the connection to Dart could not be set up
+	jwt       *jwtVerifier     // nil unless Config.JWTKeys is set
+	policy    *Policy          // nil allows every method
+	policyErr error            // why the policy could not be loaded
+	stopping  chan struct{}    // closed when the engine stops (ends health watches)
+	stopOnce  sync.Once
+
+	reportMu  sync.Mutex   // guards the health report shared by Check and Watch
+	report    HealthReport // see sharedHealth
+	reportAt  time.Time
+	reportGen uint64 // Engine.healthGen of report
 }
 
-// NewCoreService creates a new CoreServiceServer
+// NewCoreService creates a new CoreServiceServer on the default engine
 func NewCoreService(cfg *Config) *CoreServiceServer {
-	s := &CoreServiceServer{cfg: cfg}
+	return newCoreService(cfg, defaultEngine)
+}
+
+func newCoreService(cfg *Config, e *Engine) *CoreServiceServer {
+	s := &CoreServiceServer{cfg: cfg, engine: e, jwt: newJWTVerifier(cfg), stopping: make(chan struct{})}
+
+	s.policy, s.policyErr = configPolicy(cfg)
+	if s.policyErr != nil {
+		log.Printf("Warning: %v; denying every call", s.policyErr)
+		s.policy = &Policy{Default: PolicyDeny}
+	}
 
 	// Only initialize cache if enabled AND cachePath is provided
 	if cfg.EnableCache && cfg.CachePath != "" {
 		cache, err := NewCacheService(cfg.CachePath)
 		if err != nil {
 			log.Printf("Warning: Failed to initialize cache service: %v", err)
+			s.cacheErr = err
 		} else {
 			s.CacheServiceServer = cache
 		}
@@ -56,17 +83,20 @@ func NewCoreService(cfg *Config) *CoreServiceServer {
 		)
 		if err != nil {
 			log.Printf("Warning: Failed to connect to Flutter server via UDS: %v", err)
+			s.viewErr = err
 		} else {
 			s.dartConn = conn
 			log.Printf("Connected to Flutter gRPC server via UDS: %s", cfg.ViewSocketPath)
 		}
 	} else if cfg.ViewTcpPort != "" {
-		conn, err := grpc.Dial(
-			"localhost:"+cfg.ViewTcpPort,
-			grpc.WithTransportCredentials(insecure.NewCredentials()),
-		)
+		creds, err := viewCredentials(cfg)
+		var conn *grpc.ClientConn
+		if err == nil {
+			conn, err = grpc.Dial("localhost:"+cfg.ViewTcpPort, grpc.WithTransportCredentials(creds))
+		}
 		if err != nil {
 			log.Printf("Warning: Failed to connect to Flutter server via TCP: %v", err)
+			s.viewErr = err
 		} else {
 			s.dartConn = conn
 			log.Printf("Connected to Flutter gRPC server via TCP: localhost:%s", cfg.ViewTcpPort)
@@ -79,6 +109,7 @@ func NewCoreService(cfg *Config) *CoreServiceServer {
 // Close cleans up server resources
 func (s *CoreServiceServer) Close() {
 	log.Println("CoreServiceServer closing...")
+	s.stopWatches()
 	if s.dartConn != nil {
 		s.dartConn.Close()
 	}
@@ -87,17 +118,38 @@ func (s *CoreServiceServer) Close() {
 	}
 }
 
-// NewGrpcServer creates a new gRPC server with interceptors and registers services
+// stopWatches ends the grpc.health.v1 Watch streams, reporting NOT_SERVING.
+func (s *CoreServiceServer) stopWatches() {
+	s.stopOnce.Do(func() { close(s.stopping) })
+}
+
+// NewGrpcServer creates a new gRPC server with interceptors and registers
+// services. If cfg enables TLS but it cannot be set up, TCP connections are
+// refused.
 func NewGrpcServer(s *CoreServiceServer, cfg *Config, registrars ...ServiceRegistrar) *grpc.Server {
-	opts := []grpc.ServerOption{
-		grpc.UnaryInterceptor(s.authInterceptor),
-		grpc.StreamInterceptor(s.streamAuthInterceptor),
+	var config *tls.Config
+	st, err := loadServerTLS(cfg)
+	if err != nil {
+		log.Printf("Warning: %v; refusing TCP connections", err)
+		config = &tls.Config{} // no certificate: every handshake fails
+	} else if st != nil {
+		config = st.config
 	}
+	return newGrpcServer(s, cfg, config, registrars...)
+}
 
-	srv := grpc.NewServer(opts...)
+// newGrpcServer is NewGrpcServer serving TCP connections with TLS config,
+// or plaintext if config is nil.
+func newGrpcServer(s *CoreServiceServer, cfg *Config, config *tls.Config, registrars ...ServiceRegistrar) *grpc.Server {
+	srv := grpc.NewServer(
+		grpc.Creds(newServerCredentials(config, cfg.SocketPeers)),
+		grpc.ChainUnaryInterceptor(s.trackInterceptor, s.authInterceptor),
+		grpc.ChainStreamInterceptor(s.streamTrackInterceptor, s.streamAuthInterceptor),
+	)
 
 	// Register Core Services
 	pb.RegisterHealthServiceServer(srv, s)
+	healthpb.RegisterHealthServer(srv, &healthServer{s: s})
 
 	// Conditionally register cache service
 	if s.CacheServiceServer != nil {
@@ -109,53 +161,164 @@ func NewGrpcServer(s *CoreServiceServer, cfg *Config, registrars ...ServiceRegis
 		r(srv, s)
 	}
 
+	// Describe them all, and the FFI-only and plugin services of the engine
+	registerReflection(srv, s.engine)
+
 	return srv
 }
 
-// authInterceptor validates the token in metadata
-func (s *CoreServiceServer) authInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-	if s.cfg.Token == "" {
-		return handler(ctx, req)
+// Authenticate checks the bearer token in ctx's incoming metadata: a JWT
+// signed by one of Config.JWTKeys if any are set, otherwise Config.Token if
+// one is configured. It returns ctx carrying the verified JWT claims (see
+// ClaimsFromContext). The gRPC interceptors use it, and FFI entry points
+// call it with a context from FfiContext so every transport enforces the
+// same check.
+func (s *CoreServiceServer) Authenticate(ctx context.Context) (context.Context, error) {
+	if s.jwt == nil && s.cfg.Token == "" {
+		return ctx, nil
 	}
 
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
-		return nil, status.Error(codes.Unauthenticated, "missing metadata")
+		return ctx, status.Error(codes.Unauthenticated, "missing metadata")
 	}
 
 	tokens := md.Get("authorization")
 	if len(tokens) == 0 {
-		return nil, status.Error(codes.Unauthenticated, "missing token")
+		return ctx, status.Error(codes.Unauthenticated, "missing token")
+	}
+
+	if s.jwt != nil {
+		token, ok := strings.CutPrefix(tokens[0], "Bearer ")
+		if !ok {
+			return ctx, status.Error(codes.Unauthenticated, "invalid token: not a bearer token")
+		}
+		claims, err := s.jwt.verify(token)
+		if err != nil {
+			return ctx, status.Errorf(codes.Unauthenticated, "invalid token: %v", err)
+		}
+		return NewContextWithClaims(ctx, claims), nil
 	}
 
 	if subtle.ConstantTimeCompare([]byte(tokens[0]), []byte("Bearer "+s.cfg.Token)) != 1 {
-		return nil, status.Error(codes.Unauthenticated, "invalid token")
+		return ctx, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	return ctx, nil
+}
+
+// Authorize is Authenticate for callers that do not need the claims.
+func (s *CoreServiceServer) Authorize(ctx context.Context) error {
+	_, err := s.Authenticate(ctx)
+	return err
+}
+
+// AuthorizeCall authenticates ctx like Authenticate, then checks the call of
+// method against Config.Policy with the transport of ctx and the caller's
+// claims. Denied calls are logged and fail with PermissionDenied. The gRPC
+// interceptors use it, as do FFI entry points with a context from
+// FfiContext. Public methods (see isPublic) are not checked.
+func (s *CoreServiceServer) AuthorizeCall(ctx context.Context, method string) (context.Context, error) {
+	if s.isPublic(method) {
+		return ctx, nil
+	}
+	ctx, err := s.Authenticate(ctx)
+	if err != nil || s.policy == nil {
+		return ctx, err
+	}
+	claims, _ := ClaimsFromContext(ctx)
+	transport := TransportFromContext(ctx)
+	if err := s.policy.Check(method, transport, claims); err != nil {
+		subject := ""
+		if claims != nil {
+			subject = claims.Subject
+		}
+		log.Printf("Policy - denied %s over %s (subject %q): %v", method, transport, subject, err)
+		return ctx, status.Errorf(codes.PermissionDenied, "%s: %v", method, err)
+	}
+	return ctx, nil
+}
+
+// isPublic reports whether method is served without authentication and
+// policy: the grpc.health.v1 health checks, so standard probes work, and
+// server reflection with Config.PublicReflection.
+func (s *CoreServiceServer) isPublic(method string) bool {
+	if strings.HasPrefix(method, "/grpc.health.v1.Health/") {
+		return true
+	}
+	return s.cfg.PublicReflection &&
+		(strings.HasPrefix(method, "/grpc.reflection.v1.ServerReflection/") ||
+			strings.HasPrefix(method, "/grpc.reflection.v1alpha.ServerReflection/"))
+}
+
+// streamClaims returns the JWT claims of the FFI stream metadata md, or nil.
+// The FFI entry points have already authenticated md.
+func (s *CoreServiceServer) streamClaims(md metadata.MD) *Claims {
+	if s.jwt == nil {
+		return nil
+	}
+	ctx, err := s.Authenticate(metadata.NewIncomingContext(context.Background(), md))
+	if err != nil {
+		return nil
 	}
+	claims, _ := ClaimsFromContext(ctx)
+	return claims
+}
 
+// authInterceptor validates the token in metadata and applies the policy
+func (s *CoreServiceServer) authInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := s.AuthorizeCall(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
 	return handler(ctx, req)
 }
 
-// streamAuthInterceptor validates the token for streaming RPCs
+// streamAuthInterceptor validates the token for streaming RPCs and applies
+// the policy
 func (s *CoreServiceServer) streamAuthInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-	if s.cfg.Token == "" {
-		return handler(srv, ss)
+	ctx, err := s.AuthorizeCall(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
 	}
+	return handler(srv, &trackedStream{ServerStream: ss, ctx: ctx})
+}
 
-	md, ok := metadata.FromIncomingContext(ss.Context())
-	if !ok {
-		return status.Error(codes.Unauthenticated, "missing metadata")
+// trackInterceptor registers the call with the engine, so Shutdown can wait
+// for it and cancel it at the drain deadline
+func (s *CoreServiceServer) trackInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, end, err := s.engine.rpcs.begin(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
 	}
+	defer end()
+	return handler(ctx, req)
+}
 
-	tokens := md.Get("authorization")
-	if len(tokens) == 0 {
-		return status.Error(codes.Unauthenticated, "missing token")
+// streamTrackInterceptor registers streaming RPCs with the engine
+func (s *CoreServiceServer) streamTrackInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, end, err := s.engine.rpcs.begin(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
 	}
+	defer end()
+	return handler(srv, &trackedStream{ServerStream: ss, ctx: ctx})
+}
 
-	if subtle.ConstantTimeCompare([]byte(tokens[0]), []byte("Bearer "+s.cfg.Token)) != 1 {
-		return status.Error(codes.Unauthenticated, "invalid token")
-	}
+// trackedStream is a grpc.ServerStream with the context of its tracked,
+// authenticated call.
+type trackedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *trackedStream) Context() context.Context {
+	return s.ctx
+}
 
-	return handler(srv, ss)
+// Engine returns the engine the server belongs to.
+func (s *CoreServiceServer) Engine() *Engine {
+	return s.engine
 }
 
 // DartConn returns the gRPC client connection to Dart (nil if not connected)
@@ -186,7 +349,8 @@ func (s *CoreServiceServer) InvokeDart(method string, req proto.Message, resp pr
 	}
 
 	// Fallback to FFI callback
-	if DartCallback == nil {
+	dartCallback := s.engine.DartCallback()
+	if dartCallback == nil {
 		return fmt.Errorf("dart callback not registered")
 	}
 
@@ -195,7 +359,7 @@ func (s *CoreServiceServer) InvokeDart(method string, req proto.Message, resp pr
 		return fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	respBytes, err := DartCallback(method, reqBytes)
+	respBytes, err := s.engine.callDart(dartCallback, method, reqBytes)
 	if err != nil {
 		return fmt.Errorf("dart callback failed: %w", err)
 	}
@@ -261,12 +425,13 @@ func (s *CoreServiceServer) InvokeDartStream(method string, req proto.Message, f
 	}
 
 	// Fallback to FFI callback mechanism
-	if DartCallback == nil {
+	dartCallback := s.engine.DartCallback()
+	if dartCallback == nil {
 		return nil, fmt.Errorf("dart callback not registered")
 	}
 
 	// 1. Create a stream session to receive data from Dart
-	session := NewStreamSession(method, StreamTypeServerStream)
+	session := s.engine.NewStreamSession(method, StreamTypeServerStream)
 	defer func() {
 		// We don't defer CloseStreamSession here because we need it open to receive data.
 		// It will be closed by Dart (via FFI CloseStream) or by us when we are done.
@@ -277,14 +442,14 @@ func (s *CoreServiceServer) InvokeDartStream(method string, req proto.Message, f
 
 	reqBytes, err := proto.Marshal(req)
 	if err != nil {
-		CloseStreamSession(session.ID)
+		s.engine.CloseStreamSession(session.ID)
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
 	// 3. Invoke Dart (Unary call to start the stream)
-	_, err = DartCallback(methodWithId, reqBytes)
+	_, err = s.engine.callDart(dartCallback, methodWithId, reqBytes)
 	if err != nil {
-		CloseStreamSession(session.ID)
+		s.engine.CloseStreamSession(session.ID)
 		return nil, fmt.Errorf("dart callback failed: %w", err)
 	}
 
@@ -317,7 +482,7 @@ func (s *CoreServiceServer) InvokeDartStream(method string, req proto.Message, f
 			return responses, nil
 
 		case <-timeoutChan:
-			CloseStreamSession(session.ID)
+			s.engine.CloseStreamSession(session.ID)
 			return nil, fmt.Errorf("timeout waiting for stream data")
 		}
 	}
@@ -325,17 +490,18 @@ func (s *CoreServiceServer) InvokeDartStream(method string, req proto.Message, f
 
 // InvokeDartClientStream calls a Dart client streaming method
 func (s *CoreServiceServer) InvokeDartClientStream(method string, reqs []proto.Message, respFactory MessageFactory) (proto.Message, error) {
-	if DartCallback == nil {
+	dartCallback := s.engine.DartCallback()
+	if dartCallback == nil {
 		return nil, fmt.Errorf("dart callback not registered")
 	}
 
-	session := NewStreamSession(method, StreamTypeBidiStream)
-	defer CloseStreamSession(session.ID)
+	session := s.engine.NewStreamSession(method, StreamTypeBidiStream)
+	defer s.engine.CloseStreamSession(session.ID)
 
 	methodWithId := fmt.Sprintf("%s:%d", method, session.ID)
 
 	// Send initial request (empty or first?)
-	_, err := DartCallback(methodWithId, []byte{})
+	_, err := s.engine.callDart(dartCallback, methodWithId, []byte{})
 	if err != nil {
 		return nil, fmt.Errorf("dart callback failed: %w", err)
 	}
@@ -372,17 +538,18 @@ func (s *CoreServiceServer) InvokeDartClientStream(method string, reqs []proto.M
 
 // InvokeDartBidiStream calls a Dart bidirectional streaming method
 func (s *CoreServiceServer) InvokeDartBidiStream(method string, reqs []proto.Message, respFactory MessageFactory) ([]proto.Message, error) {
-	if DartCallback == nil {
+	dartCallback := s.engine.DartCallback()
+	if dartCallback == nil {
 		return nil, fmt.Errorf("dart callback not registered")
 	}
 
-	session := NewStreamSession(method, StreamTypeBidiStream)
+	session := s.engine.NewStreamSession(method, StreamTypeBidiStream)
 
 	methodWithId := fmt.Sprintf("%s:%d", method, session.ID)
 
-	_, err := DartCallback(methodWithId, []byte{})
+	_, err := s.engine.callDart(dartCallback, methodWithId, []byte{})
 	if err != nil {
-		CloseStreamSession(session.ID)
+		s.engine.CloseStreamSession(session.ID)
 		return nil, fmt.Errorf("dart callback failed: %w", err)
 	}
 
@@ -442,7 +609,7 @@ func (s *CoreServiceServer) InvokeDartBidiStream(method string, reqs []proto.Mes
 	case <-done:
 		// success
 	case <-timeoutChan:
-		CloseStreamSession(session.ID)
+		s.engine.CloseStreamSession(session.ID)
 		respErr = fmt.Errorf("timeout waiting for bidi completion")
 		timedOut = true
 	}
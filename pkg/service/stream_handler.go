@@ -21,6 +21,7 @@ import (
 	"time"
 	"unsafe"
 
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -46,6 +47,8 @@ type StreamSession struct {
 	Callback    StreamCallback    // For server streaming: sends data to Dart (1 copy)
 	CallbackFfi StreamCallbackFfi // For server streaming: zero-copy variant
 	Metadata    map[string]string // Request metadata from Dart
+	Claims      *Claims           // Verified JWT claims of the caller, nil without JWT auth
+	engine      *Engine
 	headers     map[string]string
 	headersSent bool
 	trailers    map[string]string
@@ -69,28 +72,23 @@ type StreamCallback func(streamId int64, msgType byte, data []byte)
 // StreamCallbackFfi is the zero-copy variant - receives C pointer directly
 type StreamCallbackFfi func(streamId int64, msgType byte, data unsafe.Pointer, len int64)
 
-var (
-	streamSessions       = make(map[int64]*StreamSession)
-	streamSessionsMu     sync.RWMutex
-	nextStreamId         int64
-	streamCallback       StreamCallback
-	streamCallbackFfi    StreamCallbackFfi
-	defaultStreamTimeout time.Duration
-)
+// nextStreamId is shared by all engines so that stream IDs stay unique when
+// several engines report to the same Dart stream callback.
+var nextStreamId int64
 
 // SetStreamCallback registers the callback for sending stream data to Dart (1 copy)
 func SetStreamCallback(cb StreamCallback) {
-	streamCallback = cb
+	defaultEngine.SetStreamCallback(cb)
 }
 
 // SetStreamCallbackFfi registers the zero-copy callback for FFI mode
 func SetStreamCallbackFfi(cb StreamCallbackFfi) {
-	streamCallbackFfi = cb
+	defaultEngine.SetStreamCallbackFfi(cb)
 }
 
-// SetDefaultStreamTimeout sets the global timeout for stream readiness
+// SetDefaultStreamTimeout sets the default engine's timeout for stream readiness
 func SetDefaultStreamTimeout(d time.Duration) {
-	defaultStreamTimeout = d
+	defaultEngine.SetStreamTimeout(d)
 }
 
 func (s StreamType) String() string {
@@ -106,8 +104,43 @@ func (s StreamType) String() string {
 	}
 }
 
-// NewStreamSession creates a new stream session
+// NewStreamSession creates a new stream session on the default engine
 func NewStreamSession(method string, streamType StreamType) *StreamSession {
+	return defaultEngine.NewStreamSession(method, streamType)
+}
+
+// GetStreamSession retrieves an existing stream session of the default engine
+func GetStreamSession(streamId int64) *StreamSession {
+	return defaultEngine.GetStreamSession(streamId)
+}
+
+// CloseStreamSession closes and removes a stream session of the default engine
+func CloseStreamSession(streamId int64) {
+	defaultEngine.CloseStreamSession(streamId)
+}
+
+// CloseStreamInput signals that the client has finished sending data
+func CloseStreamInput(streamId int64) {
+	defaultEngine.CloseStreamInput(streamId)
+}
+
+// SendToStream sends data to a client/bidi stream session (from Dart to Go)
+func SendToStream(streamId int64, data []byte) error {
+	return defaultEngine.SendToStream(streamId, data)
+}
+
+// SignalStreamReady signals that Dart is ready to receive on a stream of the
+// default engine.
+func SignalStreamReady(streamId int64) {
+	defaultEngine.SignalStreamReady(streamId)
+}
+
+// NewStreamSession creates a new stream session
+func (e *Engine) NewStreamSession(method string, streamType StreamType) *StreamSession {
+	e.callbackMu.RLock()
+	cb, cbFfi := e.streamCallback, e.streamCallbackFfi
+	e.callbackMu.RUnlock()
+
 	session := &StreamSession{
 		ID:          atomic.AddInt64(&nextStreamId, 1),
 		Method:      method,
@@ -116,33 +149,34 @@ func NewStreamSession(method string, streamType StreamType) *StreamSession {
 		DoneChan:    make(chan struct{}),
 		ReadyChan:   make(chan struct{}), // For server/bidi streams: wait for Dart to be ready
 		ErrorChan:   make(chan error, 1),
-		Callback:    streamCallback,
-		CallbackFfi: streamCallbackFfi,
+		Callback:    cb,
+		CallbackFfi: cbFfi,
+		engine:      e,
 	}
 
-	streamSessionsMu.Lock()
-	streamSessions[session.ID] = session
-	streamSessionsMu.Unlock()
+	e.sessionsMu.Lock()
+	e.sessions[session.ID] = session
+	e.sessionsMu.Unlock()
 
 	log.Printf("Created stream session %d for %s (type=%s)", session.ID, method, streamType)
 	return session
 }
 
 // GetStreamSession retrieves an existing stream session
-func GetStreamSession(streamId int64) *StreamSession {
-	streamSessionsMu.RLock()
-	defer streamSessionsMu.RUnlock()
-	return streamSessions[streamId]
+func (e *Engine) GetStreamSession(streamId int64) *StreamSession {
+	e.sessionsMu.RLock()
+	defer e.sessionsMu.RUnlock()
+	return e.sessions[streamId]
 }
 
 // CloseStreamSession closes and removes a stream session
-func CloseStreamSession(streamId int64) {
-	streamSessionsMu.Lock()
-	session, ok := streamSessions[streamId]
+func (e *Engine) CloseStreamSession(streamId int64) {
+	e.sessionsMu.Lock()
+	session, ok := e.sessions[streamId]
 	if ok {
-		delete(streamSessions, streamId)
+		delete(e.sessions, streamId)
 	}
-	streamSessionsMu.Unlock()
+	e.sessionsMu.Unlock()
 
 	if ok && session != nil {
 		session.mu.Lock()
@@ -157,12 +191,18 @@ func CloseStreamSession(streamId int64) {
 		}
 		session.mu.Unlock()
 		log.Printf("Closed stream session %d", streamId)
+
+		// Wake a Shutdown waiting for the sessions to end
+		select {
+		case e.sessionClosed <- struct{}{}:
+		default:
+		}
 	}
 }
 
 // CloseStreamInput signals that the client has finished sending data
-func CloseStreamInput(streamId int64) {
-	session := GetStreamSession(streamId)
+func (e *Engine) CloseStreamInput(streamId int64) {
+	session := e.GetStreamSession(streamId)
 	if session == nil {
 		return
 	}
@@ -178,8 +218,8 @@ func CloseStreamInput(streamId int64) {
 }
 
 // SendToStream sends data to a client/bidi stream session (from Dart to Go)
-func SendToStream(streamId int64, data []byte) error {
-	session := GetStreamSession(streamId)
+func (e *Engine) SendToStream(streamId int64, data []byte) error {
+	session := e.GetStreamSession(streamId)
 	if session == nil {
 		return fmt.Errorf("stream session %d not found", streamId)
 	}
@@ -201,8 +241,8 @@ func SendToStream(streamId int64, data []byte) error {
 
 // SignalStreamReady signals that Dart has registered the stream controller
 // and is ready to receive data. This is called by Dart after registering.
-func SignalStreamReady(streamId int64) {
-	session := GetStreamSession(streamId)
+func (e *Engine) SignalStreamReady(streamId int64) {
+	session := e.GetStreamSession(streamId)
 	if session == nil {
 		log.Printf("SignalStreamReady: session %d not found", streamId)
 		return
@@ -221,10 +261,12 @@ func SignalStreamReady(streamId int64) {
 }
 
 // WaitForReady waits for the ready signal from Dart (with timeout).
-// If defaultStreamTimeout is 0, waits indefinitely.
+// If the engine's stream timeout is 0, waits indefinitely.
 func (s *StreamSession) WaitForReady() bool {
+	timeout := time.Duration(s.engine.timeout.Load())
+
 	// When timeout is 0, wait indefinitely (no timeout case in select)
-	if defaultStreamTimeout == 0 {
+	if timeout == 0 {
 		select {
 		case <-s.ReadyChan:
 			return true
@@ -239,7 +281,7 @@ func (s *StreamSession) WaitForReady() bool {
 		return true
 	case <-s.DoneChan:
 		return false
-	case <-time.After(defaultStreamTimeout):
+	case <-time.After(timeout):
 		log.Printf("Stream %d: timeout waiting for ready signal", s.ID)
 		return false
 	}
@@ -441,31 +483,67 @@ func (s *StreamSession) ErrorStream(err error) {
 // HandlerFunc is the function that handles the stream logic
 type HandlerFunc func(session *StreamSession)
 
-// StartServerStream starts a server streaming RPC
+// StartServerStream starts a server streaming RPC on the default engine
 func StartServerStream(method string, handler HandlerFunc) int64 {
-	session := NewStreamSession(method, StreamTypeServerStream)
-	go func() {
-		defer CloseStreamSession(session.ID)
-		handler(session)
-	}()
-	return session.ID
+	return defaultEngine.startStream(method, StreamTypeServerStream, nil, handler)
 }
 
-// StartClientStream starts a client streaming RPC
+// StartClientStream starts a client streaming RPC on the default engine
 func StartClientStream(method string, handler HandlerFunc) int64 {
-	session := NewStreamSession(method, StreamTypeClientStream)
-	go func() {
-		defer CloseStreamSession(session.ID)
-		handler(session)
-	}()
-	return session.ID
+	return defaultEngine.startStream(method, StreamTypeClientStream, nil, handler)
 }
 
-// StartBidiStream starts a bidirectional streaming RPC
+// StartBidiStream starts a bidirectional streaming RPC on the default engine
 func StartBidiStream(method string, handler HandlerFunc) int64 {
-	session := NewStreamSession(method, StreamTypeBidiStream)
+	return defaultEngine.startStream(method, StreamTypeBidiStream, nil, handler)
+}
+
+// StartServerStreamWithMeta is StartServerStream with request metadata,
+// which the handler sees as StreamSession.Metadata.
+func StartServerStreamWithMeta(method string, md metadata.MD, handler HandlerFunc) int64 {
+	return defaultEngine.StartServerStreamWithMeta(method, md, handler)
+}
+
+// StartClientStreamWithMeta is StartClientStream with request metadata.
+func StartClientStreamWithMeta(method string, md metadata.MD, handler HandlerFunc) int64 {
+	return defaultEngine.StartClientStreamWithMeta(method, md, handler)
+}
+
+// StartBidiStreamWithMeta is StartBidiStream with request metadata.
+func StartBidiStreamWithMeta(method string, md metadata.MD, handler HandlerFunc) int64 {
+	return defaultEngine.StartBidiStreamWithMeta(method, md, handler)
+}
+
+// StartServerStreamWithMeta starts a server streaming RPC whose handler sees
+// md as StreamSession.Metadata.
+func (e *Engine) StartServerStreamWithMeta(method string, md metadata.MD, handler HandlerFunc) int64 {
+	return e.startStream(method, StreamTypeServerStream, md, handler)
+}
+
+// StartClientStreamWithMeta starts a client streaming RPC with request metadata.
+func (e *Engine) StartClientStreamWithMeta(method string, md metadata.MD, handler HandlerFunc) int64 {
+	return e.startStream(method, StreamTypeClientStream, md, handler)
+}
+
+// StartBidiStreamWithMeta starts a bidirectional streaming RPC with request metadata.
+func (e *Engine) StartBidiStreamWithMeta(method string, md metadata.MD, handler HandlerFunc) int64 {
+	return e.startStream(method, StreamTypeBidiStream, md, handler)
+}
+
+// startStream creates a session carrying md and runs handler on it. It
+// returns -1 while the engine is stopping.
+func (e *Engine) startStream(method string, streamType StreamType, md metadata.MD, handler HandlerFunc) int64 {
+	if e.draining.Load() {
+		log.Printf("Engine stopping, rejected stream %s", method)
+		return -1
+	}
+	session := e.NewStreamSession(method, streamType)
+	session.Metadata = flattenMetadata(md)
+	if core := e.Core(); core != nil {
+		session.Claims = core.streamClaims(md)
+	}
 	go func() {
-		defer CloseStreamSession(session.ID)
+		defer e.CloseStreamSession(session.ID)
 		handler(session)
 	}()
 	return session.ID
@@ -484,46 +562,62 @@ type ClientStreamHandler func() HandlerFunc
 // BidiStreamHandler handles bidirectional streaming RPCs (no initial data)
 type BidiStreamHandler func() HandlerFunc
 
-var (
-	serverStreamHandlers    = make(map[string]ServerStreamHandler)
-	clientStreamHandlers    = make(map[string]ClientStreamHandler)
-	bidiStreamHandlers      = make(map[string]BidiStreamHandler)
-	streamHandlerRegistryMu sync.RWMutex
-)
+// RegisterServerStreamHandler registers a handler for a server streaming
+// method on the default engine. This should be called during initialization,
+// typically in init() or test setup.
+func RegisterServerStreamHandler(method string, handler ServerStreamHandler) {
+	defaultEngine.RegisterServerStreamHandler(method, handler)
+}
+
+// RegisterClientStreamHandler registers a handler for a client streaming
+// method on the default engine.
+func RegisterClientStreamHandler(method string, handler ClientStreamHandler) {
+	defaultEngine.RegisterClientStreamHandler(method, handler)
+}
+
+// RegisterBidiStreamHandler registers a handler for a bidirectional streaming
+// method on the default engine.
+func RegisterBidiStreamHandler(method string, handler BidiStreamHandler) {
+	defaultEngine.RegisterBidiStreamHandler(method, handler)
+}
+
+// UnregisterAllStreamHandlers removes all stream handlers registered on the
+// default engine. Useful for test cleanup.
+func UnregisterAllStreamHandlers() {
+	defaultEngine.UnregisterAllStreamHandlers()
+}
 
 // RegisterServerStreamHandler registers a handler for a server streaming method.
-// This should be called during initialization, typically in init() or test setup.
-func RegisterServerStreamHandler(method string, handler ServerStreamHandler) {
-	streamHandlerRegistryMu.Lock()
-	defer streamHandlerRegistryMu.Unlock()
-	serverStreamHandlers[method] = handler
+func (e *Engine) RegisterServerStreamHandler(method string, handler ServerStreamHandler) {
+	e.handlersMu.Lock()
+	defer e.handlersMu.Unlock()
+	e.serverStreamHandlers[method] = handler
 	log.Printf("Registered server stream handler for: %s", method)
 }
 
 // RegisterClientStreamHandler registers a handler for a client streaming method.
-func RegisterClientStreamHandler(method string, handler ClientStreamHandler) {
-	streamHandlerRegistryMu.Lock()
-	defer streamHandlerRegistryMu.Unlock()
-	clientStreamHandlers[method] = handler
+func (e *Engine) RegisterClientStreamHandler(method string, handler ClientStreamHandler) {
+	e.handlersMu.Lock()
+	defer e.handlersMu.Unlock()
+	e.clientStreamHandlers[method] = handler
 	log.Printf("Registered client stream handler for: %s", method)
 }
 
 // RegisterBidiStreamHandler registers a handler for a bidirectional streaming method.
-func RegisterBidiStreamHandler(method string, handler BidiStreamHandler) {
-	streamHandlerRegistryMu.Lock()
-	defer streamHandlerRegistryMu.Unlock()
-	bidiStreamHandlers[method] = handler
+func (e *Engine) RegisterBidiStreamHandler(method string, handler BidiStreamHandler) {
+	e.handlersMu.Lock()
+	defer e.handlersMu.Unlock()
+	e.bidiStreamHandlers[method] = handler
 	log.Printf("Registered bidi stream handler for: %s", method)
 }
 
 // UnregisterAllStreamHandlers removes all registered stream handlers.
-// Useful for test cleanup.
-func UnregisterAllStreamHandlers() {
-	streamHandlerRegistryMu.Lock()
-	defer streamHandlerRegistryMu.Unlock()
-	serverStreamHandlers = make(map[string]ServerStreamHandler)
-	clientStreamHandlers = make(map[string]ClientStreamHandler)
-	bidiStreamHandlers = make(map[string]BidiStreamHandler)
+func (e *Engine) UnregisterAllStreamHandlers() {
+	e.handlersMu.Lock()
+	defer e.handlersMu.Unlock()
+	e.serverStreamHandlers = make(map[string]ServerStreamHandler)
+	e.clientStreamHandlers = make(map[string]ClientStreamHandler)
+	e.bidiStreamHandlers = make(map[string]BidiStreamHandler)
 	log.Println("Unregistered all stream handlers")
 }
 
@@ -533,40 +627,74 @@ func UnregisterAllStreamHandlers() {
 
 // HandleServerStream dispatches a server streaming request to the registered handler
 func HandleServerStream(method string, data []byte) int64 {
-	streamHandlerRegistryMu.RLock()
-	handler, ok := serverStreamHandlers[method]
-	streamHandlerRegistryMu.RUnlock()
+	return HandleServerStreamWithMeta(method, data, nil)
+}
+
+// HandleClientStream dispatches a client streaming request to the registered handler
+func HandleClientStream(method string) int64 {
+	return HandleClientStreamWithMeta(method, nil)
+}
+
+// HandleBidiStream dispatches a bidirectional streaming request to the registered handler
+func HandleBidiStream(method string) int64 {
+	return HandleBidiStreamWithMeta(method, nil)
+}
+
+// HandleServerStreamWithMeta is HandleServerStream with request metadata,
+// which the handler sees as StreamSession.Metadata.
+func HandleServerStreamWithMeta(method string, data []byte, md metadata.MD) int64 {
+	return defaultEngine.HandleServerStreamWithMeta(method, data, md)
+}
+
+// HandleClientStreamWithMeta is HandleClientStream with request metadata.
+func HandleClientStreamWithMeta(method string, md metadata.MD) int64 {
+	return defaultEngine.HandleClientStreamWithMeta(method, md)
+}
+
+// HandleBidiStreamWithMeta is HandleBidiStream with request metadata.
+func HandleBidiStreamWithMeta(method string, md metadata.MD) int64 {
+	return defaultEngine.HandleBidiStreamWithMeta(method, md)
+}
+
+// HandleServerStreamWithMeta dispatches a server streaming request to the
+// handler registered on e. Returns -1 if there is none.
+func (e *Engine) HandleServerStreamWithMeta(method string, data []byte, md metadata.MD) int64 {
+	e.handlersMu.RLock()
+	handler, ok := e.serverStreamHandlers[method]
+	e.handlersMu.RUnlock()
 
 	if ok {
-		return StartServerStream(method, handler(data))
+		return e.startStream(method, StreamTypeServerStream, md, handler(data))
 	}
 
 	log.Printf("HandleServerStream: method %s not implemented in core", method)
 	return -1
 }
 
-// HandleClientStream dispatches a client streaming request to the registered handler
-func HandleClientStream(method string) int64 {
-	streamHandlerRegistryMu.RLock()
-	handler, ok := clientStreamHandlers[method]
-	streamHandlerRegistryMu.RUnlock()
+// HandleClientStreamWithMeta dispatches a client streaming request to the
+// handler registered on e.
+func (e *Engine) HandleClientStreamWithMeta(method string, md metadata.MD) int64 {
+	e.handlersMu.RLock()
+	handler, ok := e.clientStreamHandlers[method]
+	e.handlersMu.RUnlock()
 
 	if ok {
-		return StartClientStream(method, handler())
+		return e.startStream(method, StreamTypeClientStream, md, handler())
 	}
 
 	log.Printf("HandleClientStream: method %s not implemented in core", method)
 	return -1
 }
 
-// HandleBidiStream dispatches a bidirectional streaming request to the registered handler
-func HandleBidiStream(method string) int64 {
-	streamHandlerRegistryMu.RLock()
-	handler, ok := bidiStreamHandlers[method]
-	streamHandlerRegistryMu.RUnlock()
+// HandleBidiStreamWithMeta dispatches a bidirectional streaming request to
+// the handler registered on e.
+func (e *Engine) HandleBidiStreamWithMeta(method string, md metadata.MD) int64 {
+	e.handlersMu.RLock()
+	handler, ok := e.bidiStreamHandlers[method]
+	e.handlersMu.RUnlock()
 
 	if ok {
-		return StartBidiStream(method, handler())
+		return e.startStream(method, StreamTypeBidiStream, md, handler())
 	}
 
 	log.Printf("HandleBidiStream: method %s not implemented in core", method)
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"log"
+	"math/big"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// selfSignedValidity is how long an auto-generated certificate is valid.
+const selfSignedValidity = 365 * 24 * time.Hour
+
+// serverTLS is the TLS setup of an engine's TCP listener.
+type serverTLS struct {
+	config      *tls.Config
+	fingerprint string // SHA-256 of the server certificate
+}
+
+// loadServerTLS builds the TLS configuration of the TCP listener from cfg:
+// the certificate of TLSCertFile/TLSKeyFile, or a self-signed one with
+// TLSAutoCert, and client certificate verification with TLSClientCAFile.
+// It returns nil if cfg does not enable TLS.
+func loadServerTLS(cfg *Config) (*serverTLS, error) {
+	if cfg.TLSCertFile == "" && cfg.TLSKeyFile == "" && !cfg.TLSAutoCert && cfg.TLSClientCAFile == "" {
+		return nil, nil
+	}
+
+	var (
+		cert tls.Certificate
+		err  error
+	)
+	switch {
+	case cfg.TLSCertFile != "" || cfg.TLSKeyFile != "":
+		cert, err = tls.LoadX509KeyPair(cfg.TLSCertFile, cfg.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+		}
+	case cfg.TLSAutoCert:
+		cert, err = selfSignedCertificate()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate TLS certificate: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("TLSClientCAFile requires TLSCertFile/TLSKeyFile or TLSAutoCert")
+	}
+
+	st := &serverTLS{
+		config: &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		},
+		fingerprint: Fingerprint(cert.Certificate[0]),
+	}
+	if cfg.TLSClientCAFile != "" {
+		pool, err := loadCertPool(cfg.TLSClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		st.config.ClientCAs = pool
+		st.config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	if cfg.TLSCertFile == "" {
+		log.Printf("Engine - self-signed TLS certificate, SHA-256 fingerprint %s", st.fingerprint)
+	}
+	return st, nil
+}
+
+// selfSignedCertificate generates a certificate for localhost, the loopback
+// addresses and the host name, for local debugging.
+func selfSignedCertificate() (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "synurang"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(selfSignedValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+	if host, err := os.Hostname(); err == nil && host != "localhost" {
+		tmpl.DNSNames = append(tmpl.DNSNames, host)
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
+
+// Fingerprint returns the SHA-256 fingerprint of a DER certificate as
+// colon-separated hex, the form browsers and openssl print.
+func Fingerprint(der []byte) string {
+	sum := sha256.Sum256(der)
+	hex := make([]string, len(sum))
+	for i, b := range sum {
+		hex[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(hex, ":")
+}
+
+// loadCertPool reads the PEM certificates of path into a pool.
+func loadCertPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates in CA file %s", path)
+	}
+	return pool, nil
+}
+
+// viewCredentials returns the transport credentials of the TCP connection
+// to the Dart server: TLS if cfg.ViewTLS is set, plaintext otherwise.
+func viewCredentials(cfg *Config) (credentials.TransportCredentials, error) {
+	if !cfg.ViewTLS {
+		return insecure.NewCredentials(), nil
+	}
+	config := &tls.Config{
+		ServerName: cfg.ViewTLSServerName,
+		MinVersion: tls.VersionTLS12,
+	}
+	if config.ServerName == "" {
+		config.ServerName = "localhost"
+	}
+	if cfg.ViewTLSCAFile != "" {
+		pool, err := loadCertPool(cfg.ViewTLSCAFile)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = pool
+	}
+	if cfg.ViewTLSCertFile != "" || cfg.ViewTLSKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.ViewTLSCertFile, cfg.ViewTLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return credentials.NewTLS(config), nil
+}
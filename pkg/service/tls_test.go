@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pb "github.com/ivere27/synurang/pkg/api"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// testPKI is a CA with a server and a client certificate, written as PEM
+// files to a temporary directory.
+type testPKI struct {
+	dir  string
+	pool *x509.CertPool
+	ca   *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestPKI(t *testing.T) *testPKI {
+	t.Helper()
+	p := &testPKI{dir: t.TempDir(), pool: x509.NewCertPool()}
+	p.ca, p.key = p.issue(t, "ca", nil, nil)
+	p.pool.AddCert(p.ca)
+	p.issue(t, "server", p.ca, p.key)
+	p.issue(t, "client", p.ca, p.key)
+	return p
+}
+
+// issue creates the certificate name.pem and key name-key.pem, signed by
+// parent, or a self-signed CA if parent is nil.
+func (p *testPKI) issue(t *testing.T, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"localhost"},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage = x509.KeyUsageCertSign
+		parent, parentKey = tmpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.write(t, name+".pem", "CERTIFICATE", der)
+	p.write(t, name+"-key.pem", "EC PRIVATE KEY", keyDer)
+	cert, _ := x509.ParseCertificate(der)
+	return cert, key
+}
+
+func (p *testPKI) write(t *testing.T, name, typ string, der []byte) {
+	t.Helper()
+	data := pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der})
+	if err := os.WriteFile(p.path(name), data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (p *testPKI) path(name string) string {
+	return filepath.Join(p.dir, name)
+}
+
+// tcpPort returns the port of the TCP listener of st.
+func tcpPort(t *testing.T, st Status) string {
+	t.Helper()
+	for _, l := range st.Listeners {
+		if l.Network == "tcp" && l.BoundAddress != "" {
+			_, port, _ := net.SplitHostPort(l.BoundAddress)
+			return port
+		}
+	}
+	t.Fatalf("no TCP listener in %+v", st)
+	return ""
+}
+
+// ping calls HealthService.Ping on target with creds.
+func ping(target string, creds credentials.TransportCredentials) error {
+	conn, err := grpc.Dial(target, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = pb.NewHealthServiceClient(conn).Ping(ctx, &emptypb.Empty{})
+	return err
+}
+
+func TestEngine_TLSAutoCert(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "tls.sock")
+	e := NewEngine()
+	st, err := e.Start(&Config{EngineSocketPath: socket, EngineTcpPort: "0", TLSAutoCert: true})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer e.Stop()
+
+	fingerprint := st.Listeners[1].TLSFingerprint
+	if fingerprint == "" || st.Listeners[0].TLSFingerprint != "" {
+		t.Fatalf("listeners = %+v, want a fingerprint for TCP only", st.Listeners)
+	}
+	if got := st.Proto().Listeners[1].TlsFingerprint; got != fingerprint {
+		t.Errorf("Proto fingerprint = %q", got)
+	}
+
+	// Trust the certificate by its fingerprint, as a debugging client would
+	pinned := credentials.NewTLS(&tls.Config{
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(raw [][]byte, _ [][]*x509.Certificate) error {
+			if Fingerprint(raw[0]) != fingerprint {
+				return errors.New("fingerprint mismatch")
+			}
+			return nil
+		},
+	})
+	target := "localhost:" + tcpPort(t, st)
+	if err := ping(target, pinned); err != nil {
+		t.Errorf("Ping over TLS: %v", err)
+	}
+	if err := ping(target, insecure.NewCredentials()); err == nil {
+		t.Error("plaintext Ping over TCP succeeded")
+	}
+	if err := ping("unix://"+socket, insecure.NewCredentials()); err != nil {
+		t.Errorf("Ping over UDS: %v", err)
+	}
+}
+
+func TestEngine_MutualTLS(t *testing.T) {
+	pki := newTestPKI(t)
+	e := NewEngine()
+	st, err := e.Start(&Config{
+		EngineTcpPort:   "0",
+		TLSCertFile:     pki.path("server.pem"),
+		TLSKeyFile:      pki.path("server-key.pem"),
+		TLSClientCAFile: pki.path("ca.pem"),
+	})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer e.Stop()
+	target := "localhost:" + tcpPort(t, st)
+
+	if err := ping(target, credentials.NewTLS(&tls.Config{RootCAs: pki.pool})); err == nil {
+		t.Error("Ping without a client certificate succeeded")
+	}
+	client, err := tls.LoadX509KeyPair(pki.path("client.pem"), pki.path("client-key.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	creds := credentials.NewTLS(&tls.Config{RootCAs: pki.pool, Certificates: []tls.Certificate{client}})
+	if err := ping(target, creds); err != nil {
+		t.Errorf("Ping with a client certificate: %v", err)
+	}
+}
+
+func TestEngine_TLSLoadError(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.pem")
+	cfg := &Config{EngineTcpPort: "0", TLSCertFile: missing, TLSKeyFile: missing}
+
+	// The TCP endpoint is skipped rather than served in plaintext
+	e := NewEngine()
+	st, err := e.Start(cfg)
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if l := st.Listeners[0]; l.Err == nil || l.BoundAddress != "" || len(e.Listeners()) != 0 {
+		t.Errorf("TCP listener = %+v, want it disabled", l)
+	}
+	e.Stop()
+
+	cfg.FailOnListenError = true
+	if _, err := e.Start(cfg); err == nil {
+		e.Stop()
+		t.Error("Start with FailOnListenError succeeded")
+	}
+}
+
+func TestViewTLS(t *testing.T) {
+	pki := newTestPKI(t)
+
+	// Another engine stands in for the Dart gRPC server
+	dart := NewEngine()
+	st, err := dart.Start(&Config{
+		EngineTcpPort: "0",
+		TLSCertFile:   pki.path("server.pem"),
+		TLSKeyFile:    pki.path("server-key.pem"),
+	})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer dart.Stop()
+
+	e := NewEngine()
+	if _, err := e.Start(&Config{
+		ViewTcpPort:   tcpPort(t, st),
+		ViewTLS:       true,
+		ViewTLSCAFile: pki.path("ca.pem"),
+	}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer e.Stop()
+
+	conn := e.Core().DartConn()
+	if conn == nil {
+		t.Fatal("no connection to Dart")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := pb.NewHealthServiceClient(conn).Ping(ctx, &emptypb.Empty{}); err != nil {
+		t.Errorf("Ping over the TLS connection to Dart: %v", err)
+	}
+}
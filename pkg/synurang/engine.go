@@ -0,0 +1,145 @@
+// Loading Synurang engine libraries from Go.
+//
+// An engine library (cmd/server built with -buildmode=c-shared) speaks the
+// same Synurang ABI as plugins, documented in ABI.md. LoadEngine starts the
+// engine through Synurang_Init with an EngineConfig and returns a *Plugin, so
+// every plugin API (PluginClientConn, RegisterPluginServices, limits, ...)
+// works with engines too:
+//
+//	engine, err := synurang.LoadEngine("./libsynurang.so", synurang.EngineConfig{
+//	    CachePath:   "/tmp/cache",
+//	    EnableCache: true,
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer engine.Close() // stops the engine
+//
+//	conn := synurang.NewPluginClientConn(engine, "")
+//	resp, err := pb.NewHealthServiceClient(conn).Ping(ctx, &emptypb.Empty{})
+
+package synurang
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// EngineConfig is the Synurang_Init configuration of an engine library,
+// encoded as JSON. It mirrors the CoreArgument struct passed to
+// StartGrpcServer by Dart. Empty listener fields run the engine in FFI-only
+// mode.
+type EngineConfig struct {
+	EngineSocketPath string `json:"engine_socket_path,omitempty"`
+	EngineTcpPort    string `json:"engine_tcp_port,omitempty"`
+	ViewSocketPath   string `json:"view_socket_path,omitempty"`
+	ViewTcpPort      string `json:"view_tcp_port,omitempty"`
+	Token            string `json:"token,omitempty"`
+	CachePath        string `json:"cache_path,omitempty"`
+	EnableCache      bool   `json:"enable_cache,omitempty"`
+	StreamTimeoutMs  int64  `json:"stream_timeout_ms,omitempty"`
+
+	// FailOnListenError fails Synurang_Init if an endpoint cannot be bound,
+	// instead of skipping it.
+	FailOnListenError bool `json:"fail_on_listen_error,omitempty"`
+
+	// ShutdownTimeoutMs is how long Synurang_Shutdown lets calls in progress
+	// finish before terminating them (0: the default of 5s, < 0: none).
+	ShutdownTimeoutMs int64 `json:"shutdown_timeout_ms,omitempty"`
+
+	// HealthIntervalMs is how often grpc.health.v1 Watch streams re-check
+	// the engine's health (0: the default of 5s).
+	HealthIntervalMs int64 `json:"health_interval_ms,omitempty"`
+
+	// TLS of the TCP listener: a certificate and key, or a generated
+	// self-signed certificate, and the CAs of the client certificates
+	// required for mutual TLS. See service.Config.
+	TLSCertFile     string `json:"tls_cert_file,omitempty"`
+	TLSKeyFile      string `json:"tls_key_file,omitempty"`
+	TLSClientCAFile string `json:"tls_client_ca_file,omitempty"`
+	TLSAutoCert     bool   `json:"tls_auto_cert,omitempty"`
+
+	// TLS of the TCP connection to the Dart gRPC server.
+	ViewTLS           bool   `json:"view_tls,omitempty"`
+	ViewTLSCAFile     string `json:"view_tls_ca_file,omitempty"`
+	ViewTLSCertFile   string `json:"view_tls_cert_file,omitempty"`
+	ViewTLSKeyFile    string `json:"view_tls_key_file,omitempty"`
+	ViewTLSServerName string `json:"view_tls_server_name,omitempty"`
+
+	// JWT authentication of incoming calls: the verification keys, rotated
+	// by "kid", the required issuer and audience, and the clock skew
+	// allowed. See service.Config.
+	JWTKeys     []JWTKey `json:"jwt_keys,omitempty"`
+	JWTIssuer   string   `json:"jwt_issuer,omitempty"`
+	JWTAudience string   `json:"jwt_audience,omitempty"`
+	JWTLeewayMs int64    `json:"jwt_leeway_ms,omitempty"`
+
+	// PolicyFile is a JSON or YAML authorization policy applied to every
+	// call; the engine does not start if it cannot be loaded. See
+	// service.Policy.
+	PolicyFile string `json:"policy_file,omitempty"`
+
+	// PublicReflection serves gRPC server reflection without the token and
+	// policy checks, for grpcurl without credentials. Health checks are
+	// always served that way.
+	PublicReflection bool `json:"public_reflection,omitempty"`
+
+	// Access to the engine socket: the file mode in octal ("0600"), the
+	// owner ("user:group"), and the uids, gids and pids of the processes
+	// allowed to connect, checked with SO_PEERCRED on Linux. See
+	// service.Config.
+	SocketMode     string   `json:"socket_mode,omitempty"`
+	SocketOwner    string   `json:"socket_owner,omitempty"`
+	SocketPeerUIDs []uint32 `json:"socket_peer_uids,omitempty"`
+	SocketPeerGIDs []uint32 `json:"socket_peer_gids,omitempty"`
+	SocketPeerPIDs []int32  `json:"socket_peer_pids,omitempty"`
+}
+
+// JWTKey is a JWT verification key of EngineConfig: an HMAC secret
+// (HS256/384/512) or an Ed25519 public key (EdDSA), base64 in JSON.
+type JWTKey struct {
+	ID        string `json:"kid,omitempty"`
+	Secret    []byte `json:"secret,omitempty"`
+	PublicKey []byte `json:"public_key,omitempty"`
+}
+
+// ErrLegacyEngine is returned by LoadEngine for engine libraries built before
+// the unified ABI; they can only be driven through StartGrpcServer/InvokeBackend.
+var ErrLegacyEngine = errors.New("engine library does not export the Synurang ABI (rebuild it with this version)")
+
+// LoadEngine loads an engine library and starts it with cfg. Closing the
+// returned Plugin stops the engine (Synurang_Shutdown).
+func LoadEngine(path string, cfg EngineConfig, opts ...PluginOption) (*Plugin, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode engine config: %w", err)
+	}
+
+	// Inspect the exports through a second reference so the library is
+	// never unloaded between the check and LoadPlugin.
+	handle, err := platformOpen(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load engine %s: %w", path, err)
+	}
+	defer platformClose(handle)
+	if err := checkEngineABI(path, handle); err != nil {
+		return nil, err
+	}
+
+	return LoadPlugin(path, append([]PluginOption{WithConfigBytes(data)}, opts...)...)
+}
+
+// checkEngineABI rejects libraries that only export the legacy engine ABI,
+// before Synurang_Init could report a less helpful error.
+func checkEngineABI(path string, handle uintptr) error {
+	for _, sym := range []string{"Synurang_Init", "Synurang_Invoke"} {
+		if ptr, err := platformSym(handle, sym); err != nil || ptr == 0 {
+			if legacy, _ := platformSym(handle, "InvokeBackend"); legacy != 0 {
+				return fmt.Errorf("engine %s: %w", path, ErrLegacyEngine)
+			}
+			return fmt.Errorf("%s is not a Synurang engine (missing %s)", path, sym)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package synurang
+
+import (
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestLoadEngine_PassesConfig(t *testing.T) {
+	mock := newMockPlatform()
+	var got EngineConfig
+	mock.initFunc = func(fn, freePtr uintptr, config []byte) ([]byte, error) {
+		if err := json.Unmarshal(config, &got); err != nil {
+			t.Errorf("config is not JSON: %v", err)
+		}
+		return []byte{0}, nil
+	}
+	restore := mock.install()
+	defer restore()
+
+	engine, err := LoadEngine("libsynurang.so", EngineConfig{CachePath: "/tmp/cache", EnableCache: true, StreamTimeoutMs: 500})
+	if err != nil {
+		t.Fatalf("LoadEngine failed: %v", err)
+	}
+	if got.CachePath != "/tmp/cache" || !got.EnableCache || got.StreamTimeoutMs != 500 {
+		t.Errorf("unexpected engine config %+v", got)
+	}
+
+	// The ABI check's extra reference is released; Close drops the last one.
+	if err := engine.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if opens, closes := atomic.LoadInt64(&mock.openCalls), atomic.LoadInt64(&mock.closeCalls); opens != closes {
+		t.Errorf("expected every open to be closed, got %d opens and %d closes", opens, closes)
+	}
+	if atomic.LoadInt64(&mock.shutdownCalls) != 1 {
+		t.Errorf("expected Close to stop the engine, got %d shutdown calls", mock.shutdownCalls)
+	}
+}
+
+func TestLoadEngine_LegacyLibrary(t *testing.T) {
+	mock := newMockPlatform()
+	mock.symFunc = func(handle uintptr, name string) (uintptr, error) {
+		switch name {
+		case "InvokeBackend", "StartGrpcServer", "FreeFfiData":
+			return 0x2000, nil
+		}
+		return 0, errors.New("not found")
+	}
+	restore := mock.install()
+	defer restore()
+
+	_, err := LoadEngine("libsynurang.so", EngineConfig{})
+	if !errors.Is(err, ErrLegacyEngine) {
+		t.Fatalf("expected ErrLegacyEngine, got %v", err)
+	}
+	if atomic.LoadInt64(&mock.initCalls) != 0 {
+		t.Error("expected no Synurang_Init call")
+	}
+	if opens, closes := atomic.LoadInt64(&mock.openCalls), atomic.LoadInt64(&mock.closeCalls); opens != closes {
+		t.Errorf("expected the library to be closed, got %d opens and %d closes", opens, closes)
+	}
+}
+
+func TestLoadEngine_NotAnEngine(t *testing.T) {
+	mock := newMockPlatform()
+	mock.symFunc = func(handle uintptr, name string) (uintptr, error) {
+		return 0, errors.New("not found")
+	}
+	restore := mock.install()
+	defer restore()
+
+	_, err := LoadEngine("libother.so", EngineConfig{})
+	if err == nil || errors.Is(err, ErrLegacyEngine) {
+		t.Fatalf("expected a missing-symbol error, got %v", err)
+	}
+}
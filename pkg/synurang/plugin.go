@@ -15,18 +15,27 @@
 //	resp, err := plugin.Invoke("MyService", "/pkg.MyService/Method", requestBytes)
 //
 //	// Option 2: Use as grpc.ClientConnInterface (recommended)
-//	conn := synurang.NewPluginClientConn(plugin, "MyService")
+//	conn := synurang.NewPluginClientConn(plugin, "")
 //	client := pb.NewMyServiceClient(conn)
 //	resp, err := client.MyMethod(ctx, req)
+//
+// An empty service name calls the plugin's generic entry points
+// (Synurang_Invoke, Synurang_Stream_Open), which route by full method name.
+// A non-empty name uses the per-service Synurang_Invoke_<ServiceName>
+// symbols of older plugins, falling back to the generic ones.
 
 package synurang
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"math"
 	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
 )
 
 // ErrDataTooLarge is returned when data exceeds the maximum size for C interop.
@@ -51,29 +60,120 @@ type Plugin struct {
 	mu      sync.RWMutex
 	// Cache of service invoke functions: serviceName -> function pointer
 	invokers map[string]uintptr
+	// Cache of per-service batch invoke functions: serviceName -> function pointer.
+	// A zero entry means the plugin predates batching.
+	batchInvokers map[string]uintptr
 	// Cache of per-service stream open functions: serviceName -> function pointer
 	streamOpeners map[string]uintptr
 	// Global stream functions (shared across all services)
 	streamFuncs *globalStreamFuncs
+	// Async invoke functions, resolved on first InvokeAsync
+	asyncFuncs *asyncFuncs
+	// Entry points taking a call header, resolved on the first call with
+	// outgoing metadata
+	headerFuncs *headerFuncs
+	// pendingCalls tracks unfinished InvokeAsync calls by call ID.
+	pendingCalls map[uint64]*Call
 
 	// activeStreams tracks currently open stream handles.
 	// Used to cancel streams when Close() is called.
 	activeStreams map[uintptr]bool
+	// streamQueues holds the push-delivery queue of subscribed streams
+	// (see plugin_stream_callback.go). Absent for polled streams.
+	streamQueues map[uintptr]*streamQueue
+	// pollStreams disables push delivery even if the plugin supports it.
+	pollStreams bool
+	// streamReleases returns each open stream's admission slot on close.
+	streamReleases map[uintptr]func()
+
+	// admission enforces the configured Limits and backs Stats.
+	admission *admission
+
+	// descriptorIssues holds incompatibilities tolerated by DescriptorCheckWarn.
+	descriptorIssues []DescriptorIssue
+
+	// servesHealth records, once CheckHealth ran, whether the plugin's
+	// descriptors declare grpc.health.v1.Health.
+	healthOnce   sync.Once
+	servesHealth bool
 
 	// wg tracks active calls into the plugin (Invoke, Send, Recv, etc).
 	// Close() waits for this waitgroup to ensure no code is executing
 	// in the shared library when it is unloaded.
 	wg sync.WaitGroup
 
+	// shutdownTimeout bounds Synurang_Shutdown when the plugin exports it.
+	shutdownTimeout time.Duration
+
+	// logToken identifies this plugin's log bridge; zero if none is installed.
+	logToken uint64
+	// setLogSinkPtr is Synurang_SetLogSink, used to detach the sink on Close.
+	setLogSinkPtr uintptr
+
+	// onClose holds the functions OnClose registered, run once Close is done.
+	onClose []func()
+
 	closed bool
 }
 
+// DefaultShutdownTimeout is how long Close lets a plugin's shutdown hooks run.
+const DefaultShutdownTimeout = 5 * time.Second
+
+// PluginOption configures LoadPlugin.
+type PluginOption func(*pluginOptions)
+
+type pluginOptions struct {
+	config          []byte
+	configSet       bool
+	configErr       error
+	shutdownTimeout time.Duration
+	logSink         func(LogRecord)
+	pollStreams     bool
+	limits          Limits
+	methodLimits    map[string]Limits
+
+	descriptorPolicy DescriptorPolicy
+}
+
+// WithConfig passes msg, marshaled as protobuf, to the plugin's OnInit hooks.
+func WithConfig(msg proto.Message) PluginOption {
+	return func(o *pluginOptions) {
+		o.config, o.configErr = proto.Marshal(msg)
+		o.configSet = true
+	}
+}
+
+// WithConfigBytes passes raw configuration bytes to the plugin's OnInit hooks.
+func WithConfigBytes(cfg []byte) PluginOption {
+	return func(o *pluginOptions) {
+		o.config = cfg
+		o.configSet = true
+	}
+}
+
+// WithShutdownTimeout sets how long Close waits for the plugin's OnShutdown
+// hooks. Zero or negative means no deadline.
+func WithShutdownTimeout(d time.Duration) PluginOption {
+	return func(o *pluginOptions) {
+		o.shutdownTimeout = d
+	}
+}
+
+// WithPollingStreams makes the plugin's streams use the blocking
+// Synurang_Stream_Recv ABI even if the plugin supports push delivery.
+func WithPollingStreams() PluginOption {
+	return func(o *pluginOptions) {
+		o.pollStreams = true
+	}
+}
+
 // globalStreamFuncs holds global function pointers for streaming operations
 type globalStreamFuncs struct {
 	send      uintptr
 	recv      uintptr
 	closeSend uintptr
 	close     uintptr
+	subscribe uintptr // optional; zero for plugins without push delivery
 }
 
 // Platform abstraction - these are set by platform-specific init() functions.
@@ -84,17 +184,55 @@ var (
 	platformClose  func(handle uintptr) error
 	platformInvoke func(fn, freePtr uintptr, method string, data []byte) ([]byte, error)
 
+	platformInvokeBatch func(fn, freePtr uintptr, data []byte) ([]byte, error)
+
+	// Call header platform functions (see plugin_header.go)
+	platformInvokeWithHeader      func(fn, freePtr uintptr, method string, header, data []byte) ([]byte, error)
+	platformInvokeAsyncWithHeader func(fn uintptr, callID uint64, method string, header, data []byte, callback uintptr) int
+	platformStreamOpenWithHeader  func(fn uintptr, method string, header []byte) uint64
+
+	// Async invoke platform functions
+	platformInvokeAsync      func(fn uintptr, callID uint64, method string, data []byte, callback uintptr) int
+	platformAsyncCallbackPtr func() uintptr
+	platformCancelCall       func(fn uintptr, callID uint64)
+
+	// Lifecycle platform functions
+	platformInit        func(fn, freePtr uintptr, config []byte) ([]byte, error)
+	platformShutdown    func(fn uintptr, timeoutMs int64) int
+	platformDescriptors func(fn, freePtr uintptr) ([]byte, error)
+
+	// Log bridge platform functions
+	platformLogSinkPtr func() uintptr
+	platformSetLogSink func(fn, sink uintptr, token uint64)
+
 	// Streaming platform functions
-	platformStreamOpen      func(fn uintptr, method string) uint64
-	platformStreamSend      func(fn uintptr, handle uint64, data []byte) int
-	platformStreamRecv      func(fn, freePtr uintptr, handle uint64) (data []byte, respLen, status int)
-	platformStreamCloseSend func(fn uintptr, handle uint64)
-	platformStreamClose     func(fn uintptr, handle uint64)
+	platformStreamOpen        func(fn uintptr, method string) uint64
+	platformStreamSubscribe   func(fn uintptr, handle uint64, callback uintptr, token uint64) int
+	platformStreamCallbackPtr func() uintptr
+	platformStreamSend        func(fn uintptr, handle uint64, data []byte) int
+	platformStreamRecv        func(fn, freePtr uintptr, handle uint64) (data []byte, respLen, status int)
+	platformStreamCloseSend   func(fn uintptr, handle uint64)
+	platformStreamClose       func(fn uintptr, handle uint64)
 )
 
 // LoadPlugin loads a shared library plugin from the given path.
 // The plugin must export Synurang_Free and Synurang_Invoke_<ServiceName> symbols.
-func LoadPlugin(path string) (*Plugin, error) {
+//
+// If the plugin exports Synurang_Init it is called with the configuration
+// given by WithConfig or WithConfigBytes (empty otherwise). An init failure
+// unloads the library and is returned as a *PluginError.
+func LoadPlugin(path string, opts ...PluginOption) (*Plugin, error) {
+	o := pluginOptions{shutdownTimeout: DefaultShutdownTimeout}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.configErr != nil {
+		return nil, fmt.Errorf("failed to marshal plugin config: %w", o.configErr)
+	}
+	if len(o.config) > math.MaxInt32 {
+		return nil, ErrDataTooLarge
+	}
+
 	handle, err := platformOpen(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load plugin %s: %w", path, err)
@@ -107,15 +245,80 @@ func LoadPlugin(path string) (*Plugin, error) {
 		return nil, fmt.Errorf("plugin %s missing Synurang_Free symbol", path)
 	}
 
+	// Check descriptors before running any plugin code
+	descriptorIssues, err := checkPluginDescriptors(path, handle, freePtr, o.descriptorPolicy)
+	if err != nil {
+		platformClose(handle)
+		return nil, err
+	}
+
+	// Install the log sink before init so init hooks can log.
+	// Plugins built before the log bridge keep writing to stderr.
+	var logToken uint64
+	var setLogSinkPtr uintptr
+	if o.logSink != nil {
+		setLogSinkPtr, _ = platformSym(handle, "Synurang_SetLogSink")
+		if setLogSinkPtr != 0 {
+			logToken = registerLogBridge(path, o.logSink)
+			platformSetLogSink(setLogSinkPtr, platformLogSinkPtr(), logToken)
+		}
+	}
+	detachLog := func() {
+		if logToken != 0 {
+			platformSetLogSink(setLogSinkPtr, 0, 0)
+			unregisterLogBridge(logToken)
+		}
+	}
+
+	// Lookup Synurang_Init (optional, required if a config was given)
+	initPtr, _ := platformSym(handle, "Synurang_Init")
+	if initPtr == 0 && o.configSet {
+		detachLog()
+		platformClose(handle)
+		return nil, fmt.Errorf("plugin %s does not accept a config (missing Synurang_Init)", path)
+	}
+	if initPtr != 0 {
+		if err := pluginInit(initPtr, freePtr, o.config); err != nil {
+			detachLog()
+			platformClose(handle)
+			return nil, fmt.Errorf("plugin %s init failed: %w", path, err)
+		}
+	}
+
 	return &Plugin{
-		handle:        handle,
-		freePtr:       freePtr,
-		invokers:      make(map[string]uintptr),
-		streamOpeners: make(map[string]uintptr),
-		activeStreams: make(map[uintptr]bool),
+		handle:           handle,
+		freePtr:          freePtr,
+		invokers:         make(map[string]uintptr),
+		batchInvokers:    make(map[string]uintptr),
+		streamOpeners:    make(map[string]uintptr),
+		activeStreams:    make(map[uintptr]bool),
+		streamQueues:     make(map[uintptr]*streamQueue),
+		pendingCalls:     make(map[uint64]*Call),
+		pollStreams:      o.pollStreams,
+		streamReleases:   make(map[uintptr]func()),
+		admission:        newAdmission(o.limits, o.methodLimits),
+		descriptorIssues: descriptorIssues,
+		shutdownTimeout:  o.shutdownTimeout,
+		logToken:         logToken,
+		setLogSinkPtr:    setLogSinkPtr,
 	}, nil
 }
 
+// pluginInit calls Synurang_Init and decodes its [status][payload] response.
+func pluginInit(initPtr, freePtr uintptr, config []byte) error {
+	result, err := platformInit(initPtr, freePtr, config)
+	if err != nil {
+		return err
+	}
+	if len(result) == 0 {
+		return fmt.Errorf("empty response from Synurang_Init")
+	}
+	if result[0] != 0 {
+		return &PluginError{Message: string(result[1:])}
+	}
+	return nil
+}
+
 // Close unloads the plugin.
 // It cancels all active streams and waits for running operations to complete.
 func (p *Plugin) Close() error {
@@ -134,6 +337,23 @@ func (p *Plugin) Close() error {
 	// Clear activeStreams to prevent double-close from concurrent closeInternal()
 	p.activeStreams = make(map[uintptr]bool)
 
+	// Wake any Recv waiting on a push queue; the plugin may never deliver EOF
+	for _, q := range p.streamQueues {
+		unregisterStreamQueue(q)
+		q.close()
+	}
+	p.streamQueues = make(map[uintptr]*streamQueue)
+
+	for _, release := range p.streamReleases {
+		release()
+	}
+	p.streamReleases = make(map[uintptr]func())
+
+	var pending []*Call
+	for _, c := range p.pendingCalls {
+		pending = append(pending, c)
+	}
+
 	// Get stream close function pointer while holding lock
 	var closeFunc uintptr
 	if p.streamFuncs != nil {
@@ -153,20 +373,68 @@ func (p *Plugin) Close() error {
 		}
 	}
 
+	// Fail pending async calls and cancel them inside the plugin; their
+	// callbacks still arrive and are waited for below
+	for _, c := range pending {
+		c.finish(nil, ErrPluginClosed)
+		if c.async {
+			platformCancelCall(c.cancelPtr, c.id)
+		}
+	}
+
 	// Wait for all active calls (Recv, Send, Invoke) to complete
 	// This prevents segfaults by ensuring no thread is executing inside
 	// the shared library when we unload it.
 	p.wg.Wait()
 
+	// Let the plugin release its own resources before it is unmapped.
+	var shutdownErr error
+	if shutdownPtr, _ := platformSym(p.handle, "Synurang_Shutdown"); shutdownPtr != 0 {
+		var timeoutMs int64
+		if p.shutdownTimeout > 0 {
+			timeoutMs = p.shutdownTimeout.Milliseconds()
+		}
+		switch platformShutdown(shutdownPtr, timeoutMs) {
+		case 0:
+		case 2:
+			shutdownErr = fmt.Errorf("plugin shutdown timed out after %v", p.shutdownTimeout)
+		default:
+			shutdownErr = &PluginError{Message: "shutdown hook failed"}
+		}
+	}
+
+	if p.logToken != 0 {
+		platformSetLogSink(p.setLogSinkPtr, 0, 0)
+		unregisterLogBridge(p.logToken)
+	}
+
 	// Now it is safe to unload
 	p.mu.Lock()
-	defer p.mu.Unlock()
-
 	if p.handle != 0 {
 		platformClose(p.handle)
 		p.handle = 0
 	}
-	return nil
+	hooks := p.onClose
+	p.onClose = nil
+	p.mu.Unlock()
+
+	for _, fn := range hooks {
+		fn()
+	}
+	return shutdownErr
+}
+
+// OnClose registers fn to run once the plugin is closed, e.g. to stop
+// routing calls to it. fn runs at once if the plugin is already closed.
+func (p *Plugin) OnClose(fn func()) {
+	p.mu.Lock()
+	if !p.closed {
+		p.onClose = append(p.onClose, fn)
+		p.mu.Unlock()
+		return
+	}
+	p.mu.Unlock()
+	fn()
 }
 
 // getInvoker returns the invoke function pointer for a service, caching it.
@@ -192,8 +460,11 @@ func (p *Plugin) getInvoker(serviceName string) (uintptr, error) {
 	}
 
 	symName := "Synurang_Invoke_" + serviceName
-	ptr, err := platformSym(p.handle, symName)
-	if err != nil || ptr == 0 {
+	ptr := p.lookupEntry(serviceName, symName, "Synurang_Invoke")
+	if ptr == 0 {
+		if serviceName == "" {
+			return 0, fmt.Errorf("plugin does not export Synurang_Invoke")
+		}
 		return 0, fmt.Errorf("service %s not found in plugin (missing %s)", serviceName, symName)
 	}
 
@@ -201,8 +472,26 @@ func (p *Plugin) getInvoker(serviceName string) (uintptr, error) {
 	return ptr, nil
 }
 
+// lookupEntry resolves an entry point: the per-service symbol legacy if
+// serviceName is set and the plugin exports it, otherwise the generic symbol
+// that routes by full method name. Returns 0 if neither exists.
+// Must be called with p.mu held.
+func (p *Plugin) lookupEntry(serviceName, legacy, generic string) uintptr {
+	if serviceName != "" {
+		if ptr, err := platformSym(p.handle, legacy); err == nil && ptr != 0 {
+			return ptr
+		}
+	}
+	ptr, err := platformSym(p.handle, generic)
+	if err != nil {
+		return 0
+	}
+	return ptr
+}
+
 // invokeInternal performs the actual FFI call and returns raw bytes.
-func (p *Plugin) invokeInternal(serviceName, method string, data []byte) ([]byte, error) {
+// header is the serialized call header, or nil.
+func (p *Plugin) invokeInternal(serviceName, method string, header, data []byte) ([]byte, error) {
 	p.mu.RLock()
 	if p.closed {
 		p.mu.RUnlock()
@@ -212,15 +501,23 @@ func (p *Plugin) invokeInternal(serviceName, method string, data []byte) ([]byte
 	p.mu.RUnlock()
 	defer p.wg.Done()
 
-	invokePtr, err := p.getInvoker(serviceName)
-	if err != nil {
-		return nil, err
-	}
-
 	if len(data) > math.MaxInt32 {
 		return nil, ErrDataTooLarge
 	}
+	if header != nil {
+		funcs, err := p.getHeaderFuncs()
+		if err != nil {
+			return nil, err
+		}
+		if funcs.invoke != 0 {
+			return platformInvokeWithHeader(funcs.invoke, p.freePtr, method, header, data)
+		}
+	}
 
+	invokePtr, err := p.getInvoker(serviceName)
+	if err != nil {
+		return nil, err
+	}
 	return platformInvoke(invokePtr, p.freePtr, method, data)
 }
 
@@ -228,14 +525,37 @@ func (p *Plugin) invokeInternal(serviceName, method string, data []byte) ([]byte
 // Returns the response bytes or an error.
 //
 // The method should be the full gRPC method name, e.g., "/pkg.ServiceName/MethodName".
+// serviceName may be empty to route by method alone (see the package example).
 // Response format from plugin: [status:1byte][payload...]
 //   - status=0: success, payload is protobuf response
 //   - status=1: error, payload is error message string
 func (p *Plugin) Invoke(serviceName, method string, data []byte) ([]byte, error) {
-	result, err := p.invokeInternal(serviceName, method, data)
+	return p.InvokeContext(context.Background(), serviceName, method, data)
+}
+
+// InvokeContext is Invoke with a context that bounds waiting for an
+// admission slot (see WithLimits) and whose outgoing metadata is passed to
+// the plugin (see plugin_header.go). The FFI call itself is not cancellable.
+func (p *Plugin) InvokeContext(ctx context.Context, serviceName, method string, data []byte) ([]byte, error) {
+	release, err := p.admission.admitCall(ctx, method)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return p.invokeAdmitted(serviceName, method, callHeader(ctx), data)
+}
+
+// invokeAdmitted performs a unary call that already holds an admission slot.
+func (p *Plugin) invokeAdmitted(serviceName, method string, header, data []byte) ([]byte, error) {
+	result, err := p.invokeInternal(serviceName, method, header, data)
 	if err != nil {
 		return nil, err
 	}
+	return decodeInvokeResult(method, result)
+}
+
+// decodeInvokeResult interprets a [status][payload] unary response.
+func decodeInvokeResult(method string, result []byte) ([]byte, error) {
 	if len(result) == 0 {
 		return nil, fmt.Errorf("empty response from plugin for %s", method)
 	}
@@ -260,6 +580,7 @@ func (p *Plugin) ensureStreamFuncs() error {
 	recvPtr, _ := platformSym(p.handle, "Synurang_Stream_Recv")
 	closeSendPtr, _ := platformSym(p.handle, "Synurang_Stream_CloseSend")
 	closePtr, _ := platformSym(p.handle, "Synurang_Stream_Close")
+	subscribePtr, _ := platformSym(p.handle, "Synurang_Stream_Subscribe")
 
 	if sendPtr == 0 || recvPtr == 0 || closeSendPtr == 0 || closePtr == 0 {
 		return fmt.Errorf("incomplete streaming support in plugin")
@@ -270,6 +591,7 @@ func (p *Plugin) ensureStreamFuncs() error {
 		recv:      recvPtr,
 		closeSend: closeSendPtr,
 		close:     closePtr,
+		subscribe: subscribePtr,
 	}
 	return nil
 }
@@ -299,8 +621,11 @@ func (p *Plugin) getStreamOpener(serviceName string) (uintptr, error) {
 	}
 
 	symName := "Synurang_Stream_" + serviceName + "_Open"
-	openPtr, err := platformSym(p.handle, symName)
-	if err != nil || openPtr == 0 {
+	openPtr := p.lookupEntry(serviceName, symName, "Synurang_Stream_Open")
+	if openPtr == 0 {
+		if serviceName == "" {
+			return 0, fmt.Errorf("streaming not supported by plugin (missing Synurang_Stream_Open)")
+		}
 		return 0, fmt.Errorf("streaming not supported for service %s (missing %s)", serviceName, symName)
 	}
 
@@ -310,9 +635,30 @@ func (p *Plugin) getStreamOpener(serviceName string) (uintptr, error) {
 
 // OpenStream opens a streaming RPC to the plugin.
 func (p *Plugin) OpenStream(serviceName, method string) (*PluginStream, error) {
+	return p.OpenStreamContext(context.Background(), serviceName, method)
+}
+
+// OpenStreamContext is OpenStream with a context that bounds waiting for an
+// admission slot (see WithLimits) and whose outgoing metadata is passed to
+// the plugin (see plugin_header.go). The slot is held until the stream is
+// closed.
+func (p *Plugin) OpenStreamContext(ctx context.Context, serviceName, method string) (*PluginStream, error) {
+	p.mu.RLock()
+	if p.closed {
+		p.mu.RUnlock()
+		return nil, ErrPluginClosed
+	}
+	p.mu.RUnlock()
+
+	release, err := p.admission.admitStream(ctx, method)
+	if err != nil {
+		return nil, err
+	}
+
 	p.mu.RLock()
 	if p.closed {
 		p.mu.RUnlock()
+		release()
 		return nil, ErrPluginClosed
 	}
 	p.wg.Add(1)
@@ -321,11 +667,22 @@ func (p *Plugin) OpenStream(serviceName, method string) (*PluginStream, error) {
 
 	openPtr, err := p.getStreamOpener(serviceName)
 	if err != nil {
+		release()
 		return nil, err
 	}
 
-	handle := platformStreamOpen(openPtr, method)
+	var handle uint64
+	if header := callHeader(ctx); header != nil {
+		if funcs, err := p.getHeaderFuncs(); err == nil && funcs.streamOpen != 0 {
+			handle = platformStreamOpenWithHeader(funcs.streamOpen, method, header)
+		} else {
+			handle = platformStreamOpen(openPtr, method)
+		}
+	} else {
+		handle = platformStreamOpen(openPtr, method)
+	}
 	if handle == 0 {
+		release()
 		return nil, fmt.Errorf("failed to open stream for %s", method)
 	}
 
@@ -338,17 +695,49 @@ func (p *Plugin) OpenStream(serviceName, method string) (*PluginStream, error) {
 		p.wg.Add(1)
 		platformStreamClose(p.streamFuncs.close, handle)
 		p.wg.Done()
+		release()
 		return nil, ErrPluginClosed
 	}
 	p.activeStreams[uintptr(handle)] = true
+	p.streamReleases[uintptr(handle)] = release
 	p.mu.Unlock()
 
+	if p.streamFuncs.subscribe != 0 && !p.pollStreams {
+		p.subscribeStream(uintptr(handle))
+	}
+
 	return &PluginStream{
 		plugin: p,
 		handle: uintptr(handle),
 	}, nil
 }
 
+// subscribeStream switches handle to push delivery. If the plugin refuses,
+// the stream stays on the polling ABI.
+func (p *Plugin) subscribeStream(handle uintptr) {
+	q := newStreamQueue()
+	token := registerStreamQueue(q)
+
+	// Register before subscribing so Recv finds the queue as soon as events can arrive
+	p.mu.Lock()
+	if p.closed || !p.activeStreams[handle] {
+		p.mu.Unlock()
+		unregisterStreamQueue(q)
+		return
+	}
+	p.streamQueues[handle] = q
+	p.wg.Add(1)
+	p.mu.Unlock()
+	defer p.wg.Done()
+
+	if platformStreamSubscribe(p.streamFuncs.subscribe, uint64(handle), platformStreamCallbackPtr(), token) != 0 {
+		p.mu.Lock()
+		delete(p.streamQueues, handle)
+		p.mu.Unlock()
+		unregisterStreamQueue(q)
+	}
+}
+
 // acquireForStreamOp prepares for a stream operation.
 func (p *Plugin) acquireForStreamOp() error {
 	p.mu.RLock()
@@ -386,13 +775,41 @@ func (p *Plugin) StreamSend(handle uintptr, data []byte) error {
 // StreamRecv receives data from a stream.
 // Returns io.EOF when stream is complete.
 func (p *Plugin) StreamRecv(handle uintptr) ([]byte, error) {
+	p.mu.RLock()
+	q := p.streamQueues[handle]
+	p.mu.RUnlock()
+	if q != nil {
+		return p.streamRecvPushed(q)
+	}
+
 	if err := p.acquireForStreamOp(); err != nil {
 		return nil, err
 	}
 	defer p.wg.Done()
 
 	data, respLen, status := platformStreamRecv(p.streamFuncs.recv, p.freePtr, uint64(handle))
+	return decodeStreamRecv(data, respLen, status)
+}
+
+// streamRecvPushed waits for the next pushed event. No plugin code runs here,
+// so it is not tracked by p.wg and holds no OS thread.
+func (p *Plugin) streamRecvPushed(q *streamQueue) ([]byte, error) {
+	p.mu.RLock()
+	closed := p.closed
+	p.mu.RUnlock()
+	if closed {
+		return nil, ErrPluginClosed
+	}
+
+	ev, ok := q.pop()
+	if !ok {
+		return nil, io.EOF
+	}
+	return decodeStreamRecv(ev.data, ev.respLen, ev.status)
+}
 
+// decodeStreamRecv interprets a Synurang_Stream_Recv result.
+func decodeStreamRecv(data []byte, respLen, status int) ([]byte, error) {
 	switch status {
 	case 0: // data
 		if len(data) == 0 {
@@ -423,6 +840,20 @@ func (p *Plugin) StreamCloseSend(handle uintptr) error {
 	return nil
 }
 
+// ForceStreamClose calls the plugin's Synurang_Stream_Close for handle without
+// consulting host-side stream tracking, so it may be called repeatedly or with
+// handles the host never opened. It exists for conformance tooling
+// (see pkg/synurangtest); applications should use PluginStream.Close.
+func (p *Plugin) ForceStreamClose(handle uintptr) error {
+	if err := p.acquireForStreamOp(); err != nil {
+		return err
+	}
+	defer p.wg.Done()
+
+	platformStreamClose(p.streamFuncs.close, uint64(handle))
+	return nil
+}
+
 // StreamClose closes a stream completely.
 func (p *Plugin) StreamClose(handle uintptr) {
 	p.mu.Lock()
@@ -435,6 +866,15 @@ func (p *Plugin) StreamClose(handle uintptr) {
 		return
 	}
 	delete(p.activeStreams, handle)
+	if release := p.streamReleases[handle]; release != nil {
+		delete(p.streamReleases, handle)
+		release()
+	}
+	if q := p.streamQueues[handle]; q != nil {
+		delete(p.streamQueues, handle)
+		unregisterStreamQueue(q)
+		q.close()
+	}
 	sf := p.streamFuncs
 	if sf != nil {
 		p.wg.Add(1)
@@ -0,0 +1,264 @@
+// Asynchronous unary calls.
+//
+// Plugins that export Synurang_InvokeAsync run each call on their own
+// goroutine and post the result to a host callback, so a pending call holds no
+// host thread and can be cancelled inside the plugin (Synurang_CancelCall).
+// For older plugins InvokeAsync runs the synchronous ABI on a goroutine; a
+// cancelled call then returns immediately but keeps running in the plugin.
+//
+// Usage:
+//
+//	call := plugin.InvokeAsync(ctx, "", "/pkg.MyService/Method", requestBytes)
+//	select {
+//	case <-call.Done():
+//	    resp, err := call.Result()
+//	case <-time.After(time.Second):
+//	    call.Cancel()
+//	}
+
+package synurang
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+)
+
+// Call is a unary call started with Plugin.InvokeAsync.
+type Call struct {
+	id     uint64
+	method string
+	plugin *Plugin
+
+	once sync.Once
+	done chan struct{}
+	resp []byte
+	err  error
+
+	// async is set for calls running on the plugin's async ABI; they hold
+	// p.wg until the plugin's callback arrives.
+	async     bool
+	cancelPtr uintptr
+	release   func()      // admission slot
+	stopCtx   func() bool // unregisters the context watcher
+}
+
+// ID returns the call ID, unique within the process. It is zero for calls
+// that failed before reaching the plugin.
+func (c *Call) ID() uint64 { return c.id }
+
+// Method returns the full method name of the call.
+func (c *Call) Method() string { return c.method }
+
+// Done is closed once the call's result is available.
+func (c *Call) Done() <-chan struct{} { return c.done }
+
+// Result waits for the call to finish and returns the response bytes or an
+// error. A cancelled call returns the context's error (context.Canceled for
+// Cancel).
+func (c *Call) Result() ([]byte, error) {
+	<-c.done
+	return c.resp, c.err
+}
+
+// Cancel abandons the call: Result returns context.Canceled immediately and
+// the plugin is asked to cancel the handler's context. It is a no-op once the
+// call has finished.
+func (c *Call) Cancel() {
+	c.cancel(context.Canceled)
+}
+
+func (c *Call) cancel(err error) {
+	if c.finish(nil, err) {
+		c.plugin.cancelCall(c)
+	}
+}
+
+// finish records the result. Only the first result counts; it reports
+// whether this was it.
+func (c *Call) finish(resp []byte, err error) bool {
+	first := false
+	c.once.Do(func() {
+		c.resp, c.err = resp, err
+		close(c.done)
+		first = true
+	})
+	return first
+}
+
+// asyncFuncs holds the async ABI entry points; zero values mean the plugin
+// predates Synurang_InvokeAsync.
+type asyncFuncs struct {
+	invoke uintptr
+	cancel uintptr
+
+	// invokeHeader is Synurang_InvokeAsyncWithHeader, if exported.
+	invokeHeader uintptr
+}
+
+var (
+	asyncCallCounter uint64
+	asyncCalls       sync.Map // call ID -> *Call awaiting the plugin's callback
+)
+
+// InvokeAsync starts a unary call and returns without waiting for it. The
+// call is cancelled when ctx is done or by Call.Cancel / Plugin.CancelCall.
+// With WithLimits configured, InvokeAsync first waits for an admission slot.
+// The outgoing metadata of ctx is passed to the plugin (see plugin_header.go).
+// serviceName is only used by plugins without the async ABI (see Invoke).
+func (p *Plugin) InvokeAsync(ctx context.Context, serviceName, method string, data []byte) *Call {
+	c := &Call{method: method, plugin: p, done: make(chan struct{})}
+	if err := ctx.Err(); err != nil {
+		c.finish(nil, err)
+		return c
+	}
+	if len(data) > math.MaxInt32 {
+		c.finish(nil, ErrDataTooLarge)
+		return c
+	}
+
+	funcs, err := p.getAsyncFuncs()
+	if err != nil {
+		c.finish(nil, err)
+		return c
+	}
+	header := callHeader(ctx)
+
+	release, err := p.admission.admitCall(ctx, method)
+	if err != nil {
+		c.finish(nil, err)
+		return c
+	}
+	c.release = release
+
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		release()
+		c.finish(nil, ErrPluginClosed)
+		return c
+	}
+	c.id = atomic.AddUint64(&asyncCallCounter, 1)
+	c.async = funcs.invoke != 0
+	c.cancelPtr = funcs.cancel
+	p.pendingCalls[c.id] = c
+	if c.async {
+		p.wg.Add(1)
+	}
+	p.mu.Unlock()
+
+	c.stopCtx = context.AfterFunc(ctx, func() { c.cancel(ctx.Err()) })
+
+	if !c.async {
+		go func() {
+			c.finish(p.invokeAdmitted(serviceName, method, header, data))
+			p.callDone(c)
+		}()
+		return c
+	}
+
+	asyncCalls.Store(c.id, c)
+	var rc int
+	if header != nil && funcs.invokeHeader != 0 {
+		rc = platformInvokeAsyncWithHeader(funcs.invokeHeader, c.id, method, header, data, platformAsyncCallbackPtr())
+	} else {
+		rc = platformInvokeAsync(funcs.invoke, c.id, method, data, platformAsyncCallbackPtr())
+	}
+	if rc != 0 {
+		asyncCalls.Delete(c.id)
+		c.finish(nil, fmt.Errorf("plugin refused async call for %s", method))
+		p.callDone(c)
+		return c
+	}
+
+	// A cancel that raced with the start reached the plugin too early; repeat it
+	select {
+	case <-c.done:
+		p.cancelCall(c)
+	default:
+	}
+	return c
+}
+
+// CancelCall cancels a pending call by ID. It returns false if no call with
+// that ID is pending on this plugin.
+func (p *Plugin) CancelCall(id uint64) bool {
+	p.mu.RLock()
+	c := p.pendingCalls[id]
+	p.mu.RUnlock()
+	if c == nil {
+		return false
+	}
+	c.Cancel()
+	return true
+}
+
+// getAsyncFuncs resolves the async ABI once.
+func (p *Plugin) getAsyncFuncs() (asyncFuncs, error) {
+	p.mu.RLock()
+	if f := p.asyncFuncs; f != nil {
+		p.mu.RUnlock()
+		return *f, nil
+	}
+	p.mu.RUnlock()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed || p.handle == 0 {
+		return asyncFuncs{}, ErrPluginClosed
+	}
+	if p.asyncFuncs == nil {
+		f := &asyncFuncs{}
+		invokePtr, _ := platformSym(p.handle, "Synurang_InvokeAsync")
+		cancelPtr, _ := platformSym(p.handle, "Synurang_CancelCall")
+		if invokePtr != 0 && cancelPtr != 0 {
+			f.invoke, f.cancel = invokePtr, cancelPtr
+			f.invokeHeader, _ = platformSym(p.handle, "Synurang_InvokeAsyncWithHeader")
+		}
+		p.asyncFuncs = f
+	}
+	return *p.asyncFuncs, nil
+}
+
+// cancelCall asks the plugin to cancel c's handler context.
+func (p *Plugin) cancelCall(c *Call) {
+	if !c.async {
+		return
+	}
+	p.mu.RLock()
+	if p.closed {
+		p.mu.RUnlock()
+		return // Close cancels pending calls itself
+	}
+	p.wg.Add(1)
+	p.mu.RUnlock()
+	defer p.wg.Done()
+
+	platformCancelCall(c.cancelPtr, c.id)
+}
+
+// callDone releases everything a finished call held.
+func (p *Plugin) callDone(c *Call) {
+	p.mu.Lock()
+	delete(p.pendingCalls, c.id)
+	p.mu.Unlock()
+	c.stopCtx()
+	c.release()
+	if c.async {
+		p.wg.Done()
+	}
+}
+
+// dispatchAsyncResult is called by the platform async callback.
+// Results for unknown call IDs are dropped.
+func dispatchAsyncResult(callID uint64, data []byte) {
+	val, ok := asyncCalls.LoadAndDelete(callID)
+	if !ok {
+		return
+	}
+	c := val.(*Call)
+	c.finish(decodeInvokeResult(c.method, data))
+	c.plugin.callDone(c)
+}
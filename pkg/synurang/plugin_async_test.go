@@ -0,0 +1,191 @@
+package synurang
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// cancellableAsyncMock makes async calls pend until the host cancels them,
+// then post an error result like a handler observing ctx.Done().
+func cancellableAsyncMock(mock *mockPlatform) {
+	mock.invokeAsyncFunc = func(fn uintptr, callID uint64, method string, data []byte, callback uintptr) int {
+		return 0
+	}
+	mock.cancelCallFunc = func(fn uintptr, callID uint64) {
+		go dispatchAsyncResult(callID, append([]byte{1}, "context canceled"...))
+	}
+}
+
+func TestInvokeAsync_Result(t *testing.T) {
+	mock := newMockPlatform()
+	restore := mock.install()
+	defer restore()
+
+	plugin, err := LoadPlugin("test.so")
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+	defer plugin.Close()
+
+	call := plugin.InvokeAsync(context.Background(), "", "/pkg.Svc/Method", []byte("req"))
+	if call.ID() == 0 {
+		t.Error("expected a call ID")
+	}
+	resp, err := call.Result()
+	if err != nil || string(resp) != "response" {
+		t.Fatalf("unexpected result %q, %v", resp, err)
+	}
+	if atomic.LoadInt64(&mock.asyncCalls) != 1 {
+		t.Errorf("expected the async ABI to be used, got %d async calls", mock.asyncCalls)
+	}
+	if plugin.CancelCall(call.ID()) {
+		t.Error("expected a finished call to be forgotten")
+	}
+}
+
+func TestInvokeAsync_Cancel(t *testing.T) {
+	mock := newMockPlatform()
+	cancellableAsyncMock(mock)
+	restore := mock.install()
+	defer restore()
+
+	plugin, err := LoadPlugin("test.so", WithLimits(Limits{MaxConcurrentCalls: 1}))
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+	defer plugin.Close()
+
+	call := plugin.InvokeAsync(context.Background(), "", "/pkg.Svc/Method", nil)
+	select {
+	case <-call.Done():
+		t.Fatal("expected the call to pend")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	if !plugin.CancelCall(call.ID()) {
+		t.Fatal("expected CancelCall to find the pending call")
+	}
+	if _, err := call.Result(); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if atomic.LoadInt64(&mock.cancelCalls) != 1 {
+		t.Errorf("expected Synurang_CancelCall once, got %d", mock.cancelCalls)
+	}
+
+	// The admission slot returns once the plugin reports the cancelled call
+	deadline := time.Now().Add(time.Second)
+	for plugin.Stats().ActiveCalls != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("admission slot not released after the plugin's callback")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestInvokeAsync_ContextDeadline(t *testing.T) {
+	mock := newMockPlatform()
+	cancellableAsyncMock(mock)
+	restore := mock.install()
+	defer restore()
+
+	plugin, err := LoadPlugin("test.so")
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+	defer plugin.Close()
+
+	conn := NewPluginClientConn(plugin, "")
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err = conn.Invoke(ctx, "/pkg.Svc/Method", &emptypb.Empty{}, &emptypb.Empty{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected DeadlineExceeded, got %v", err)
+	}
+	if atomic.LoadInt64(&mock.cancelCalls) != 1 {
+		t.Errorf("expected the deadline to cancel the call in the plugin, got %d cancels", mock.cancelCalls)
+	}
+}
+
+func TestInvokeAsync_LegacyPluginFallback(t *testing.T) {
+	mock := newMockPlatform()
+	mock.symFunc = func(handle uintptr, name string) (uintptr, error) {
+		if name == "Synurang_InvokeAsync" || name == "Synurang_CancelCall" {
+			return 0, errors.New("not found")
+		}
+		return 0x2000, nil
+	}
+	restore := mock.install()
+	defer restore()
+
+	plugin, err := LoadPlugin("test.so")
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+	defer plugin.Close()
+
+	resp, err := plugin.InvokeAsync(context.Background(), "Svc", "/pkg.Svc/Method", nil).Result()
+	if err != nil || string(resp) != "response" {
+		t.Fatalf("unexpected result %q, %v", resp, err)
+	}
+	if atomic.LoadInt64(&mock.asyncCalls) != 0 || atomic.LoadInt64(&mock.invokeCalls) != 1 {
+		t.Errorf("expected one synchronous invoke, got %d async and %d total", mock.asyncCalls, mock.invokeCalls)
+	}
+}
+
+func TestInvokeAsync_Refused(t *testing.T) {
+	mock := newMockPlatform()
+	mock.invokeAsyncFunc = func(fn uintptr, callID uint64, method string, data []byte, callback uintptr) int {
+		return 1
+	}
+	restore := mock.install()
+	defer restore()
+
+	plugin, err := LoadPlugin("test.so")
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+
+	if _, err := plugin.InvokeAsync(context.Background(), "", "/pkg.Svc/Method", nil).Result(); err == nil {
+		t.Error("expected an error for a refused call")
+	}
+	// A refused call holds nothing, so Close must not wait for it
+	done := make(chan error, 1)
+	go func() { done <- plugin.Close() }()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Close blocked on a refused call")
+	}
+}
+
+func TestInvokeAsync_CloseFailsPendingCalls(t *testing.T) {
+	mock := newMockPlatform()
+	cancellableAsyncMock(mock)
+	restore := mock.install()
+	defer restore()
+
+	plugin, err := LoadPlugin("test.so")
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+
+	call := plugin.InvokeAsync(context.Background(), "", "/pkg.Svc/Method", nil)
+	if err := plugin.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, err := call.Result(); !errors.Is(err, ErrPluginClosed) {
+		t.Errorf("expected ErrPluginClosed, got %v", err)
+	}
+	if atomic.LoadInt64(&mock.cancelCalls) != 1 {
+		t.Errorf("expected Close to cancel the call in the plugin, got %d cancels", mock.cancelCalls)
+	}
+
+	if _, err := plugin.InvokeAsync(context.Background(), "", "/pkg.Svc/Method", nil).Result(); !errors.Is(err, ErrPluginClosed) {
+		t.Errorf("expected ErrPluginClosed after Close, got %v", err)
+	}
+}
@@ -0,0 +1,26 @@
+//go:build !windows
+
+package synurang
+
+/*
+extern void synurangHostAsyncCallback(unsigned long long callId, char* data, int dataLen);
+*/
+import "C"
+
+import "unsafe"
+
+// synurangHostAsyncCallback is the C callback handed to plugins via
+// Synurang_InvokeAsync. data is only valid during the call.
+//
+//export synurangHostAsyncCallback
+func synurangHostAsyncCallback(callID C.ulonglong, data *C.char, dataLen C.int) {
+	var d []byte
+	if data != nil && dataLen > 0 {
+		d = C.GoBytes(unsafe.Pointer(data), dataLen)
+	}
+	dispatchAsyncResult(uint64(callID), d)
+}
+
+func unixAsyncCallbackPtr() uintptr {
+	return uintptr(unsafe.Pointer(C.synurangHostAsyncCallback))
+}
@@ -0,0 +1,330 @@
+// Batched unary invocation for plugin shared libraries.
+//
+// A batch sends N method/payload pairs to the plugin in a single FFI crossing
+// via Synurang_InvokeBatch_<ServiceName>. Plugins built before batching was
+// introduced are still supported: InvokeBatch falls back to one Invoke per call.
+//
+// Usage:
+//
+//	results, err := plugin.InvokeBatch("MyService", []synurang.BatchCall{
+//	    {Method: "/pkg.MyService/Get", Data: req1},
+//	    {Method: "/pkg.MyService/Get", Data: req2},
+//	})
+//
+//	// Or coalesce concurrent unary calls transparently
+//	conn := synurang.NewPluginClientConn(plugin, "MyService",
+//	    synurang.WithBatching(time.Millisecond, 64))
+
+package synurang
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// BatchCall is a single unary call within a batch.
+type BatchCall struct {
+	Method string
+	Data   []byte
+}
+
+// BatchResult is the outcome of a single call within a batch.
+// Exactly one of Data or Err is meaningful.
+type BatchResult struct {
+	Data []byte
+	Err  error
+}
+
+// getBatchInvoker returns the batch invoke function pointer for a service,
+// or 0 if the plugin does not export one. The result is cached either way.
+func (p *Plugin) getBatchInvoker(serviceName string) (uintptr, error) {
+	p.mu.RLock()
+	if ptr, ok := p.batchInvokers[serviceName]; ok {
+		p.mu.RUnlock()
+		return ptr, nil
+	}
+	p.mu.RUnlock()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed || p.handle == 0 {
+		return 0, ErrPluginClosed
+	}
+	if ptr, ok := p.batchInvokers[serviceName]; ok {
+		return ptr, nil
+	}
+
+	ptr := p.lookupEntry(serviceName, "Synurang_InvokeBatch_"+serviceName, "Synurang_InvokeBatch")
+	p.batchInvokers[serviceName] = ptr
+	return ptr, nil
+}
+
+// InvokeBatch calls several unary methods on a service in one FFI crossing.
+// The returned slice has one result per call, in order. A non-nil error is
+// returned only when the batch as a whole could not be executed.
+func (p *Plugin) InvokeBatch(serviceName string, calls []BatchCall) ([]BatchResult, error) {
+	if len(calls) == 0 {
+		return nil, nil
+	}
+
+	// Each call needs its own admission slot. A batch never waits in the
+	// queue (it could end up waiting on its own slots); calls that find no
+	// free slot fail individually and are left out of the crossing.
+	results := make([]BatchResult, len(calls))
+	admitted := make([]BatchCall, 0, len(calls))
+	index := make([]int, 0, len(calls))
+	for i, call := range calls {
+		release, err := p.admission.tryAdmitCall(call.Method)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		defer release()
+		admitted = append(admitted, call)
+		index = append(index, i)
+	}
+	if len(admitted) == 0 {
+		return results, nil
+	}
+
+	sub, err := p.invokeBatchAdmitted(serviceName, admitted)
+	if err != nil {
+		return nil, err
+	}
+	for j, i := range index {
+		results[i] = sub[j]
+	}
+	return results, nil
+}
+
+// invokeBatchAdmitted runs calls that already hold admission slots.
+func (p *Plugin) invokeBatchAdmitted(serviceName string, calls []BatchCall) ([]BatchResult, error) {
+	p.mu.RLock()
+	if p.closed {
+		p.mu.RUnlock()
+		return nil, ErrPluginClosed
+	}
+	p.wg.Add(1)
+	p.mu.RUnlock()
+	defer p.wg.Done()
+
+	batchPtr, err := p.getBatchInvoker(serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Legacy plugin: no batch export, fall back to individual calls.
+	if batchPtr == 0 {
+		results := make([]BatchResult, len(calls))
+		for i, call := range calls {
+			results[i].Data, results[i].Err = p.invokeAdmitted(serviceName, call.Method, nil, call.Data)
+		}
+		return results, nil
+	}
+	return p.invokeBatchCrossing(batchPtr, serviceName, calls)
+}
+
+// invokeBatchCrossing sends calls to Synurang_InvokeBatch_<ServiceName> in one FFI call.
+func (p *Plugin) invokeBatchCrossing(batchPtr uintptr, serviceName string, calls []BatchCall) ([]BatchResult, error) {
+	req, err := encodeBatch(calls)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := platformInvokeBatch(batchPtr, p.freePtr, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("empty batch response from plugin for %s", serviceName)
+	}
+	if result[0] == 1 {
+		return nil, &PluginError{Message: string(result[1:])}
+	}
+
+	results, err := decodeBatchResults(result[1:])
+	if err != nil {
+		return nil, err
+	}
+	if len(results) != len(calls) {
+		return nil, fmt.Errorf("batch response has %d results, expected %d", len(results), len(calls))
+	}
+	return results, nil
+}
+
+// encodeBatch serializes calls using the layout documented in pkg/plugin/batch.go.
+func encodeBatch(calls []BatchCall) ([]byte, error) {
+	size := 4
+	for _, call := range calls {
+		size += 8 + len(call.Method) + len(call.Data)
+	}
+	if size > math.MaxInt32 {
+		return nil, ErrDataTooLarge
+	}
+
+	buf := make([]byte, 0, size)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(calls)))
+	for _, call := range calls {
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(call.Method)))
+		buf = append(buf, call.Method...)
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(call.Data)))
+		buf = append(buf, call.Data...)
+	}
+	return buf, nil
+}
+
+// decodeBatchResults parses the per-call results returned by the plugin.
+func decodeBatchResults(data []byte) ([]BatchResult, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("malformed batch response")
+	}
+	count := binary.LittleEndian.Uint32(data)
+	data = data[4:]
+	// Each result needs at least 5 bytes (status + length).
+	if uint64(count)*5 > uint64(len(data)) {
+		return nil, fmt.Errorf("malformed batch response")
+	}
+
+	results := make([]BatchResult, count)
+	for i := range results {
+		if len(data) < 5 {
+			return nil, fmt.Errorf("malformed batch response")
+		}
+		status := data[0]
+		n := binary.LittleEndian.Uint32(data[1:5])
+		data = data[5:]
+		if uint64(n) > uint64(len(data)) {
+			return nil, fmt.Errorf("malformed batch response")
+		}
+		payload := data[:n:n]
+		data = data[n:]
+
+		if status == 1 {
+			results[i].Err = &PluginError{Message: string(payload)}
+		} else {
+			results[i].Data = payload
+		}
+	}
+	return results, nil
+}
+
+// =============================================================================
+// Auto-batching for PluginClientConn
+// =============================================================================
+
+// batchRequest is a unary call waiting to be flushed in a batch.
+type batchRequest struct {
+	call    BatchCall
+	done    chan BatchResult // buffered, capacity 1
+	release func()           // returns the call's admission slot
+}
+
+// batcher coalesces concurrent unary calls issued within a short window
+// into a single InvokeBatch crossing.
+type batcher struct {
+	plugin      *Plugin
+	serviceName string
+	window      time.Duration
+	maxSize     int
+
+	mu      sync.Mutex
+	pending []*batchRequest
+	timer   *time.Timer
+}
+
+func newBatcher(plugin *Plugin, serviceName string, window time.Duration, maxSize int) *batcher {
+	if maxSize <= 0 {
+		maxSize = 64
+	}
+	return &batcher{
+		plugin:      plugin,
+		serviceName: serviceName,
+		window:      window,
+		maxSize:     maxSize,
+	}
+}
+
+// invoke queues a call and waits for its result or ctx cancellation.
+// On cancellation the call may still execute as part of its batch;
+// the result is discarded. The admission slot is held until the batch runs.
+func (b *batcher) invoke(ctx context.Context, method string, data []byte) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	release, err := b.plugin.admission.admitCall(ctx, method)
+	if err != nil {
+		return nil, err
+	}
+	req := &batchRequest{
+		call:    BatchCall{Method: method, Data: data},
+		done:    make(chan BatchResult, 1),
+		release: release,
+	}
+
+	b.mu.Lock()
+	b.pending = append(b.pending, req)
+	var flush []*batchRequest
+	if len(b.pending) >= b.maxSize {
+		flush = b.takeLocked()
+	} else if b.timer == nil {
+		b.timer = time.AfterFunc(b.window, b.flushPending)
+	}
+	b.mu.Unlock()
+
+	if flush != nil {
+		go b.run(flush)
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-req.done:
+		return r.Data, r.Err
+	}
+}
+
+// takeLocked detaches the pending batch and stops the window timer.
+// Must be called with b.mu held.
+func (b *batcher) takeLocked() []*batchRequest {
+	reqs := b.pending
+	b.pending = nil
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	return reqs
+}
+
+func (b *batcher) flushPending() {
+	b.mu.Lock()
+	reqs := b.takeLocked()
+	b.mu.Unlock()
+
+	if len(reqs) > 0 {
+		b.run(reqs)
+	}
+}
+
+// run executes a batch and delivers each result to its waiter.
+func (b *batcher) run(reqs []*batchRequest) {
+	calls := make([]BatchCall, len(reqs))
+	for i, r := range reqs {
+		calls[i] = r.call
+	}
+
+	results, err := b.plugin.invokeBatchAdmitted(b.serviceName, calls)
+	for i, r := range reqs {
+		r.release()
+		if err != nil {
+			r.done <- BatchResult{Err: err}
+			continue
+		}
+		r.done <- results[i]
+	}
+}
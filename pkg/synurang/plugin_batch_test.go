@@ -0,0 +1,250 @@
+package synurang
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// mockBatchResponse decodes a batch request the way the plugin runtime does
+// and builds a [status][results...] response using handle for each call.
+func mockBatchResponse(req []byte, handle func(method string, data []byte) (byte, []byte)) []byte {
+	count := binary.LittleEndian.Uint32(req)
+	req = req[4:]
+
+	out := []byte{0}
+	out = binary.LittleEndian.AppendUint32(out, count)
+	for i := uint32(0); i < count; i++ {
+		n := binary.LittleEndian.Uint32(req)
+		method := string(req[4 : 4+n])
+		req = req[4+n:]
+		n = binary.LittleEndian.Uint32(req)
+		data := req[4 : 4+n]
+		req = req[4+n:]
+
+		status, payload := handle(method, data)
+		out = append(out, status)
+		out = binary.LittleEndian.AppendUint32(out, uint32(len(payload)))
+		out = append(out, payload...)
+	}
+	return out
+}
+
+func TestPlugin_InvokeBatch_Success(t *testing.T) {
+	mock := newMockPlatform()
+	mock.invokeBatchFunc = func(fn, freePtr uintptr, data []byte) ([]byte, error) {
+		return mockBatchResponse(data, func(method string, data []byte) (byte, []byte) {
+			if method == "/test.Fail" {
+				return 1, []byte("boom")
+			}
+			return 0, append([]byte(method+":"), data...)
+		}), nil
+	}
+	restore := mock.install()
+	defer restore()
+
+	plugin, err := LoadPlugin("test.so")
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+	defer plugin.Close()
+
+	results, err := plugin.InvokeBatch("TestService", []BatchCall{
+		{Method: "/test.A", Data: []byte("1")},
+		{Method: "/test.Fail", Data: nil},
+		{Method: "/test.B", Data: []byte("2")},
+	})
+	if err != nil {
+		t.Fatalf("InvokeBatch failed: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	if string(results[0].Data) != "/test.A:1" || results[0].Err != nil {
+		t.Errorf("unexpected result[0]: %q, %v", results[0].Data, results[0].Err)
+	}
+	var pluginErr *PluginError
+	if !errors.As(results[1].Err, &pluginErr) || pluginErr.Message != "boom" {
+		t.Errorf("expected PluginError 'boom', got %v", results[1].Err)
+	}
+	if string(results[2].Data) != "/test.B:2" || results[2].Err != nil {
+		t.Errorf("unexpected result[2]: %q, %v", results[2].Data, results[2].Err)
+	}
+
+	if atomic.LoadInt64(&mock.batchCalls) != 1 {
+		t.Errorf("expected 1 batch call, got %d", mock.batchCalls)
+	}
+	if atomic.LoadInt64(&mock.invokeCalls) != 0 {
+		t.Errorf("expected no individual invokes, got %d", mock.invokeCalls)
+	}
+}
+
+func TestPlugin_InvokeBatch_LegacyFallback(t *testing.T) {
+	mock := newMockPlatform()
+	mock.symFunc = func(handle uintptr, name string) (uintptr, error) {
+		if name == "Synurang_InvokeBatch_TestService" || name == "Synurang_InvokeBatch" {
+			return 0, errors.New("symbol not found")
+		}
+		return 0x2000, nil
+	}
+	restore := mock.install()
+	defer restore()
+
+	plugin, err := LoadPlugin("test.so")
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+	defer plugin.Close()
+
+	results, err := plugin.InvokeBatch("TestService", []BatchCall{
+		{Method: "/test.A"},
+		{Method: "/test.B"},
+	})
+	if err != nil {
+		t.Fatalf("InvokeBatch failed: %v", err)
+	}
+	for i, r := range results {
+		if r.Err != nil || string(r.Data) != "response" {
+			t.Errorf("result[%d]: %q, %v", i, r.Data, r.Err)
+		}
+	}
+	if atomic.LoadInt64(&mock.invokeCalls) != 2 {
+		t.Errorf("expected 2 individual invokes, got %d", mock.invokeCalls)
+	}
+	if atomic.LoadInt64(&mock.batchCalls) != 0 {
+		t.Errorf("expected no batch calls, got %d", mock.batchCalls)
+	}
+}
+
+func TestPlugin_InvokeBatch_WholeBatchError(t *testing.T) {
+	mock := newMockPlatform()
+	mock.invokeBatchFunc = func(fn, freePtr uintptr, data []byte) ([]byte, error) {
+		return append([]byte{1}, "malformed batch request"...), nil
+	}
+	restore := mock.install()
+	defer restore()
+
+	plugin, err := LoadPlugin("test.so")
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+	defer plugin.Close()
+
+	_, err = plugin.InvokeBatch("TestService", []BatchCall{{Method: "/test.A"}})
+	var pluginErr *PluginError
+	if !errors.As(err, &pluginErr) {
+		t.Fatalf("expected PluginError, got %v", err)
+	}
+}
+
+func TestPlugin_InvokeBatch_AfterClose(t *testing.T) {
+	mock := newMockPlatform()
+	restore := mock.install()
+	defer restore()
+
+	plugin, err := LoadPlugin("test.so")
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+	plugin.Close()
+
+	_, err = plugin.InvokeBatch("TestService", []BatchCall{{Method: "/test.A"}})
+	if !errors.Is(err, ErrPluginClosed) {
+		t.Errorf("expected ErrPluginClosed, got %v", err)
+	}
+}
+
+func TestPluginClientConn_Batching_Coalesces(t *testing.T) {
+	mock := newMockPlatform()
+	var mu sync.Mutex
+	var batchSizes []int
+	mock.invokeBatchFunc = func(fn, freePtr uintptr, data []byte) ([]byte, error) {
+		mu.Lock()
+		batchSizes = append(batchSizes, int(binary.LittleEndian.Uint32(data)))
+		mu.Unlock()
+		// Echo the request payload back as the response
+		return mockBatchResponse(data, func(_ string, data []byte) (byte, []byte) {
+			return 0, data
+		}), nil
+	}
+	restore := mock.install()
+	defer restore()
+
+	plugin, err := LoadPlugin("test.so")
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+	defer plugin.Close()
+
+	const calls = 8
+	conn := NewPluginClientConn(plugin, "TestService", WithBatching(20*time.Millisecond, calls))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, calls)
+	for i := 0; i < calls; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			want := fmt.Sprintf("req-%d", i)
+			reply := &wrapperspb.StringValue{}
+			if err := conn.Invoke(context.Background(), "/test.Echo", wrapperspb.String(want), reply); err != nil {
+				errs <- err
+				return
+			}
+			if reply.Value != want {
+				errs <- fmt.Errorf("got %q, want %q", reply.Value, want)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	total := 0
+	for _, n := range batchSizes {
+		total += n
+	}
+	if total != calls {
+		t.Errorf("expected %d calls across batches, got %d", calls, total)
+	}
+	if len(batchSizes) >= calls {
+		t.Errorf("calls were not coalesced: %d batches for %d calls", len(batchSizes), calls)
+	}
+}
+
+func TestPluginClientConn_Batching_ContextCancelled(t *testing.T) {
+	mock := newMockPlatform()
+	restore := mock.install()
+	defer restore()
+
+	plugin, err := LoadPlugin("test.so")
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+	defer plugin.Close()
+
+	conn := NewPluginClientConn(plugin, "TestService", WithBatching(time.Second, 0))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err = conn.Invoke(ctx, "/test.Echo", wrapperspb.String("x"), &wrapperspb.StringValue{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected DeadlineExceeded, got %v", err)
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Errorf("Invoke did not return promptly on context deadline")
+	}
+}
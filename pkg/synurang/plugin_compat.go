@@ -0,0 +1,294 @@
+// Descriptor compatibility checks.
+//
+// Plugins built with this runtime embed the proto files they were compiled
+// against (Synurang_Descriptors). With WithDescriptorCheck, LoadPlugin
+// compares the plugin's services and messages with the host's registered
+// descriptors (protoregistry.GlobalFiles) and reports changes that break the
+// wire format:
+//
+//   - a method the host knows is missing or changed its streaming kind
+//   - a method's request or response type changed
+//   - a field changed its number, cardinality or to a wire-incompatible type
+//
+// Additions (new fields, methods or services) and removed fields are
+// compatible and not reported. Services the host has not registered are
+// skipped.
+//
+// Usage:
+//
+//	plugin, err := synurang.LoadPlugin("./plugin.so",
+//	    synurang.WithDescriptorCheck(synurang.DescriptorCheckRefuse))
+
+package synurang
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// DescriptorPolicy selects what LoadPlugin does with descriptor incompatibilities.
+type DescriptorPolicy int
+
+const (
+	// DescriptorCheckOff skips the check (the default).
+	DescriptorCheckOff DescriptorPolicy = iota
+	// DescriptorCheckWarn logs each incompatibility and loads the plugin.
+	DescriptorCheckWarn
+	// DescriptorCheckRefuse fails the load with a *DescriptorMismatchError.
+	// Plugins that do not embed descriptors are refused as well.
+	DescriptorCheckRefuse
+)
+
+// WithDescriptorCheck enables the descriptor compatibility check at load time.
+func WithDescriptorCheck(policy DescriptorPolicy) PluginOption {
+	return func(o *pluginOptions) {
+		o.descriptorPolicy = policy
+	}
+}
+
+// DescriptorIssue is one wire-incompatible difference between the plugin's
+// and the host's descriptors.
+type DescriptorIssue struct {
+	// Element is the full name of the method or field concerned.
+	Element string
+	// Problem describes the difference, host side first.
+	Problem string
+}
+
+func (i DescriptorIssue) String() string {
+	return i.Element + ": " + i.Problem
+}
+
+// DescriptorMismatchError is returned by LoadPlugin when DescriptorCheckRefuse
+// finds incompatibilities.
+type DescriptorMismatchError struct {
+	Path   string
+	Issues []DescriptorIssue
+}
+
+func (e *DescriptorMismatchError) Error() string {
+	if len(e.Issues) == 0 {
+		return fmt.Sprintf("plugin %s does not embed descriptors", e.Path)
+	}
+	msg := fmt.Sprintf("plugin %s is incompatible with host descriptors: %s", e.Path, e.Issues[0])
+	if n := len(e.Issues) - 1; n > 0 {
+		msg += fmt.Sprintf(" (and %d more)", n)
+	}
+	return msg
+}
+
+// DescriptorIssues returns the incompatibilities found at load time
+// (only populated with DescriptorCheckWarn).
+func (p *Plugin) DescriptorIssues() []DescriptorIssue {
+	return p.descriptorIssues
+}
+
+// DescriptorSet returns the serialized FileDescriptorSet the plugin embeds
+// (Synurang_Descriptors), or nil if it embeds none. service.Engine's
+// RegisterPlugin registers it so that server reflection and ListServices
+// describe the plugin's services.
+func (p *Plugin) DescriptorSet() ([]byte, error) {
+	p.mu.RLock()
+	if p.closed {
+		p.mu.RUnlock()
+		return nil, ErrPluginClosed
+	}
+	p.wg.Add(1)
+	p.mu.RUnlock()
+	defer p.wg.Done()
+
+	fn, _ := platformSym(p.handle, "Synurang_Descriptors")
+	if fn == 0 {
+		return nil, nil
+	}
+	return platformDescriptors(fn, p.freePtr)
+}
+
+// checkPluginDescriptors applies policy to the plugin at handle. It returns
+// the issues to keep on the Plugin, or an error if the load must fail.
+func checkPluginDescriptors(path string, handle, freePtr uintptr, policy DescriptorPolicy) ([]DescriptorIssue, error) {
+	if policy == DescriptorCheckOff {
+		return nil, nil
+	}
+
+	var files *protoregistry.Files
+	if fn, _ := platformSym(handle, "Synurang_Descriptors"); fn != 0 {
+		data, err := platformDescriptors(fn, freePtr)
+		if err != nil {
+			return nil, fmt.Errorf("plugin %s: failed to read descriptors: %w", path, err)
+		}
+		if len(data) > 0 {
+			if files, err = decodeDescriptorSet(data); err != nil {
+				return nil, fmt.Errorf("plugin %s: invalid descriptors: %w", path, err)
+			}
+		}
+	}
+	if files == nil {
+		if policy == DescriptorCheckRefuse {
+			return nil, &DescriptorMismatchError{Path: path}
+		}
+		log.Printf("Plugin %s does not embed descriptors; skipping compatibility check", path)
+		return nil, nil
+	}
+
+	issues := compareDescriptors(protoregistry.GlobalFiles, files)
+	if len(issues) == 0 {
+		return nil, nil
+	}
+	if policy == DescriptorCheckRefuse {
+		return nil, &DescriptorMismatchError{Path: path, Issues: issues}
+	}
+	for _, issue := range issues {
+		log.Printf("Plugin %s descriptor incompatible with host: %s: %s", path, issue.Element, issue.Problem)
+	}
+	return issues, nil
+}
+
+func decodeDescriptorSet(data []byte) (*protoregistry.Files, error) {
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(data, set); err != nil {
+		return nil, err
+	}
+	return protodesc.NewFiles(set)
+}
+
+// compareDescriptors reports wire-incompatible differences between the
+// services in plugin and the same-named services in host.
+func compareDescriptors(host, plugin *protoregistry.Files) []DescriptorIssue {
+	c := &descriptorComparer{seen: make(map[[2]protoreflect.FullName]bool)}
+	plugin.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		services := fd.Services()
+		for i := 0; i < services.Len(); i++ {
+			ps := services.Get(i)
+			d, err := host.FindDescriptorByName(ps.FullName())
+			if err != nil {
+				continue
+			}
+			if hs, ok := d.(protoreflect.ServiceDescriptor); ok {
+				c.compareService(hs, ps)
+			}
+		}
+		return true
+	})
+	return c.issues
+}
+
+type descriptorComparer struct {
+	issues []DescriptorIssue
+	seen   map[[2]protoreflect.FullName]bool // message pairs already compared
+}
+
+func (c *descriptorComparer) add(element protoreflect.FullName, format string, args ...any) {
+	c.issues = append(c.issues, DescriptorIssue{Element: string(element), Problem: fmt.Sprintf(format, args...)})
+}
+
+func (c *descriptorComparer) compareService(host, plugin protoreflect.ServiceDescriptor) {
+	methods := host.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		hm := methods.Get(i)
+		pm := plugin.Methods().ByName(hm.Name())
+		if pm == nil {
+			c.add(hm.FullName(), "method missing in plugin")
+			continue
+		}
+		if hk, pk := streamingKind(hm), streamingKind(pm); hk != pk {
+			c.add(hm.FullName(), "streaming kind changed: host %s, plugin %s", hk, pk)
+		}
+		c.compareMessage(hm.FullName(), "request", hm.Input(), pm.Input())
+		c.compareMessage(hm.FullName(), "response", hm.Output(), pm.Output())
+	}
+}
+
+func streamingKind(m protoreflect.MethodDescriptor) string {
+	switch {
+	case m.IsStreamingClient() && m.IsStreamingServer():
+		return "bidi streaming"
+	case m.IsStreamingClient():
+		return "client streaming"
+	case m.IsStreamingServer():
+		return "server streaming"
+	default:
+		return "unary"
+	}
+}
+
+// compareMessage checks that plugin's encoding of a message is readable by
+// host and vice versa. role names the message's use for method-level issues.
+func (c *descriptorComparer) compareMessage(method protoreflect.FullName, role string, host, plugin protoreflect.MessageDescriptor) {
+	if role != "" && host.FullName() != plugin.FullName() {
+		c.add(method, "%s type changed: host %s, plugin %s", role, host.FullName(), plugin.FullName())
+	}
+	key := [2]protoreflect.FullName{host.FullName(), plugin.FullName()}
+	if c.seen[key] {
+		return
+	}
+	c.seen[key] = true
+
+	fields := host.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		hf := fields.Get(i)
+		pf := plugin.Fields().ByNumber(hf.Number())
+		if pf == nil {
+			// The number may have been reassigned to a renamed field.
+			if byName := plugin.Fields().ByName(hf.Name()); byName != nil {
+				c.add(hf.FullName(), "field number changed: host %d, plugin %d", hf.Number(), byName.Number())
+			}
+			continue
+		}
+		if hf.Cardinality() == protoreflect.Repeated != (pf.Cardinality() == protoreflect.Repeated) || hf.IsMap() != pf.IsMap() {
+			c.add(hf.FullName(), "cardinality changed: host %s, plugin %s", fieldShape(hf), fieldShape(pf))
+			continue
+		}
+		if !wireCompatible(hf.Kind(), pf.Kind()) {
+			c.add(hf.FullName(), "type changed: host %s, plugin %s", hf.Kind(), pf.Kind())
+			continue
+		}
+		if hf.Message() != nil && pf.Message() != nil {
+			c.compareMessage("", "", hf.Message(), pf.Message())
+		}
+	}
+}
+
+func fieldShape(f protoreflect.FieldDescriptor) string {
+	switch {
+	case f.IsMap():
+		return "map"
+	case f.Cardinality() == protoreflect.Repeated:
+		return "repeated"
+	default:
+		return "singular"
+	}
+}
+
+// wireCompatible reports whether values of kind a can be decoded as kind b,
+// following the protobuf language guide's rules for changing field types.
+func wireCompatible(a, b protoreflect.Kind) bool {
+	if a == b {
+		return true
+	}
+	group := func(k protoreflect.Kind) string {
+		switch k {
+		case protoreflect.Int32Kind, protoreflect.Uint32Kind, protoreflect.Int64Kind,
+			protoreflect.Uint64Kind, protoreflect.BoolKind, protoreflect.EnumKind:
+			return "varint"
+		case protoreflect.Sint32Kind, protoreflect.Sint64Kind:
+			return "zigzag"
+		case protoreflect.Fixed32Kind, protoreflect.Sfixed32Kind:
+			return "fixed32"
+		case protoreflect.Fixed64Kind, protoreflect.Sfixed64Kind:
+			return "fixed64"
+		case protoreflect.StringKind, protoreflect.BytesKind:
+			return "bytes"
+		default:
+			return strings.ToLower(k.String())
+		}
+	}
+	return group(a) == group(b)
+}
@@ -0,0 +1,197 @@
+package synurang
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// healthDescriptors returns the health.proto descriptor set as a plugin would
+// embed it, after applying mutate to a copy of the file.
+func healthDescriptors(t *testing.T, mutate func(*descriptorpb.FileDescriptorProto)) []byte {
+	t.Helper()
+	fd := protodesc.ToFileDescriptorProto(healthpb.File_grpc_health_v1_health_proto)
+	if mutate != nil {
+		mutate(fd)
+	}
+	data, err := proto.Marshal(&descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{fd}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func findMessage(fd *descriptorpb.FileDescriptorProto, name string) *descriptorpb.DescriptorProto {
+	for _, m := range fd.MessageType {
+		if m.GetName() == name {
+			return m
+		}
+	}
+	return nil
+}
+
+// breakHealth makes three wire-incompatible changes and one compatible one.
+func breakHealth(fd *descriptorpb.FileDescriptorProto) {
+	req := findMessage(fd, "HealthCheckRequest")
+	req.Field[0].Type = descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum() // string -> int32
+	resp := findMessage(fd, "HealthCheckResponse")
+	resp.Field[0].Type = descriptorpb.FieldDescriptorProto_TYPE_INT64.Enum() // enum -> int64 is compatible
+	resp.Field[0].TypeName = nil
+	for _, m := range fd.Service[0].Method {
+		if m.GetName() == "Watch" {
+			m.ClientStreaming = proto.Bool(true)
+		}
+	}
+	fd.Service[0].Method = append(fd.Service[0].Method[:0:0], fd.Service[0].Method[1:]...) // drop Check
+}
+
+func TestDescriptorCheck_Compatible(t *testing.T) {
+	mock := newMockPlatform()
+	mock.descriptorsFunc = func(fn, freePtr uintptr) ([]byte, error) {
+		return healthDescriptors(t, nil), nil
+	}
+	restore := mock.install()
+	defer restore()
+
+	plugin, err := LoadPlugin("test.so", WithDescriptorCheck(DescriptorCheckRefuse))
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+	defer plugin.Close()
+	if issues := plugin.DescriptorIssues(); len(issues) != 0 {
+		t.Errorf("expected no issues, got %v", issues)
+	}
+}
+
+func TestDescriptorCheck_Refuse(t *testing.T) {
+	mock := newMockPlatform()
+	mock.descriptorsFunc = func(fn, freePtr uintptr) ([]byte, error) {
+		return healthDescriptors(t, breakHealth), nil
+	}
+	restore := mock.install()
+	defer restore()
+
+	_, err := LoadPlugin("test.so", WithDescriptorCheck(DescriptorCheckRefuse))
+	var mismatch *DescriptorMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("expected DescriptorMismatchError, got %v", err)
+	}
+	if atomic.LoadInt64(&mock.initCalls) != 0 {
+		t.Error("expected Synurang_Init not to run for a refused plugin")
+	}
+	if atomic.LoadInt64(&mock.closeCalls) != 1 {
+		t.Errorf("expected the library to be closed, got %d closes", mock.closeCalls)
+	}
+
+	want := map[string]string{
+		"grpc.health.v1.Health.Check":               "method missing",
+		"grpc.health.v1.Health.Watch":               "streaming kind changed",
+		"grpc.health.v1.HealthCheckRequest.service": "type changed",
+	}
+	if len(mismatch.Issues) != len(want) {
+		t.Errorf("expected %d issues, got %v", len(want), mismatch.Issues)
+	}
+	for _, issue := range mismatch.Issues {
+		if !strings.Contains(issue.Problem, want[issue.Element]) || want[issue.Element] == "" {
+			t.Errorf("unexpected issue %s", issue)
+		}
+	}
+}
+
+func TestDescriptorCheck_Warn(t *testing.T) {
+	mock := newMockPlatform()
+	mock.descriptorsFunc = func(fn, freePtr uintptr) ([]byte, error) {
+		return healthDescriptors(t, breakHealth), nil
+	}
+	restore := mock.install()
+	defer restore()
+
+	plugin, err := LoadPlugin("test.so", WithDescriptorCheck(DescriptorCheckWarn))
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+	defer plugin.Close()
+	if issues := plugin.DescriptorIssues(); len(issues) != 3 {
+		t.Errorf("expected 3 issues, got %v", issues)
+	}
+}
+
+func TestDescriptorCheck_FieldNumberChanged(t *testing.T) {
+	mock := newMockPlatform()
+	mock.descriptorsFunc = func(fn, freePtr uintptr) ([]byte, error) {
+		return healthDescriptors(t, func(fd *descriptorpb.FileDescriptorProto) {
+			findMessage(fd, "HealthCheckRequest").Field[0].Number = proto.Int32(7)
+		}), nil
+	}
+	restore := mock.install()
+	defer restore()
+
+	_, err := LoadPlugin("test.so", WithDescriptorCheck(DescriptorCheckRefuse))
+	if err == nil || !strings.Contains(err.Error(), "field number changed: host 1, plugin 7") {
+		t.Errorf("expected field number issue, got %v", err)
+	}
+}
+
+func TestDescriptorCheck_NoEmbeddedDescriptors(t *testing.T) {
+	mock := newMockPlatform()
+	var looked int64
+	mock.symFunc = func(handle uintptr, name string) (uintptr, error) {
+		if name == "Synurang_Descriptors" {
+			atomic.AddInt64(&looked, 1)
+			return 0, errors.New("not found")
+		}
+		return 0x2000, nil
+	}
+	restore := mock.install()
+	defer restore()
+
+	if _, err := LoadPlugin("test.so", WithDescriptorCheck(DescriptorCheckRefuse)); err == nil {
+		t.Error("expected refuse policy to reject a plugin without descriptors")
+	}
+
+	plugin, err := LoadPlugin("test.so", WithDescriptorCheck(DescriptorCheckWarn))
+	if err != nil {
+		t.Fatalf("expected warn policy to load the plugin, got %v", err)
+	}
+	plugin.Close()
+
+	atomic.StoreInt64(&looked, 0)
+	plugin, err = LoadPlugin("test.so")
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+	plugin.Close()
+	if atomic.LoadInt64(&looked) != 0 {
+		t.Error("expected no descriptor lookup without WithDescriptorCheck")
+	}
+}
+
+func TestPlugin_DescriptorSet(t *testing.T) {
+	mock := newMockPlatform()
+	want := healthDescriptors(t, nil)
+	mock.descriptorsFunc = func(fn, freePtr uintptr) ([]byte, error) {
+		return want, nil
+	}
+	restore := mock.install()
+	defer restore()
+
+	plugin, err := LoadPlugin("test.so")
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+	data, err := plugin.DescriptorSet()
+	if err != nil || !bytes.Equal(data, want) {
+		t.Errorf("DescriptorSet() = %d bytes, %v; want %d bytes", len(data), err, len(want))
+	}
+	plugin.Close()
+	if _, err := plugin.DescriptorSet(); !errors.Is(err, ErrPluginClosed) {
+		t.Errorf("DescriptorSet after Close = %v, want ErrPluginClosed", err)
+	}
+}
@@ -4,7 +4,7 @@
 // Usage:
 //
 //	plugin, _ := synurang.LoadPlugin("./plugin.so")
-//	conn := synurang.NewPluginClientConn(plugin, "MyService")
+//	conn := synurang.NewPluginClientConn(plugin, "")
 //	client := pb.NewMyServiceClient(conn)
 //
 //	// Same API as standard gRPC - unary and streaming
@@ -20,6 +20,7 @@ import (
 	"io"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
@@ -66,21 +67,42 @@ func withContext[T any](ctx context.Context, fn func() (T, error)) (T, error) {
 type PluginClientConn struct {
 	plugin      *Plugin
 	serviceName string
+	batcher     *batcher // nil unless WithBatching is used
+}
+
+// PluginConnOption configures a PluginClientConn.
+type PluginConnOption func(*PluginClientConn)
+
+// WithBatching coalesces concurrent unary calls issued within window into a
+// single Synurang_InvokeBatch (or Synurang_InvokeBatch_<ServiceName>)
+// crossing. A batch is flushed early once maxSize calls are pending
+// (maxSize <= 0 uses a default of 64). Streaming calls are unaffected.
+func WithBatching(window time.Duration, maxSize int) PluginConnOption {
+	return func(c *PluginClientConn) {
+		c.batcher = newBatcher(c.plugin, c.serviceName, window, maxSize)
+	}
 }
 
 // NewPluginClientConn creates a gRPC client connection that routes calls through a plugin.
-// The serviceName should match the service name used in Synurang_Invoke_<ServiceName>.
-func NewPluginClientConn(plugin *Plugin, serviceName string) *PluginClientConn {
-	return &PluginClientConn{
+// With an empty serviceName the connection targets the whole plugin and calls
+// are routed by full method name (Synurang_Invoke / Synurang_Stream_Open).
+// Otherwise serviceName should match the service name used in the legacy
+// Synurang_Invoke_<ServiceName> symbols.
+func NewPluginClientConn(plugin *Plugin, serviceName string, opts ...PluginConnOption) *PluginClientConn {
+	c := &PluginClientConn{
 		plugin:      plugin,
 		serviceName: serviceName,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // Invoke implements grpc.ClientConnInterface for unary calls.
-// Respects context cancellation and deadline.
-// Note: FFI calls cannot be truly cancelled. On context cancellation,
-// this returns immediately but the underlying call continues until completion.
+// Respects context cancellation and deadline: on cancellation this returns
+// immediately and, for plugins with the async ABI, cancels the handler's
+// context. Older plugins run the call to completion in the background.
 func (c *PluginClientConn) Invoke(ctx context.Context, method string, args any, reply any, opts ...grpc.CallOption) error {
 	if err := ctx.Err(); err != nil {
 		return err
@@ -101,9 +123,12 @@ func (c *PluginClientConn) Invoke(ctx context.Context, method string, args any,
 		return fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	respBytes, err := withContext(ctx, func() ([]byte, error) {
-		return c.plugin.Invoke(c.serviceName, method, reqBytes)
-	})
+	var respBytes []byte
+	if c.batcher != nil {
+		respBytes, err = c.batcher.invoke(ctx, method, reqBytes)
+	} else {
+		respBytes, err = c.plugin.InvokeAsync(ctx, c.serviceName, method, reqBytes).Result()
+	}
 	if err != nil {
 		return err
 	}
@@ -118,7 +143,7 @@ func (c *PluginClientConn) NewStream(ctx context.Context, desc *grpc.StreamDesc,
 		return nil, err
 	}
 
-	stream, err := c.plugin.OpenStream(c.serviceName, method)
+	stream, err := c.plugin.OpenStreamContext(ctx, c.serviceName, method)
 	if err != nil {
 		return nil, err
 	}
@@ -202,6 +227,11 @@ type PluginStream struct {
 // ErrStreamClosed is returned when operations are attempted on a closed stream.
 var ErrStreamClosed = errors.New("stream is closed")
 
+// Handle returns the plugin-side stream handle.
+func (s *PluginStream) Handle() uintptr {
+	return s.handle
+}
+
 // Send sends data to the stream (for client-streaming and bidi).
 func (s *PluginStream) Send(data []byte) error {
 	s.sendMu.Lock()
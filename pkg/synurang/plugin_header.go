@@ -0,0 +1,85 @@
+// Call headers: request metadata through the Synurang ABI.
+//
+// Plugins and engines that export Synurang_InvokeWithHeader,
+// Synurang_InvokeAsyncWithHeader and Synurang_Stream_OpenWithHeader receive
+// the outgoing gRPC metadata of a call's context as a serialized
+// core.v1.CallHeader, e.g. the credentials of an engine with a token:
+//
+//	conn := synurang.NewPluginClientConn(engine, "")
+//	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+//	resp, err := pb.NewHealthServiceClient(conn).Ping(ctx, &emptypb.Empty{})
+//
+// Libraries without those exports are called without the metadata.
+
+package synurang
+
+import (
+	"context"
+	"sort"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// callHeaderVersion is the core.v1.CallHeader version hosts write.
+const callHeaderVersion = 1
+
+// headerFuncs holds the entry points taking a call header; zero values mean
+// the plugin predates them.
+type headerFuncs struct {
+	invoke     uintptr
+	streamOpen uintptr
+}
+
+// getHeaderFuncs resolves the call header entry points once.
+func (p *Plugin) getHeaderFuncs() (headerFuncs, error) {
+	p.mu.RLock()
+	if f := p.headerFuncs; f != nil {
+		p.mu.RUnlock()
+		return *f, nil
+	}
+	p.mu.RUnlock()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed || p.handle == 0 {
+		return headerFuncs{}, ErrPluginClosed
+	}
+	if p.headerFuncs == nil {
+		f := &headerFuncs{}
+		f.invoke, _ = platformSym(p.handle, "Synurang_InvokeWithHeader")
+		f.streamOpen, _ = platformSym(p.handle, "Synurang_Stream_OpenWithHeader")
+		p.headerFuncs = f
+	}
+	return *p.headerFuncs, nil
+}
+
+// callHeader encodes the outgoing metadata of ctx as a serialized
+// core.v1.CallHeader, or returns nil if there is none. pkg/api imports this
+// package, so the message is written with protowire.
+func callHeader(ctx context.Context) []byte {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if !ok || md.Len() == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(md))
+	for k := range md {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	b := protowire.AppendTag(nil, 1, protowire.VarintType) // version
+	b = protowire.AppendVarint(b, callHeaderVersion)
+	for _, k := range keys {
+		for _, v := range md[k] {
+			var entry []byte // core.v1.MetadataEntry
+			entry = protowire.AppendTag(entry, 1, protowire.BytesType)
+			entry = protowire.AppendString(entry, k)
+			entry = protowire.AppendTag(entry, 2, protowire.BytesType)
+			entry = protowire.AppendString(entry, v)
+			b = protowire.AppendTag(b, 2, protowire.BytesType) // metadata
+			b = protowire.AppendBytes(b, entry)
+		}
+	}
+	return b
+}
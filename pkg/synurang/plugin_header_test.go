@@ -0,0 +1,142 @@
+package synurang
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// decodeCallHeader returns the version and "key=value" metadata entries of
+// a serialized core.v1.CallHeader.
+func decodeCallHeader(t *testing.T, b []byte) (uint64, []string) {
+	t.Helper()
+	var version uint64
+	var entries []string
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			t.Fatalf("bad call header tag: %v", protowire.ParseError(n))
+		}
+		b = b[n:]
+		switch {
+		case num == 1 && typ == protowire.VarintType:
+			version, n = protowire.ConsumeVarint(b)
+		case num == 2 && typ == protowire.BytesType:
+			var entry []byte
+			entry, n = protowire.ConsumeBytes(b)
+			var key, value string
+			for len(entry) > 0 {
+				num, _, m := protowire.ConsumeTag(entry)
+				entry = entry[m:]
+				s, m := protowire.ConsumeString(entry)
+				entry = entry[m:]
+				if num == 1 {
+					key = s
+				} else {
+					value = s
+				}
+			}
+			entries = append(entries, key+"="+value)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			t.Fatalf("bad call header field %d: %v", num, protowire.ParseError(n))
+		}
+		b = b[n:]
+	}
+	return version, entries
+}
+
+func TestCallHeader(t *testing.T) {
+	if h := callHeader(context.Background()); h != nil {
+		t.Errorf("callHeader without metadata = %x, want nil", h)
+	}
+	ctx := metadata.AppendToOutgoingContext(context.Background(),
+		"authorization", "Bearer secret", "x-tag", "a", "x-tag", "b")
+	version, entries := decodeCallHeader(t, callHeader(ctx))
+	if version != callHeaderVersion {
+		t.Errorf("version = %d, want %d", version, callHeaderVersion)
+	}
+	if got, want := strings.Join(entries, " "), "authorization=Bearer secret x-tag=a x-tag=b"; got != want {
+		t.Errorf("metadata = %s, want %s", got, want)
+	}
+}
+
+func TestPlugin_CallHeader(t *testing.T) {
+	mock := newMockPlatform()
+	restore := mock.install()
+	defer restore()
+
+	plugin, err := LoadPlugin("test.so")
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+	defer plugin.Close()
+
+	// Calls without metadata keep the plain entry points
+	if _, err := plugin.InvokeContext(context.Background(), "", "/pkg.Svc/M", nil); err != nil {
+		t.Fatalf("InvokeContext failed: %v", err)
+	}
+	if len(mock.headers) != 0 {
+		t.Fatalf("plain call sent %d call headers", len(mock.headers))
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer secret")
+	if _, err := plugin.InvokeContext(ctx, "", "/pkg.Svc/M", nil); err != nil {
+		t.Fatalf("InvokeContext failed: %v", err)
+	}
+	if _, err := plugin.InvokeAsync(ctx, "", "/pkg.Svc/M", nil).Result(); err != nil {
+		t.Fatalf("InvokeAsync failed: %v", err)
+	}
+	stream, err := plugin.OpenStreamContext(ctx, "", "/pkg.Svc/Stream")
+	if err != nil {
+		t.Fatalf("OpenStreamContext failed: %v", err)
+	}
+	stream.Close()
+
+	mock.headersMu.Lock()
+	defer mock.headersMu.Unlock()
+	if len(mock.headers) != 3 {
+		t.Fatalf("got %d call headers, want 3", len(mock.headers))
+	}
+	for _, h := range mock.headers {
+		if _, entries := decodeCallHeader(t, h); len(entries) != 1 || entries[0] != "authorization=Bearer secret" {
+			t.Errorf("call header metadata = %v", entries)
+		}
+	}
+}
+
+func TestPlugin_CallHeader_Unsupported(t *testing.T) {
+	mock := newMockPlatform()
+	mock.symFunc = func(handle uintptr, name string) (uintptr, error) {
+		if strings.HasSuffix(name, "WithHeader") {
+			return 0, errors.New("not found")
+		}
+		return 0x2000, nil
+	}
+	restore := mock.install()
+	defer restore()
+
+	plugin, err := LoadPlugin("test.so")
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+	defer plugin.Close()
+
+	// Older plugins are called without the metadata
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer secret")
+	if _, err := plugin.InvokeContext(ctx, "", "/pkg.Svc/M", nil); err != nil {
+		t.Fatalf("InvokeContext failed: %v", err)
+	}
+	if _, err := plugin.InvokeAsync(ctx, "", "/pkg.Svc/M", nil).Result(); err != nil {
+		t.Fatalf("InvokeAsync failed: %v", err)
+	}
+	if len(mock.headers) != 0 {
+		t.Errorf("sent %d call headers to a plugin without the entry points", len(mock.headers))
+	}
+}
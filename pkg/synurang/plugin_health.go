@@ -0,0 +1,70 @@
+// Plugin health.
+//
+// CheckHealth reports whether a loaded plugin can serve, so that hosts can
+// tie their own health to their plugins. A service.Engine checks it once the
+// plugin is registered, until the plugin is closed:
+//
+//	engine.RegisterPlugin(path, plugin)
+//
+// A plugin is healthy until it is closed. Plugins that embed descriptors
+// declaring grpc.health.v1.Health are asked as well: they are healthy while
+// Check for the "" service returns SERVING.
+
+package synurang
+
+import (
+	"context"
+	"fmt"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/protobuf/proto"
+)
+
+const healthCheckMethod = "/grpc.health.v1.Health/Check"
+
+// CheckHealth returns nil if the plugin can serve, ErrPluginClosed once it
+// is closed, or why its grpc.health.v1.Health service reports otherwise.
+func (p *Plugin) CheckHealth(ctx context.Context) error {
+	p.mu.RLock()
+	closed := p.closed
+	p.mu.RUnlock()
+	if closed {
+		return ErrPluginClosed
+	}
+
+	p.healthOnce.Do(func() { p.servesHealth = p.declaresHealth() })
+	if !p.servesHealth {
+		return nil
+	}
+	req, err := proto.Marshal(&healthpb.HealthCheckRequest{})
+	if err != nil {
+		return err
+	}
+	data, err := p.InvokeContext(ctx, "Health", healthCheckMethod, req)
+	if err != nil {
+		return fmt.Errorf("health check failed: %w", err)
+	}
+	resp := &healthpb.HealthCheckResponse{}
+	if err := proto.Unmarshal(data, resp); err != nil {
+		return fmt.Errorf("health check failed: %w", err)
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("plugin reports %s", resp.GetStatus())
+	}
+	return nil
+}
+
+// declaresHealth reports whether the descriptors the plugin embeds declare
+// grpc.health.v1.Health.
+func (p *Plugin) declaresHealth() bool {
+	data, err := p.DescriptorSet()
+	if err != nil || len(data) == 0 {
+		return false
+	}
+	files, err := decodeDescriptorSet(data)
+	if err != nil {
+		return false
+	}
+	_, err = files.FindDescriptorByName(healthpb.File_grpc_health_v1_health_proto.Services().Get(0).FullName())
+	return err == nil
+}
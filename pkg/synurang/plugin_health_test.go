@@ -0,0 +1,67 @@
+package synurang
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestPlugin_CheckHealth(t *testing.T) {
+	mock := newMockPlatform()
+	mock.descriptorsFunc = func(fn, freePtr uintptr) ([]byte, error) {
+		return healthDescriptors(t, nil), nil
+	}
+	var serving atomic.Bool
+	mock.invokeFunc = func(fn, freePtr uintptr, method string, data []byte) ([]byte, error) {
+		if method != healthCheckMethod {
+			return append([]byte{1}, "unexpected method"...), nil
+		}
+		st := healthpb.HealthCheckResponse_NOT_SERVING
+		if serving.Load() {
+			st = healthpb.HealthCheckResponse_SERVING
+		}
+		resp, _ := proto.Marshal(&healthpb.HealthCheckResponse{Status: st})
+		return append([]byte{0}, resp...), nil
+	}
+	restore := mock.install()
+	defer restore()
+
+	plugin, err := LoadPlugin("test.so")
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+	if err := plugin.CheckHealth(context.Background()); err == nil || !strings.Contains(err.Error(), "NOT_SERVING") {
+		t.Errorf("CheckHealth of a NOT_SERVING plugin = %v", err)
+	}
+	serving.Store(true)
+	if err := plugin.CheckHealth(context.Background()); err != nil {
+		t.Errorf("CheckHealth of a SERVING plugin = %v", err)
+	}
+	plugin.Close()
+	if err := plugin.CheckHealth(context.Background()); !errors.Is(err, ErrPluginClosed) {
+		t.Errorf("CheckHealth after Close = %v, want ErrPluginClosed", err)
+	}
+}
+
+func TestPlugin_CheckHealthWithoutHealthService(t *testing.T) {
+	mock := newMockPlatform()
+	restore := mock.install()
+	defer restore()
+
+	plugin, err := LoadPlugin("test.so")
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+	defer plugin.Close()
+	if err := plugin.CheckHealth(context.Background()); err != nil {
+		t.Errorf("CheckHealth = %v", err)
+	}
+	if n := atomic.LoadInt64(&mock.invokeCalls); n != 0 {
+		t.Errorf("CheckHealth invoked the plugin %d times without a health service", n)
+	}
+}
@@ -0,0 +1,282 @@
+// Admission control for plugin calls.
+//
+// Limits cap how much concurrent work a host can push into a plugin. They are
+// set at load time for the whole plugin and, optionally, per method:
+//
+//	plugin, err := synurang.LoadPlugin("./plugin.so",
+//	    synurang.WithLimits(synurang.Limits{MaxConcurrentCalls: 64, MaxOpenStreams: 256}),
+//	    synurang.WithMethodLimits("/pkg.MyService/Heavy", synurang.Limits{
+//	        MaxConcurrentCalls: 4,
+//	        MaxQueue:           16,
+//	        QueueTimeout:       time.Second,
+//	    }),
+//	)
+//
+// A call that finds no free slot waits in the queue (if any) and fails with
+// codes.ResourceExhausted when the queue is full or the timeout expires.
+// Plugin.Stats reports current usage and rejections.
+
+package synurang
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Limits bounds concurrent work. Zero values mean unlimited / no queue.
+type Limits struct {
+	// MaxConcurrentCalls is the number of unary calls allowed in flight.
+	MaxConcurrentCalls int
+	// MaxOpenStreams is the number of streams allowed open at once.
+	MaxOpenStreams int
+	// MaxQueue is how many callers may wait for a free slot before new
+	// callers are rejected. With zero, callers are rejected immediately.
+	MaxQueue int
+	// QueueTimeout bounds how long a queued caller waits (zero waits until
+	// the caller's context is done).
+	QueueTimeout time.Duration
+}
+
+// WithLimits sets plugin-wide limits.
+func WithLimits(l Limits) PluginOption {
+	return func(o *pluginOptions) {
+		o.limits = l
+	}
+}
+
+// WithMethodLimits sets limits for one method, given as the full gRPC method
+// name ("/pkg.Service/Method"). They apply in addition to the plugin-wide limits.
+func WithMethodLimits(method string, l Limits) PluginOption {
+	return func(o *pluginOptions) {
+		if o.methodLimits == nil {
+			o.methodLimits = make(map[string]Limits)
+		}
+		o.methodLimits[method] = l
+	}
+}
+
+// PluginStats is a snapshot of a plugin's admission state.
+type PluginStats struct {
+	ActiveCalls     int
+	QueuedCalls     int
+	RejectedCalls   uint64
+	OpenStreams     int
+	QueuedStreams   int
+	RejectedStreams uint64
+	// Methods holds per-method counters for every method called so far.
+	Methods map[string]MethodStats
+}
+
+// MethodStats is the per-method part of PluginStats.
+type MethodStats struct {
+	ActiveCalls     int
+	QueuedCalls     int
+	RejectedCalls   uint64
+	OpenStreams     int
+	QueuedStreams   int
+	RejectedStreams uint64
+}
+
+// Stats returns the plugin's current admission counters.
+func (p *Plugin) Stats() PluginStats {
+	a := p.admission
+	st := PluginStats{
+		ActiveCalls:     a.calls.activeCount(),
+		QueuedCalls:     a.calls.queuedCount(),
+		RejectedCalls:   a.calls.rejected.Load(),
+		OpenStreams:     a.streams.activeCount(),
+		QueuedStreams:   a.streams.queuedCount(),
+		RejectedStreams: a.streams.rejected.Load(),
+		Methods:         make(map[string]MethodStats),
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for method, m := range a.methods {
+		st.Methods[method] = MethodStats{
+			ActiveCalls:     m.calls.activeCount(),
+			QueuedCalls:     m.calls.queuedCount(),
+			RejectedCalls:   m.calls.rejected.Load(),
+			OpenStreams:     m.streams.activeCount(),
+			QueuedStreams:   m.streams.queuedCount(),
+			RejectedStreams: m.streams.rejected.Load(),
+		}
+	}
+	return st
+}
+
+// admission holds the plugin-wide and per-method limiters.
+type admission struct {
+	calls   *limiter
+	streams *limiter
+
+	methodLimits map[string]Limits
+	mu           sync.Mutex
+	methods      map[string]*methodAdmission
+}
+
+type methodAdmission struct {
+	calls   *limiter
+	streams *limiter
+}
+
+func newAdmission(l Limits, methodLimits map[string]Limits) *admission {
+	return &admission{
+		calls:        newLimiter("concurrent calls", l.MaxConcurrentCalls, l.MaxQueue, l.QueueTimeout),
+		streams:      newLimiter("open streams", l.MaxOpenStreams, l.MaxQueue, l.QueueTimeout),
+		methodLimits: methodLimits,
+		methods:      make(map[string]*methodAdmission),
+	}
+}
+
+func (a *admission) method(name string) *methodAdmission {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	m, ok := a.methods[name]
+	if !ok {
+		l := a.methodLimits[name]
+		m = &methodAdmission{
+			calls:   newLimiter("concurrent calls to "+name, l.MaxConcurrentCalls, l.MaxQueue, l.QueueTimeout),
+			streams: newLimiter("open streams to "+name, l.MaxOpenStreams, l.MaxQueue, l.QueueTimeout),
+		}
+		a.methods[name] = m
+	}
+	return m
+}
+
+// admitCall reserves a unary call slot; the returned func releases it.
+func (a *admission) admitCall(ctx context.Context, method string) (func(), error) {
+	return admitBoth(ctx, a.calls, a.method(method).calls)
+}
+
+// admitStream reserves a stream slot; the returned func releases it.
+func (a *admission) admitStream(ctx context.Context, method string) (func(), error) {
+	return admitBoth(ctx, a.streams, a.method(method).streams)
+}
+
+// tryAdmitCall is admitCall without queueing: it fails at once if no slot is free.
+func (a *admission) tryAdmitCall(method string) (func(), error) {
+	m := a.method(method)
+	if err := m.calls.tryAcquire(); err != nil {
+		return nil, err
+	}
+	if err := a.calls.tryAcquire(); err != nil {
+		m.calls.release()
+		return nil, err
+	}
+	return releaseBoth(a.calls, m.calls), nil
+}
+
+func admitBoth(ctx context.Context, plugin, method *limiter) (func(), error) {
+	if err := method.acquire(ctx); err != nil {
+		return nil, err
+	}
+	if err := plugin.acquire(ctx); err != nil {
+		method.release()
+		return nil, err
+	}
+	return releaseBoth(plugin, method), nil
+}
+
+func releaseBoth(plugin, method *limiter) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			plugin.release()
+			method.release()
+		})
+	}
+}
+
+// limiter is a counting semaphore with a bounded wait queue.
+// With max <= 0 it only counts.
+type limiter struct {
+	what     string
+	max      int
+	maxQueue int
+	timeout  time.Duration
+
+	active   atomic.Int64
+	queued   atomic.Int64
+	rejected atomic.Uint64
+	slots    chan struct{} // nil if unlimited
+}
+
+func newLimiter(what string, max, maxQueue int, timeout time.Duration) *limiter {
+	l := &limiter{what: what, max: max, maxQueue: maxQueue, timeout: timeout}
+	if max > 0 {
+		l.slots = make(chan struct{}, max)
+	}
+	return l
+}
+
+// tryAcquire takes a free slot or rejects without queueing.
+func (l *limiter) tryAcquire() error {
+	if l.slots == nil {
+		l.active.Add(1)
+		return nil
+	}
+	select {
+	case l.slots <- struct{}{}:
+		l.active.Add(1)
+		return nil
+	default:
+		return l.reject()
+	}
+}
+
+func (l *limiter) acquire(ctx context.Context) error {
+	if l.slots == nil {
+		l.active.Add(1)
+		return nil
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		l.active.Add(1)
+		return nil
+	default:
+	}
+
+	if l.queued.Add(1) > int64(l.maxQueue) {
+		l.queued.Add(-1)
+		return l.reject()
+	}
+	defer l.queued.Add(-1)
+
+	var timeout <-chan time.Time
+	if l.timeout > 0 {
+		timer := time.NewTimer(l.timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		l.active.Add(1)
+		return nil
+	case <-timeout:
+		return l.reject()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l *limiter) reject() error {
+	l.rejected.Add(1)
+	return status.Errorf(codes.ResourceExhausted, "plugin limit reached: %s (max %d)", l.what, l.max)
+}
+
+func (l *limiter) release() {
+	l.active.Add(-1)
+	if l.slots != nil {
+		<-l.slots
+	}
+}
+
+func (l *limiter) activeCount() int { return int(l.active.Load()) }
+func (l *limiter) queuedCount() int { return int(l.queued.Load()) }
@@ -0,0 +1,226 @@
+package synurang
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// blockingInvoke makes the mock plugin hold every unary call until release is closed.
+func blockingInvoke(mock *mockPlatform) (started <-chan struct{}, release chan struct{}) {
+	s := make(chan struct{}, 16)
+	release = make(chan struct{})
+	mock.invokeFunc = func(fn, freePtr uintptr, method string, data []byte) ([]byte, error) {
+		s <- struct{}{}
+		<-release
+		return []byte{0}, nil
+	}
+	return s, release
+}
+
+func TestLimits_RejectsWhenFull(t *testing.T) {
+	mock := newMockPlatform()
+	started, release := blockingInvoke(mock)
+	restore := mock.install()
+	defer restore()
+
+	plugin, err := LoadPlugin("test.so", WithLimits(Limits{MaxConcurrentCalls: 1}))
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+	defer plugin.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := plugin.Invoke("TestService", "/test.Method", nil)
+		done <- err
+	}()
+	<-started
+
+	_, err = plugin.Invoke("TestService", "/test.Method", nil)
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("expected ResourceExhausted, got %v", err)
+	}
+	if st := plugin.Stats(); st.ActiveCalls != 1 || st.RejectedCalls != 1 {
+		t.Errorf("unexpected stats: %+v", st)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Errorf("first call failed: %v", err)
+	}
+	if st := plugin.Stats(); st.ActiveCalls != 0 {
+		t.Errorf("expected slot to be released, got %d active", st.ActiveCalls)
+	}
+}
+
+func TestLimits_QueueWaitsForSlot(t *testing.T) {
+	mock := newMockPlatform()
+	started, release := blockingInvoke(mock)
+	restore := mock.install()
+	defer restore()
+
+	plugin, err := LoadPlugin("test.so", WithLimits(Limits{MaxConcurrentCalls: 1, MaxQueue: 1}))
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+	defer plugin.Close()
+
+	go plugin.Invoke("TestService", "/test.Method", nil)
+	<-started
+
+	queued := make(chan error, 1)
+	go func() {
+		_, err := plugin.Invoke("TestService", "/test.Method", nil)
+		queued <- err
+	}()
+	deadline := time.Now().Add(time.Second)
+	for plugin.Stats().QueuedCalls != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("second call never queued")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// The queue is full: a third caller is turned away
+	if _, err := plugin.Invoke("TestService", "/test.Method", nil); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("expected ResourceExhausted with a full queue, got %v", err)
+	}
+
+	close(release)
+	if err := <-queued; err != nil {
+		t.Errorf("queued call failed: %v", err)
+	}
+}
+
+func TestLimits_QueueTimeout(t *testing.T) {
+	mock := newMockPlatform()
+	started, release := blockingInvoke(mock)
+	restore := mock.install()
+	defer restore()
+
+	plugin, err := LoadPlugin("test.so", WithLimits(Limits{
+		MaxConcurrentCalls: 1,
+		MaxQueue:           1,
+		QueueTimeout:       20 * time.Millisecond,
+	}))
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+	defer plugin.Close()
+	defer close(release)
+
+	go plugin.Invoke("TestService", "/test.Method", nil)
+	<-started
+
+	_, err = plugin.Invoke("TestService", "/test.Method", nil)
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("expected ResourceExhausted after queue timeout, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := plugin.InvokeContext(ctx, "TestService", "/test.Method", nil); err != context.Canceled {
+		t.Errorf("expected context.Canceled while queued, got %v", err)
+	}
+}
+
+func TestLimits_MethodLimits(t *testing.T) {
+	mock := newMockPlatform()
+	started, release := blockingInvoke(mock)
+	restore := mock.install()
+	defer restore()
+
+	plugin, err := LoadPlugin("test.so", WithMethodLimits("/test.Heavy", Limits{MaxConcurrentCalls: 1}))
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+	defer plugin.Close()
+	defer close(release)
+
+	go plugin.Invoke("TestService", "/test.Heavy", nil)
+	<-started
+
+	if _, err := plugin.Invoke("TestService", "/test.Heavy", nil); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("expected ResourceExhausted for /test.Heavy, got %v", err)
+	}
+
+	// Other methods are unaffected
+	go plugin.Invoke("TestService", "/test.Light", nil)
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("/test.Light was blocked by the /test.Heavy limit")
+	}
+
+	st := plugin.Stats()
+	if st.Methods["/test.Heavy"].RejectedCalls != 1 || st.Methods["/test.Light"].RejectedCalls != 0 {
+		t.Errorf("unexpected per-method stats: %+v", st.Methods)
+	}
+}
+
+func TestLimits_StreamSlotReleasedOnClose(t *testing.T) {
+	mock := newMockPlatform()
+	var next uint64
+	mock.streamOpenFunc = func(fn uintptr, method string) uint64 {
+		return atomic.AddUint64(&next, 1)
+	}
+	restore := mock.install()
+	defer restore()
+
+	plugin, err := LoadPlugin("test.so", WithLimits(Limits{MaxOpenStreams: 1}))
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+	defer plugin.Close()
+
+	stream, err := plugin.OpenStream("TestService", "/test.Stream")
+	if err != nil {
+		t.Fatalf("OpenStream failed: %v", err)
+	}
+	if _, err := plugin.OpenStream("TestService", "/test.Stream"); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("expected ResourceExhausted for a second stream, got %v", err)
+	}
+	if st := plugin.Stats(); st.OpenStreams != 1 || st.RejectedStreams != 1 {
+		t.Errorf("unexpected stats: %+v", st)
+	}
+
+	stream.Close()
+	stream, err = plugin.OpenStream("TestService", "/test.Stream")
+	if err != nil {
+		t.Fatalf("OpenStream after Close failed: %v", err)
+	}
+	stream.Close()
+}
+
+func TestLimits_BatchRejectsExcessCalls(t *testing.T) {
+	mock := newMockPlatform()
+	restore := mock.install()
+	defer restore()
+
+	plugin, err := LoadPlugin("test.so", WithLimits(Limits{MaxConcurrentCalls: 2, MaxQueue: 10}))
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+	defer plugin.Close()
+
+	results, err := plugin.InvokeBatch("TestService", []BatchCall{
+		{Method: "/test.A"}, {Method: "/test.B"}, {Method: "/test.C"},
+	})
+	if err != nil {
+		t.Fatalf("InvokeBatch failed: %v", err)
+	}
+	if string(results[0].Data) != "/test.A" || string(results[1].Data) != "/test.B" {
+		t.Errorf("admitted calls returned wrong results: %+v", results)
+	}
+	if status.Code(results[2].Err) != codes.ResourceExhausted {
+		t.Errorf("expected ResourceExhausted for the third call, got %v", results[2].Err)
+	}
+	if st := plugin.Stats(); st.ActiveCalls != 0 {
+		t.Errorf("expected all slots released, got %d active", st.ActiveCalls)
+	}
+}
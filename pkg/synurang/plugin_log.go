@@ -0,0 +1,109 @@
+package synurang
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// LogRecord is a log record forwarded from a plugin's slog handler
+// (plugin.LogHandler) to the host.
+type LogRecord struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	// Plugin is the name the plugin set with plugin.SetName, or the base name
+	// of the library file it was loaded from.
+	Plugin string
+	Attrs  []slog.Attr
+}
+
+// WithLogSink registers fn to receive the plugin's log records.
+// fn may be called concurrently from any plugin goroutine.
+func WithLogSink(fn func(LogRecord)) PluginOption {
+	return func(o *pluginOptions) {
+		o.logSink = fn
+	}
+}
+
+// WithLogger forwards the plugin's log records to logger, tagged with a
+// "plugin" attribute. The logger's handler decides filtering and output.
+func WithLogger(logger *slog.Logger) PluginOption {
+	return WithLogSink(func(rec LogRecord) {
+		ctx := context.Background()
+		if !logger.Enabled(ctx, rec.Level) {
+			return
+		}
+		r := slog.NewRecord(rec.Time, rec.Level, rec.Message, 0)
+		r.AddAttrs(slog.String("plugin", rec.Plugin))
+		r.AddAttrs(rec.Attrs...)
+		_ = logger.Handler().Handle(ctx, r)
+	})
+}
+
+// logBridge routes records for one loaded plugin.
+type logBridge struct {
+	name string
+	sink func(LogRecord)
+}
+
+var (
+	logBridgeCounter uint64
+	logBridges       sync.Map // token -> *logBridge
+)
+
+func registerLogBridge(path string, sink func(LogRecord)) uint64 {
+	name := filepath.Base(path)
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	token := atomic.AddUint64(&logBridgeCounter, 1)
+	logBridges.Store(token, &logBridge{name: name, sink: sink})
+	return token
+}
+
+func unregisterLogBridge(token uint64) {
+	logBridges.Delete(token)
+}
+
+// wireLogRecord mirrors the JSON record written by plugin.LogHandler.
+type wireLogRecord struct {
+	Time    time.Time `json:"time"`
+	Level   int       `json:"level"`
+	Message string    `json:"msg"`
+	Plugin  string    `json:"plugin"`
+	Attrs   []struct {
+		Key   string `json:"k"`
+		Value any    `json:"v"`
+	} `json:"attrs"`
+}
+
+// dispatchPluginLog is called by the platform log sink callback.
+// Records for unknown tokens (plugins already closed) are dropped.
+func dispatchPluginLog(token uint64, level int, data []byte) {
+	val, ok := logBridges.Load(token)
+	if !ok {
+		return
+	}
+	bridge := val.(*logBridge)
+
+	rec := LogRecord{Level: slog.Level(level), Plugin: bridge.name}
+	var wire wireLogRecord
+	if err := json.Unmarshal(data, &wire); err != nil {
+		rec.Time = time.Now()
+		rec.Message = string(data)
+	} else {
+		rec.Time = wire.Time
+		rec.Message = wire.Message
+		if wire.Plugin != "" {
+			rec.Plugin = wire.Plugin
+		}
+		for _, a := range wire.Attrs {
+			rec.Attrs = append(rec.Attrs, slog.Any(a.Key, a.Value))
+		}
+	}
+	bridge.sink(rec)
+}
@@ -0,0 +1,87 @@
+package synurang
+
+import (
+	"log/slog"
+	"testing"
+)
+
+func TestLoadPlugin_LogSinkForwardsRecords(t *testing.T) {
+	mock := newMockPlatform()
+	var installed, detached bool
+	var token uint64
+	mock.setLogSinkFunc = func(fn, sink uintptr, tok uint64) {
+		if sink == 0 {
+			detached = true
+			return
+		}
+		installed = true
+		token = tok
+	}
+	restore := mock.install()
+	defer restore()
+
+	var got []LogRecord
+	plugin, err := LoadPlugin("/opt/plugins/greeter.so", WithLogSink(func(rec LogRecord) {
+		got = append(got, rec)
+	}))
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+	if !installed || token == 0 {
+		t.Fatal("expected log sink to be installed with a token")
+	}
+
+	dispatchPluginLog(token, int(slog.LevelWarn), []byte(`{"time":"2024-01-02T03:04:05Z","level":4,"msg":"disk low","attrs":[{"k":"db.free","v":12}]}`))
+	if len(got) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(got))
+	}
+	rec := got[0]
+	if rec.Message != "disk low" || rec.Level != slog.LevelWarn || rec.Plugin != "greeter" {
+		t.Errorf("unexpected record: %+v", rec)
+	}
+	if len(rec.Attrs) != 1 || rec.Attrs[0].Key != "db.free" || rec.Attrs[0].Value.Float64() != 12 {
+		t.Errorf("unexpected attrs: %v", rec.Attrs)
+	}
+
+	// A name set by the plugin overrides the file name
+	dispatchPluginLog(token, 0, []byte(`{"msg":"hi","plugin":"custom"}`))
+	if got[1].Plugin != "custom" {
+		t.Errorf("expected plugin name 'custom', got %q", got[1].Plugin)
+	}
+
+	plugin.Close()
+	if !detached {
+		t.Error("expected log sink to be detached on Close")
+	}
+
+	// Records arriving after Close are dropped
+	dispatchPluginLog(token, 0, []byte(`{"msg":"late"}`))
+	if len(got) != 2 {
+		t.Errorf("expected late record to be dropped, got %d records", len(got))
+	}
+}
+
+func TestLoadPlugin_LogSinkDetachedOnInitFailure(t *testing.T) {
+	mock := newMockPlatform()
+	var token uint64
+	mock.setLogSinkFunc = func(fn, sink uintptr, tok uint64) {
+		if sink != 0 {
+			token = tok
+		}
+	}
+	mock.initFunc = func(fn, freePtr uintptr, config []byte) ([]byte, error) {
+		return append([]byte{1}, "boom"...), nil
+	}
+	restore := mock.install()
+	defer restore()
+
+	called := false
+	_, err := LoadPlugin("test.so", WithLogSink(func(LogRecord) { called = true }))
+	if err == nil {
+		t.Fatal("expected init failure")
+	}
+	dispatchPluginLog(token, 0, []byte(`{"msg":"late"}`))
+	if called {
+		t.Error("expected sink to be unregistered after init failure")
+	}
+}
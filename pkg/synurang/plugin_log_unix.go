@@ -0,0 +1,25 @@
+//go:build !windows
+
+package synurang
+
+/*
+extern void synurangHostLogSink(unsigned long long token, int level, char* record, int recordLen);
+*/
+import "C"
+
+import "unsafe"
+
+// synurangHostLogSink is the C callback handed to plugins via Synurang_SetLogSink.
+//
+//export synurangHostLogSink
+func synurangHostLogSink(token C.ulonglong, level C.int, record *C.char, recordLen C.int) {
+	var data []byte
+	if record != nil && recordLen > 0 {
+		data = C.GoBytes(unsafe.Pointer(record), recordLen)
+	}
+	dispatchPluginLog(uint64(token), int(level), data)
+}
+
+func unixLogSinkPtr() uintptr {
+	return uintptr(unsafe.Pointer(C.synurangHostLogSink))
+}
@@ -0,0 +1,155 @@
+// Serving plugins over the network.
+//
+// RegisterPluginServices exposes the services of a loaded plugin on a regular
+// grpc.Server, so a plugin can be exercised with grpcurl or Postman over TCP
+// or UDS without writing a host. Messages are decoded with dynamicpb from the
+// supplied descriptors and forwarded through the plugin ABI.
+//
+// Usage:
+//
+//	plugin, _ := synurang.LoadPlugin("./plugin.so")
+//	s := grpc.NewServer()
+//	synurang.RegisterPluginServices(s, plugin, pb.File_my_proto.Services().ByName("MyService"))
+//	s.Serve(lis)
+//
+// See cmd/synurang-plugin-serve for a ready-made command.
+
+package synurang
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// RegisterPluginServices registers a forwarding handler on s for every method
+// of services. Calls reach the plugin through its generic entry points, or
+// through Synurang_Invoke_<Name> and Synurang_Stream_<Name>_Open for older
+// plugins that export each service under its proto name.
+func RegisterPluginServices(s grpc.ServiceRegistrar, p *Plugin, services ...protoreflect.ServiceDescriptor) {
+	for _, sd := range services {
+		fwd := &pluginForwarder{
+			plugin:      p,
+			serviceName: string(sd.Name()),
+			conn:        NewPluginClientConn(p, string(sd.Name())),
+		}
+		s.RegisterService(fwd.serviceDesc(sd), fwd)
+	}
+}
+
+// pluginForwarder forwards the methods of one service to the plugin.
+type pluginForwarder struct {
+	plugin      *Plugin
+	serviceName string
+	conn        *PluginClientConn
+}
+
+func (f *pluginForwarder) serviceDesc(sd protoreflect.ServiceDescriptor) *grpc.ServiceDesc {
+	desc := &grpc.ServiceDesc{
+		ServiceName: string(sd.FullName()),
+		HandlerType: (*any)(nil),
+		Metadata:    sd.ParentFile().Path(),
+	}
+
+	methods := sd.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		md := methods.Get(i)
+		fullMethod := "/" + string(sd.FullName()) + "/" + string(md.Name())
+		if !md.IsStreamingClient() && !md.IsStreamingServer() {
+			desc.Methods = append(desc.Methods, grpc.MethodDesc{
+				MethodName: string(md.Name()),
+				Handler:    f.unaryHandler(md, fullMethod),
+			})
+			continue
+		}
+		desc.Streams = append(desc.Streams, grpc.StreamDesc{
+			StreamName:    string(md.Name()),
+			Handler:       f.streamHandler(md, fullMethod),
+			ServerStreams: md.IsStreamingServer(),
+			ClientStreams: md.IsStreamingClient(),
+		})
+	}
+	return desc
+}
+
+func (f *pluginForwarder) unaryHandler(md protoreflect.MethodDescriptor, fullMethod string) func(any, context.Context, func(any) error, grpc.UnaryServerInterceptor) (any, error) {
+	return func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+		in := dynamicpb.NewMessage(md.Input())
+		if err := dec(in); err != nil {
+			return nil, err
+		}
+		handler := func(ctx context.Context, req any) (any, error) {
+			out := dynamicpb.NewMessage(md.Output())
+			if err := f.conn.Invoke(ctx, fullMethod, req, out); err != nil {
+				return nil, err
+			}
+			return out, nil
+		}
+		if interceptor == nil {
+			return handler(ctx, in)
+		}
+		info := &grpc.UnaryServerInfo{Server: srv, FullMethod: fullMethod}
+		return interceptor(ctx, in, info, handler)
+	}
+}
+
+// streamHandler proxies all streaming kinds the same way: client messages are
+// pumped into the plugin stream (followed by CloseSend on EOF) while plugin
+// responses are sent back until the plugin reports EOF.
+func (f *pluginForwarder) streamHandler(md protoreflect.MethodDescriptor, fullMethod string) grpc.StreamHandler {
+	return func(srv any, ss grpc.ServerStream) error {
+		stream, err := f.plugin.OpenStreamContext(ss.Context(), f.serviceName, fullMethod)
+		if err != nil {
+			return err
+		}
+		defer stream.Close()
+
+		// Closing the plugin stream unblocks Recv when the client goes away
+		stop := context.AfterFunc(ss.Context(), func() { stream.Close() })
+		defer stop()
+
+		go func() {
+			for {
+				in := dynamicpb.NewMessage(md.Input())
+				if err := ss.RecvMsg(in); err != nil {
+					if errors.Is(err, io.EOF) {
+						stream.CloseSend()
+					} else {
+						stream.Close()
+					}
+					return
+				}
+				data, err := proto.Marshal(in)
+				if err != nil {
+					stream.Close()
+					return
+				}
+				if err := stream.Send(data); err != nil {
+					return
+				}
+			}
+		}()
+
+		for {
+			data, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return ss.Context().Err()
+			}
+			if err != nil {
+				return err
+			}
+			out := dynamicpb.NewMessage(md.Output())
+			if err := proto.Unmarshal(data, out); err != nil {
+				return err
+			}
+			if err := ss.SendMsg(out); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package synurang
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+// startPluginServer serves the health service of a mocked plugin over bufconn.
+func startPluginServer(t *testing.T, plugin *Plugin) healthpb.HealthClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	RegisterPluginServices(s, plugin, healthpb.File_grpc_health_v1_health_proto.Services().ByName("Health"))
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return healthpb.NewHealthClient(conn)
+}
+
+func TestRegisterPluginServices_Unary(t *testing.T) {
+	mock := newMockPlatform()
+	var gotMethod, gotService string
+	mock.symFunc = func(handle uintptr, name string) (uintptr, error) {
+		if name == "Synurang_Invoke_Health" {
+			gotService = "Health"
+		}
+		if name == "Synurang_InvokeAsync" {
+			// A plugin predating the async ABI, so the per-service symbol is used
+			return 0, errors.New("not found")
+		}
+		return 0x2000, nil
+	}
+	mock.invokeFunc = func(fn, freePtr uintptr, method string, data []byte) ([]byte, error) {
+		gotMethod = method
+		req := &healthpb.HealthCheckRequest{}
+		if err := proto.Unmarshal(data, req); err != nil || req.Service != "db" {
+			return append([]byte{1}, "bad request"...), nil
+		}
+		resp, _ := proto.Marshal(&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING})
+		return append([]byte{0}, resp...), nil
+	}
+	restore := mock.install()
+	defer restore()
+
+	plugin, err := LoadPlugin("test.so")
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+	defer plugin.Close()
+
+	client := startPluginServer(t, plugin)
+	resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "db"})
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("expected SERVING, got %v", resp.Status)
+	}
+	if gotMethod != "/grpc.health.v1.Health/Check" || gotService != "Health" {
+		t.Errorf("unexpected routing: service %q method %q", gotService, gotMethod)
+	}
+
+	if _, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "other"}); err == nil {
+		t.Error("expected plugin error to reach the client")
+	}
+}
+
+func TestRegisterPluginServices_ServerStream(t *testing.T) {
+	mock := newMockPlatform()
+	sent := make(chan []byte, 1)
+	var recvCount int
+	mock.streamSendFunc = func(fn uintptr, handle uint64, data []byte) int {
+		sent <- data
+		return 0
+	}
+	mock.streamRecvFunc = func(fn, freePtr uintptr, handle uint64) ([]byte, int, int) {
+		// Like a real server-streaming handler, wait for the request first
+		if recvCount == 0 {
+			req := &healthpb.HealthCheckRequest{}
+			if err := proto.Unmarshal(<-sent, req); err != nil || req.Service != "db" {
+				msg := []byte("unexpected forwarded request")
+				return msg, len(msg), 2
+			}
+		}
+		recvCount++
+		if recvCount > 2 {
+			return nil, 0, 1 // EOF
+		}
+		resp, _ := proto.Marshal(&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING})
+		data := append([]byte{0}, resp...)
+		return data, len(data), 0
+	}
+	restore := mock.install()
+	defer restore()
+
+	plugin, err := LoadPlugin("test.so")
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+	defer plugin.Close()
+
+	client := startPluginServer(t, plugin)
+	stream, err := client.Watch(context.Background(), &healthpb.HealthCheckRequest{Service: "db"})
+	if err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+	n := 0
+	for {
+		_, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv failed: %v", err)
+		}
+		n++
+	}
+	if n != 2 {
+		t.Errorf("expected 2 responses, got %d", n)
+	}
+}
@@ -0,0 +1,109 @@
+package synurang
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Push-based stream delivery.
+//
+// Plugins that export Synurang_Stream_Subscribe deliver stream results through
+// a host callback instead of a blocking Synurang_Stream_Recv call, so open
+// streams do not pin host OS threads. OpenStream subscribes automatically when
+// the symbol is present; the callback feeds a per-stream queue that
+// Plugin.StreamRecv reads from. Older plugins keep using the polling ABI.
+
+// streamEvent is one Synurang_Stream_Recv-equivalent result pushed by a plugin.
+type streamEvent struct {
+	data    []byte
+	respLen int
+	status  int
+}
+
+// streamQueue buffers pushed events for one stream. It is unbounded so the
+// plugin's callback never blocks.
+type streamQueue struct {
+	token  uint64
+	mu     sync.Mutex
+	events []streamEvent
+	done   bool
+	notify chan struct{}
+}
+
+func newStreamQueue() *streamQueue {
+	return &streamQueue{notify: make(chan struct{}, 1)}
+}
+
+func (q *streamQueue) push(ev streamEvent) {
+	q.mu.Lock()
+	q.events = append(q.events, ev)
+	q.mu.Unlock()
+	q.signal()
+}
+
+// pop blocks until an event is available. It returns false once the queue is
+// closed and drained.
+func (q *streamQueue) pop() (streamEvent, bool) {
+	for {
+		q.mu.Lock()
+		if len(q.events) > 0 {
+			ev := q.events[0]
+			q.events = q.events[1:]
+			q.mu.Unlock()
+			return ev, true
+		}
+		if q.done {
+			q.mu.Unlock()
+			return streamEvent{}, false
+		}
+		q.mu.Unlock()
+		<-q.notify
+	}
+}
+
+func (q *streamQueue) close() {
+	q.mu.Lock()
+	q.done = true
+	q.mu.Unlock()
+	q.signal()
+}
+
+func (q *streamQueue) signal() {
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
+
+var (
+	streamQueueCounter uint64
+	streamQueues       sync.Map // token -> *streamQueue
+)
+
+func registerStreamQueue(q *streamQueue) uint64 {
+	q.token = atomic.AddUint64(&streamQueueCounter, 1)
+	streamQueues.Store(q.token, q)
+	return q.token
+}
+
+func unregisterStreamQueue(q *streamQueue) {
+	streamQueues.Delete(q.token)
+}
+
+// dispatchStreamEvent is called by the platform stream callback.
+// Events for unknown tokens (streams already closed) are dropped.
+func dispatchStreamEvent(token uint64, status int, data []byte) {
+	val, ok := streamQueues.Load(token)
+	if !ok {
+		return
+	}
+	q := val.(*streamQueue)
+	q.push(streamEvent{data: data, respLen: len(data), status: status})
+
+	// EOF or a handler error ends the stream; nothing more will be pushed,
+	// so later receives return io.EOF as they do on the polling ABI
+	if status != 0 || (len(data) > 0 && data[0] == 1) {
+		unregisterStreamQueue(q)
+		q.close()
+	}
+}
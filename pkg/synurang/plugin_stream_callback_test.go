@@ -0,0 +1,178 @@
+package synurang
+
+import (
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// pushMock makes the mock plugin accept Synurang_Stream_Subscribe and records
+// the token so the test can push events as the plugin would.
+func pushMock(mock *mockPlatform) <-chan uint64 {
+	tokens := make(chan uint64, 16)
+	mock.streamSubscribeFunc = func(fn uintptr, handle uint64, callback uintptr, token uint64) int {
+		tokens <- token
+		return 0
+	}
+	mock.streamRecvFunc = func(fn, freePtr uintptr, handle uint64) ([]byte, int, int) {
+		panic("polling Recv used on a subscribed stream")
+	}
+	return tokens
+}
+
+func TestPluginStream_PushDelivery(t *testing.T) {
+	mock := newMockPlatform()
+	tokens := pushMock(mock)
+	restore := mock.install()
+	defer restore()
+
+	plugin, err := LoadPlugin("test.so")
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+	defer plugin.Close()
+
+	stream, err := plugin.OpenStream("TestService", "/test.Stream")
+	if err != nil {
+		t.Fatalf("OpenStream failed: %v", err)
+	}
+	token := <-tokens
+
+	go func() {
+		dispatchStreamEvent(token, 0, []byte{0, 'a'})
+		dispatchStreamEvent(token, 0, []byte{0, 'b'})
+		dispatchStreamEvent(token, 1, nil)
+	}()
+
+	for _, want := range []string{"a", "b"} {
+		data, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv failed: %v", err)
+		}
+		if string(data) != want {
+			t.Errorf("expected %q, got %q", want, data)
+		}
+	}
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+
+	// Receiving again after EOF must not block
+	done := make(chan error, 1)
+	go func() {
+		_, err := stream.Recv()
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != io.EOF {
+			t.Errorf("expected io.EOF again, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Recv after EOF blocked")
+	}
+}
+
+func TestPluginStream_PushDelivery_Error(t *testing.T) {
+	mock := newMockPlatform()
+	tokens := pushMock(mock)
+	restore := mock.install()
+	defer restore()
+
+	plugin, err := LoadPlugin("test.so")
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+	defer plugin.Close()
+
+	stream, err := plugin.OpenStream("TestService", "/test.Stream")
+	if err != nil {
+		t.Fatalf("OpenStream failed: %v", err)
+	}
+	dispatchStreamEvent(<-tokens, 0, append([]byte{1}, "handler failed"...))
+
+	_, err = stream.Recv()
+	var pluginErr *PluginError
+	if !errors.As(err, &pluginErr) || pluginErr.Message != "handler failed" {
+		t.Errorf("expected PluginError 'handler failed', got %v", err)
+	}
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Errorf("expected io.EOF after the error, got %v", err)
+	}
+}
+
+func TestPluginStream_PushDelivery_CloseUnblocksRecv(t *testing.T) {
+	mock := newMockPlatform()
+	pushMock(mock)
+	restore := mock.install()
+	defer restore()
+
+	plugin, err := LoadPlugin("test.so")
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+
+	stream, err := plugin.OpenStream("TestService", "/test.Stream")
+	if err != nil {
+		t.Fatalf("OpenStream failed: %v", err)
+	}
+
+	recvDone := make(chan error, 1)
+	go func() {
+		_, err := stream.Recv()
+		recvDone <- err
+	}()
+
+	// The plugin never pushes anything; closing the plugin must still wake Recv
+	time.Sleep(10 * time.Millisecond)
+	closeDone := make(chan struct{})
+	go func() {
+		plugin.Close()
+		close(closeDone)
+	}()
+
+	select {
+	case err := <-recvDone:
+		if err == nil {
+			t.Error("expected error from Recv after Close")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Recv still blocked after plugin Close")
+	}
+	select {
+	case <-closeDone:
+	case <-time.After(time.Second):
+		t.Fatal("Close blocked by a pending push-delivery Recv")
+	}
+}
+
+func TestPluginStream_PollingFallback(t *testing.T) {
+	mock := newMockPlatform()
+	var subscribeCalls int64
+	mock.streamSubscribeFunc = func(fn uintptr, handle uint64, callback uintptr, token uint64) int {
+		atomic.AddInt64(&subscribeCalls, 1)
+		return 0
+	}
+	restore := mock.install()
+	defer restore()
+
+	plugin, err := LoadPlugin("test.so", WithPollingStreams())
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+	defer plugin.Close()
+
+	stream, err := plugin.OpenStream("TestService", "/test.Stream")
+	if err != nil {
+		t.Fatalf("OpenStream failed: %v", err)
+	}
+	data, err := stream.Recv()
+	if err != nil || string(data) != "hi" {
+		t.Errorf("expected polled 'hi', got %q (%v)", data, err)
+	}
+	if atomic.LoadInt64(&subscribeCalls) != 0 {
+		t.Errorf("expected no subscribe with WithPollingStreams, got %d", subscribeCalls)
+	}
+}
@@ -0,0 +1,26 @@
+//go:build !windows
+
+package synurang
+
+/*
+extern void synurangHostStreamCallback(unsigned long long token, int status, char* data, int dataLen);
+*/
+import "C"
+
+import "unsafe"
+
+// synurangHostStreamCallback is the C callback handed to plugins via
+// Synurang_Stream_Subscribe. data is only valid during the call.
+//
+//export synurangHostStreamCallback
+func synurangHostStreamCallback(token C.ulonglong, status C.int, data *C.char, dataLen C.int) {
+	var d []byte
+	if data != nil && dataLen > 0 {
+		d = C.GoBytes(unsafe.Pointer(data), dataLen)
+	}
+	dispatchStreamEvent(uint64(token), int(status), d)
+}
+
+func unixStreamCallbackPtr() uintptr {
+	return uintptr(unsafe.Pointer(C.synurangHostStreamCallback))
+}
@@ -1,12 +1,16 @@
 package synurang
 
 import (
+	"context"
 	"errors"
 	"io"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
+
+	"google.golang.org/grpc"
 )
 
 // mockPlatform provides mock implementations for testing
@@ -15,21 +19,39 @@ type mockPlatform struct {
 	symFunc             func(handle uintptr, name string) (uintptr, error)
 	closeFunc           func(handle uintptr) error
 	invokeFunc          func(fn, freePtr uintptr, method string, data []byte) ([]byte, error)
+	invokeBatchFunc     func(fn, freePtr uintptr, data []byte) ([]byte, error)
+	invokeAsyncFunc     func(fn uintptr, callID uint64, method string, data []byte, callback uintptr) int
+	cancelCallFunc      func(fn uintptr, callID uint64)
+	initFunc            func(fn, freePtr uintptr, config []byte) ([]byte, error)
+	shutdownFunc        func(fn uintptr, timeoutMs int64) int
+	descriptorsFunc     func(fn, freePtr uintptr) ([]byte, error)
+	setLogSinkFunc      func(fn, sink uintptr, token uint64)
 	streamOpenFunc      func(fn uintptr, method string) uint64
+	streamSubscribeFunc func(fn uintptr, handle uint64, callback uintptr, token uint64) int
 	streamSendFunc      func(fn uintptr, handle uint64, data []byte) int
 	streamRecvFunc      func(fn, freePtr uintptr, handle uint64) ([]byte, int, int)
 	streamCloseSendFunc func(fn uintptr, handle uint64)
 	streamCloseFunc     func(fn uintptr, handle uint64)
 
+	// headers records the call headers of the ...WithHeader entry points,
+	// which otherwise behave like the plain ones.
+	headersMu sync.Mutex
+	headers   [][]byte
+
 	// Counters for verification
-	openCalls   int64
-	symCalls    int64
-	closeCalls  int64
-	invokeCalls int64
+	openCalls     int64
+	symCalls      int64
+	closeCalls    int64
+	invokeCalls   int64
+	batchCalls    int64
+	asyncCalls    int64
+	cancelCalls   int64
+	initCalls     int64
+	shutdownCalls int64
 }
 
 func newMockPlatform() *mockPlatform {
-	return &mockPlatform{
+	m := &mockPlatform{
 		openFunc: func(path string) (uintptr, error) {
 			return 0x1000, nil
 		},
@@ -43,9 +65,28 @@ func newMockPlatform() *mockPlatform {
 			// Return success with status byte 0
 			return append([]byte{0}, []byte("response")...), nil
 		},
+		invokeBatchFunc: func(fn, freePtr uintptr, data []byte) ([]byte, error) {
+			// Echo each call's method back as its response
+			return mockBatchResponse(data, func(method string, _ []byte) (byte, []byte) {
+				return 0, []byte(method)
+			}), nil
+		},
+		initFunc: func(fn, freePtr uintptr, config []byte) ([]byte, error) {
+			return []byte{0}, nil
+		},
+		shutdownFunc: func(fn uintptr, timeoutMs int64) int {
+			return 0
+		},
+		descriptorsFunc: func(fn, freePtr uintptr) ([]byte, error) {
+			return nil, nil // no embedded descriptors
+		},
+		setLogSinkFunc: func(fn, sink uintptr, token uint64) {},
 		streamOpenFunc: func(fn uintptr, method string) uint64 {
 			return 1
 		},
+		streamSubscribeFunc: func(fn uintptr, handle uint64, callback uintptr, token uint64) int {
+			return 1 // refuse, so streams use the polling mocks below
+		},
 		streamSendFunc: func(fn uintptr, handle uint64, data []byte) int {
 			return 0 // success
 		},
@@ -54,7 +95,20 @@ func newMockPlatform() *mockPlatform {
 		},
 		streamCloseSendFunc: func(fn uintptr, handle uint64) {},
 		streamCloseFunc:     func(fn uintptr, handle uint64) {},
+		cancelCallFunc:      func(fn uintptr, callID uint64) {},
 	}
+	// Like the plugin runtime: run invokeFunc on a goroutine and post the result
+	m.invokeAsyncFunc = func(fn uintptr, callID uint64, method string, data []byte, callback uintptr) int {
+		go func() {
+			result, err := m.invokeFunc(fn, 0, method, data)
+			if err != nil {
+				result = append([]byte{1}, err.Error()...)
+			}
+			dispatchAsyncResult(callID, result)
+		}()
+		return 0
+	}
+	return m
 }
 
 func (m *mockPlatform) install() func() {
@@ -62,7 +116,21 @@ func (m *mockPlatform) install() func() {
 	oldSym := platformSym
 	oldClose := platformClose
 	oldInvoke := platformInvoke
+	oldInvokeBatch := platformInvokeBatch
+	oldInvokeAsync := platformInvokeAsync
+	oldInvokeWithHeader := platformInvokeWithHeader
+	oldInvokeAsyncWithHeader := platformInvokeAsyncWithHeader
+	oldStreamOpenWithHeader := platformStreamOpenWithHeader
+	oldAsyncCallbackPtr := platformAsyncCallbackPtr
+	oldCancelCall := platformCancelCall
+	oldInit := platformInit
+	oldShutdown := platformShutdown
+	oldDescriptors := platformDescriptors
+	oldLogSinkPtr := platformLogSinkPtr
+	oldSetLogSink := platformSetLogSink
 	oldStreamOpen := platformStreamOpen
+	oldStreamSubscribe := platformStreamSubscribe
+	oldStreamCallbackPtr := platformStreamCallbackPtr
 	oldStreamSend := platformStreamSend
 	oldStreamRecv := platformStreamRecv
 	oldStreamCloseSend := platformStreamCloseSend
@@ -84,7 +152,46 @@ func (m *mockPlatform) install() func() {
 		atomic.AddInt64(&m.invokeCalls, 1)
 		return m.invokeFunc(fn, freePtr, method, data)
 	}
+	platformInvokeBatch = func(fn, freePtr uintptr, data []byte) ([]byte, error) {
+		atomic.AddInt64(&m.batchCalls, 1)
+		return m.invokeBatchFunc(fn, freePtr, data)
+	}
+	platformInvokeAsync = func(fn uintptr, callID uint64, method string, data []byte, callback uintptr) int {
+		atomic.AddInt64(&m.invokeCalls, 1)
+		atomic.AddInt64(&m.asyncCalls, 1)
+		return m.invokeAsyncFunc(fn, callID, method, data, callback)
+	}
+	platformInvokeWithHeader = func(fn, freePtr uintptr, method string, header, data []byte) ([]byte, error) {
+		m.recordHeader(header)
+		return platformInvoke(fn, freePtr, method, data)
+	}
+	platformInvokeAsyncWithHeader = func(fn uintptr, callID uint64, method string, header, data []byte, callback uintptr) int {
+		m.recordHeader(header)
+		return platformInvokeAsync(fn, callID, method, data, callback)
+	}
+	platformStreamOpenWithHeader = func(fn uintptr, method string, header []byte) uint64 {
+		m.recordHeader(header)
+		return platformStreamOpen(fn, method)
+	}
+	platformAsyncCallbackPtr = func() uintptr { return 0x5000 }
+	platformCancelCall = func(fn uintptr, callID uint64) {
+		atomic.AddInt64(&m.cancelCalls, 1)
+		m.cancelCallFunc(fn, callID)
+	}
+	platformInit = func(fn, freePtr uintptr, config []byte) ([]byte, error) {
+		atomic.AddInt64(&m.initCalls, 1)
+		return m.initFunc(fn, freePtr, config)
+	}
+	platformShutdown = func(fn uintptr, timeoutMs int64) int {
+		atomic.AddInt64(&m.shutdownCalls, 1)
+		return m.shutdownFunc(fn, timeoutMs)
+	}
+	platformDescriptors = m.descriptorsFunc
+	platformLogSinkPtr = func() uintptr { return 0x3000 }
+	platformSetLogSink = m.setLogSinkFunc
 	platformStreamOpen = m.streamOpenFunc
+	platformStreamSubscribe = m.streamSubscribeFunc
+	platformStreamCallbackPtr = func() uintptr { return 0x4000 }
 	platformStreamSend = m.streamSendFunc
 	platformStreamRecv = m.streamRecvFunc
 	platformStreamCloseSend = m.streamCloseSendFunc
@@ -95,7 +202,21 @@ func (m *mockPlatform) install() func() {
 		platformSym = oldSym
 		platformClose = oldClose
 		platformInvoke = oldInvoke
+		platformInvokeBatch = oldInvokeBatch
+		platformInvokeAsync = oldInvokeAsync
+		platformInvokeWithHeader = oldInvokeWithHeader
+		platformInvokeAsyncWithHeader = oldInvokeAsyncWithHeader
+		platformStreamOpenWithHeader = oldStreamOpenWithHeader
+		platformAsyncCallbackPtr = oldAsyncCallbackPtr
+		platformCancelCall = oldCancelCall
+		platformInit = oldInit
+		platformShutdown = oldShutdown
+		platformDescriptors = oldDescriptors
+		platformLogSinkPtr = oldLogSinkPtr
+		platformSetLogSink = oldSetLogSink
 		platformStreamOpen = oldStreamOpen
+		platformStreamSubscribe = oldStreamSubscribe
+		platformStreamCallbackPtr = oldStreamCallbackPtr
 		platformStreamSend = oldStreamSend
 		platformStreamRecv = oldStreamRecv
 		platformStreamCloseSend = oldStreamCloseSend
@@ -103,6 +224,12 @@ func (m *mockPlatform) install() func() {
 	}
 }
 
+func (m *mockPlatform) recordHeader(header []byte) {
+	m.headersMu.Lock()
+	m.headers = append(m.headers, header)
+	m.headersMu.Unlock()
+}
+
 func TestLoadPlugin_Success(t *testing.T) {
 	mock := newMockPlatform()
 	restore := mock.install()
@@ -121,12 +248,12 @@ func TestLoadPlugin_Success(t *testing.T) {
 		t.Error("expected non-zero freePtr")
 	}
 
-	// Should have called open once and sym once (for Synurang_Free)
+	// Should have called open once and sym twice (Synurang_Free, Synurang_Init)
 	if atomic.LoadInt64(&mock.openCalls) != 1 {
 		t.Errorf("expected 1 open call, got %d", mock.openCalls)
 	}
-	if atomic.LoadInt64(&mock.symCalls) != 1 {
-		t.Errorf("expected 1 sym call, got %d", mock.symCalls)
+	if atomic.LoadInt64(&mock.symCalls) != 2 {
+		t.Errorf("expected 2 sym calls, got %d", mock.symCalls)
 	}
 }
 
@@ -190,6 +317,142 @@ func TestPlugin_Close_Idempotent(t *testing.T) {
 	}
 }
 
+func TestLoadPlugin_InitReceivesConfig(t *testing.T) {
+	mock := newMockPlatform()
+	var got []byte
+	mock.initFunc = func(fn, freePtr uintptr, config []byte) ([]byte, error) {
+		got = append([]byte(nil), config...)
+		return []byte{0}, nil
+	}
+	restore := mock.install()
+	defer restore()
+
+	plugin, err := LoadPlugin("test.so", WithConfigBytes([]byte("cfg")))
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+	defer plugin.Close()
+
+	if string(got) != "cfg" {
+		t.Errorf("expected config %q, got %q", "cfg", got)
+	}
+}
+
+func TestLoadPlugin_InitFails(t *testing.T) {
+	mock := newMockPlatform()
+	mock.initFunc = func(fn, freePtr uintptr, config []byte) ([]byte, error) {
+		return append([]byte{1}, []byte("bad config")...), nil
+	}
+	restore := mock.install()
+	defer restore()
+
+	_, err := LoadPlugin("test.so", WithConfigBytes([]byte("cfg")))
+	if err == nil {
+		t.Fatal("expected error when init fails")
+	}
+	var pluginErr *PluginError
+	if !errors.As(err, &pluginErr) || pluginErr.Message != "bad config" {
+		t.Errorf("expected PluginError 'bad config', got %v", err)
+	}
+
+	// The library must be unloaded without running shutdown hooks
+	if atomic.LoadInt64(&mock.closeCalls) != 1 {
+		t.Errorf("expected 1 close call after init failure, got %d", mock.closeCalls)
+	}
+	if atomic.LoadInt64(&mock.shutdownCalls) != 0 {
+		t.Errorf("expected no shutdown call after init failure, got %d", mock.shutdownCalls)
+	}
+}
+
+func TestLoadPlugin_ConfigWithoutInit(t *testing.T) {
+	mock := newMockPlatform()
+	mock.symFunc = func(handle uintptr, name string) (uintptr, error) {
+		if name == "Synurang_Init" {
+			return 0, errors.New("symbol not found")
+		}
+		return 0x2000, nil
+	}
+	restore := mock.install()
+	defer restore()
+
+	if _, err := LoadPlugin("test.so", WithConfigBytes([]byte("cfg"))); err == nil {
+		t.Fatal("expected error when config is given to a plugin without Synurang_Init")
+	}
+	if atomic.LoadInt64(&mock.closeCalls) != 1 {
+		t.Errorf("expected 1 close call, got %d", mock.closeCalls)
+	}
+
+	// Without a config, a legacy plugin loads fine
+	plugin, err := LoadPlugin("test.so")
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+	plugin.Close()
+	if atomic.LoadInt64(&mock.initCalls) != 0 {
+		t.Errorf("expected no init call, got %d", mock.initCalls)
+	}
+}
+
+func TestPlugin_OnClose(t *testing.T) {
+	mock := newMockPlatform()
+	restore := mock.install()
+	defer restore()
+
+	plugin, err := LoadPlugin("test.so")
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+	var calls []string
+	plugin.OnClose(func() {
+		if atomic.LoadInt64(&mock.closeCalls) != 1 {
+			t.Error("OnClose function ran before the library was closed")
+		}
+		calls = append(calls, "first")
+	})
+	plugin.OnClose(func() { calls = append(calls, "second") })
+	if len(calls) != 0 {
+		t.Fatalf("OnClose functions ran before Close: %v", calls)
+	}
+
+	plugin.Close()
+	plugin.Close()
+	plugin.OnClose(func() { calls = append(calls, "late") })
+	if want := "first second late"; strings.Join(calls, " ") != want {
+		t.Errorf("calls = %v, want %s", calls, want)
+	}
+}
+
+func TestPlugin_Close_CallsShutdown(t *testing.T) {
+	mock := newMockPlatform()
+	var gotTimeout int64
+	mock.shutdownFunc = func(fn uintptr, timeoutMs int64) int {
+		gotTimeout = timeoutMs
+		return 2
+	}
+	restore := mock.install()
+	defer restore()
+
+	plugin, err := LoadPlugin("test.so", WithShutdownTimeout(250*time.Millisecond))
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+
+	if err := plugin.Close(); err == nil {
+		t.Error("expected error when shutdown times out")
+	}
+	if gotTimeout != 250 {
+		t.Errorf("expected timeout 250ms, got %d", gotTimeout)
+	}
+
+	// The library is unloaded even if shutdown fails
+	if atomic.LoadInt64(&mock.closeCalls) != 1 {
+		t.Errorf("expected 1 close call, got %d", mock.closeCalls)
+	}
+	if err := plugin.Close(); err != nil {
+		t.Errorf("second Close returned error: %v", err)
+	}
+}
+
 func TestPlugin_Invoke_Success(t *testing.T) {
 	mock := newMockPlatform()
 	mock.invokeFunc = func(fn, freePtr uintptr, method string, data []byte) ([]byte, error) {
@@ -285,6 +548,80 @@ func TestPlugin_Invoke_ServiceNotFound(t *testing.T) {
 	}
 }
 
+// genericOnlyMock exports only the generic routing entry points, as a plugin
+// whose service names are not valid C identifiers would.
+func genericOnlyMock(mock *mockPlatform) {
+	mock.symFunc = func(handle uintptr, name string) (uintptr, error) {
+		switch name {
+		case "Synurang_Invoke":
+			return 0x2100, nil
+		case "Synurang_Stream_Open":
+			return 0x2200, nil
+		}
+		if strings.HasSuffix(name, "_Open") || strings.HasPrefix(name, "Synurang_Invoke") {
+			return 0, errors.New("symbol not found")
+		}
+		return 0x2000, nil
+	}
+}
+
+func TestPlugin_Invoke_GenericEntryPoint(t *testing.T) {
+	mock := newMockPlatform()
+	genericOnlyMock(mock)
+	var gotFn uintptr
+	mock.invokeFunc = func(fn, freePtr uintptr, method string, data []byte) ([]byte, error) {
+		gotFn = fn
+		return []byte{0, 'o', 'k'}, nil
+	}
+	restore := mock.install()
+	defer restore()
+
+	plugin, err := LoadPlugin("test.so")
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+	defer plugin.Close()
+
+	// No service name: routed by method through Synurang_Invoke
+	if _, err := plugin.Invoke("", "/test.Service/Method", nil); err != nil || gotFn != 0x2100 {
+		t.Errorf("expected Synurang_Invoke to be called, got fn %#x (%v)", gotFn, err)
+	}
+
+	// Service name without a per-service symbol falls back to the generic one
+	gotFn = 0
+	if _, err := plugin.Invoke("TestService", "/test.Service/Method", nil); err != nil || gotFn != 0x2100 {
+		t.Errorf("expected fallback to Synurang_Invoke, got fn %#x (%v)", gotFn, err)
+	}
+}
+
+func TestPlugin_OpenStream_GenericEntryPoint(t *testing.T) {
+	mock := newMockPlatform()
+	genericOnlyMock(mock)
+	var gotFn uintptr
+	mock.streamOpenFunc = func(fn uintptr, method string) uint64 {
+		gotFn = fn
+		return 1
+	}
+	restore := mock.install()
+	defer restore()
+
+	plugin, err := LoadPlugin("test.so")
+	if err != nil {
+		t.Fatalf("LoadPlugin failed: %v", err)
+	}
+	defer plugin.Close()
+
+	conn := NewPluginClientConn(plugin, "")
+	stream, err := conn.NewStream(context.Background(), &grpc.StreamDesc{ServerStreams: true}, "/test.Service/Stream")
+	if err != nil {
+		t.Fatalf("NewStream failed: %v", err)
+	}
+	defer stream.CloseSend()
+	if gotFn != 0x2200 {
+		t.Errorf("expected Synurang_Stream_Open to be called, got fn %#x", gotFn)
+	}
+}
+
 func TestPlugin_Invoke_DataTooLarge(t *testing.T) {
 	mock := newMockPlatform()
 	restore := mock.install()
@@ -12,9 +12,22 @@ package synurang
 // Function pointer types matching Synurang exports
 typedef char* (*synurang_invoke_func)(char* method, char* data, int dataLen, int* respLen);
 typedef void (*synurang_free_func)(char* ptr);
+typedef char* (*synurang_invoke_batch_func)(char* data, int dataLen, int* respLen);
+typedef int (*synurang_invoke_async_func)(unsigned long long callId, char* method, char* data, int dataLen, void* callback);
+typedef void (*synurang_cancel_call_func)(unsigned long long callId);
+typedef char* (*synurang_invoke_header_func)(char* method, char* header, int headerLen, char* data, int dataLen, int* respLen);
+typedef int (*synurang_invoke_async_header_func)(unsigned long long callId, char* method, char* header, int headerLen, char* data, int dataLen, void* callback);
+
+// Lifecycle function pointer types
+typedef char* (*synurang_init_func)(char* config, int configLen, int* respLen);
+typedef int (*synurang_shutdown_func)(long long timeoutMs);
+typedef void (*synurang_set_log_sink_func)(void* sink, unsigned long long token);
+typedef char* (*synurang_descriptors_func)(int* respLen);
 
 // Streaming function pointer types
 typedef unsigned long long (*synurang_stream_open_func)(char* method);
+typedef unsigned long long (*synurang_stream_open_header_func)(char* method, char* header, int headerLen);
+typedef int (*synurang_stream_subscribe_func)(unsigned long long handle, void* callback, unsigned long long token);
 typedef int (*synurang_stream_send_func)(unsigned long long handle, char* data, int dataLen);
 typedef char* (*synurang_stream_recv_func)(unsigned long long handle, int* respLen, int* status);
 typedef void (*synurang_stream_close_send_func)(unsigned long long handle);
@@ -25,6 +38,50 @@ static char* call_invoke(void* fn, char* method, char* data, int dataLen, int* r
     return ((synurang_invoke_func)fn)(method, data, dataLen, respLen);
 }
 
+// Wrapper to call batch invoke function pointer
+static char* call_invoke_batch(void* fn, char* data, int dataLen, int* respLen) {
+    return ((synurang_invoke_batch_func)fn)(data, dataLen, respLen);
+}
+
+// Async invoke wrappers
+static int call_invoke_async(void* fn, unsigned long long callId, char* method, char* data, int dataLen, void* callback) {
+    return ((synurang_invoke_async_func)fn)(callId, method, data, dataLen, callback);
+}
+
+static void call_cancel_call(void* fn, unsigned long long callId) {
+    ((synurang_cancel_call_func)fn)(callId);
+}
+
+// Call header wrappers
+static char* call_invoke_header(void* fn, char* method, char* header, int headerLen, char* data, int dataLen, int* respLen) {
+    return ((synurang_invoke_header_func)fn)(method, header, headerLen, data, dataLen, respLen);
+}
+
+static int call_invoke_async_header(void* fn, unsigned long long callId, char* method, char* header, int headerLen, char* data, int dataLen, void* callback) {
+    return ((synurang_invoke_async_header_func)fn)(callId, method, header, headerLen, data, dataLen, callback);
+}
+
+static unsigned long long call_stream_open_header(void* fn, char* method, char* header, int headerLen) {
+    return ((synurang_stream_open_header_func)fn)(method, header, headerLen);
+}
+
+// Lifecycle wrappers
+static char* call_init(void* fn, char* config, int configLen, int* respLen) {
+    return ((synurang_init_func)fn)(config, configLen, respLen);
+}
+
+static int call_shutdown(void* fn, long long timeoutMs) {
+    return ((synurang_shutdown_func)fn)(timeoutMs);
+}
+
+static void call_set_log_sink(void* fn, void* sink, unsigned long long token) {
+    ((synurang_set_log_sink_func)fn)(sink, token);
+}
+
+static char* call_descriptors(void* fn, int* respLen) {
+    return ((synurang_descriptors_func)fn)(respLen);
+}
+
 // Wrapper to call free function pointer
 static void call_free(void* fn, char* ptr) {
     ((synurang_free_func)fn)(ptr);
@@ -35,6 +92,10 @@ static unsigned long long call_stream_open(void* fn, char* method) {
     return ((synurang_stream_open_func)fn)(method);
 }
 
+static int call_stream_subscribe(void* fn, unsigned long long handle, void* callback, unsigned long long token) {
+    return ((synurang_stream_subscribe_func)fn)(handle, callback, token);
+}
+
 static int call_stream_send(void* fn, unsigned long long handle, char* data, int dataLen) {
     return ((synurang_stream_send_func)fn)(handle, data, dataLen);
 }
@@ -63,7 +124,21 @@ func init() {
 	platformSym = unixSym
 	platformClose = unixClose
 	platformInvoke = unixInvoke
+	platformInvokeBatch = unixInvokeBatch
+	platformInvokeAsync = unixInvokeAsync
+	platformInvokeWithHeader = unixInvokeWithHeader
+	platformInvokeAsyncWithHeader = unixInvokeAsyncWithHeader
+	platformStreamOpenWithHeader = unixStreamOpenWithHeader
+	platformAsyncCallbackPtr = unixAsyncCallbackPtr
+	platformCancelCall = unixCancelCall
+	platformInit = unixInit
+	platformShutdown = unixShutdown
+	platformDescriptors = unixDescriptors
+	platformLogSinkPtr = unixLogSinkPtr
+	platformSetLogSink = unixSetLogSink
 	platformStreamOpen = unixStreamOpen
+	platformStreamSubscribe = unixStreamSubscribe
+	platformStreamCallbackPtr = unixStreamCallbackPtr
 	platformStreamSend = unixStreamSend
 	platformStreamRecv = unixStreamRecv
 	platformStreamCloseSend = unixStreamCloseSend
@@ -117,6 +192,117 @@ func unixInvoke(fn, freePtr uintptr, method string, data []byte) ([]byte, error)
 	return C.GoBytes(unsafe.Pointer(cResp), respLen), nil
 }
 
+func unixInvokeBatch(fn, freePtr uintptr, data []byte) ([]byte, error) {
+	var cData *C.char
+	if len(data) > 0 {
+		cData = (*C.char)(C.CBytes(data))
+		defer C.free(unsafe.Pointer(cData))
+	}
+
+	var respLen C.int
+	cResp := C.call_invoke_batch(unsafe.Pointer(fn), cData, C.int(len(data)), &respLen)
+	if cResp == nil {
+		return nil, fmt.Errorf("plugin returned nil")
+	}
+	defer C.call_free(unsafe.Pointer(freePtr), cResp)
+
+	return C.GoBytes(unsafe.Pointer(cResp), respLen), nil
+}
+
+func unixInvokeAsync(fn uintptr, callID uint64, method string, data []byte, callback uintptr) int {
+	cMethod := C.CString(method)
+	defer C.free(unsafe.Pointer(cMethod))
+
+	// The plugin copies method and data before returning
+	var cData *C.char
+	if len(data) > 0 {
+		cData = (*C.char)(C.CBytes(data))
+		defer C.free(unsafe.Pointer(cData))
+	}
+
+	return int(C.call_invoke_async(unsafe.Pointer(fn), C.ulonglong(callID), cMethod, cData, C.int(len(data)), unsafe.Pointer(callback)))
+}
+
+func unixInvokeWithHeader(fn, freePtr uintptr, method string, header, data []byte) ([]byte, error) {
+	cMethod := C.CString(method)
+	defer C.free(unsafe.Pointer(cMethod))
+	cHeader := cBytesOrNil(header)
+	defer C.free(unsafe.Pointer(cHeader))
+	cData := cBytesOrNil(data)
+	defer C.free(unsafe.Pointer(cData))
+
+	var respLen C.int
+	cResp := C.call_invoke_header(unsafe.Pointer(fn), cMethod, cHeader, C.int(len(header)), cData, C.int(len(data)), &respLen)
+	if cResp == nil {
+		return nil, fmt.Errorf("plugin returned nil")
+	}
+	defer C.call_free(unsafe.Pointer(freePtr), cResp)
+
+	return C.GoBytes(unsafe.Pointer(cResp), respLen), nil
+}
+
+func unixInvokeAsyncWithHeader(fn uintptr, callID uint64, method string, header, data []byte, callback uintptr) int {
+	cMethod := C.CString(method)
+	defer C.free(unsafe.Pointer(cMethod))
+
+	// The plugin copies method, header and data before returning
+	cHeader := cBytesOrNil(header)
+	defer C.free(unsafe.Pointer(cHeader))
+	cData := cBytesOrNil(data)
+	defer C.free(unsafe.Pointer(cData))
+
+	return int(C.call_invoke_async_header(unsafe.Pointer(fn), C.ulonglong(callID), cMethod, cHeader, C.int(len(header)), cData, C.int(len(data)), unsafe.Pointer(callback)))
+}
+
+// cBytesOrNil copies b into C memory, or returns nil if it is empty.
+// C.free accepts the nil.
+func cBytesOrNil(b []byte) *C.char {
+	if len(b) == 0 {
+		return nil
+	}
+	return (*C.char)(C.CBytes(b))
+}
+
+func unixCancelCall(fn uintptr, callID uint64) {
+	C.call_cancel_call(unsafe.Pointer(fn), C.ulonglong(callID))
+}
+
+func unixInit(fn, freePtr uintptr, config []byte) ([]byte, error) {
+	var cConfig *C.char
+	if len(config) > 0 {
+		cConfig = (*C.char)(C.CBytes(config))
+		defer C.free(unsafe.Pointer(cConfig))
+	}
+
+	var respLen C.int
+	cResp := C.call_init(unsafe.Pointer(fn), cConfig, C.int(len(config)), &respLen)
+	if cResp == nil {
+		return nil, fmt.Errorf("plugin returned nil")
+	}
+	defer C.call_free(unsafe.Pointer(freePtr), cResp)
+
+	return C.GoBytes(unsafe.Pointer(cResp), respLen), nil
+}
+
+func unixShutdown(fn uintptr, timeoutMs int64) int {
+	return int(C.call_shutdown(unsafe.Pointer(fn), C.longlong(timeoutMs)))
+}
+
+func unixDescriptors(fn, freePtr uintptr) ([]byte, error) {
+	var respLen C.int
+	cResp := C.call_descriptors(unsafe.Pointer(fn), &respLen)
+	if cResp == nil {
+		return nil, nil // nothing registered
+	}
+	defer C.call_free(unsafe.Pointer(freePtr), cResp)
+
+	return C.GoBytes(unsafe.Pointer(cResp), respLen), nil
+}
+
+func unixSetLogSink(fn, sink uintptr, token uint64) {
+	C.call_set_log_sink(unsafe.Pointer(fn), unsafe.Pointer(sink), C.ulonglong(token))
+}
+
 func unixStreamOpen(fn uintptr, method string) uint64 {
 	cMethod := C.CString(method)
 	defer C.free(unsafe.Pointer(cMethod))
@@ -124,6 +310,19 @@ func unixStreamOpen(fn uintptr, method string) uint64 {
 	return uint64(C.call_stream_open(unsafe.Pointer(fn), cMethod))
 }
 
+func unixStreamOpenWithHeader(fn uintptr, method string, header []byte) uint64 {
+	cMethod := C.CString(method)
+	defer C.free(unsafe.Pointer(cMethod))
+	cHeader := cBytesOrNil(header)
+	defer C.free(unsafe.Pointer(cHeader))
+
+	return uint64(C.call_stream_open_header(unsafe.Pointer(fn), cMethod, cHeader, C.int(len(header))))
+}
+
+func unixStreamSubscribe(fn uintptr, handle uint64, callback uintptr, token uint64) int {
+	return int(C.call_stream_subscribe(unsafe.Pointer(fn), C.ulonglong(handle), unsafe.Pointer(callback), C.ulonglong(token)))
+}
+
 func unixStreamSend(fn uintptr, handle uint64, data []byte) int {
 	var cData *C.char
 	if len(data) > 0 {
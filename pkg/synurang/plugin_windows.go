@@ -4,6 +4,7 @@ package synurang
 
 import (
 	"fmt"
+	"sync"
 	"syscall"
 	"unsafe"
 )
@@ -20,7 +21,21 @@ func init() {
 	platformSym = windowsSym
 	platformClose = windowsClose
 	platformInvoke = windowsInvoke
+	platformInvokeBatch = windowsInvokeBatch
+	platformInvokeAsync = windowsInvokeAsync
+	platformInvokeWithHeader = windowsInvokeWithHeader
+	platformInvokeAsyncWithHeader = windowsInvokeAsyncWithHeader
+	platformStreamOpenWithHeader = windowsStreamOpenWithHeader
+	platformAsyncCallbackPtr = windowsAsyncCallbackPtr
+	platformCancelCall = windowsCancelCall
+	platformInit = windowsInit
+	platformShutdown = windowsShutdown
+	platformDescriptors = windowsDescriptors
+	platformLogSinkPtr = windowsLogSinkPtr
+	platformSetLogSink = windowsSetLogSink
 	platformStreamOpen = windowsStreamOpen
+	platformStreamSubscribe = windowsStreamSubscribe
+	platformStreamCallbackPtr = windowsStreamCallbackPtr
 	platformStreamSend = windowsStreamSend
 	platformStreamRecv = windowsStreamRecv
 	platformStreamCloseSend = windowsStreamCloseSend
@@ -105,6 +120,253 @@ func windowsInvoke(fn, freePtr uintptr, method string, data []byte) ([]byte, err
 	return result, nil
 }
 
+// bytesPtr returns a pointer to a copy of b, or 0 if it is empty.
+func bytesPtr(b []byte) (uintptr, func()) {
+	if len(b) == 0 {
+		return 0, func() {}
+	}
+	c := make([]byte, len(b))
+	copy(c, b)
+	return uintptr(unsafe.Pointer(&c[0])), func() { /* prevent GC during call */ _ = c }
+}
+
+func windowsInvokeWithHeader(fn, freePtr uintptr, method string, header, data []byte) ([]byte, error) {
+	methodPtr, methodCleanup := cstring(method)
+	defer methodCleanup()
+	headerPtr, headerCleanup := bytesPtr(header)
+	defer headerCleanup()
+	dataPtr, dataCleanup := bytesPtr(data)
+	defer dataCleanup()
+
+	var respLen int32
+
+	// Call: char* invoke(char* method, char* header, int headerLen, char* data, int dataLen, int* respLen)
+	ret, _, _ := syscall.SyscallN(fn,
+		methodPtr,
+		headerPtr,
+		uintptr(len(header)),
+		dataPtr,
+		uintptr(len(data)),
+		uintptr(unsafe.Pointer(&respLen)),
+	)
+	if ret == 0 {
+		return nil, fmt.Errorf("plugin returned nil")
+	}
+
+	result := make([]byte, respLen)
+	for i := int32(0); i < respLen; i++ {
+		result[i] = *(*byte)(unsafe.Pointer(ret + uintptr(i)))
+	}
+	syscall.SyscallN(freePtr, ret)
+	return result, nil
+}
+
+func windowsInvokeAsyncWithHeader(fn uintptr, callID uint64, method string, header, data []byte, callback uintptr) int {
+	methodPtr, methodCleanup := cstring(method)
+	defer methodCleanup()
+	headerPtr, headerCleanup := bytesPtr(header)
+	defer headerCleanup()
+	dataPtr, dataCleanup := bytesPtr(data)
+	defer dataCleanup()
+
+	// Call: int invokeAsync(unsigned long long callId, char* method, char* header, int headerLen, char* data, int dataLen, void* callback)
+	ret, _, _ := syscall.SyscallN(fn,
+		uintptr(callID),
+		methodPtr,
+		headerPtr,
+		uintptr(len(header)),
+		dataPtr,
+		uintptr(len(data)),
+		callback,
+	)
+	return int(int32(ret))
+}
+
+func windowsStreamOpenWithHeader(fn uintptr, method string, header []byte) uint64 {
+	methodPtr, methodCleanup := cstring(method)
+	defer methodCleanup()
+	headerPtr, headerCleanup := bytesPtr(header)
+	defer headerCleanup()
+
+	// Call: unsigned long long open(char* method, char* header, int headerLen)
+	ret, _, _ := syscall.SyscallN(fn, methodPtr, headerPtr, uintptr(len(header)))
+	return uint64(ret)
+}
+
+func windowsInvokeBatch(fn, freePtr uintptr, data []byte) ([]byte, error) {
+	var dataPtr uintptr
+	dataLen := len(data)
+	if dataLen > 0 {
+		dataCopy := make([]byte, dataLen)
+		copy(dataCopy, data)
+		dataPtr = uintptr(unsafe.Pointer(&dataCopy[0]))
+		defer func() { _ = dataCopy }()
+	}
+
+	var respLen int32
+
+	// Call: char* invokeBatch(char* data, int dataLen, int* respLen)
+	ret, _, _ := syscall.SyscallN(fn,
+		dataPtr,
+		uintptr(dataLen),
+		uintptr(unsafe.Pointer(&respLen)),
+	)
+
+	if ret == 0 {
+		return nil, fmt.Errorf("plugin returned nil")
+	}
+
+	result := make([]byte, respLen)
+	for i := int32(0); i < respLen; i++ {
+		result[i] = *(*byte)(unsafe.Pointer(ret + uintptr(i)))
+	}
+
+	syscall.SyscallN(freePtr, ret)
+
+	return result, nil
+}
+
+func windowsInit(fn, freePtr uintptr, config []byte) ([]byte, error) {
+	var configPtr uintptr
+	configLen := len(config)
+	if configLen > 0 {
+		configCopy := make([]byte, configLen)
+		copy(configCopy, config)
+		configPtr = uintptr(unsafe.Pointer(&configCopy[0]))
+		defer func() { _ = configCopy }()
+	}
+
+	var respLen int32
+
+	// Call: char* init(char* config, int configLen, int* respLen)
+	ret, _, _ := syscall.SyscallN(fn,
+		configPtr,
+		uintptr(configLen),
+		uintptr(unsafe.Pointer(&respLen)),
+	)
+
+	if ret == 0 {
+		return nil, fmt.Errorf("plugin returned nil")
+	}
+
+	result := make([]byte, respLen)
+	for i := int32(0); i < respLen; i++ {
+		result[i] = *(*byte)(unsafe.Pointer(ret + uintptr(i)))
+	}
+
+	syscall.SyscallN(freePtr, ret)
+
+	return result, nil
+}
+
+func windowsInvokeAsync(fn uintptr, callID uint64, method string, data []byte, callback uintptr) int {
+	methodPtr, methodCleanup := cstring(method)
+	defer methodCleanup()
+
+	var dataPtr uintptr
+	dataLen := len(data)
+	if dataLen > 0 {
+		dataCopy := make([]byte, dataLen)
+		copy(dataCopy, data)
+		dataPtr = uintptr(unsafe.Pointer(&dataCopy[0]))
+		defer func() { _ = dataCopy }()
+	}
+
+	// Call: int invokeAsync(unsigned long long callId, char* method, char* data, int dataLen, void* callback)
+	ret, _, _ := syscall.SyscallN(fn,
+		uintptr(callID),
+		methodPtr,
+		dataPtr,
+		uintptr(dataLen),
+		callback,
+	)
+	return int(int32(ret))
+}
+
+var (
+	asyncCallbackOnce sync.Once
+	asyncCallback     uintptr
+)
+
+func windowsAsyncCallbackPtr() uintptr {
+	asyncCallbackOnce.Do(func() {
+		// void callback(unsigned long long callId, char* data, int dataLen)
+		asyncCallback = syscall.NewCallback(func(callID, data, dataLen uintptr) uintptr {
+			n := int(int32(dataLen))
+			var d []byte
+			if data != 0 && n > 0 {
+				d = make([]byte, n)
+				for i := 0; i < n; i++ {
+					d[i] = *(*byte)(unsafe.Pointer(data + uintptr(i)))
+				}
+			}
+			dispatchAsyncResult(uint64(callID), d)
+			return 0
+		})
+	})
+	return asyncCallback
+}
+
+func windowsCancelCall(fn uintptr, callID uint64) {
+	// Call: void cancelCall(unsigned long long callId)
+	syscall.SyscallN(fn, uintptr(callID))
+}
+
+func windowsShutdown(fn uintptr, timeoutMs int64) int {
+	// Call: int shutdown(long long timeoutMs)
+	ret, _, _ := syscall.SyscallN(fn, uintptr(timeoutMs))
+	return int(int32(ret))
+}
+
+func windowsDescriptors(fn, freePtr uintptr) ([]byte, error) {
+	var respLen int32
+
+	// Call: char* descriptors(int* respLen)
+	ret, _, _ := syscall.SyscallN(fn, uintptr(unsafe.Pointer(&respLen)))
+	if ret == 0 {
+		return nil, nil // nothing registered
+	}
+
+	result := make([]byte, respLen)
+	for i := int32(0); i < respLen; i++ {
+		result[i] = *(*byte)(unsafe.Pointer(ret + uintptr(i)))
+	}
+
+	syscall.SyscallN(freePtr, ret)
+
+	return result, nil
+}
+
+// logSinkCallback is created once; Windows callbacks are never released.
+var (
+	logSinkOnce     sync.Once
+	logSinkCallback uintptr
+)
+
+func windowsLogSinkPtr() uintptr {
+	logSinkOnce.Do(func() {
+		// void sink(unsigned long long token, int level, char* record, int recordLen)
+		logSinkCallback = syscall.NewCallback(func(token, level, record, recordLen uintptr) uintptr {
+			n := int(int32(recordLen))
+			var data []byte
+			if record != 0 && n > 0 {
+				data = make([]byte, n)
+				for i := 0; i < n; i++ {
+					data[i] = *(*byte)(unsafe.Pointer(record + uintptr(i)))
+				}
+			}
+			dispatchPluginLog(uint64(token), int(int32(level)), data)
+			return 0
+		})
+	})
+	return logSinkCallback
+}
+
+func windowsSetLogSink(fn, sink uintptr, token uint64) {
+	// Call: void setLogSink(void* sink, unsigned long long token)
+	syscall.SyscallN(fn, sink, uintptr(token))
+}
+
 func windowsStreamOpen(fn uintptr, method string) uint64 {
 	methodPtr, methodCleanup := cstring(method)
 	defer methodCleanup()
@@ -114,6 +376,36 @@ func windowsStreamOpen(fn uintptr, method string) uint64 {
 	return uint64(ret)
 }
 
+var (
+	streamCallbackOnce sync.Once
+	streamCallback     uintptr
+)
+
+func windowsStreamCallbackPtr() uintptr {
+	streamCallbackOnce.Do(func() {
+		// void callback(unsigned long long token, int status, char* data, int dataLen)
+		streamCallback = syscall.NewCallback(func(token, status, data, dataLen uintptr) uintptr {
+			n := int(int32(dataLen))
+			var d []byte
+			if data != 0 && n > 0 {
+				d = make([]byte, n)
+				for i := 0; i < n; i++ {
+					d[i] = *(*byte)(unsafe.Pointer(data + uintptr(i)))
+				}
+			}
+			dispatchStreamEvent(uint64(token), int(int32(status)), d)
+			return 0
+		})
+	})
+	return streamCallback
+}
+
+func windowsStreamSubscribe(fn uintptr, handle uint64, callback uintptr, token uint64) int {
+	// Call: int subscribe(unsigned long long handle, void* callback, unsigned long long token)
+	ret, _, _ := syscall.SyscallN(fn, uintptr(handle), callback, uintptr(token))
+	return int(int32(ret))
+}
+
 func windowsStreamSend(fn uintptr, handle uint64, data []byte) int {
 	var dataPtr uintptr
 	dataLen := len(data)
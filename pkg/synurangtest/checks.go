@@ -0,0 +1,456 @@
+package synurangtest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/ivere27/synurang/pkg/synurang"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// unknownMethodSuffix is appended to the service path to build a method name
+// no real plugin implements.
+const unknownMethodSuffix = "/__SynurangConformanceUnknown"
+
+// malformedRequest is not a valid protobuf encoding for any message
+// (field number 0 is reserved).
+var malformedRequest = []byte{0x00, 0xff, 0xff, 0xff}
+
+// skipError marks a check as not applicable.
+type skipError string
+
+func (e skipError) Error() string { return string(e) }
+
+// checkEnv carries the plugin and service under test.
+type checkEnv struct {
+	plugin  *synurang.Plugin
+	service protoreflect.ServiceDescriptor
+	opts    Options
+}
+
+func (e *checkEnv) symbolName() string {
+	return string(e.service.Name())
+}
+
+// run executes fn with the configured timeout and converts its error to a Result.
+// A check that times out is left running; the plugin is assumed to be hung.
+func (e *checkEnv) run(name, method string, fn func() error) Result {
+	res := Result{Service: string(e.service.FullName()), Method: method, Check: name}
+	start := time.Now()
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		done <- fn()
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-time.After(e.opts.Timeout):
+		err = fmt.Errorf("timed out after %v", e.opts.Timeout)
+	}
+	res.Duration = time.Since(start)
+
+	var skip skipError
+	switch {
+	case err == nil:
+		res.Outcome = Pass
+	case errors.As(err, &skip):
+		res.Outcome = Skip
+		res.Detail = skip.Error()
+	default:
+		res.Outcome = Fail
+		res.Detail = err.Error()
+	}
+	return res
+}
+
+// firstStreamingMethod returns a streaming method of the service, preferring
+// one that produces output before reading input (server streaming).
+func (e *checkEnv) firstStreamingMethod() protoreflect.MethodDescriptor {
+	var found protoreflect.MethodDescriptor
+	methods := e.service.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		md := methods.Get(i)
+		if md.IsStreamingServer() && !md.IsStreamingClient() {
+			return md
+		}
+		if found == nil && (md.IsStreamingServer() || md.IsStreamingClient()) {
+			found = md
+		}
+	}
+	return found
+}
+
+func (e *checkEnv) firstUnaryMethod() protoreflect.MethodDescriptor {
+	methods := e.service.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		md := methods.Get(i)
+		if !md.IsStreamingServer() && !md.IsStreamingClient() {
+			return md
+		}
+	}
+	return nil
+}
+
+// expectPluginError verifies err is a framed error from the plugin (status byte 1).
+func expectPluginError(err error, what string) error {
+	if err == nil {
+		return fmt.Errorf("%s succeeded, expected an error", what)
+	}
+	var pluginErr *synurang.PluginError
+	if !errors.As(err, &pluginErr) {
+		return fmt.Errorf("%s: expected framed plugin error, got %v", what, err)
+	}
+	if pluginErr.Message == "" {
+		return fmt.Errorf("%s: plugin error has empty message", what)
+	}
+	return nil
+}
+
+// drain receives until EOF or error and returns the number of messages and
+// the terminating error (nil for a clean EOF).
+func drain(stream *synurang.PluginStream, output protoreflect.MessageDescriptor) (int, error) {
+	n := 0
+	for {
+		data, err := stream.Recv()
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+		if err := proto.Unmarshal(data, dynamicpb.NewMessage(output)); err != nil {
+			return n, fmt.Errorf("message %d does not decode as %s: %v", n, output.FullName(), err)
+		}
+		n++
+	}
+}
+
+// =============================================================================
+// Service-level checks
+// =============================================================================
+
+var serviceChecks = []struct {
+	name string
+	fn   func(e *checkEnv) error
+}{
+	{"unary/unknown-method", checkUnaryUnknownMethod},
+	{"unary/malformed-request", checkUnaryMalformedRequest},
+	{"batch/framing", checkBatchFraming},
+	{"stream/unknown-method", checkStreamUnknownMethod},
+	{"stream/close-idempotent", checkStreamCloseIdempotent},
+	{"stream/close-unblocks-recv", checkStreamCloseUnblocksRecv},
+	{"stream/concurrent-open-close", checkStreamConcurrentOpenClose},
+}
+
+func checkUnaryUnknownMethod(e *checkEnv) error {
+	method := "/" + string(e.service.FullName()) + unknownMethodSuffix
+	_, err := e.plugin.Invoke(e.symbolName(), method, nil)
+	return expectPluginError(err, "unknown unary method")
+}
+
+func checkUnaryMalformedRequest(e *checkEnv) error {
+	md := e.firstUnaryMethod()
+	if md == nil {
+		return skipError("service has no unary methods")
+	}
+	_, err := e.plugin.Invoke(e.symbolName(), fullMethodName(md), malformedRequest)
+	return expectPluginError(err, "malformed request to "+fullMethodName(md))
+}
+
+func checkBatchFraming(e *checkEnv) error {
+	unknown := "/" + string(e.service.FullName()) + unknownMethodSuffix
+	calls := []synurang.BatchCall{{Method: unknown}, {Method: unknown, Data: malformedRequest}}
+	if md := e.firstUnaryMethod(); md != nil {
+		calls = append(calls, synurang.BatchCall{Method: fullMethodName(md), Data: malformedRequest})
+	}
+
+	results, err := e.plugin.InvokeBatch(e.symbolName(), calls)
+	if err != nil {
+		return fmt.Errorf("batch failed as a whole: %v", err)
+	}
+	if len(results) != len(calls) {
+		return fmt.Errorf("got %d results for %d calls", len(results), len(calls))
+	}
+	for i, r := range results {
+		if err := expectPluginError(r.Err, fmt.Sprintf("batch item %d", i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkStreamUnknownMethod(e *checkEnv) error {
+	if e.firstStreamingMethod() == nil {
+		return skipError("service has no streaming methods")
+	}
+	method := "/" + string(e.service.FullName()) + unknownMethodSuffix
+	stream, err := e.plugin.OpenStream(e.symbolName(), method)
+	if err != nil {
+		// Refusing to open is an acceptable answer.
+		return nil
+	}
+	defer stream.Close()
+
+	_, err = stream.Recv()
+	if err == io.EOF {
+		return fmt.Errorf("unknown stream method ended with EOF instead of an error")
+	}
+	return expectPluginError(err, "unknown stream method")
+}
+
+func checkStreamCloseIdempotent(e *checkEnv) error {
+	md := e.firstStreamingMethod()
+	if md == nil {
+		return skipError("service has no streaming methods")
+	}
+	stream, err := e.plugin.OpenStream(e.symbolName(), fullMethodName(md))
+	if err != nil {
+		return fmt.Errorf("open %s: %v", fullMethodName(md), err)
+	}
+	defer stream.Close()
+
+	handle := stream.Handle()
+	for i := 0; i < 3; i++ {
+		if err := e.plugin.ForceStreamClose(handle); err != nil {
+			return fmt.Errorf("Synurang_Stream_Close #%d: %v", i+1, err)
+		}
+	}
+	// A handle that was never issued must be ignored as well.
+	if err := e.plugin.ForceStreamClose(^uintptr(0) >> 1); err != nil {
+		return fmt.Errorf("Synurang_Stream_Close on unknown handle: %v", err)
+	}
+
+	// The closed handle must now be rejected without blocking.
+	if data, err := e.plugin.StreamRecv(handle); err == nil {
+		return fmt.Errorf("Recv on closed handle returned %d bytes of data", len(data))
+	}
+	if err := e.plugin.StreamSend(handle, nil); err == nil {
+		return fmt.Errorf("Send on closed handle succeeded")
+	}
+	return nil
+}
+
+func checkStreamCloseUnblocksRecv(e *checkEnv) error {
+	md := e.firstStreamingMethod()
+	if md == nil {
+		return skipError("service has no streaming methods")
+	}
+	stream, err := e.plugin.OpenStream(e.symbolName(), fullMethodName(md))
+	if err != nil {
+		return fmt.Errorf("open %s: %v", fullMethodName(md), err)
+	}
+
+	// No request is sent, so a well-behaved handler blocks waiting for input
+	// and Recv blocks waiting for output until the stream is closed.
+	recvDone := make(chan error, 1)
+	go func() {
+		_, err := stream.Recv()
+		recvDone <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	stream.Close()
+
+	select {
+	case err := <-recvDone:
+		if err == nil {
+			return fmt.Errorf("Recv returned data on a stream that was never fed")
+		}
+		return nil
+	case <-time.After(e.opts.Timeout / 2):
+		return fmt.Errorf("Recv still blocked after Synurang_Stream_Close")
+	}
+}
+
+func checkStreamConcurrentOpenClose(e *checkEnv) error {
+	md := e.firstStreamingMethod()
+	if md == nil {
+		return skipError("service has no streaming methods")
+	}
+
+	const workers = 16
+	const iterations = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				stream, err := e.plugin.OpenStream(e.symbolName(), fullMethodName(md))
+				if err != nil {
+					errs <- fmt.Errorf("open: %v", err)
+					return
+				}
+				// Race Recv against Close.
+				go stream.Recv()
+				stream.Close()
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	return <-errs
+}
+
+// =============================================================================
+// Method-level checks
+// =============================================================================
+
+var methodChecks = []struct {
+	name    string
+	applies func(md protoreflect.MethodDescriptor) bool
+	invokes bool
+	fn      func(e *checkEnv, md protoreflect.MethodDescriptor) error
+}{
+	{"unary/empty-request", isUnary, true, checkUnaryEmptyRequest},
+	{"stream/eof", isStreaming, true, checkStreamEOF},
+	{"stream/send-after-close-send", isClientStreaming, false, checkStreamSendAfterCloseSend},
+	{"stream/concurrent-send-recv", isBidi, true, checkStreamConcurrentSendRecv},
+}
+
+func isUnary(md protoreflect.MethodDescriptor) bool {
+	return !md.IsStreamingServer() && !md.IsStreamingClient()
+}
+
+func isStreaming(md protoreflect.MethodDescriptor) bool {
+	return !isUnary(md)
+}
+
+func isClientStreaming(md protoreflect.MethodDescriptor) bool {
+	return md.IsStreamingClient()
+}
+
+func isBidi(md protoreflect.MethodDescriptor) bool {
+	return md.IsStreamingClient() && md.IsStreamingServer()
+}
+
+// checkUnaryEmptyRequest invokes the method with an empty (default) request.
+// Either a framed error or a response decoding as the output type is accepted.
+func checkUnaryEmptyRequest(e *checkEnv, md protoreflect.MethodDescriptor) error {
+	data, err := e.plugin.Invoke(e.symbolName(), fullMethodName(md), nil)
+	if err != nil {
+		var pluginErr *synurang.PluginError
+		if errors.As(err, &pluginErr) {
+			return nil
+		}
+		return fmt.Errorf("expected response or framed plugin error, got %v", err)
+	}
+	if err := proto.Unmarshal(data, dynamicpb.NewMessage(md.Output())); err != nil {
+		return fmt.Errorf("response does not decode as %s: %v", md.Output().FullName(), err)
+	}
+	return nil
+}
+
+// checkStreamEOF verifies the stream terminates after the host half-closes:
+// server streams after their single request, client streams with exactly one
+// response, bidi streams once the handler observes EOF.
+func checkStreamEOF(e *checkEnv, md protoreflect.MethodDescriptor) error {
+	stream, err := e.plugin.OpenStream(e.symbolName(), fullMethodName(md))
+	if err != nil {
+		return fmt.Errorf("open: %v", err)
+	}
+	defer stream.Close()
+
+	if !md.IsStreamingClient() {
+		if err := stream.Send(nil); err != nil {
+			return fmt.Errorf("send initial request: %v", err)
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		return fmt.Errorf("CloseSend: %v", err)
+	}
+
+	n, err := drain(stream, md.Output())
+	if err != nil {
+		var pluginErr *synurang.PluginError
+		if errors.As(err, &pluginErr) {
+			// Handler rejected the empty request; the stream still terminated.
+			return nil
+		}
+		return err
+	}
+	if md.IsStreamingClient() && !md.IsStreamingServer() && n != 1 {
+		return fmt.Errorf("client stream produced %d responses, expected 1", n)
+	}
+
+	// EOF must be sticky.
+	if _, err := stream.Recv(); err != io.EOF {
+		return fmt.Errorf("Recv after EOF returned %v, expected io.EOF", err)
+	}
+	return nil
+}
+
+func checkStreamSendAfterCloseSend(e *checkEnv, md protoreflect.MethodDescriptor) error {
+	stream, err := e.plugin.OpenStream(e.symbolName(), fullMethodName(md))
+	if err != nil {
+		return fmt.Errorf("open: %v", err)
+	}
+	defer stream.Close()
+
+	if err := stream.CloseSend(); err != nil {
+		return fmt.Errorf("CloseSend: %v", err)
+	}
+	if err := stream.CloseSend(); err != nil {
+		return fmt.Errorf("second CloseSend: %v", err)
+	}
+	if err := stream.Send(nil); err == nil {
+		return fmt.Errorf("Send after CloseSend succeeded")
+	}
+	return nil
+}
+
+// checkStreamConcurrentSendRecv runs several bidi streams in parallel, each
+// with a sender and a receiver goroutine, and requires all to terminate.
+func checkStreamConcurrentSendRecv(e *checkEnv, md protoreflect.MethodDescriptor) error {
+	const streams = 4
+	const messages = 32
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*streams)
+	for s := 0; s < streams; s++ {
+		stream, err := e.plugin.OpenStream(e.symbolName(), fullMethodName(md))
+		if err != nil {
+			return fmt.Errorf("open: %v", err)
+		}
+		defer stream.Close()
+
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < messages; i++ {
+				if err := stream.Send(nil); err != nil {
+					// The handler may legitimately end the stream early.
+					break
+				}
+			}
+			if err := stream.CloseSend(); err != nil {
+				errs <- fmt.Errorf("CloseSend: %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := drain(stream, md.Output()); err != nil {
+				var pluginErr *synurang.PluginError
+				if !errors.As(err, &pluginErr) {
+					errs <- err
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	return <-errs
+}
@@ -0,0 +1,191 @@
+// Package synurangtest checks that a loaded plugin shared library obeys the
+// Synurang plugin contract.
+//
+// The checks exercise the C ABI through synurang.Plugin rather than through
+// generated clients, so they apply to any plugin regardless of the language
+// or generator it was built with. They cover error framing, unknown methods,
+// stream EOF semantics, Synurang_Stream_Close idempotency and concurrent
+// Send/Recv on a stream.
+//
+// Usage from a Go test:
+//
+//	func TestPluginConformance(t *testing.T) {
+//	    plugin, err := synurang.LoadPlugin("./plugin.so")
+//	    if err != nil {
+//	        t.Fatal(err)
+//	    }
+//	    defer plugin.Close()
+//
+//	    svc := pb.File_my_proto.Services().ByName("MyService")
+//	    synurangtest.Check(t, plugin, svc)
+//	}
+//
+// The same battery is available from the command line as synurang-plugin-check.
+package synurangtest
+
+import (
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/ivere27/synurang/pkg/synurang"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Options controls which checks are run.
+type Options struct {
+	// Timeout bounds every individual check. A check that does not finish in
+	// time is reported as failed (the plugin is considered hung). Defaults to 5s.
+	Timeout time.Duration
+
+	// SkipInvoke restricts the battery to checks that never reach a handler
+	// (unknown methods, malformed requests, stream lifecycle). Use it for
+	// plugins whose methods have side effects even on empty requests.
+	SkipInvoke bool
+}
+
+// Outcome is the result of a single check.
+type Outcome int
+
+const (
+	Pass Outcome = iota
+	Fail
+	Skip
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case Pass:
+		return "PASS"
+	case Fail:
+		return "FAIL"
+	case Skip:
+		return "SKIP"
+	default:
+		return fmt.Sprintf("Outcome(%d)", int(o))
+	}
+}
+
+// Result describes the outcome of one check against one service or method.
+type Result struct {
+	Service  string // full service name
+	Method   string // full method name, empty for service-level checks
+	Check    string
+	Outcome  Outcome
+	Detail   string // failure or skip reason
+	Duration time.Duration
+}
+
+// Report collects the results of a conformance run.
+type Report struct {
+	Results []Result
+}
+
+// Count returns the number of results with the given outcome.
+func (r *Report) Count(o Outcome) int {
+	n := 0
+	for _, res := range r.Results {
+		if res.Outcome == o {
+			n++
+		}
+	}
+	return n
+}
+
+// Failed reports whether any check failed.
+func (r *Report) Failed() bool {
+	return r.Count(Fail) > 0
+}
+
+// WriteTo writes a human-readable pass/fail report to w.
+func (r *Report) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+	write := func(format string, args ...any) error {
+		n, err := fmt.Fprintf(w, format, args...)
+		total += int64(n)
+		return err
+	}
+
+	for _, res := range r.Results {
+		target := res.Service
+		if res.Method != "" {
+			target = res.Method
+		}
+		if err := write("%s  %-32s %s (%v)\n", res.Outcome, res.Check, target, res.Duration.Round(time.Microsecond)); err != nil {
+			return total, err
+		}
+		if res.Detail != "" {
+			if err := write("      %s\n", res.Detail); err != nil {
+				return total, err
+			}
+		}
+	}
+	err := write("--- %d passed, %d failed, %d skipped\n", r.Count(Pass), r.Count(Fail), r.Count(Skip))
+	return total, err
+}
+
+// Run executes the conformance battery against p for each service.
+// The services must be implemented by the plugin under their proto names,
+// i.e. exported as Synurang_Invoke_<Name> and Synurang_Stream_<Name>_Open.
+func Run(p *synurang.Plugin, services []protoreflect.ServiceDescriptor, opts Options) *Report {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Second
+	}
+
+	report := &Report{}
+	for _, sd := range services {
+		env := &checkEnv{plugin: p, service: sd, opts: opts}
+
+		for _, c := range serviceChecks {
+			report.Results = append(report.Results, env.run(c.name, "", func() error {
+				return c.fn(env)
+			}))
+		}
+
+		methods := sd.Methods()
+		for i := 0; i < methods.Len(); i++ {
+			md := methods.Get(i)
+			for _, c := range methodChecks {
+				if !c.applies(md) {
+					continue
+				}
+				if opts.SkipInvoke && c.invokes {
+					report.Results = append(report.Results, Result{
+						Service: string(sd.FullName()),
+						Method:  fullMethodName(md),
+						Check:   c.name,
+						Outcome: Skip,
+						Detail:  "invokes handler (SkipInvoke set)",
+					})
+					continue
+				}
+				report.Results = append(report.Results, env.run(c.name, fullMethodName(md), func() error {
+					return c.fn(env, md)
+				}))
+			}
+		}
+	}
+	return report
+}
+
+// Check runs the conformance battery and reports each failed check as a test error.
+func Check(t testing.TB, p *synurang.Plugin, services ...protoreflect.ServiceDescriptor) {
+	t.Helper()
+	report := Run(p, services, Options{})
+	for _, res := range report.Results {
+		if res.Outcome != Fail {
+			continue
+		}
+		target := res.Service
+		if res.Method != "" {
+			target = res.Method
+		}
+		t.Errorf("%s %s: %s", res.Check, target, res.Detail)
+	}
+}
+
+// fullMethodName returns the gRPC method path, e.g. "/pkg.Service/Method".
+func fullMethodName(md protoreflect.MethodDescriptor) string {
+	return "/" + string(md.Parent().FullName()) + "/" + string(md.Name())
+}
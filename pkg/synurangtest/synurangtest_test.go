@@ -0,0 +1,59 @@
+package synurangtest
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReport_Counts(t *testing.T) {
+	r := &Report{Results: []Result{
+		{Check: "a", Outcome: Pass},
+		{Check: "b", Outcome: Fail, Detail: "broken"},
+		{Check: "c", Outcome: Skip},
+		{Check: "d", Outcome: Pass},
+	}}
+
+	if r.Count(Pass) != 2 || r.Count(Fail) != 1 || r.Count(Skip) != 1 {
+		t.Errorf("unexpected counts: pass=%d fail=%d skip=%d", r.Count(Pass), r.Count(Fail), r.Count(Skip))
+	}
+	if !r.Failed() {
+		t.Error("expected Failed() to be true")
+	}
+}
+
+func TestReport_WriteTo(t *testing.T) {
+	r := &Report{Results: []Result{
+		{Service: "pkg.Svc", Check: "unary/unknown-method", Outcome: Pass, Duration: time.Millisecond},
+		{Service: "pkg.Svc", Method: "/pkg.Svc/Stream", Check: "stream/eof", Outcome: Fail, Detail: "timed out"},
+	}}
+
+	var buf bytes.Buffer
+	n, err := r.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("WriteTo returned %d, wrote %d bytes", n, buf.Len())
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		"PASS  unary/unknown-method",
+		"FAIL  stream/eof",
+		"/pkg.Svc/Stream",
+		"timed out",
+		"--- 1 passed, 1 failed, 0 skipped",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestOutcome_String(t *testing.T) {
+	if Pass.String() != "PASS" || Fail.String() != "FAIL" || Skip.String() != "SKIP" {
+		t.Errorf("unexpected outcome strings: %s %s %s", Pass, Fail, Skip)
+	}
+}
@@ -121,6 +121,52 @@ pub extern "C" fn StopGrpcServer() -> i32 {
     0
 }
 
+/// Returns an empty `core.v1.ShutdownReport`: the Rust backend has no calls
+/// to drain.
+#[no_mangle]
+pub extern "C" fn StopGrpcServerWithTimeout(_timeout_ms: i64) -> FfiData {
+    FfiData::empty()
+}
+
+/// Returns `core.v1.ServerStatus{running: true}`: the Rust backend serves
+/// over FFI only and has no listeners or cache.
+#[no_mangle]
+pub extern "C" fn GetServerStatus() -> FfiData {
+    FfiData::from_vec(vec![0x08, 1])
+}
+
+/// Returns an empty `core.v1.ServiceList`: the Rust backend does not
+/// describe its services yet.
+#[no_mangle]
+pub extern "C" fn ListServices() -> FfiData {
+    FfiData::empty()
+}
+
+/// Returns empty data, as for a method no service has.
+#[no_mangle]
+pub extern "C" fn DescribeMethod(_method: *const c_char) -> FfiData {
+    FfiData::empty()
+}
+
+/// Returns `core.v1.HealthReport{status: "SERVING"}`: the Rust backend has
+/// no cache, view connection or plugins to degrade.
+#[no_mangle]
+pub extern "C" fn GetHealth() -> FfiData {
+    FfiData::from_vec(b"\x0a\x07SERVING".to_vec())
+}
+
+#[no_mangle]
+pub extern "C" fn StartGrpcServerWithStatus(arg: CoreArgument, _fail_on_listen_error: i32) -> FfiData {
+    StartGrpcServer(arg);
+    GetServerStatus()
+}
+
+type ServeErrorCallback = extern "C" fn(*mut c_void, i64);
+
+/// Without listeners there are no serve errors to report.
+#[no_mangle]
+pub extern "C" fn RegisterServeErrorCallback(_callback: Option<ServeErrorCallback>) {}
+
 // =============================================================================
 // FFI Exports - Unary Invocation (Dart → Rust) - ZERO-COPY
 // =============================================================================
@@ -163,6 +209,112 @@ pub extern "C" fn InvokeBackendWithMeta(
     InvokeBackend(method, data, len)
 }
 
+#[no_mangle]
+pub extern "C" fn InvokeBackendWithHeader(
+    method: *const c_char,
+    data: *const c_void,
+    len: i64,
+    _header: *const c_void,
+    _header_len: i64,
+) -> FfiData {
+    // Call header not used in Rust backend yet
+    InvokeBackend(method, data, len)
+}
+
+/// Like `InvokeBackendWithHeader`, but returns a serialized
+/// `core.v1.CallResponse`. Rust services set no header or trailer metadata.
+#[no_mangle]
+pub extern "C" fn InvokeBackendCall(
+    method: *const c_char,
+    data: *const c_void,
+    len: i64,
+    _header: *const c_void,
+    _header_len: i64,
+) -> FfiData {
+    let method_str = unsafe { c_str_to_str(method) };
+    let data_slice = if data.is_null() || len <= 0 {
+        &[]
+    } else {
+        unsafe { slice::from_raw_parts(data as *const u8, len as usize) }
+    };
+    FfiData::from_vec(call_response(method_str, data_slice))
+}
+
+/// Runs a call and encodes its `core.v1.CallResponse`.
+fn call_response(method: &str, data: &[u8]) -> Vec<u8> {
+    let guard = SERVICE.lock().unwrap();
+    let result = match *guard {
+        Some(ref service) => service.invoke(method, data),
+        None => Err("service not registered".to_string()),
+    };
+    let mut out = Vec::new();
+    match result {
+        // CallResponse.payload
+        Ok(payload) => put_bytes(&mut out, 1, &payload),
+        Err(msg) => {
+            // CallResponse.error = Error{message, grpc_code: UNKNOWN}
+            let mut err = Vec::new();
+            put_bytes(&mut err, 2, msg.as_bytes());
+            err.extend_from_slice(&[0x18, 2]);
+            put_bytes(&mut out, 2, &err);
+        }
+    }
+    out
+}
+
+type CallCompleteCallback = extern "C" fn(i64, *mut c_void, i64);
+
+static CALL_COMPLETE_CALLBACK: Mutex<Option<CallCompleteCallback>> = Mutex::new(None);
+
+#[no_mangle]
+pub extern "C" fn RegisterCallCompleteCallback(callback: Option<CallCompleteCallback>) {
+    *CALL_COMPLETE_CALLBACK.lock().unwrap() = callback;
+}
+
+/// Runs the call on its own thread and delivers the `core.v1.CallResponse`
+/// to the completion callback. Returns 1 if no callback is registered.
+#[no_mangle]
+pub extern "C" fn InvokeBackendAsync(
+    call_id: i64,
+    method: *const c_char,
+    data: *const c_void,
+    len: i64,
+    _header: *const c_void,
+    _header_len: i64,
+) -> i32 {
+    let Some(callback) = *CALL_COMPLETE_CALLBACK.lock().unwrap() else {
+        return 1;
+    };
+    // Copy the request: the caller frees its buffers when we return
+    let method_str = unsafe { c_str_to_str(method) }.to_string();
+    let data_vec = if data.is_null() || len <= 0 {
+        Vec::new()
+    } else {
+        unsafe { slice::from_raw_parts(data as *const u8, len as usize) }.to_vec()
+    };
+    std::thread::spawn(move || {
+        let resp = FfiData::from_vec(call_response(&method_str, &data_vec));
+        callback(call_id, resp.data, resp.len);
+    });
+    0
+}
+
+/// Rust services take no context, so calls run to completion.
+#[no_mangle]
+pub extern "C" fn CancelBackendCall(_call_id: i64) {}
+
+/// Appends a length-delimited protobuf field.
+fn put_bytes(out: &mut Vec<u8>, field: u8, value: &[u8]) {
+    out.push(field << 3 | 2);
+    let mut n = value.len();
+    while n > 0x7f {
+        out.push((n as u8 & 0x7f) | 0x80);
+        n >>= 7;
+    }
+    out.push(n as u8);
+    out.extend_from_slice(value);
+}
+
 // =============================================================================
 // FFI Exports - Memory Management
 // =============================================================================
@@ -223,6 +375,48 @@ pub extern "C" fn InvokeBackendBidiStream(_method: *const c_char) -> i64 {
     -1 
 }
 
+#[no_mangle]
+pub extern "C" fn InvokeBackendServerStreamWithMeta(
+    method: *const c_char,
+    data: *const c_void,
+    len: i64,
+    _meta: *const c_void,
+    _meta_len: i64,
+) -> i64 {
+    InvokeBackendServerStream(method, data, len)
+}
+
+#[no_mangle]
+pub extern "C" fn InvokeBackendClientStreamWithMeta(method: *const c_char, _meta: *const c_void, _meta_len: i64) -> i64 {
+    InvokeBackendClientStream(method)
+}
+
+#[no_mangle]
+pub extern "C" fn InvokeBackendBidiStreamWithMeta(method: *const c_char, _meta: *const c_void, _meta_len: i64) -> i64 {
+    InvokeBackendBidiStream(method)
+}
+
+#[no_mangle]
+pub extern "C" fn InvokeBackendServerStreamWithHeader(
+    method: *const c_char,
+    data: *const c_void,
+    len: i64,
+    _header: *const c_void,
+    _header_len: i64,
+) -> i64 {
+    InvokeBackendServerStream(method, data, len)
+}
+
+#[no_mangle]
+pub extern "C" fn InvokeBackendClientStreamWithHeader(method: *const c_char, _header: *const c_void, _header_len: i64) -> i64 {
+    InvokeBackendClientStream(method)
+}
+
+#[no_mangle]
+pub extern "C" fn InvokeBackendBidiStreamWithHeader(method: *const c_char, _header: *const c_void, _header_len: i64) -> i64 {
+    InvokeBackendBidiStream(method)
+}
+
 #[no_mangle]
 pub extern "C" fn SendStreamData(_stream_id: i64, _data: *const c_void, _len: i64) -> i32 { 
     // TODO: Implement stream data sending
@@ -33,6 +33,61 @@ int StopGrpcServer() {
     return 0;
 }
 
+// Nothing runs long in this mock: an empty core.v1.ShutdownReport
+struct FfiData StopGrpcServerWithTimeout(long long timeoutMs) {
+    StopGrpcServer();
+    struct FfiData result;
+    result.data = NULL;
+    result.len = 0;
+    return result;
+}
+
+// core.v1.ServerStatus{running: true}
+struct FfiData GetServerStatus() {
+    unsigned char* out = (unsigned char*)malloc(2);
+    out[0] = 0x08; // field 1, varint
+    out[1] = 1;
+
+    struct FfiData result;
+    result.data = out;
+    result.len = 2;
+    return result;
+}
+
+// No services to describe in this mock: an empty core.v1.ServiceList
+struct FfiData ListServices() {
+    struct FfiData result;
+    result.data = NULL;
+    result.len = 0;
+    return result;
+}
+
+struct FfiData DescribeMethod(char* method) {
+    return ListServices();
+}
+
+// core.v1.HealthReport{status: "SERVING"}
+struct FfiData GetHealth() {
+    static const char report[] = "\x0a\x07SERVING";
+    unsigned char* out = (unsigned char*)malloc(sizeof(report) - 1);
+    memcpy(out, report, sizeof(report) - 1);
+
+    struct FfiData result;
+    result.data = out;
+    result.len = sizeof(report) - 1;
+    return result;
+}
+
+struct FfiData StartGrpcServerWithStatus(struct CoreArgument cArg, int failOnListenError) {
+    StartGrpcServer(cArg);
+    return GetServerStatus();
+}
+
+// Listeners never fail in this mock
+typedef void (*ServeErrorCallback)(void* data, long long len);
+
+void RegisterServeErrorCallback(ServeErrorCallback callback) {}
+
 struct FfiData InvokeBackend(char* method, void* data, long long len) {
     printf("[C++] InvokeBackend called: %s (len: %lld)\n", method, len);
 
@@ -52,6 +107,46 @@ struct FfiData InvokeBackendWithMeta(char* method, void* data, long long len, vo
     return InvokeBackend(method, data, len);
 }
 
+struct FfiData InvokeBackendWithHeader(char* method, void* data, long long len, void* header, long long headerLen) {
+    return InvokeBackend(method, data, len);
+}
+
+// Wraps the InvokeBackend response as core.v1.CallResponse{payload}
+struct FfiData InvokeBackendCall(char* method, void* data, long long len, void* header, long long headerLen) {
+    struct FfiData resp = InvokeBackend(method, data, len);
+    unsigned char* out = (unsigned char*)malloc(resp.len + 11);
+    long long n = 0;
+    out[n++] = 0x0a; // field 1, length-delimited
+    for (unsigned long long v = resp.len; ; v >>= 7) {
+        out[n++] = (v & 0x7f) | (v > 0x7f ? 0x80 : 0);
+        if (v <= 0x7f) break;
+    }
+    memcpy(out + n, resp.data, resp.len);
+    free(resp.data);
+
+    struct FfiData result;
+    result.data = out;
+    result.len = n + resp.len;
+    return result;
+}
+
+// Async calls complete synchronously in this mock
+typedef void (*CallCompleteCallback)(long long callId, void* data, long long len);
+static CallCompleteCallback callCompleteCallback = NULL;
+
+void RegisterCallCompleteCallback(CallCompleteCallback callback) {
+    callCompleteCallback = callback;
+}
+
+int InvokeBackendAsync(long long callId, char* method, void* data, long long len, void* header, long long headerLen) {
+    if (!callCompleteCallback) return 1;
+    struct FfiData resp = InvokeBackendCall(method, data, len, header, headerLen);
+    callCompleteCallback(callId, resp.data, resp.len);
+    return 0;
+}
+
+void CancelBackendCall(long long callId) {}
+
 void FreeFfiData(void* data) {
     if (data) {
         printf("[C++] FreeFfiData called\n");
@@ -63,6 +158,12 @@ void FreeFfiData(void* data) {
 long long InvokeBackendServerStream(char* method, void* data, long long len) { return -1; }
 long long InvokeBackendClientStream(char* method) { return -1; }
 long long InvokeBackendBidiStream(char* method) { return -1; }
+long long InvokeBackendServerStreamWithMeta(char* method, void* data, long long len, void* meta, long long metaLen) { return -1; }
+long long InvokeBackendClientStreamWithMeta(char* method, void* meta, long long metaLen) { return -1; }
+long long InvokeBackendBidiStreamWithMeta(char* method, void* meta, long long metaLen) { return -1; }
+long long InvokeBackendServerStreamWithHeader(char* method, void* data, long long len, void* header, long long headerLen) { return -1; }
+long long InvokeBackendClientStreamWithHeader(char* method, void* header, long long headerLen) { return -1; }
+long long InvokeBackendBidiStreamWithHeader(char* method, void* header, long long headerLen) { return -1; }
 int SendStreamData(long long streamId, void* data, long long len) { return 0; }
 void CloseStream(long long streamId) {}
 void CloseStreamInput(long long streamId) {}
@@ -54,6 +54,27 @@ func RegisterGoGreeterServicePlugin(s GoGreeterServicePlugin) {
 	pluginGoGreeterService = s
 }
 
+// Embed this file's descriptors so hosts can check wire compatibility at load,
+// and route the generic Synurang_Invoke / Synurang_Stream_Open exports.
+func init() {
+	plugin.RegisterDescriptors(File_example_proto)
+	plugin.RegisterService("example.v1.GoGreeterService", invokeGoGreeterService, openGoGreeterServiceStream)
+}
+
+// OnInit registers fn to receive the configuration bytes passed to the host's
+// LoadPlugin (synurang.WithConfig). Returning an error aborts the load.
+// This should be called in the plugin's init() function.
+func OnInit(fn func(cfg []byte) error) {
+	plugin.OnInit(fn)
+}
+
+// OnShutdown registers fn to run when the host closes the plugin.
+// The context carries the host's shutdown deadline, if any.
+// This should be called in the plugin's init() function.
+func OnShutdown(fn func(ctx context.Context) error) {
+	plugin.OnShutdown(fn)
+}
+
 // =============================================================================
 // Internal Invoke Functions (unary methods only)
 // =============================================================================
@@ -136,17 +157,50 @@ func Synurang_Invoke_GoGreeterService(method *C.char, data *C.char, dataLen C.in
 	return (*C.char)(C.CBytes(result))
 }
 
+// Batch request/response layout is documented in pkg/plugin/batch.go.
+// The whole response carries the same status byte as Synurang_Invoke_GoGreeterService;
+// status=1 is only used when the batch itself cannot be decoded.
+
+//export Synurang_InvokeBatch_GoGreeterService
+func Synurang_InvokeBatch_GoGreeterService(data *C.char, dataLen C.int, respLen *C.int) *C.char {
+	ctx := context.Background()
+
+	var d []byte
+	if data != nil && dataLen > 0 {
+		d = C.GoBytes(unsafe.Pointer(data), dataLen)
+	}
+
+	res, err := plugin.InvokeBatch(ctx, d, invokeGoGreeterService)
+	if err != nil {
+		errBytes := []byte(err.Error())
+		result := make([]byte, 1+len(errBytes))
+		result[0] = 1 // error status
+		copy(result[1:], errBytes)
+		*respLen = C.int(len(result))
+		return (*C.char)(C.CBytes(result))
+	}
+
+	result := make([]byte, 1+len(res))
+	result[0] = 0 // success status
+	copy(result[1:], res)
+	*respLen = C.int(len(result))
+	return (*C.char)(C.CBytes(result))
+}
+
 // =============================================================================
 // Streaming Support
 // =============================================================================
 
 //export Synurang_Stream_GoGreeterService_Open
 func Synurang_Stream_GoGreeterService_Open(method *C.char) C.ulonglong {
+	return C.ulonglong(openGoGreeterServiceStream(C.GoString(method)))
+}
+
+func openGoGreeterServiceStream(m string) uint64 {
 	if pluginGoGreeterService == nil {
 		return 0
 	}
 
-	m := C.GoString(method)
 	handle, ps := plugin.NewStream(m)
 
 	// Start stream handler goroutine
@@ -232,10 +286,12 @@ func Synurang_Stream_GoGreeterService_Open(method *C.char) C.ulonglong {
 			if err := pluginGoGreeterService.BidiFile(&pluginStreamGoGreeterServiceBidiFile{ps}); err != nil && err != io.EOF {
 				trySendErr(ps.ErrCh, err)
 			}
+		default:
+			trySendErr(ps.ErrCh, fmt.Errorf("unknown method: %s", m))
 		}
 	}()
 
-	return C.ulonglong(handle)
+	return handle
 }
 
 // =============================================================================
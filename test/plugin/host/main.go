@@ -2,16 +2,21 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"math/rand"
+	"os"
+	"runtime/pprof"
 	"sync"
 	"time"
 
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 
 	"github.com/ivere27/synurang/pkg/synurang"
+	"github.com/ivere27/synurang/pkg/synurangtest"
 	pb "github.com/ivere27/synurang/test/plugin/api"
 )
 
@@ -148,7 +153,14 @@ func testContextTimeout(plugin *synurang.Plugin) {
 
 func main() {
 	// Load the plugin using synurang's PluginLoader
-	plugin, err := synurang.LoadPlugin("../impl/plugin.so")
+	plugin, err := synurang.LoadPlugin("../impl/plugin.so",
+		synurang.WithConfig(&pb.HelloRequest{Name: "host"}),
+		synurang.WithDescriptorCheck(synurang.DescriptorCheckRefuse),
+		synurang.WithLogSink(func(rec synurang.LogRecord) {
+			fmt.Printf("[Host log] %s %s %s %v\n", rec.Level, rec.Plugin, rec.Message, rec.Attrs)
+			pluginLogs <- rec
+		}),
+	)
 	if err != nil {
 		log.Fatalf("Failed to load plugin: %v", err)
 	}
@@ -181,6 +193,30 @@ func main() {
 	fmt.Println("\n=== Test 9: Context Timeout ===")
 	testContextTimeout(plugin)
 
+	fmt.Println("\n=== Test 10: Batched Unary ===")
+	testBatch(plugin)
+
+	fmt.Println("\n=== Test 11: Conformance Kit ===")
+	testConformance(plugin)
+
+	fmt.Println("\n=== Test 12: Init Failure Aborts Load ===")
+	testInitFailure()
+
+	fmt.Println("\n=== Test 13: Log Bridge ===")
+	testLogBridge()
+
+	fmt.Println("\n=== Test 14: Push Streams Do Not Pin Threads ===")
+	testPushStreamThreads(plugin)
+
+	fmt.Println("\n=== Test 15: Descriptor Check ===")
+	testDescriptorCheck(plugin)
+
+	fmt.Println("\n=== Test 16: Generic Entry Points (no service name) ===")
+	testGenericEntryPoints(plugin)
+
+	fmt.Println("\n=== Test 17: Async Invoke and Cancellation ===")
+	testInvokeAsync(plugin)
+
 	fmt.Println("\n=== All tests passed! ===")
 }
 
@@ -238,6 +274,224 @@ func testRawInvoke(plugin *synurang.Plugin) {
 	fmt.Printf("  OK: %s\n", resp.Message)
 }
 
+// testBatch verifies raw InvokeBatch and auto-batching through PluginClientConn
+func testBatch(plugin *synurang.Plugin) {
+	var calls []synurang.BatchCall
+	for i := 0; i < 5; i++ {
+		reqBytes, err := proto.Marshal(&pb.HelloRequest{Name: fmt.Sprintf("Batch %d", i)})
+		if err != nil {
+			log.Fatalf("Failed to marshal request: %v", err)
+		}
+		calls = append(calls, synurang.BatchCall{Method: "/example.v1.GoGreeterService/Bar", Data: reqBytes})
+	}
+	calls = append(calls, synurang.BatchCall{Method: "/example.v1.GoGreeterService/Unknown"})
+
+	fmt.Println("Calling plugin via raw InvokeBatch...")
+	results, err := plugin.InvokeBatch("GoGreeterService", calls)
+	if err != nil {
+		log.Fatalf("InvokeBatch failed: %v", err)
+	}
+	if len(results) != len(calls) {
+		log.Fatalf("Expected %d results, got %d", len(calls), len(results))
+	}
+	for i, r := range results[:5] {
+		if r.Err != nil {
+			log.Fatalf("Batch item %d failed: %v", i, r.Err)
+		}
+		resp := &pb.HelloResponse{}
+		if err := proto.Unmarshal(r.Data, resp); err != nil {
+			log.Fatalf("Failed to unmarshal batch item %d: %v", i, err)
+		}
+		fmt.Printf("  Item[%d]: %s\n", i, resp.Message)
+	}
+	if results[5].Err == nil {
+		log.Fatalf("Expected error for unknown method in batch")
+	}
+	fmt.Printf("  OK: Unknown method in batch returns: %v\n", results[5].Err)
+
+	fmt.Println("Calling Bar concurrently with auto-batching...")
+	conn := synurang.NewPluginClientConn(plugin, "GoGreeterService", synurang.WithBatching(time.Millisecond, 32))
+	client := pb.NewGoGreeterServiceClient(conn)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("Auto %d", i)
+			resp, err := client.Bar(context.Background(), &pb.HelloRequest{Name: name})
+			if err != nil {
+				log.Fatalf("Batched Bar %d failed: %v", i, err)
+			}
+			if resp.Message != "Hello from Plugin (SO)! "+name {
+				log.Fatalf("Batched Bar %d got mismatched response: %s", i, resp.Message)
+			}
+		}(i)
+	}
+	wg.Wait()
+	fmt.Println("  OK: 50 concurrent calls served via batches")
+}
+
+// testConformance runs the synurangtest battery against the test plugin
+func testConformance(plugin *synurang.Plugin) {
+	svc := pb.File_example_proto.Services().ByName("GoGreeterService")
+	report := synurangtest.Run(plugin, []protoreflect.ServiceDescriptor{svc}, synurangtest.Options{})
+	report.WriteTo(os.Stdout)
+	if report.Failed() {
+		log.Fatalf("Conformance checks failed")
+	}
+}
+
+// testInitFailure verifies that a config rejected by OnInit fails LoadPlugin
+func testInitFailure() {
+	_, err := synurang.LoadPlugin("../impl/plugin.so", synurang.WithConfig(&pb.HelloRequest{Name: "fail"}))
+	if err == nil {
+		log.Fatalf("Expected LoadPlugin to fail for rejected config")
+	}
+	fmt.Printf("  OK: LoadPlugin returned: %v\n", err)
+}
+
+// testDescriptorCheck verifies that the plugin's embedded descriptors matched
+// the host's (LoadPlugin used DescriptorCheckRefuse)
+func testDescriptorCheck(plugin *synurang.Plugin) {
+	if issues := plugin.DescriptorIssues(); len(issues) != 0 {
+		log.Fatalf("Unexpected descriptor issues: %v", issues)
+	}
+	fmt.Println("  OK: plugin descriptors are compatible with the host")
+}
+
+// testGenericEntryPoints calls the plugin through Synurang_Invoke and
+// Synurang_Stream_Open, routed by full method name
+func testGenericEntryPoints(plugin *synurang.Plugin) {
+	conn := synurang.NewPluginClientConn(plugin, "")
+	client := pb.NewGoGreeterServiceClient(conn)
+
+	resp, err := client.Bar(context.Background(), &pb.HelloRequest{Name: "Generic"})
+	if err != nil {
+		log.Fatalf("Generic unary failed: %v", err)
+	}
+	fmt.Printf("  OK: Unary: %s\n", resp.Message)
+
+	stream, err := client.BarServerStream(context.Background(), &pb.HelloRequest{Name: "Generic"})
+	if err != nil {
+		log.Fatalf("Generic stream open failed: %v", err)
+	}
+	n := 0
+	for {
+		if _, err := stream.Recv(); err == io.EOF {
+			break
+		} else if err != nil {
+			log.Fatalf("Generic stream recv failed: %v", err)
+		}
+		n++
+	}
+	fmt.Printf("  OK: ServerStream received %d messages\n", n)
+
+	if _, err := plugin.Invoke("", "/example.v1.Unknown/Bar", nil); err == nil {
+		log.Fatalf("Expected an error for an unknown service")
+	} else {
+		fmt.Printf("  OK: Unknown service returns: %v\n", err)
+	}
+}
+
+// testInvokeAsync checks that async calls complete and that cancelling one
+// reaches the handler's context inside the plugin
+func testInvokeAsync(plugin *synurang.Plugin) {
+	const method = "/example.v1.GoGreeterService/Bar"
+	req, _ := proto.Marshal(&pb.HelloRequest{Name: "Async"})
+	data, err := plugin.InvokeAsync(context.Background(), "", method, req).Result()
+	if err != nil {
+		log.Fatalf("Async invoke failed: %v", err)
+	}
+	resp := &pb.HelloResponse{}
+	if err := proto.Unmarshal(data, resp); err != nil {
+		log.Fatalf("Async response: %v", err)
+	}
+	fmt.Printf("  OK: Async unary: %s\n", resp.Message)
+
+	req, _ = proto.Marshal(&pb.HelloRequest{Name: "WaitForCancel"})
+	call := plugin.InvokeAsync(context.Background(), "", method, req)
+	time.Sleep(20 * time.Millisecond)
+	if !plugin.CancelCall(call.ID()) {
+		log.Fatalf("Expected call %d to be pending", call.ID())
+	}
+	if _, err := call.Result(); !errors.Is(err, context.Canceled) {
+		log.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	// The handler only returns once its context is cancelled
+	deadline := time.Now().Add(time.Second)
+	for plugin.CancelCall(call.ID()) {
+		if time.Now().After(deadline) {
+			log.Fatalf("Plugin handler did not observe the cancellation")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	fmt.Printf("  OK: Call %d cancelled inside the plugin\n", call.ID())
+
+	client := pb.NewGoGreeterServiceClient(synurang.NewPluginClientConn(plugin, ""))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.Bar(ctx, &pb.HelloRequest{Name: "WaitForCancel"}); !errors.Is(err, context.DeadlineExceeded) {
+		log.Fatalf("Expected DeadlineExceeded, got %v", err)
+	}
+	fmt.Println("  OK: Deadline cancels a PluginClientConn call")
+}
+
+// pluginLogs receives records forwarded by the plugin's slog handler
+var pluginLogs = make(chan synurang.LogRecord, 64)
+
+// testLogBridge verifies that the OnInit log record reached the host sink
+func testLogBridge() {
+	for {
+		select {
+		case rec := <-pluginLogs:
+			if rec.Message != "OnInit" {
+				continue
+			}
+			if rec.Plugin != "test-greeter" {
+				log.Fatalf("Expected plugin name test-greeter, got %q", rec.Plugin)
+			}
+			fmt.Printf("  OK: received %q from %s with %v\n", rec.Message, rec.Plugin, rec.Attrs)
+			return
+		default:
+			log.Fatalf("Expected OnInit log record from plugin")
+		}
+	}
+}
+
+// testPushStreamThreads opens many idle streams and checks that waiting on
+// them does not create one OS thread per stream
+func testPushStreamThreads(plugin *synurang.Plugin) {
+	const n = 200
+	conn := synurang.NewPluginClientConn(plugin, "GoGreeterService")
+	client := pb.NewGoGreeterServiceClient(conn)
+
+	threadsBefore := pprof.Lookup("threadcreate").Count()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		stream, err := client.BarBidiStream(ctx)
+		if err != nil {
+			log.Fatalf("BarBidiStream failed: %v", err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stream.Recv() // blocks until cancel
+		}()
+	}
+	time.Sleep(200 * time.Millisecond)
+	created := pprof.Lookup("threadcreate").Count() - threadsBefore
+	cancel()
+	wg.Wait()
+
+	if created > n/4 {
+		log.Fatalf("%d idle streams created %d OS threads", n, created)
+	}
+	fmt.Printf("  OK: %d idle streams created %d OS threads\n", n, created)
+}
+
 // testUnary demonstrates unary RPC via gRPC client
 func testUnary(plugin *synurang.Plugin) {
 	conn := synurang.NewPluginClientConn(plugin, "GoGreeterService")
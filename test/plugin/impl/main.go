@@ -2,9 +2,13 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 
+	"github.com/ivere27/synurang/pkg/plugin"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	pb "github.com/ivere27/synurang/test/plugin/api"
@@ -16,6 +20,11 @@ type Server struct{}
 // Unary methods
 func (s *Server) Bar(ctx context.Context, req *pb.HelloRequest) (*pb.HelloResponse, error) {
 	fmt.Printf("[Plugin] Received Bar request: %s\n", req.Name)
+	if req.Name == "WaitForCancel" {
+		// Used by the host's async test: only returns once cancelled
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
 	return &pb.HelloResponse{
 		Message:   "Hello from Plugin (SO)! " + req.Name,
 		From:      "plugin",
@@ -145,6 +154,27 @@ func init() {
 	fmt.Println("[Plugin] Initializing...")
 	// Per-service registration - only register the service we implement
 	pb.RegisterGoGreeterServicePlugin(&Server{})
+
+	// Forward logs to the host's logger
+	plugin.SetName("test-greeter")
+	slog.SetDefault(slog.New(plugin.NewLogHandler(nil)))
+
+	// Host configuration arrives as a HelloRequest (see synurang.WithConfig)
+	pb.OnInit(func(cfg []byte) error {
+		req := &pb.HelloRequest{}
+		if err := proto.Unmarshal(cfg, req); err != nil {
+			return err
+		}
+		if req.Name == "fail" {
+			return errors.New("rejected config")
+		}
+		slog.Info("OnInit", "config.name", req.Name)
+		return nil
+	})
+	pb.OnShutdown(func(ctx context.Context) error {
+		fmt.Println("[Plugin] OnShutdown")
+		return nil
+	})
 }
 
 func main() {} // Required for -buildmode=c-shared
@@ -96,6 +96,48 @@ pub extern "C" fn StopGrpcServer() -> i32 {
     0
 }
 
+/// Empty core.v1.ShutdownReport: nothing is left to terminate
+#[no_mangle]
+pub extern "C" fn StopGrpcServerWithTimeout(_timeout_ms: i64) -> FfiData {
+    StopGrpcServer();
+    FfiData::empty()
+}
+
+/// core.v1.ServerStatus{running: true}
+#[no_mangle]
+pub extern "C" fn GetServerStatus() -> FfiData {
+    FfiData::from_vec(vec![0x08, 1])
+}
+
+/// No services to describe in this mock: an empty core.v1.ServiceList.
+#[no_mangle]
+pub extern "C" fn ListServices() -> FfiData {
+    FfiData::empty()
+}
+
+#[no_mangle]
+pub extern "C" fn DescribeMethod(_method: *const c_char) -> FfiData {
+    FfiData::empty()
+}
+
+/// core.v1.HealthReport{status: "SERVING"}
+#[no_mangle]
+pub extern "C" fn GetHealth() -> FfiData {
+    FfiData::from_vec(b"\x0a\x07SERVING".to_vec())
+}
+
+#[no_mangle]
+pub extern "C" fn StartGrpcServerWithStatus(arg: CoreArgument, _fail_on_listen_error: i32) -> FfiData {
+    StartGrpcServer(arg);
+    GetServerStatus()
+}
+
+type ServeErrorCallback = extern "C" fn(*mut c_void, i64);
+
+/// Listeners never fail in this mock.
+#[no_mangle]
+pub extern "C" fn RegisterServeErrorCallback(_callback: Option<ServeErrorCallback>) {}
+
 #[no_mangle]
 pub extern "C" fn InvokeBackend(
     method: *const c_char,
@@ -120,6 +162,65 @@ pub extern "C" fn InvokeBackendWithMeta(
     InvokeBackend(method, data, len)
 }
 
+#[no_mangle]
+pub extern "C" fn InvokeBackendWithHeader(
+    method: *const c_char,
+    data: *const c_void,
+    len: i64,
+    _header: *const c_void,
+    _header_len: i64,
+) -> FfiData {
+    InvokeBackend(method, data, len)
+}
+
+/// Wraps the InvokeBackend response as core.v1.CallResponse{payload}.
+#[no_mangle]
+pub extern "C" fn InvokeBackendCall(
+    method: *const c_char,
+    _data: *const c_void,
+    len: i64,
+    _header: *const c_void,
+    _header_len: i64,
+) -> FfiData {
+    let method_str = c_str_to_string(method);
+    println!("[Rust] InvokeBackendCall called: {} (len: {})", method_str, len);
+
+    let payload = b"Hello from Rust Backend!";
+    let mut out = vec![0x0a, payload.len() as u8];
+    out.extend_from_slice(payload);
+    FfiData::from_vec(out)
+}
+
+type CallCompleteCallback = extern "C" fn(i64, *mut c_void, i64);
+
+static CALL_COMPLETE_CALLBACK: std::sync::Mutex<Option<CallCompleteCallback>> = std::sync::Mutex::new(None);
+
+#[no_mangle]
+pub extern "C" fn RegisterCallCompleteCallback(callback: Option<CallCompleteCallback>) {
+    *CALL_COMPLETE_CALLBACK.lock().unwrap() = callback;
+}
+
+/// Async calls complete synchronously in this mock.
+#[no_mangle]
+pub extern "C" fn InvokeBackendAsync(
+    call_id: i64,
+    method: *const c_char,
+    data: *const c_void,
+    len: i64,
+    header: *const c_void,
+    header_len: i64,
+) -> i32 {
+    let Some(callback) = *CALL_COMPLETE_CALLBACK.lock().unwrap() else {
+        return 1;
+    };
+    let resp = InvokeBackendCall(method, data, len, header, header_len);
+    callback(call_id, resp.data, resp.len);
+    0
+}
+
+#[no_mangle]
+pub extern "C" fn CancelBackendCall(_call_id: i64) {}
+
 #[no_mangle]
 pub extern "C" fn FreeFfiData(data: *mut c_void) {
     if !data.is_null() {
@@ -143,6 +244,18 @@ pub extern "C" fn InvokeBackendClientStream(_method: *const c_char) -> i64 { -1
 #[no_mangle]
 pub extern "C" fn InvokeBackendBidiStream(_method: *const c_char) -> i64 { -1 }
 #[no_mangle]
+pub extern "C" fn InvokeBackendServerStreamWithMeta(_method: *const c_char, _data: *const c_void, _len: i64, _meta: *const c_void, _meta_len: i64) -> i64 { -1 }
+#[no_mangle]
+pub extern "C" fn InvokeBackendClientStreamWithMeta(_method: *const c_char, _meta: *const c_void, _meta_len: i64) -> i64 { -1 }
+#[no_mangle]
+pub extern "C" fn InvokeBackendBidiStreamWithMeta(_method: *const c_char, _meta: *const c_void, _meta_len: i64) -> i64 { -1 }
+#[no_mangle]
+pub extern "C" fn InvokeBackendServerStreamWithHeader(_method: *const c_char, _data: *const c_void, _len: i64, _header: *const c_void, _header_len: i64) -> i64 { -1 }
+#[no_mangle]
+pub extern "C" fn InvokeBackendClientStreamWithHeader(_method: *const c_char, _header: *const c_void, _header_len: i64) -> i64 { -1 }
+#[no_mangle]
+pub extern "C" fn InvokeBackendBidiStreamWithHeader(_method: *const c_char, _header: *const c_void, _header_len: i64) -> i64 { -1 }
+#[no_mangle]
 pub extern "C" fn SendStreamData(_stream_id: i64, _data: *const c_void, _len: i64) -> i32 { 0 }
 #[no_mangle]
 pub extern "C" fn CloseStream(_stream_id: i64) {}